@@ -0,0 +1,37 @@
+package command
+
+import (
+	"github.com/pkg/errors"
+)
+
+// isDryRun resolves whether resources are only shown instead of deleted. Deleting resources
+// has to be requested explicitly, either on the command line or via the config.
+func (c *Wipe) isDryRun() bool {
+	if c.dryRunFlag != nil {
+		return *c.dryRunFlag
+	}
+	if c.filter.DryRun != nil {
+		return *c.filter.DryRun
+	}
+	return true
+}
+
+// confirm asks the user to approve the deletion by typing the ID of the account
+// resources will be deleted in (the same way as deleting a repository on GitHub
+// requires typing its name), which guards against using unexpected credentials.
+func (c *Wipe) confirm(approvalQuestion string) (bool, error) {
+	accountID, err := c.regions[0].client.AccountID()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get the account ID of the used credentials")
+	}
+
+	v, err := c.UI.Ask(
+		approvalQuestion +
+			"Only the ID of the account ('" + accountID + "') will be accepted to approve.\n\n" +
+			"Enter a value: ")
+	if err != nil {
+		return false, err
+	}
+
+	return v == accountID, nil
+}
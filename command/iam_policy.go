@@ -0,0 +1,54 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/mitchellh/cli"
+)
+
+// IamPolicy prints the IAM policy that is needed to sweep the resource types of a yaml config,
+// so that AWSweeper can be run with least-privilege credentials.
+type IamPolicy struct {
+	UI cli.Ui
+	// profileName is the profile of the config to generate the policy for (empty for the types outside of profiles)
+	profileName string
+}
+
+// Run executes the iam-policy command.
+func (c *IamPolicy) Run(args []string) int {
+	set := flag.NewFlagSet("iam-policy", flag.ContinueOnError)
+	listOnly := set.Bool("list-only", false, "Only allow to list resources (sufficient for dry runs)")
+	set.Usage = func() { fmt.Println(help()) }
+
+	if err := set.Parse(args); err != nil || len(set.Args()) != 1 {
+		fmt.Println(help())
+		return 1
+	}
+
+	filter := resource.NewProfileFilter(set.Args()[0], c.profileName)
+	if err := filter.Validate(); err != nil {
+		c.UI.Error(fmt.Sprintf("Invalid config: %s", err))
+		return 1
+	}
+
+	policy, err := filter.Policy(*listOnly)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to generate IAM policy: %s", err))
+		return 1
+	}
+	c.UI.Output(string(policy))
+
+	return 0
+}
+
+// Help returns help information of this command
+func (c *IamPolicy) Help() string {
+	return help()
+}
+
+// Synopsis returns a short version of the help information of this command
+func (c *IamPolicy) Synopsis() string {
+	return "Print the IAM policy needed to sweep the resources of a yaml configuration"
+}
@@ -0,0 +1,55 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/mitchellh/cli"
+	"github.com/spf13/afero"
+)
+
+// MigrateConfig prints a yaml config converted into the format of the current config version.
+type MigrateConfig struct {
+	UI cli.Ui
+}
+
+// Run executes the migrate-config command.
+func (c *MigrateConfig) Run(args []string) int {
+	set := flag.NewFlagSet("migrate-config", flag.ContinueOnError)
+	set.Usage = func() { fmt.Println(help()) }
+
+	if err := set.Parse(args); err != nil || len(set.Args()) != 1 {
+		fmt.Println(help())
+		return 1
+	}
+
+	data, err := afero.ReadFile(resource.AppFs, set.Args()[0])
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to read config file: %s", err))
+		return 1
+	}
+
+	migrated, version, err := resource.MigrateConfig(data)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to migrate config: %s", err))
+		return 1
+	}
+	if version == resource.ConfigVersion {
+		c.UI.Info(fmt.Sprintf("Config is already up to date (version %d)", version))
+		return 0
+	}
+	c.UI.Output(string(migrated))
+
+	return 0
+}
+
+// Help returns help information of this command
+func (c *MigrateConfig) Help() string {
+	return help()
+}
+
+// Synopsis returns a short version of the help information of this command
+func (c *MigrateConfig) Synopsis() string {
+	return "Print a yaml configuration converted into the current config format"
+}
@@ -0,0 +1,54 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/cloudetc/awsweeper/resource"
+)
+
+// Nuke deletes all supported resources of an AWS account.
+//
+// Resources that AWS creates for every account (e.g., default VPCs)
+// or that are managed by AWS are left untouched.
+type Nuke struct {
+	*Wipe
+}
+
+// Run executes the nuke command.
+func (c *Nuke) Run(args []string) int {
+	set := flag.NewFlagSet("nuke", flag.ContinueOnError)
+	accountID := set.String("account-id", "", "ID of the account to nuke")
+	set.Usage = func() { fmt.Println(help()) }
+
+	if err := set.Parse(args); err != nil || *accountID == "" || len(set.Args()) > 0 {
+		fmt.Println(help())
+		return 1
+	}
+
+	// guard against nuking the wrong account, e.g. due to an unexpected AWS profile
+	currentAccountID, err := c.regions[0].client.AccountID()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to get the account ID of the used credentials: %s", err))
+		return 1
+	}
+	if currentAccountID != *accountID {
+		c.UI.Error(fmt.Sprintf("Refusing to nuke account %s: the used credentials belong to account %s",
+			*accountID, currentAccountID))
+		return 1
+	}
+
+	c.filter = resource.NewNukeFilter()
+
+	return c.run("Do you really want to delete ALL resources of account '" + *accountID + "'?\n")
+}
+
+// Help returns help information of this command
+func (c *Nuke) Help() string {
+	return help()
+}
+
+// Synopsis returns a short version of the help information of this command
+func (c *Nuke) Synopsis() string {
+	return "Delete all supported resources of an AWS account"
+}
@@ -0,0 +1,85 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/sirupsen/logrus"
+)
+
+// Preflight checks whether everything is in place to sweep the resources of a yaml config
+// (or of all supported resource types), without listing or deleting any resources.
+type Preflight struct {
+	*Wipe
+}
+
+// Run executes the preflight command.
+func (c *Preflight) Run(args []string) int {
+	switch len(args) {
+	case 0:
+		c.filter = resource.NewNukeFilter()
+	case 1:
+		c.filter = resource.NewProfileFilter(args[0], c.profileName)
+		if err := c.filter.Validate(); err != nil {
+			logrus.WithError(err).Fatal()
+		}
+	default:
+		fmt.Println(help())
+		return 1
+	}
+
+	if err := c.configureClients(); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	var numFailed, numWarnings int
+
+	identity, err := c.regions[0].client.Identity()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("FAIL\tcredentials: %s", err))
+		return 1
+	}
+	c.UI.Output(fmt.Sprintf("OK\tcredentials: %s (account %s)", identity.Arn, identity.Account))
+
+	services := c.filter.Services()
+	for i, reg := range c.regions {
+		c.UI.Output(fmt.Sprintf("\nRegion: %s", reg.name))
+
+		for _, service := range services {
+			if i > 0 && resource.IsGlobalService(service) {
+				continue
+			}
+
+			res := reg.client.Probe(service)
+			switch {
+			case res.Err != nil:
+				numFailed++
+				c.UI.Error(fmt.Sprintf("FAIL\t%s: %s", service, res.Err))
+			case res.Throttled:
+				numWarnings++
+				c.UI.Warn(fmt.Sprintf("WARN\t%s: throttled, no headroom left before hitting the rate limit", service))
+			default:
+				c.UI.Output(fmt.Sprintf("OK\t%s (%s)", service, res.Latency))
+			}
+		}
+	}
+
+	if numFailed > 0 {
+		c.UI.Error(fmt.Sprintf("\nNot ready: %d check(s) failed, %d warning(s)", numFailed, numWarnings))
+		return 1
+	}
+	c.UI.Output(fmt.Sprintf("\nReady: all checks passed, %d warning(s)", numWarnings))
+
+	return 0
+}
+
+// Help returns help information of this command
+func (c *Preflight) Help() string {
+	return help()
+}
+
+// Synopsis returns a short version of the help information of this command
+func (c *Preflight) Synopsis() string {
+	return "Check credentials, region access and API reachability before sweeping"
+}
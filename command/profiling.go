@@ -0,0 +1,54 @@
+package command
+
+import (
+	"os"
+	"runtime/pprof"
+	"runtime/trace"
+
+	"github.com/pkg/errors"
+)
+
+// startProfiling writes a CPU profile and/or an execution trace to the given files (if not empty)
+// until the returned function is called.
+func startProfiling(cpuProfileFile, traceFile string) (func(), error) {
+	var stops []func()
+	stop := func() {
+		for _, s := range stops {
+			s()
+		}
+	}
+
+	if cpuProfileFile != "" {
+		f, err := os.Create(cpuProfileFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create CPU profile")
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return nil, errors.Wrap(err, "failed to start CPU profile")
+		}
+		stops = append(stops, func() {
+			pprof.StopCPUProfile()
+			f.Close()
+		})
+	}
+
+	if traceFile != "" {
+		f, err := os.Create(traceFile)
+		if err != nil {
+			stop()
+			return nil, errors.Wrap(err, "failed to create trace")
+		}
+		if err := trace.Start(f); err != nil {
+			f.Close()
+			stop()
+			return nil, errors.Wrap(err, "failed to start trace")
+		}
+		stops = append(stops, func() {
+			trace.Stop()
+			f.Close()
+		})
+	}
+
+	return stop, nil
+}
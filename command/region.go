@@ -0,0 +1,384 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// region bundles the clients to list and delete resources in a particular AWS region.
+type region struct {
+	name     string
+	client   *resource.AWS
+	provider *terraform.ResourceProvider
+	// sess is the session the client has been created from (nil for replayed runs)
+	sess *session.Session
+	// out is where the output of sweeping the region is written to
+	out io.Writer
+	// leases are the leases stored in the region (besides the ones in the tags of resources)
+	leases resource.Leases
+	// span is the span of sweeping the current resource type in the region (nil if not traced)
+	span *resource.Span
+}
+
+// forEachRegion calls fn for all regions in parallel. As the resources of global resource types (e.g., IAM)
+// are the same in every region, global types are only passed for the first region,
+// so that their resources are listed (and deleted) exactly once.
+func (c *Wipe) forEachRegion(fn func(reg *region, resTypes []resource.TerraformResourceType)) {
+	sweep := func(reg *region, resTypes []resource.TerraformResourceType) {
+		defer c.recoverFatal(reg)
+		fn(reg, resTypes)
+	}
+
+	if len(c.regions) == 1 {
+		c.regions[0].out = c.output()
+		sweep(c.regions[0], c.filter.Types())
+		return
+	}
+
+	outs := make([]*sectionedBuffer, len(c.regions))
+	done := make([]chan struct{}, len(c.regions))
+
+	for i, reg := range c.regions {
+		resTypes := c.filter.Types()
+		if i > 0 {
+			resTypes = resource.RegionalTypes(resTypes)
+		}
+
+		// output of regions is buffered, so that it doesn't interleave
+		outs[i] = &sectionedBuffer{}
+		done[i] = make(chan struct{})
+
+		go func(reg *region, resTypes []resource.TerraformResourceType, out *sectionedBuffer, done chan struct{}) {
+			defer close(done)
+
+			reg.out = out
+			sweep(reg, resTypes)
+		}(reg, resTypes, outs[i], done[i])
+	}
+
+	// the output is written in the order of the regions (or grouped by type), so that it is the same across runs
+	typesFirst := c.sortOrder.TypesFirst()
+	for i, reg := range c.regions {
+		<-done[i]
+		if !typesFirst {
+			fmt.Fprintf(c.output(), "\n===\nRegion: %s\n===\n%s", reg.name, outs[i].String())
+		}
+	}
+	if typesFirst {
+		c.writeByType(outs)
+	}
+}
+
+// writeByType writes the output of the regions grouped by resource type. The output that doesn't belong
+// to a type (e.g., about leases) comes first.
+func (c *Wipe) writeByType(outs []*sectionedBuffer) {
+	var resTypes []resource.TerraformResourceType
+	seen := map[resource.TerraformResourceType]bool{"": true}
+	for _, out := range outs {
+		for _, resType := range out.types() {
+			if !seen[resType] {
+				seen[resType] = true
+				resTypes = append(resTypes, resType)
+			}
+		}
+	}
+
+	for _, resType := range append([]resource.TerraformResourceType{""}, resTypes...) {
+		for i, reg := range c.regions {
+			if s := outs[i].section(resType); s != "" {
+				fmt.Fprintf(c.output(), "\n===\nRegion: %s\n===\n%s", reg.name, s)
+			}
+		}
+	}
+}
+
+// providerFor returns the provider to delete a resource with, which is the provider of the region
+// the resource has been listed in, unless it is located in another region.
+func (c *Wipe) providerFor(reg *region, r *resource.Resource) (*terraform.ResourceProvider, error) {
+	if r.Region == "" || r.Region == reg.name {
+		return reg.provider, nil
+	}
+
+	for _, other := range c.regions {
+		if other.name == r.Region {
+			return other.provider, nil
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.providers == nil {
+		c.providers = map[string]*terraform.ResourceProvider{}
+	}
+	p, found := c.providers[r.Region]
+	if !found {
+		var err error
+		p, err = c.newProvider(r.Region, c.filter.Clients)
+		if err != nil {
+			return nil, err
+		}
+		c.providers[r.Region] = p
+	}
+	return p, nil
+}
+
+// clientFor returns the clients to access a resource with directly (e.g., to empty an S3 bucket), which are the
+// clients of the region the resource has been listed in, unless it is located in another region.
+func (c *Wipe) clientFor(reg *region, r *resource.Resource) (*resource.AWS, error) {
+	if r.Region == "" || r.Region == reg.name {
+		return reg.client, nil
+	}
+
+	for _, other := range c.regions {
+		if other.name == r.Region {
+			return other.client, nil
+		}
+	}
+
+	if reg.sess == nil || c.clients == nil {
+		// replayed runs have no sessions to access other regions with
+		return reg.client, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.regionClients == nil {
+		c.regionClients = map[string]*resource.AWS{}
+	}
+	client, found := c.regionClients[r.Region]
+	if !found {
+		sess, err := c.filter.Clients.Session(reg.sess.Copy(&aws.Config{Region: aws.String(r.Region)}))
+		if err != nil {
+			return nil, err
+		}
+		client, err = c.clients.Client(r.Region, sess)
+		if err != nil {
+			return nil, err
+		}
+		client.Identities = reg.client.Identities
+		c.regionClients[r.Region] = client
+	}
+	return client, nil
+}
+
+// output returns where the output of sweeping regions is written to.
+func (c *Wipe) output() io.Writer {
+	if c.out != nil {
+		return c.out
+	}
+	return os.Stdout
+}
+
+// dedup removes resources of a global type that have already been listed in another region,
+// so that they are deleted only once. IDs of regional resources (e.g., names of key pairs)
+// are only unique per region.
+func (c *Wipe) dedup(reg *region, res resource.Resources) resource.Resources {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.seen == nil {
+		c.seen = map[string]string{}
+	}
+
+	result := resource.Resources{}
+	for _, r := range res {
+		if !resource.IsGlobal(r.Type) {
+			result = append(result, r)
+			continue
+		}
+		key := string(r.Type) + ":" + r.ID
+		if seenIn, found := c.seen[key]; found && seenIn != reg.name {
+			fmt.Fprintf(reg.out, "INFO: Skipping %s (already listed in region %s)\n", key, seenIn)
+			continue
+		}
+		c.seen[key] = reg.name
+		result = append(result, r)
+	}
+	return result
+}
+
+// sectionedBuffer is a buffer that can be written to concurrently. Its output is split into sections
+// per resource type, so that the output of multiple regions can be grouped by type.
+type sectionedBuffer struct {
+	mu       sync.Mutex
+	sections []*outputSection
+}
+
+// outputSection is the output of sweeping a resource type (or of anything else, if the type is empty).
+type outputSection struct {
+	resType resource.TerraformResourceType
+	buf     bytes.Buffer
+}
+
+func (b *sectionedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.sections) == 0 {
+		b.sections = append(b.sections, &outputSection{})
+	}
+	return b.sections[len(b.sections)-1].buf.Write(p)
+}
+
+// startSection makes the following output part of the section of a resource type.
+func (b *sectionedBuffer) startSection(resType resource.TerraformResourceType) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sections = append(b.sections, &outputSection{resType: resType})
+}
+
+// types returns the resource types of the sections in the order they have been started in.
+func (b *sectionedBuffer) types() []resource.TerraformResourceType {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var result []resource.TerraformResourceType
+	for _, s := range b.sections {
+		result = append(result, s.resType)
+	}
+	return result
+}
+
+// section returns the output of all sections of a resource type.
+func (b *sectionedBuffer) section(resType resource.TerraformResourceType) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var result string
+	for _, s := range b.sections {
+		if s.resType == resType {
+			result += s.buf.String()
+		}
+	}
+	return result
+}
+
+func (b *sectionedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var result string
+	for _, s := range b.sections {
+		result += s.buf.String()
+	}
+	return result
+}
+
+// startSection makes the following output of a region part of the section of a resource type,
+// if the output of the region is buffered.
+func startSection(reg *region, resType resource.TerraformResourceType) {
+	if b, ok := reg.out.(*sectionedBuffer); ok {
+		b.startSection(resType)
+	}
+}
+
+// orderedOutput collects the output of resources that are processed in parallel and writes it in the order
+// of the resources, as soon as the output of all previous resources is complete. A nil orderedOutput writes
+// the output right away.
+type orderedOutput struct {
+	mu   sync.Mutex
+	out  io.Writer
+	bufs []*bytes.Buffer
+	done []bool
+	next int
+}
+
+func newOrderedOutput(out io.Writer, n int) *orderedOutput {
+	o := &orderedOutput{out: out, bufs: make([]*bytes.Buffer, n), done: make([]bool, n)}
+	for i := range o.bufs {
+		o.bufs[i] = &bytes.Buffer{}
+	}
+	return o
+}
+
+// region returns a copy of a region that writes its output to the buffer of the i-th resource.
+func (o *orderedOutput) region(reg *region, i int) *region {
+	if o == nil {
+		return reg
+	}
+	cp := *reg
+	cp.out = o.bufs[i]
+	return &cp
+}
+
+// finish marks the output of the i-th resource as complete.
+func (o *orderedOutput) finish(i int) {
+	if o == nil {
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.done[i] = true
+	for o.next < len(o.done) && o.done[o.next] {
+		o.out.Write(o.bufs[o.next].Bytes())
+		o.bufs[o.next] = nil
+		o.next++
+	}
+}
+
+// recoverFatal recovers from a FatalError that aborted the sweep of a region (see resource.Fatal)
+// and keeps the first one, so that the run fails with it.
+func (c *Wipe) recoverFatal(reg *region) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	err, ok := r.(resource.FatalError)
+	if !ok {
+		panic(r)
+	}
+	c.UI.Error(fmt.Sprintf("Aborted sweeping region %s: %s", reg.name, err))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fatalErr == nil {
+		c.fatalErr = err
+	}
+}
+
+// configureClients recreates the clients and providers of the regions with the client settings of the config.
+func (c *Wipe) configureClients() error {
+	cfg := c.filter.Clients
+	if cfg == nil {
+		return nil
+	}
+
+	for _, reg := range c.regions {
+		if reg.sess == nil {
+			continue
+		}
+		sess, err := cfg.Session(reg.sess)
+		if err != nil {
+			return err
+		}
+		client, err := c.clients.Client(reg.name, sess)
+		if err != nil {
+			return err
+		}
+		client.Identities = reg.client.Identities
+		reg.client = client
+		p, err := c.newProvider(reg.name, cfg)
+		if err != nil {
+			return err
+		}
+		reg.provider = p
+	}
+
+	if cfg.Proxy != "" && os.Getenv("HTTPS_PROXY") == "" && os.Getenv("https_proxy") == "" {
+		c.UI.Output("WARN: The Terraform AWS provider only uses the proxy given by HTTPS_PROXY " +
+			"to delete resources, not the proxy of the config.")
+	}
+	return nil
+}
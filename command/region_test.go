@@ -0,0 +1,73 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/resourcetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// regionClients is a ClientProvider returning fake clients per region.
+type regionClients map[string]*resource.AWS
+
+func (c regionClients) Session(region string) (*session.Session, error) {
+	return session.NewSession(&aws.Config{Region: aws.String(region)})
+}
+
+func (c regionClients) Client(region string, sess *session.Session) (*resource.AWS, error) {
+	return c[region], nil
+}
+
+func TestWipe_ClientFor_BucketInOtherRegion(t *testing.T) {
+	// given
+	listing, listingBackend := resourcetest.NewAWS(resourcetest.Fixtures{})
+	located, locatedBackend := resourcetest.NewAWS(resourcetest.Fixtures{
+		"s3": {
+			"ListObjectVersions": map[string]interface{}{
+				"Versions": []interface{}{map[string]interface{}{"Key": "foo.txt", "VersionId": "1"}},
+			},
+		},
+	})
+	sess, err := session.NewSession(&aws.Config{Region: aws.String("us-east-1")})
+	require.NoError(t, err)
+
+	reg := &region{name: "us-east-1", client: listing, sess: sess}
+	c := &Wipe{
+		regions: []*region{reg},
+		filter:  &resource.Filter{},
+		clients: regionClients{"us-east-1": listing, "eu-west-1": located},
+	}
+	bucket := &resource.Resource{Type: resource.S3Bucket, ID: "foo", Region: "eu-west-1"}
+
+	// when
+	client, err := c.clientFor(reg, bucket)
+	require.NoError(t, err)
+	err = client.Empty(bucket)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s3:ListObjectVersions", "s3:DeleteObjects"}, locatedBackend.Calls())
+	assert.Empty(t, listingBackend.Calls())
+
+	cached, err := c.clientFor(reg, bucket)
+	require.NoError(t, err)
+	assert.True(t, cached == client)
+}
+
+func TestWipe_ClientFor_SameRegion(t *testing.T) {
+	// given
+	listing, _ := resourcetest.NewAWS(resourcetest.Fixtures{})
+	reg := &region{name: "us-east-1", client: listing}
+	c := &Wipe{regions: []*region{reg}, filter: &resource.Filter{}}
+
+	// when
+	client, err := c.clientFor(reg, &resource.Resource{Type: resource.S3Bucket, ID: "foo", Region: "us-east-1"})
+
+	// then
+	require.NoError(t, err)
+	assert.True(t, client == listing)
+}
@@ -0,0 +1,49 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/resourcetest"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+// replayRegion is the region of replayed runs if no region is given.
+const replayRegion = "us-east-1"
+
+// newReplayWipe sets up a dry run that lists resources from recorded outputs of AWS API calls instead of AWS.
+// As there are no providers, the state of resources is not refreshed.
+func newReplayWipe(ui cli.Ui, fixtures resourcetest.Fixtures, regionFlag string, dryRun *bool,
+	compliance bool, sample *resource.Sample, sortOrder resource.SortOrder) *Wipe {
+
+	names := []string{replayRegion}
+	if regionFlag != "" {
+		names = strings.Split(regionFlag, ",")
+	}
+
+	var regs []*region
+	for _, name := range names {
+		client, _ := resourcetest.NewAWS(fixtures)
+		client.Region = name
+		regs = append(regs, &region{
+			name:   name,
+			client: client,
+		})
+	}
+
+	return &Wipe{
+		UI: &cli.ColoredUi{
+			Ui:          ui,
+			OutputColor: cli.UiColorBlue,
+		},
+		regions: regs,
+		newProvider: func(region string, clients *resource.ClientsConfig) (*terraform.ResourceProvider, error) {
+			return nil, nil
+		},
+		dryRunFlag: dryRun,
+		compliance: compliance,
+		sample:     sample,
+		sortOrder:  sortOrder,
+	}
+}
@@ -0,0 +1,76 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/cloudetc/awsweeper/resource"
+)
+
+// Report aggregates the results of past runs stored in the audit bucket of a yaml config per week.
+type Report struct {
+	*Wipe
+}
+
+// Run executes the report command.
+func (c *Report) Run(args []string) int {
+	set := flag.NewFlagSet("report", flag.ContinueOnError)
+	weeks := set.Int("weeks", 4, "The number of weeks to report (including the current one)")
+	html := set.String("html", "", "Write the report as HTML page to the given file")
+	set.Usage = func() { fmt.Println(help()) }
+
+	if err := set.Parse(args); err != nil || len(set.Args()) != 1 || *weeks < 1 {
+		fmt.Println(help())
+		return 1
+	}
+
+	c.filter = resource.NewProfileFilter(set.Args()[0], c.profileName)
+	if err := c.filter.Validate(); err != nil {
+		c.UI.Error(fmt.Sprintf("Invalid config: %s", err))
+		return 1
+	}
+	if c.filter.Audit == nil {
+		c.UI.Error("The report requires the audit bucket to be configured under audit in the config.")
+		return 1
+	}
+	if err := c.configureClients(); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	since := resource.WeekStart(time.Now()).AddDate(0, 0, -7*(*weeks-1))
+	results, err := c.regions[0].client.RunResults(c.filter.Audit, since)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	stats := resource.AggregateWeekly(results)
+
+	if *html != "" {
+		if err := resource.WriteWeeklyReport(*html, stats); err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		c.UI.Output(fmt.Sprintf("INFO: Reported %d runs since %s to %s.", len(results), since.Format("2006-01-02"), *html))
+		return 0
+	}
+
+	if len(stats) == 0 {
+		c.UI.Output(fmt.Sprintf("No runs since %s.", since.Format("2006-01-02")))
+		return 0
+	}
+	c.UI.Output(resource.FormatWeeklyStats(stats))
+
+	return 0
+}
+
+// Help returns help information of this command
+func (c *Report) Help() string {
+	return help()
+}
+
+// Synopsis returns a short version of the help information of this command
+func (c *Report) Synopsis() string {
+	return "Report weekly aggregates of the runs stored in the audit bucket"
+}
@@ -0,0 +1,136 @@
+package command
+
+import (
+	"bytes"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"runtime"
+
+	"github.com/cloudetc/awsweeper/update"
+	goversion "github.com/hashicorp/go-version"
+	"github.com/mitchellh/cli"
+)
+
+// SelfUpdate replaces the running binary with the one of the latest release of AWSweeper.
+type SelfUpdate struct {
+	UI cli.Ui
+	// releaseURL is the API endpoint of the latest release (empty for the one of AWSweeper on GitHub)
+	releaseURL string
+}
+
+// Run executes the self-update command.
+func (c *SelfUpdate) Run(args []string) int {
+	set := flag.NewFlagSet("self-update", flag.ContinueOnError)
+	check := set.Bool("check", false, "Only check whether a newer release exists")
+	keyFile := set.String("key", "", "File with the (armored) public key releases are signed with")
+	allowDowngrade := set.Bool("allow-downgrade", false, "Replace the binary even if it is newer than the latest release")
+	set.Usage = func() { fmt.Println(help()) }
+
+	if err := set.Parse(args); err != nil || len(set.Args()) != 0 {
+		fmt.Println(help())
+		return 1
+	}
+
+	// looking up the latest release doesn't need the key, only downloading it does
+	u, err := update.NewUpdater(nil)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if c.releaseURL != "" {
+		u.ReleaseURL = c.releaseURL
+	}
+
+	release, err := u.Latest()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to get the latest release: %s", err))
+		return 1
+	}
+
+	current, err := goversion.NewVersion(version)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Invalid version of this build %q: %s", version, err))
+		return 1
+	}
+	latest, err := goversion.NewVersion(release.Version)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Invalid version of the latest release %q: %s", release.Version, err))
+		return 1
+	}
+	if latest.Equal(current) {
+		c.UI.Output(fmt.Sprintf("AWSweeper %s is the latest release", version))
+		return 0
+	}
+	if latest.LessThan(current) && !*allowDowngrade {
+		c.UI.Output(fmt.Sprintf("AWSweeper %s is newer than the latest release %s "+
+			"(use --allow-downgrade to replace it anyway)", version, release.Version))
+		return 0
+	}
+	if *check {
+		c.UI.Output(fmt.Sprintf("AWSweeper %s is available (current version is %s)", release.Version, version))
+		return 0
+	}
+
+	key, err := c.releaseKey(*keyFile)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if u, err = update.NewUpdater(key); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	binary, err := u.Binary(release, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to download release %s: %s", release.Version, err))
+		return 1
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to find the running binary: %s", err))
+		return 1
+	}
+	if err := update.Replace(executable, binary); err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to replace %s: %s", executable, err))
+		return 1
+	}
+	c.UI.Output(fmt.Sprintf("Updated AWSweeper from %s to %s", version, release.Version))
+
+	return 0
+}
+
+// releaseKey returns the public key releases are verified with, either from the given file or the one of the build.
+func (c *SelfUpdate) releaseKey(keyFile string) (io.Reader, error) {
+	if keyFile != "" {
+		key, err := ioutil.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read release key: %s", err)
+		}
+		return bytes.NewReader(key), nil
+	}
+	if releaseKey == "" {
+		return nil, fmt.Errorf("this build has no key to verify releases with, use --key <public-key.asc>")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(releaseKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid release key of this build: %s", err)
+	}
+	return bytes.NewReader(key), nil
+}
+
+// Help returns help information of this command
+func (c *SelfUpdate) Help() string {
+	return help()
+}
+
+// Synopsis returns a short version of the help information of this command
+func (c *SelfUpdate) Synopsis() string {
+	return "Replace the binary with the one of the latest release"
+}
@@ -0,0 +1,66 @@
+package command
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+// testLatestRelease serves the given version as the latest release (without any files).
+func testLatestRelease(version string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"tag_name": "v%s", "assets": []}`, version)
+	}))
+}
+
+func TestSelfUpdate_Run_CheckWithoutKey(t *testing.T) {
+	// given
+	server := testLatestRelease("99.0.0")
+	defer server.Close()
+
+	ui := cli.NewMockUi()
+	c := &SelfUpdate{UI: ui, releaseURL: server.URL}
+
+	// when
+	status := c.Run([]string{"--check"})
+
+	// then
+	assert.Equal(t, 0, status, ui.ErrorWriter.String())
+	assert.Contains(t, ui.OutputWriter.String(), "AWSweeper 99.0.0 is available")
+}
+
+func TestSelfUpdate_Run_WithoutKey(t *testing.T) {
+	// given
+	server := testLatestRelease("99.0.0")
+	defer server.Close()
+
+	ui := cli.NewMockUi()
+	c := &SelfUpdate{UI: ui, releaseURL: server.URL}
+
+	// when
+	status := c.Run(nil)
+
+	// then
+	assert.Equal(t, 1, status)
+	assert.Contains(t, ui.ErrorWriter.String(), "--key")
+}
+
+func TestSelfUpdate_Run_Downgrade(t *testing.T) {
+	// given
+	server := testLatestRelease("0.0.1")
+	defer server.Close()
+
+	ui := cli.NewMockUi()
+	c := &SelfUpdate{UI: ui, releaseURL: server.URL}
+
+	// when
+	status := c.Run(nil)
+
+	// then
+	assert.Equal(t, 0, status)
+	assert.Contains(t, ui.OutputWriter.String(), "--allow-downgrade")
+}
@@ -0,0 +1,216 @@
+package command
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/mitchellh/cli"
+)
+
+// serveTokenEnv is the environment variable with the token clients of the API have to authenticate with.
+const serveTokenEnv = "AWSWEEPER_TOKEN"
+
+// Serve exposes listing, planning and deleting the resources selected by a yaml config over an HTTP+JSON API.
+type Serve struct {
+	UI      cli.Ui
+	newWipe func() (*Wipe, error)
+	// profileName is the profile of the config to serve (empty for the types outside of profiles)
+	profileName string
+
+	filter *resource.Filter
+	token  string
+	// mu ensures that a single request is served at a time, as they share the rate limits of the account
+	mu sync.Mutex
+}
+
+// apiResource is the representation of a resource in responses of the API.
+type apiResource struct {
+	Type    resource.TerraformResourceType `json:"type"`
+	ID      string                         `json:"id"`
+	Region  string                         `json:"region"`
+	Tags    map[string]string              `json:"tags,omitempty"`
+	Created *time.Time                     `json:"created,omitempty"`
+}
+
+// applyResult is the response of applying the config, i.e. deleting the selected resources.
+type applyResult struct {
+	ExitStatus int    `json:"exit_status"`
+	Output     string `json:"output"`
+}
+
+// Run executes the serve command.
+func (c *Serve) Run(args []string) int {
+	set := flag.NewFlagSet("serve", flag.ContinueOnError)
+	listen := set.String("listen", "127.0.0.1:8080", "The address to listen on")
+	set.Usage = func() { fmt.Println(help()) }
+
+	if err := set.Parse(args); err != nil || len(set.Args()) != 1 {
+		fmt.Println(help())
+		return 1
+	}
+
+	c.token = os.Getenv(serveTokenEnv)
+	if c.token == "" {
+		c.UI.Error(fmt.Sprintf("Refusing to serve without authentication, set a token via %s", serveTokenEnv))
+		return 1
+	}
+
+	c.filter = resource.NewProfileFilter(set.Args()[0], c.profileName)
+	if err := c.filter.Validate(); err != nil {
+		c.UI.Error(fmt.Sprintf("Invalid config: %s", err))
+		return 1
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/resources", c.handle(http.MethodGet, c.list))
+	mux.HandleFunc("/v1/plan", c.handle(http.MethodPost, c.plan))
+	mux.HandleFunc("/v1/apply", c.handle(http.MethodPost, c.apply))
+
+	c.UI.Output(fmt.Sprintf("Serving the resources selected by '%s' on %s", set.Args()[0], *listen))
+	if err := http.ListenAndServe(*listen, mux); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	return 0
+}
+
+// handle authenticates requests with the given method and serves them one at a time.
+func (c *Serve) handle(method string, fn func() (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(c.token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.Method != method {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		c.mu.Lock()
+		result, err := fn()
+		c.mu.Unlock()
+
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// list returns all resources of the types in the config (not only the selected ones).
+func (c *Serve) list() (interface{}, error) {
+	return c.resources(true)
+}
+
+// plan returns the resources that would be deleted by applying the config.
+func (c *Serve) plan() (interface{}, error) {
+	return c.resources(false)
+}
+
+func (c *Serve) resources(all bool) ([]apiResource, error) {
+	w, err := c.newWipe()
+	if err != nil {
+		return nil, err
+	}
+	w.filter = c.filter
+	w.out = ioutil.Discard
+	w.runTime = time.Now()
+
+	if err := w.configureClients(); err != nil {
+		return nil, err
+	}
+	if err := w.lookupSeeds(); err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	result := []apiResource{}
+	add := func(reg *region, res resource.Resources) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for _, r := range res {
+			regionName := reg.name
+			if r.Region != "" {
+				regionName = r.Region
+			}
+			result = append(result, apiResource{
+				Type:    r.Type,
+				ID:      r.ID,
+				Region:  regionName,
+				Tags:    r.Tags,
+				Created: r.Created,
+			})
+		}
+	}
+
+	w.forEachRegion(func(reg *region, resTypes []resource.TerraformResourceType) {
+		w.loadLeases(reg)
+
+		for _, resType := range resTypes {
+			deletableResources, _, filteredRes := w.selectResources(reg, resType)
+			if all {
+				add(reg, deletableResources)
+				continue
+			}
+			for _, res := range filteredRes {
+				add(reg, w.skipLeased(reg, w.dedup(reg, res)))
+			}
+		}
+	})
+
+	if w.fatalErr != nil {
+		return nil, w.fatalErr
+	}
+
+	// regions are swept in parallel, so that the resources are sorted to be returned in the same order every time
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		return w.sortOrder.Less(&resource.Resource{Type: a.Type, ID: a.ID, Created: a.Created}, a.Region,
+			&resource.Resource{Type: b.Type, ID: b.ID, Created: b.Created}, b.Region)
+	})
+	return result, nil
+}
+
+// apply deletes the resources selected by the config (without asking for confirmation).
+func (c *Serve) apply() (interface{}, error) {
+	var out bytes.Buffer
+
+	w, err := c.newWipe()
+	if err != nil {
+		return nil, err
+	}
+	w.filter = c.filter
+	w.out = &out
+	w.UI = &cli.BasicUi{Writer: &out, ErrorWriter: &out}
+	w.dryRunFlag = aws.Bool(false)
+	w.forceDelete = true
+
+	status := w.run("")
+	return applyResult{ExitStatus: status, Output: out.String()}, nil
+}
+
+// Help returns help information of this command
+func (c *Serve) Help() string {
+	return help()
+}
+
+// Synopsis returns a short version of the help information of this command
+func (c *Serve) Synopsis() string {
+	return "Serve listing and deleting the resources of a yaml configuration over an HTTP API"
+}
@@ -0,0 +1,327 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// reviewItem is a resource listed in the review of --tui, which is deleted if it is checked.
+type reviewItem struct {
+	region  string
+	r       *resource.Resource
+	checked bool
+}
+
+// line returns the text of the item shown in the table (which the filter is matched against).
+func (i *reviewItem) line() string {
+	var tags []string
+	for k, v := range i.r.Tags {
+		tags = append(tags, k+"="+v)
+	}
+	sort.Strings(tags)
+
+	return fmt.Sprintf("%-20s %-40s %-14s %s", i.r.Type, i.r.ID, i.region, strings.Join(tags, ","))
+}
+
+// review is the state of the interactive review of the resources selected for deletion.
+type review struct {
+	items []*reviewItem
+	// query is the text the shown items have to contain (case-insensitive)
+	query     string
+	filtering bool
+	// cursor is the position of the highlighted item among the shown ones
+	cursor int
+	// offset is the position of the first shown item that fits on the screen
+	offset    int
+	done      bool
+	cancelled bool
+}
+
+// These are the keys the review reacts to, besides printable characters.
+const (
+	keyUp        = "\x1b[A"
+	keyDown      = "\x1b[B"
+	keyPageUp    = "\x1b[5~"
+	keyPageDown  = "\x1b[6~"
+	keyEnter     = "\r"
+	keyEscape    = "\x1b"
+	keyBackspace = "\x7f"
+	keyCtrlC     = "\x03"
+)
+
+// reviewPageSize is the number of items the cursor moves by a page.
+const reviewPageSize = 10
+
+// newReview returns the review of the given items, which are all checked initially.
+func newReview(items []*reviewItem) *review {
+	for _, i := range items {
+		i.checked = true
+	}
+	return &review{items: items}
+}
+
+// shown returns the items that match the query.
+func (v *review) shown() []*reviewItem {
+	if v.query == "" {
+		return v.items
+	}
+
+	var result []*reviewItem
+	query := strings.ToLower(v.query)
+	for _, i := range v.items {
+		if strings.Contains(strings.ToLower(i.line()), query) {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// numChecked returns the number of checked items (of all items, not only the shown ones).
+func (v *review) numChecked() int {
+	n := 0
+	for _, i := range v.items {
+		if i.checked {
+			n++
+		}
+	}
+	return n
+}
+
+// handleKey updates the review according to a key pressed by the user.
+func (v *review) handleKey(key string) {
+	if v.filtering {
+		switch key {
+		case keyEnter:
+			v.filtering = false
+		case keyEscape:
+			v.filtering = false
+			v.query = ""
+		case keyBackspace, "\b":
+			if v.query != "" {
+				v.query = v.query[:len(v.query)-1]
+			}
+		default:
+			if len(key) == 1 && key[0] >= ' ' && key[0] <= '~' {
+				v.query += key
+			}
+		}
+		v.cursor = 0
+		return
+	}
+
+	shown := v.shown()
+	switch key {
+	case keyUp, "k":
+		v.cursor--
+	case keyDown, "j":
+		v.cursor++
+	case keyPageUp:
+		v.cursor -= reviewPageSize
+	case keyPageDown:
+		v.cursor += reviewPageSize
+	case " ":
+		if v.cursor < len(shown) {
+			shown[v.cursor].checked = !shown[v.cursor].checked
+		}
+	case "a":
+		// checks all shown items, unless all of them are checked already
+		allChecked := true
+		for _, i := range shown {
+			allChecked = allChecked && i.checked
+		}
+		for _, i := range shown {
+			i.checked = !allChecked
+		}
+	case "/":
+		v.filtering = true
+	case keyEnter:
+		v.done = true
+	case "q", keyEscape, keyCtrlC:
+		v.cancelled = true
+	}
+
+	if v.cursor >= len(shown) {
+		v.cursor = len(shown) - 1
+	}
+	if v.cursor < 0 {
+		v.cursor = 0
+	}
+}
+
+// render draws the review on a screen of the given size.
+func (v *review) render(w io.Writer, width, height int) {
+	shown := v.shown()
+
+	// the header, column names and the help line take four rows
+	rows := height - 4
+	if rows < 1 {
+		rows = 1
+	}
+	if v.cursor < v.offset {
+		v.offset = v.cursor
+	}
+	if v.cursor >= v.offset+rows {
+		v.offset = v.cursor - rows + 1
+	}
+
+	status := fmt.Sprintf("Resources to delete: %d of %d checked", v.numChecked(), len(v.items))
+	if v.query != "" || v.filtering {
+		status += fmt.Sprintf(" (%d shown, filter: %s)", len(shown), v.query)
+	}
+
+	lines := []string{status, "", "      " + fmt.Sprintf("%-20s %-40s %-14s %s", "TYPE", "ID", "REGION", "TAGS")}
+	for i := v.offset; i < len(shown) && i < v.offset+rows; i++ {
+		cursor, checkbox := "  ", "[ ]"
+		if i == v.cursor {
+			cursor = "> "
+		}
+		if shown[i].checked {
+			checkbox = "[x]"
+		}
+		lines = append(lines, cursor+checkbox+" "+shown[i].line())
+	}
+
+	help := "up/down: move  space: check  a: check all shown  /: filter  enter: delete checked  q: cancel"
+	if v.filtering {
+		help = "Filter: " + v.query + "_  (enter: apply, esc: clear)"
+	}
+
+	// clears the screen and writes the lines truncated to the width (in raw mode, lines end with \r\n)
+	fmt.Fprint(w, "\x1b[H\x1b[2J")
+	for _, l := range lines {
+		fmt.Fprint(w, truncate(l, width)+"\r\n")
+	}
+	for i := len(lines); i < height-1; i++ {
+		fmt.Fprint(w, "\r\n")
+	}
+	fmt.Fprint(w, truncate(help, width))
+}
+
+// truncate shortens a line to the given width.
+func truncate(s string, width int) string {
+	if width > 0 && len(s) > width {
+		return s[:width]
+	}
+	return s
+}
+
+// run shows the review in the terminal until the user confirms or cancels it.
+func (v *review) run(in *os.File, out *os.File) error {
+	inFd, outFd := int(in.Fd()), int(out.Fd())
+	if !terminal.IsTerminal(inFd) || !terminal.IsTerminal(outFd) {
+		return errors.New("--tui requires an interactive terminal")
+	}
+
+	state, err := terminal.MakeRaw(inFd)
+	if err != nil {
+		return errors.Wrap(err, "failed to put the terminal into raw mode")
+	}
+	defer terminal.Restore(inFd, state)
+
+	// the review is shown on the alternate screen (without cursor), so the previous output is kept
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	buf := make([]byte, 16)
+	for !v.done && !v.cancelled {
+		width, height, err := terminal.GetSize(outFd)
+		if err != nil {
+			width, height = 80, 24
+		}
+		v.render(out, width, height)
+
+		n, err := in.Read(buf)
+		if err != nil {
+			return errors.Wrap(err, "failed to read from the terminal")
+		}
+		v.handleKey(string(buf[:n]))
+	}
+	return nil
+}
+
+// review lists the resources selected for deletion and lets the user check the ones to delete in a terminal UI.
+// The checked resources are the only ones deleted by the run. It returns false if the review has been cancelled.
+func (c *Wipe) review() (bool, error) {
+	// the output of listing the resources is shown once they are deleted
+	out := c.out
+	c.out = ioutil.Discard
+	defer func() { c.out = out }()
+
+	var mu sync.Mutex
+	var items []*reviewItem
+	c.forEachRegion(func(reg *region, resTypes []resource.TerraformResourceType) {
+		c.loadLeases(reg)
+
+		for _, resType := range resTypes {
+			_, _, filteredRes := c.selectResources(reg, resType)
+			for _, res := range filteredRes {
+				res = c.skipDeleted(reg, c.skipLeased(reg, c.dedup(reg, res)))
+
+				mu.Lock()
+				for _, r := range res {
+					// resources of filter entries with dry_run are never deleted
+					if !r.DryRun {
+						items = append(items, &reviewItem{region: reg.name, r: r})
+					}
+				}
+				mu.Unlock()
+			}
+		}
+	})
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].region != items[j].region {
+			return items[i].region < items[j].region
+		}
+		if items[i].r.Type != items[j].r.Type {
+			return items[i].r.Type < items[j].r.Type
+		}
+		return items[i].r.ID < items[j].r.ID
+	})
+
+	v := newReview(items)
+	if len(items) > 0 {
+		if err := v.run(os.Stdin, os.Stdout); err != nil {
+			return false, err
+		}
+	}
+	if v.cancelled {
+		return false, nil
+	}
+
+	c.approved = resource.NewDeleteCache()
+	for _, i := range items {
+		if i.checked {
+			c.approved.Add(i.region, i.r)
+		}
+	}
+	c.UI.Output(fmt.Sprintf("INFO: %d of %d selected resources have been checked for deletion.", v.numChecked(),
+		len(items)))
+	return true, nil
+}
+
+// skipUnapproved removes the resources that haven't been checked in the review of --tui from the ones to delete.
+func (c *Wipe) skipUnapproved(reg *region, res resource.Resources) resource.Resources {
+	if c.approved == nil {
+		return res
+	}
+
+	var result resource.Resources
+	for _, r := range res {
+		if !c.approved.Contains(reg.name, r) {
+			fmt.Fprintf(reg.out, "INFO: Skipping unchecked resource %s (%s)\n", r.ID, r.Type)
+			continue
+		}
+		result = append(result, r)
+	}
+	return result
+}
@@ -2,33 +2,125 @@ package command
 
 import (
 	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
-
-	"log"
+	"sync/atomic"
+	"time"
 
 	"github.com/cloudetc/awsweeper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/mitchellh/cli"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
+// credentialsExpiryMargin is how long before the credentials expire no more resources are deleted, so that
+// pending deletions can complete and the run can be resumed with new credentials.
+const credentialsExpiryMargin = 5 * time.Minute
+
+var (
+	// eventClient is used to post events about deleted resources to a webhook.
+	eventClient = &http.Client{Timeout: 10 * time.Second}
+	// terraformCloudClient is used to look up the resources managed by Terraform Cloud/Enterprise workspaces.
+	terraformCloudClient = &http.Client{Timeout: time.Minute}
+)
+
 // Wipe is currently the only command.
 //
 // It deletes selected AWS resources by
 // a given filter (yaml configuration file).
 type Wipe struct {
-	UI          cli.Ui
+	UI cli.Ui
+	// dryRunFlag is the dry-run mode requested on the command line (nil if not given),
+	// which takes precedence over the one of the config
+	dryRunFlag  *bool
 	dryRun      bool
 	forceDelete bool
-	client      *resource.AWS
-	provider    *terraform.ResourceProvider
-	filter      *resource.Filter
+	compliance  bool
+	// sample selects a random subset of the resources to delete (nil to delete all of them)
+	sample *resource.Sample
+	// faults simulates deleting resources instead of deleting them (nil to delete them)
+	faults  *resource.FaultInjection
+	regions []*region
+	filter  *resource.Filter
+	// profileName is the profile of the config to sweep (empty to only sweep the types outside of profiles)
+	profileName string
+	// newProvider initializes a provider for regions resources have to be deleted in,
+	// but which are not swept themselves (and for swept regions, if clients are configured)
+	newProvider func(region string, clients *resource.ClientsConfig) (*terraform.ResourceProvider, error)
+	// clients creates the clients of the regions from their sessions (nil for replayed runs)
+	clients resource.ClientProvider
+
+	// runTime is the time the run has been started at
+	runTime time.Time
+	// advisorFindings are the resources flagged by the Trusted Advisor checks of the config
+	advisorFindings resource.AdvisorFindings
+	// managed are the resources managed by the Terraform Cloud/Enterprise workspaces of the config
+	managed *resource.ManagedResources
+	// resumeFile is where the deleted resources are kept across runs (empty to only keep them during the run)
+	resumeFile string
+	// deleted are the resources that have been deleted or don't exist anymore
+	deleted *resource.DeleteCache
+	// out is where the output of sweeping regions is written to (os.Stdout if nil)
+	out io.Writer
+	// failures are the number of resources that failed to be deleted per class of error
+	failures map[resource.ErrorClass]int
+	// fatalErr is the first error a sweep of a region has been aborted with (see recoverFatal)
+	fatalErr error
+	// numDeleted are the number of resources that have been deleted during the run (or didn't exist anymore) per type
+	numDeleted map[resource.TerraformResourceType]int
+	// deniedActions are the IAM actions deletions failed with an access denied error for
+	deniedActions []string
+	// credentialsExpiry is when the credentials expire, if they can't be refreshed (zero otherwise)
+	credentialsExpiry time.Time
+	// expired is set once deletions have been stopped, because the credentials are about to expire
+	expired    int32
+	expireOnce sync.Once
+	// lock is the run lock held while deleting resources (nil if no lock is configured)
+	lock         *resource.RunLock
+	lockLostOnce sync.Once
+	// hookFailed is set once a hook has failed
+	hookFailed int32
+	// planned are the numbers of resources to delete per type and region
+	planned map[string]map[resource.TerraformResourceType]int
+	// ownerReport is the file the resources to delete that miss required tags are reported to,
+	// grouped by their inferred owner (empty for no report)
+	ownerReport string
+	ownerships  []resource.Ownership
+	// baseline are the resources expected to remain after the sweep (nil if not configured)
+	baseline *resource.Baseline
+	// selected are the resources a test run would delete
+	selected *resource.DeleteCache
+	// spillDir is where the resources of spillable types are spilled to while sweeping them in chunks
+	// (empty to keep all listed resources in memory)
+	spillDir string
+	// wait is set to wait until resources that are deleted asynchronously (e.g., state machines) are gone
+	wait bool
+	// allowHighSeverity is set to allow deleting resources of types with high severity (e.g., IAM roles)
+	allowHighSeverity bool
+	// sortOrder is the order of the resources in the output (nil for the default order)
+	sortOrder resource.SortOrder
+	// tui is set to review the resources selected for deletion in a terminal UI before deleting them
+	tui bool
+	// approved are the resources checked in the review of --tui (nil to delete all selected resources)
+	approved *resource.DeleteCache
+	// tracer records the spans of the run (nil if tracing is not configured)
+	tracer *resource.Tracer
+
+	mu            sync.Mutex
+	providers     map[string]*terraform.ResourceProvider
+	regionClients map[string]*resource.AWS
+	seen          map[string]string
 }
 
 // Run executes the wipe command.
 func (c *Wipe) Run(args []string) int {
 	if len(args) == 1 {
-		c.filter = resource.NewFilter(args[0])
+		c.filter = resource.NewProfileFilter(args[0], c.profileName)
 
 		err := c.filter.Validate()
 		if err != nil {
@@ -39,64 +131,1017 @@ func (c *Wipe) Run(args []string) int {
 		return 1
 	}
 
+	return c.run("Do you really want to delete resources filtered by '" + args[0] + "'?\n")
+}
+
+// run asks for approval (if required) and deletes all resources selected by the filter of the command.
+// Nothing is deleted unless deletion has been requested explicitly, via --no-dry-run or the config.
+func (c *Wipe) run(approvalQuestion string) int {
+	if c.compliance {
+		return c.checkCompliance()
+	}
+
+	if err := c.configureClients(); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if c.ownerReport != "" && len(c.filter.RequiredTags) == 0 {
+		c.UI.Error("The owner report requires the cost allocation tags to be listed under required_tags in the config.")
+		return 1
+	}
+
+	c.dryRun = c.isDryRun()
+	c.runTime = time.Now()
+
+	span := c.tracer.Start(nil, "sweep", "awsweeper.dry_run", strconv.FormatBool(c.dryRun))
+	defer c.exportTrace(span)
+
+	// deletions are simulated when failures are injected
+	if c.faults != nil {
+		c.dryRun = false
+	}
+
+	if !c.dryRun && c.faults == nil && !c.filter.DeletionAllowed(c.runTime) {
+		c.UI.Error("Refusing to delete resources outside of the allowed windows of the config. " +
+			"Run with --dry-run to see what would be deleted.")
+		return 1
+	}
+
+	if !c.dryRun && c.faults == nil && !c.allowHighSeverity {
+		if resTypes := c.filter.HighSeverityTypes(); len(resTypes) > 0 {
+			c.UI.Error(fmt.Sprintf("Refusing to delete resources of types with high severity (%s) without "+
+				"--allow-high-severity. Run with --dry-run to see what would be deleted.", joinTypes(resTypes)))
+			return 1
+		}
+	}
+
 	if c.dryRun {
-		c.UI.Output("INFO: This is a test run, nothing will be deleted!")
-	} else if !c.forceDelete {
-		v, err := c.UI.Ask(
-			"Do you really want to delete resources filtered by '" + args[0] + "'?\n" +
-				"Only 'yes' will be accepted to approve.\n\n" +
-				"Enter a value: ")
+		c.UI.Output("INFO: This is a test run, nothing will be deleted! Use --no-dry-run to delete resources.")
+	} else if c.faults != nil {
+		c.UI.Output(fmt.Sprintf("INFO: Deletions are simulated and %g%% of them fail, nothing will be deleted!",
+			c.faults.Rate*100))
+	} else if !c.forceDelete && !c.tui {
+		approved, err := c.confirm(approvalQuestion)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error asking for approval: %s", err))
+			return 1
+		}
+		if !approved {
+			c.UI.Output("Deletion cancelled.")
+			return 0
+		}
+	}
+
+	c.warnUnsweptRegions()
+
+	if !c.credentialsExpiry.IsZero() {
+		c.UI.Output(fmt.Sprintf("INFO: The credentials expire at %s, no resources are deleted after %s.",
+			c.credentialsExpiry.Format(time.RFC3339),
+			c.credentialsExpiry.Add(-credentialsExpiryMargin).Format(time.RFC3339)))
+		if c.resumeFile == "" && !c.dryRun {
+			c.UI.Output("WARN: Use --resume <file> to be able to resume the run with new credentials.")
+		}
+	}
+
+	if !c.dryRun && c.faults == nil && c.filter.Lock != nil {
+		lock, err := c.acquireLock()
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		c.lock = lock
+		defer func() {
+			if err := lock.Release(); err != nil {
+				c.UI.Error(err.Error())
+			}
+		}()
+	}
+
+	c.deleted = resource.NewDeleteCache()
+	if c.resumeFile != "" {
+		deleted, err := resource.LoadDeleteCache(c.resumeFile)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		c.deleted = deleted
+
+		defer func() {
+			if err := c.deleted.Save(c.resumeFile); err != nil {
+				c.UI.Error(err.Error())
+			}
+		}()
+	}
 
+	if err := c.lookupSeeds(); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	// with --tui, the deletion is confirmed once the resources to delete have been reviewed
+	if c.tui && !c.dryRun && c.faults == nil {
+		approved, err := c.review()
 		if err != nil {
-			fmt.Println("Error asking for approval: {{err}}", err)
+			c.UI.Error(fmt.Sprintf("Error reviewing resources: %s", err))
+			return 1
+		}
+		if c.fatalErr != nil {
 			return 1
 		}
-		if v != "yes" {
+		if approved && !c.forceDelete {
+			if approved, err = c.confirm(approvalQuestion); err != nil {
+				c.UI.Error(fmt.Sprintf("Error asking for approval: %s", err))
+				return 1
+			}
+		}
+		if !approved {
+			c.UI.Output("Deletion cancelled.")
 			return 0
 		}
 	}
 
-	for _, resType := range c.filter.Types() {
-		rawResources, err := c.client.RawResources(resType)
+	if !c.runHooks(nil, resource.HookBefore, "", nil) {
+		return 1
+	}
+
+	c.forEachRegion(c.sweep)
+	if c.fatalErr != nil {
+		return 1
+	}
+
+	c.runHooks(nil, resource.HookAfter, "", nil)
+
+	var drift int64
+	if c.baseline != nil {
+		drift = c.checkDrift()
+	}
+
+	if c.dryRun {
+		c.reportEstimate()
+		c.reportSeverities()
+	}
+	if c.ownerReport != "" {
+		c.writeOwnerReport()
+	}
+	if c.filter.Audit != nil && !c.dryRun && c.faults == nil {
+		c.writeRunResult()
+	}
+
+	status := c.reportFailures()
+	if drift > 0 || atomic.LoadInt32(&c.hookFailed) == 1 {
+		status = 1
+	}
+	if atomic.LoadInt32(&c.expired) == 1 || c.lock.Lost() {
+		return 1
+	}
+	return status
+}
+
+// exportTrace ends the root span of the run and exports all spans, if tracing is configured.
+func (c *Wipe) exportTrace(span *resource.Span) {
+	span.End(nil)
+	if err := c.tracer.Export(); err != nil {
+		c.UI.Error(err.Error())
+	}
+}
+
+// warnUnsweptRegions warns about filter entries restricted to regions that are not swept,
+// which would select nothing in these regions.
+func (c *Wipe) warnUnsweptRegions() {
+	var swept []string
+	for _, reg := range c.regions {
+		swept = append(swept, reg.name)
+	}
+
+	unswept := c.filter.UnsweptRegions(swept)
+	var resTypes []string
+	for resType := range unswept {
+		resTypes = append(resTypes, string(resType))
+	}
+	sort.Strings(resTypes)
+
+	for _, resType := range resTypes {
+		c.UI.Output(fmt.Sprintf("WARN: The filter of %s selects resources in %s, which are not swept "+
+			"(add them via --region or use --all-regions)", resType,
+			strings.Join(unswept[resource.TerraformResourceType(resType)], ", ")))
+	}
+}
+
+// reportEstimate prints how long deleting the resources selected by a test run would take approximately
+// and how many API calls it would issue.
+func (c *Wipe) reportEstimate() {
+	var estimates []resource.Estimate
+	for _, counts := range c.planned {
+		estimates = append(estimates, resource.EstimateDeletion(counts))
+	}
+
+	e := resource.CombineEstimates(estimates)
+	if e.Resources == 0 {
+		return
+	}
+	c.UI.Output(fmt.Sprintf("INFO: Deleting the %d selected resources takes about %s and issues about %d API calls "+
+		"(estimated from typical deletion times and API rate limits).", e.Resources, formatDuration(e.Duration),
+		e.APICalls))
+}
+
+// reportSeverities prints how many of the resources selected by a test run are of types with low, medium
+// and high severity.
+func (c *Wipe) reportSeverities() {
+	counts := map[resource.Severity]map[resource.TerraformResourceType]int{}
+	for _, planned := range c.planned {
+		for resType, n := range planned {
+			s := c.filter.Severity(resType)
+			if counts[s] == nil {
+				counts[s] = map[resource.TerraformResourceType]int{}
+			}
+			counts[s][resType] += n
+		}
+	}
+
+	var summary []string
+	for _, s := range []resource.Severity{resource.SeverityHigh, resource.SeverityMedium, resource.SeverityLow} {
+		if len(counts[s]) == 0 {
+			continue
+		}
+		total := 0
+		var resTypes []resource.TerraformResourceType
+		for resType, n := range counts[s] {
+			total += n
+			resTypes = append(resTypes, resType)
+		}
+		sort.Slice(resTypes, func(i, j int) bool { return resTypes[i] < resTypes[j] })
+		summary = append(summary, fmt.Sprintf("%s: %d (%s)", s, total, joinTypes(resTypes)))
+	}
+	if len(summary) == 0 {
+		return
+	}
+
+	c.UI.Output(fmt.Sprintf("INFO: Selected resources by severity: %s.", strings.Join(summary, ", ")))
+	if len(counts[resource.SeverityHigh]) > 0 && !c.allowHighSeverity {
+		c.UI.Output("WARN: Deleting resources of types with high severity requires --allow-high-severity.")
+	}
+}
+
+// joinTypes returns a comma-separated list of resource types.
+func joinTypes(resTypes []resource.TerraformResourceType) string {
+	var names []string
+	for _, resType := range resTypes {
+		names = append(names, string(resType))
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatDuration rounds a duration to minutes (or seconds, if shorter than a minute) for output.
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+}
+
+// writeOwnerReport writes the resources selected for deletion that miss required tags to the owner report.
+func (c *Wipe) writeOwnerReport() {
+	if err := resource.WriteOwnerReport(c.ownerReport, c.ownerships); err != nil {
+		c.UI.Error(err.Error())
+		return
+	}
+	c.UI.Output(fmt.Sprintf("INFO: Reported %d resources missing required tags %v to %s.", len(c.ownerships),
+		c.filter.RequiredTags, c.ownerReport))
+}
+
+// writeRunResult stores the result of the run in the audit bucket, so that it is part of later reports.
+func (c *Wipe) writeRunResult() {
+	accountID, err := c.regions[0].client.AccountID()
+	if err != nil {
+		c.UI.Error(errors.Wrap(err, "failed to get the account ID of the used credentials").Error())
+		return
+	}
+
+	result := resource.RunResult{
+		Account:  accountID,
+		Start:    c.runTime,
+		End:      time.Now(),
+		Deleted:  c.numDeleted,
+		Failures: c.failures,
+	}
+	for _, reg := range c.regions {
+		result.Regions = append(result.Regions, reg.name)
+	}
+
+	if err := c.regions[0].client.WriteRunResult(c.filter.Audit, result); err != nil {
+		c.UI.Error(err.Error())
+	}
+}
+
+// inferOwners keeps track of the owners of resources (of the same type) in a region that miss required tags,
+// if an owner report has been requested.
+func (c *Wipe) inferOwners(reg *region, res resource.Resources) {
+	if c.ownerReport == "" {
+		return
+	}
+
+	for _, r := range res {
+		missing := c.filter.MissingTags(r)
+		if len(missing) == 0 {
+			continue
+		}
+		owner, source := reg.client.InferOwner(r)
+
+		c.mu.Lock()
+		c.ownerships = append(c.ownerships, resource.Ownership{
+			Resource:    r,
+			Region:      reg.name,
+			Owner:       owner,
+			Source:      source,
+			MissingTags: missing,
+		})
+		c.mu.Unlock()
+	}
+}
+
+// plan keeps track of the number of resources (of the same type) to delete in a region.
+func (c *Wipe) plan(reg *region, res resource.Resources) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.planned == nil {
+		c.planned = map[string]map[resource.TerraformResourceType]int{}
+	}
+	if c.planned[reg.name] == nil {
+		c.planned[reg.name] = map[resource.TerraformResourceType]int{}
+	}
+	c.planned[reg.name][res[0].Type] += len(res)
+
+	if c.dryRun && c.selected != nil {
+		for _, r := range res {
+			c.selected.Add(reg.name, r)
+		}
+	}
+}
+
+// checkDrift lists the resources of the swept types again and reports the remaining ones that are not part
+// of the baseline (in a test run, the ones that would remain). It returns the number of drifted resources.
+func (c *Wipe) checkDrift() int64 {
+	var numDrifted int64
+
+	c.forEachRegion(func(reg *region, resTypes []resource.TerraformResourceType) {
+		for _, resType := range resTypes {
+			rawResources, err := reg.client.RawResources(resType)
+			if err != nil {
+				fmt.Fprintf(reg.out, "WARN: Failed to list %s resources to check for drift: %s\n", resType, err)
+				continue
+			}
+
+			deletableResources, err := resource.DeletableResources(resType, rawResources)
+			if err != nil {
+				resource.Fatal(err)
+			}
+			if err := reg.client.MarkDefaults(resType, deletableResources, rawResources); err != nil {
+				resource.Fatal(err)
+			}
+
+			var remaining resource.Resources
+			for _, r := range deletableResources {
+				if c.deleted.Contains(reg.name, r) || c.selected.Contains(reg.name, r) {
+					continue
+				}
+				remaining = append(remaining, r)
+			}
+
+			drifted := c.baseline.Drift(remaining)
+			if len(drifted) == 0 {
+				continue
+			}
+
+			fmt.Fprintf(reg.out, "\n---\nType: %s\nNot in baseline: %d\n\n", resType, len(drifted))
+			for _, r := range drifted {
+				fmt.Fprintln(reg.out, formatResource(r))
+			}
+			fmt.Fprint(reg.out, "---\n\n")
+
+			atomic.AddInt64(&numDrifted, int64(len(drifted)))
+		}
+	})
+
+	if numDrifted > 0 {
+		c.UI.Error(fmt.Sprintf("%d remaining resources are not part of the baseline.", numDrifted))
+	} else {
+		c.UI.Output("INFO: All remaining resources are part of the baseline.")
+	}
+	return numDrifted
+}
+
+// stopped checks whether no more resources are to be deleted, because the credentials are about to expire
+// or the run lock has been lost.
+func (c *Wipe) stopped() bool {
+	return c.credentialsExpiring() || c.lockLost()
+}
+
+// lockLost checks whether the run lock failed to be renewed, so that another run might delete resources
+// at the same time.
+func (c *Wipe) lockLost() bool {
+	if !c.lock.Lost() {
+		return false
+	}
+
+	c.lockLostOnce.Do(func() {
+		c.UI.Error("Stopping the run, as the run lock failed to be renewed.")
+	})
+	return true
+}
+
+// credentialsExpiring checks whether the credentials are about to expire, in which case no more resources
+// are to be deleted. The first time, the deleted resources are saved to the resume file (if any),
+// so that the run can be resumed with new credentials even if it is killed.
+func (c *Wipe) credentialsExpiring() bool {
+	if c.credentialsExpiry.IsZero() || time.Now().Add(credentialsExpiryMargin).Before(c.credentialsExpiry) {
+		return false
+	}
+
+	c.expireOnce.Do(func() {
+		atomic.StoreInt32(&c.expired, 1)
+		c.UI.Error(fmt.Sprintf("Stopping the run, as the credentials expire at %s.",
+			c.credentialsExpiry.Format(time.RFC3339)))
+		if c.resumeFile == "" {
+			return
+		}
+		if err := c.deleted.Save(c.resumeFile); err != nil {
+			c.UI.Error(err.Error())
+			return
+		}
+		c.UI.Output(fmt.Sprintf("Run again with new credentials and --resume %s to continue.", c.resumeFile))
+	})
+	return true
+}
+
+// reportFailures prints the number of resources that failed to be deleted per class of error.
+// It returns a non-zero exit status if any deletion failed.
+func (c *Wipe) reportFailures() int {
+	if len(c.failures) == 0 {
+		return 0
+	}
+
+	var total int
+	var classes []string
+	for class, n := range c.failures {
+		total += n
+		classes = append(classes, fmt.Sprintf("%s: %d", class, n))
+	}
+	sort.Strings(classes)
+
+	c.UI.Error(fmt.Sprintf("Failed to delete %d resources (%s)", total, strings.Join(classes, ", ")))
+
+	if len(c.deniedActions) > 0 {
+		policy, err := resource.DeniedActionsPolicy(c.deniedActions)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		c.UI.Output("The following IAM policy allows the actions that have been denied:\n" + string(policy))
+	}
+	return 1
+}
+
+// lookupSeeds looks up the resources that restrict which resources the filter is applied to
+// (i.e. the ones flagged by Trusted Advisor and the ones managed by Terraform workspaces), if configured.
+func (c *Wipe) lookupSeeds() error {
+	if len(c.filter.TrustedAdvisor) > 0 {
+		findings, err := c.regions[0].client.TrustedAdvisorFindings(c.filter.TrustedAdvisor)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
+		c.advisorFindings = findings
+	}
 
-		deletableResources, err := resource.DeletableResources(resType, rawResources)
+	if cfg := c.filter.TerraformCloud; cfg != nil {
+		managed, err := cfg.ManagedResources(terraformCloudClient)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
+		c.managed = managed
+	}
 
-		filteredRes := c.filter.Apply(resType, deletableResources, rawResources, c.client)
-		for _, res := range filteredRes {
-			c.wipe(res)
+	if cfg := c.filter.Baseline; cfg != nil {
+		baseline, err := cfg.LoadBaseline()
+		if err != nil {
+			return err
 		}
+		c.baseline = baseline
+		c.selected = resource.NewDeleteCache()
 	}
+	return nil
+}
 
-	return 0
+// acquireLock acquires the run lock of the account, so that no other run deletes resources at the same time.
+func (c *Wipe) acquireLock() (*resource.RunLock, error) {
+	accountID, err := c.regions[0].client.AccountID()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the account ID of the used credentials")
+	}
+
+	cfg := c.filter.Lock
+	lock, err := c.regions[0].client.AcquireLock(cfg.DynamoDBTable, accountID, cfg.LockDuration())
+	if err == resource.ErrLocked {
+		return nil, errors.Errorf("refusing to delete resources: another run against account %s is in progress", accountID)
+	}
+	return lock, err
+}
+
+// sweep deletes (or tags) the resources of the given types selected by the filter in a particular region.
+func (c *Wipe) sweep(reg *region, resTypes []resource.TerraformResourceType) {
+	regionSpan := c.tracer.Start(nil, "region", "cloud.region", reg.name)
+	defer regionSpan.End(nil)
+
+	c.loadLeases(reg)
+
+	for _, resType := range resTypes {
+		if c.stopped() {
+			return
+		}
+		startSection(reg, resType)
+		c.sweepType(reg, regionSpan, resType)
+	}
+}
+
+// sweepType deletes (or tags) the resources of a type selected by the filter in a particular region.
+func (c *Wipe) sweepType(reg *region, regionSpan *resource.Span, resType resource.TerraformResourceType) {
+	reg.span = c.tracer.Start(regionSpan, string(resType), "cloud.region", reg.name, "resource.type", string(resType))
+	defer reg.span.End(nil)
+
+	if c.spillDir != "" && resource.Spillable(resType) {
+		c.sweepSpilled(reg, resType)
+		return
+	}
+
+	deletableResources, candidates, filteredRes := c.selectResources(reg, resType)
+	c.sweepSelected(reg, resType, deletableResources, candidates, filteredRes)
+}
+
+// sweepSpilled sweeps the resources of a type in large-account mode: they are listed page by page into a spill file,
+// which is then filtered and swept in chunks, so that only a chunk of the resources is kept in memory at a time.
+func (c *Wipe) sweepSpilled(reg *region, resType resource.TerraformResourceType) {
+	listSpan := c.tracer.Start(reg.span, "list", "resource.type", string(resType))
+	spill, err := reg.client.SpillRawResources(c.spillDir, resType, c.filter.APITagFilters(resType))
+	listSpan.End(err)
+	if err != nil {
+		resource.Fatal(err)
+	}
+	defer func() {
+		if err := spill.Close(); err != nil {
+			fmt.Fprintf(reg.out, "WARN: %s\n", err)
+		}
+	}()
+
+	if spill.Len() > resource.SpillChunkSize {
+		fmt.Fprintf(reg.out, "INFO: Sweeping %d %s resources in chunks of %d\n", spill.Len(), resType,
+			resource.SpillChunkSize)
+	}
+
+	err = spill.Chunks(resource.SpillChunkSize, func(raw interface{}) error {
+		if c.stopped() {
+			return nil
+		}
+		deletableResources, candidates, filteredRes := c.filterResources(reg, resType, raw)
+		c.sweepSelected(reg, resType, deletableResources, candidates, filteredRes)
+		return nil
+	})
+	if err != nil {
+		resource.Fatal(err)
+	}
+}
+
+// sweepSelected deletes (or tags) the resources of a type selected by the filter in a particular region.
+func (c *Wipe) sweepSelected(reg *region, resType resource.TerraformResourceType, deletableResources,
+	candidates resource.Resources, filteredRes []resource.Resources) {
+	c.tag(reg, c.filter.Taggings(candidates))
+	c.untag(reg, c.filter.Untaggings(candidates))
+	c.deactivate(reg, c.filter.Deactivations(candidates))
+	c.setRetention(reg, c.filter.Retentions(candidates))
+	c.exportInventory(reg, resType, deletableResources, filteredRes)
+
+	remaining := make([]resource.Resources, 0, len(filteredRes))
+	for _, res := range filteredRes {
+		remaining = append(remaining, c.skipDeleted(reg, c.skipUnapproved(reg, c.skipLeased(reg, c.dedup(reg, res)))))
+	}
+
+	var toDelete []resource.Resources
+	numToDelete := 0
+	// dependents are sampled together with the resources they are deleted along with
+	for _, res := range c.sample.ApplyGroups(remaining) {
+		res = c.reportDryRun(reg, res)
+		toDelete = append(toDelete, res)
+		numToDelete += len(res)
+	}
+	reg.span.SetAttribute("awsweeper.selected", strconv.Itoa(numToDelete))
+
+	if numToDelete > 0 && !c.runHooks(reg, resource.HookBefore, resType, toDelete) {
+		fmt.Fprintf(reg.out, "WARN: Skipping deletion of %s resources, as a hook failed\n", resType)
+		return
+	}
+	for _, res := range toDelete {
+		res = c.createFinalSnapshots(reg, res)
+		c.unshare(reg, res)
+		c.wipe(reg, res)
+	}
+	if numToDelete > 0 {
+		c.runHooks(reg, resource.HookAfter, resType, toDelete)
+	}
+}
+
+// reportDryRun prints the resources selected by filter entries with dry_run, which are not deleted
+// (even if the run isn't a test run), and returns the other ones.
+func (c *Wipe) reportDryRun(reg *region, res resource.Resources) resource.Resources {
+	if c.dryRun {
+		return res
+	}
+
+	var result, dryRun resource.Resources
+	for _, r := range res {
+		if r.DryRun {
+			dryRun = append(dryRun, r)
+		} else {
+			result = append(result, r)
+		}
+	}
+
+	if len(dryRun) > 0 {
+		fmt.Fprintf(reg.out, "\n---\nType: %s\nFound: %d (dry run of the filter entry, nothing is deleted)\n\n",
+			dryRun[0].Type, len(dryRun))
+		for _, r := range dryRun {
+			fmt.Fprintln(reg.out, formatResource(r))
+		}
+		fmt.Fprint(reg.out, "---\n\n")
+	}
+	return result
+}
+
+// runHooks runs the hooks of a phase for the resources of a type in a region (or for the whole sweep,
+// if reg is nil) and reports whether all of them succeeded. Hooks don't run in test runs
+// or when deletions are simulated.
+func (c *Wipe) runHooks(reg *region, phase string, resType resource.TerraformResourceType,
+	res []resource.Resources) bool {
+	hooks := c.filter.Hooks.Hooks(phase, resType)
+	if len(hooks) == 0 {
+		return true
+	}
+
+	out := c.output()
+	client := c.regions[0].client
+	e := resource.HookEvent{Phase: phase, Type: resType}
+	if reg != nil {
+		out = reg.out
+		client = reg.client
+		e.Region = reg.name
+	}
+	for _, rs := range res {
+		for _, r := range rs {
+			e.IDs = append(e.IDs, r.ID)
+		}
+	}
+
+	for _, h := range hooks {
+		if c.dryRun || c.faults != nil {
+			fmt.Fprintf(out, "INFO: Skipping %s hook in test run: %s\n", phase, h)
+			continue
+		}
+
+		fmt.Fprintf(out, "INFO: Running %s hook: %s\n", phase, h)
+		if err := h.Run(client, e, out); err != nil {
+			fmt.Fprintf(out, "ERROR: %s\n", err)
+			atomic.StoreInt32(&c.hookFailed, 1)
+			return false
+		}
+	}
+	return true
+}
+
+// loadLeases looks up the leases stored in a region, if configured.
+func (c *Wipe) loadLeases(reg *region) {
+	if cfg := c.filter.Leases; cfg != nil {
+		leases, err := reg.client.SSMLeases(cfg.SSMPrefix)
+		if err != nil {
+			resource.Fatal(err)
+		}
+		reg.leases = leases
+	}
+}
+
+// selectResources lists the resources of a type in a region and applies the filter to them. It returns
+// all listed resources, the ones the filter has been applied to and the selected ones.
+func (c *Wipe) selectResources(reg *region, resType resource.TerraformResourceType) (resource.Resources,
+	resource.Resources, []resource.Resources) {
+	listSpan := c.tracer.Start(reg.span, "list", "resource.type", string(resType))
+	// resources of EC2 types are only listed if they could match the tags of the filter
+	rawResources, err := reg.client.RawResourcesWithTags(resType, c.filter.APITagFilters(resType))
+	listSpan.End(err)
+	if err != nil {
+		resource.Fatal(err)
+	}
+
+	return c.filterResources(reg, resType, rawResources)
+}
+
+// filterResources applies the filter to the listed resources of a type in a region like selectResources.
+func (c *Wipe) filterResources(reg *region, resType resource.TerraformResourceType,
+	rawResources interface{}) (resource.Resources, resource.Resources, []resource.Resources) {
+	deletableResources, err := resource.DeletableResources(resType, rawResources)
+	if err != nil {
+		resource.Fatal(err)
+	}
+	c.fetchTags(reg, resType, deletableResources)
+
+	// the filter only applies to resources flagged by Trusted Advisor, if the type is covered by a check,
+	// and to resources (not) managed by Terraform workspaces
+	candidates := c.managed.Seed(c.advisorFindings.Seed(resType, reg.name, deletableResources))
+
+	// applying the filter first looks up the data (e.g., metrics) entries with other actions match on
+	filterSpan := c.tracer.Start(reg.span, "filter", "resource.type", string(resType),
+		"awsweeper.listed", strconv.Itoa(len(deletableResources)))
+	filteredRes := c.filter.Apply(resType, candidates, rawResources, reg.client)
+	filterSpan.End(nil)
+
+	return deletableResources, candidates, filteredRes
+}
+
+// fetchTags looks up the tags of resources not returned by their list API, if the filter needs them.
+func (c *Wipe) fetchTags(reg *region, resType resource.TerraformResourceType, res resource.Resources) {
+	if !c.filter.UsesTags(resType) {
+		return
+	}
+
+	if err := reg.client.FetchTags(res); err != nil {
+		fmt.Fprintf(reg.out, "WARN: Failed to fetch tags of %s resources, skipping the ones without tags: %s\n", resType, err)
+	}
+}
+
+// skipLeased removes the resources that are protected by a lease from the ones to delete.
+func (c *Wipe) skipLeased(reg *region, res resource.Resources) resource.Resources {
+	var result resource.Resources
+
+	for _, r := range res {
+		if resource.Leased(r, reg.leases, c.runTime) {
+			fmt.Fprintf(reg.out, "INFO: Skipping leased resource %s (%s)\n", r.ID, r.Type)
+			continue
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// skipDeleted removes the resources that have already been deleted (e.g., by the run that is resumed).
+func (c *Wipe) skipDeleted(reg *region, res resource.Resources) resource.Resources {
+	var result resource.Resources
+
+	for _, r := range res {
+		if c.deleted != nil && c.deleted.Contains(reg.name, r) {
+			fmt.Fprintf(reg.out, "INFO: Skipping already deleted resource %s (%s)\n", r.ID, r.Type)
+			continue
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// exportInventory writes all discovered resources of a type (not only the ones selected by the filter)
+// into the inventory table, if configured.
+func (c *Wipe) exportInventory(reg *region, resType resource.TerraformResourceType,
+	res resource.Resources, filteredRes []resource.Resources) {
+	cfg := c.filter.Inventory
+	if cfg == nil || len(res) == 0 {
+		return
+	}
+
+	// some filters return copies of the selected resources (or additional ones of other types)
+	matched := map[string]bool{}
+	for _, fr := range filteredRes {
+		for _, r := range fr {
+			matched[string(r.Type)+"/"+r.ID] = true
+		}
+	}
+
+	items := make([]resource.InventoryItem, 0, len(res))
+	for _, r := range res {
+		regionName := reg.name
+		if r.Region != "" {
+			regionName = r.Region
+		}
+		items = append(items, resource.InventoryItem{
+			Resource: r,
+			Region:   regionName,
+			Matched:  matched[string(r.Type)+"/"+r.ID],
+		})
+	}
+
+	if err := reg.client.WriteInventory(cfg.DynamoDBTable, c.runTime, items); err != nil {
+		fmt.Fprintf(reg.out, "WARN: Failed to export inventory of %s resources: %s\n", resType, err)
+	}
+}
+
+// tag writes tags onto resources (of the same type) instead of deleting them.
+func (c *Wipe) tag(reg *region, taggings []resource.Tagging) {
+	if len(taggings) == 0 {
+		return
+	}
+
+	fmt.Fprintf(reg.out, "\n---\nType: %s\nTagging: %d\n\n", taggings[0].Resource.Type, len(taggings))
+
+	// resources that get the same tags are tagged together to reduce the number of API calls
+	batches := map[string]resource.Resources{}
+	batchTags := map[string]map[string]string{}
+
+	for _, t := range taggings {
+		fmt.Fprint(reg.out, formatResource(t.Resource))
+
+		tags, err := reg.client.ResolveTags(t.Resource, t.Tags)
+		if err != nil {
+			fmt.Fprintf(reg.out, "\t%s\n\n", err)
+			continue
+		}
+		fmt.Fprintf(reg.out, "\tApply tags:\t%v\n\n", tags)
+
+		key := fmt.Sprint(tags)
+		batches[key] = append(batches[key], t.Resource)
+		batchTags[key] = tags
+	}
+
+	if !c.dryRun && c.faults == nil {
+		for key, res := range batches {
+			if err := reg.client.Tag(res, batchTags[key]); err != nil {
+				fmt.Fprintf(reg.out, "\t%s\n", err)
+			}
+		}
+	}
+	fmt.Fprint(reg.out, "---\n\n")
+}
+
+// untag removes tags from resources instead of deleting them.
+func (c *Wipe) untag(reg *region, untaggings []resource.Untagging) {
+	if len(untaggings) == 0 {
+		return
+	}
+
+	fmt.Fprintf(reg.out, "\n---\nType: %s\nRemoving tags: %d\n\n", untaggings[0].Resource.Type, len(untaggings))
+
+	for _, u := range untaggings {
+		fmt.Fprint(reg.out, formatResource(u.Resource))
+		fmt.Fprintf(reg.out, "\tRemove tags:\t%v\n\n", u.Keys)
+	}
+
+	if !c.dryRun && c.faults == nil {
+		errs := reg.client.UntagAll(untaggings)
+		for _, u := range untaggings {
+			if err, found := errs[u.Resource.ID]; found {
+				fmt.Fprintf(reg.out, "\t%s\n", err)
+			}
+		}
+	}
+	fmt.Fprint(reg.out, "---\n\n")
+}
+
+// deactivate deactivates resources instead of deleting them.
+func (c *Wipe) deactivate(reg *region, res resource.Resources) {
+	if len(res) == 0 {
+		return
+	}
+
+	fmt.Fprintf(reg.out, "\n---\nType: %s\nDeactivating: %d\n\n", res[0].Type, len(res))
+
+	for _, r := range res {
+		fmt.Fprintln(reg.out, formatResource(r))
+
+		if !c.dryRun && c.faults == nil {
+			if err := reg.client.Deactivate(r); err != nil {
+				fmt.Fprintf(reg.out, "\t%s\n", err)
+			}
+		}
+	}
+	fmt.Fprint(reg.out, "---\n\n")
+}
+
+// createFinalSnapshots snapshots the resources selected by filter entries with final_snapshot and returns the
+// resources to delete, without the ones that failed to be snapshotted. Nothing is snapshotted in test runs
+// or when deletions are simulated.
+func (c *Wipe) createFinalSnapshots(reg *region, res resource.Resources) resource.Resources {
+	snapshots := c.filter.FinalSnapshots(res)
+	if len(snapshots) == 0 {
+		return res
+	}
+
+	fmt.Fprintf(reg.out, "\n---\nType: %s\nCreating final snapshots: %d\n\n", res[0].Type, len(snapshots))
+
+	failed := map[*resource.Resource]bool{}
+	for _, r := range snapshots {
+		fmt.Fprintln(reg.out, formatResource(r))
+
+		if c.dryRun || c.faults != nil {
+			continue
+		}
+		id, err := reg.client.CreateFinalSnapshot(r, time.Now())
+		if err != nil {
+			fmt.Fprintf(reg.out, "\t%s\n", err)
+			failed[r] = true
+			continue
+		}
+		fmt.Fprintf(reg.out, "\tSnapshot:\t%s\n", id)
+	}
+	fmt.Fprint(reg.out, "---\n\n")
+
+	result := resource.Resources{}
+	for _, r := range res {
+		if !failed[r] {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// unshare reports which other accounts have access to resources that are about to be deleted
+// and removes their permissions, if configured. Nothing is looked up when deletions are simulated.
+func (c *Wipe) unshare(reg *region, res resource.Resources) {
+	if !c.filter.RemoveSharing || c.faults != nil || len(res) == 0 || !resource.SupportsSharing(res[0].Type) {
+		return
+	}
+
+	for _, r := range res {
+		accounts, err := reg.client.SharedWith(r)
+		if err != nil {
+			fmt.Fprintf(reg.out, "WARN: %s\n", err)
+			continue
+		}
+		if len(accounts) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(reg.out, "INFO: %s %s is shared with: %s\n", r.Type, r.ID, strings.Join(accounts, ", "))
+		if c.dryRun {
+			continue
+		}
+		if err := reg.client.Unshare(r); err != nil {
+			fmt.Fprintf(reg.out, "WARN: %s\n", err)
+		}
+	}
+}
+
+// setRetention sets a retention on log groups instead of deleting them.
+func (c *Wipe) setRetention(reg *region, retentions []resource.Retention) {
+	if len(retentions) == 0 {
+		return
+	}
+
+	fmt.Fprintf(reg.out, "\n---\nType: %s\nSetting retention: %d\n\n", retentions[0].Resource.Type, len(retentions))
+
+	for _, r := range retentions {
+		fmt.Fprint(reg.out, formatResource(r.Resource))
+		fmt.Fprintf(reg.out, "\tRetention:\t%d days\n\n", r.Days)
+
+		if !c.dryRun && c.faults == nil {
+			if err := reg.client.SetRetention(r.Resource, r.Days); err != nil {
+				fmt.Fprintf(reg.out, "\t%s\n", err)
+			}
+		}
+	}
+	fmt.Fprint(reg.out, "---\n\n")
 }
 
 // wipe does the actual deletion (in parallel) of a given (filtered) list of AWS resources.
 // It takes advantage of the AWS terraform provider by using its delete functions
 // (so we get retries, detaching of policies from some IAM resources before deletion, and other stuff for free).
-func (c *Wipe) wipe(res resource.Resources) {
-	numWorkerThreads := 10
+func (c *Wipe) wipe(reg *region, res resource.Resources) {
+	numWorkerThreads := resource.DeleteWorkers
 
 	if len(res) == 0 {
 		return
 	}
+	c.sortOrder.Sort(res, reg.name)
+	c.plan(reg, res)
+	c.inferOwners(reg, res)
 
-	fmt.Printf("\n---\nType: %s\nFound: %d\n\n", res[0].Type, len(res))
+	fmt.Fprintf(reg.out, "\n---\nType: %s\nFound: %d\n\n", res[0].Type, len(res))
+
+	if resource.SupportsBatchDelete(res[0].Type) {
+		c.wipeBatch(reg, res)
+		return
+	}
 
 	ii := &terraform.InstanceInfo{
-		Type: string(res[0].Type),
+		Type: string(resource.DeleteType(res[0].Type)),
 	}
 
 	d := &terraform.InstanceDiff{
 		Destroy: true,
 	}
 
-	chResources := make(chan *resource.Resource, numWorkerThreads)
+	pacer := resource.NewPacer(resource.DeleteInterval(res[0].Type))
+
+	chResources := make(chan int, numWorkerThreads)
+
+	// the output of a test run is written in the order of the resources (instead of the order they are
+	// processed in), so that it is the same across runs
+	var ordered *orderedOutput
+	if c.dryRun {
+		ordered = newOrderedOutput(reg.out, len(res))
+	}
 
 	var wg sync.WaitGroup
 	wg.Add(len(res))
@@ -104,50 +1149,110 @@ func (c *Wipe) wipe(res resource.Resources) {
 	for j := 1; j <= numWorkerThreads; j++ {
 		go func() {
 			for {
-				r, more := <-chResources
+				i, more := <-chResources
 				if more {
-					printStat := fmt.Sprintf("\tId:\t\t%s", r.ID)
-					if r.Tags != nil {
-						if len(r.Tags) > 0 {
-							printStat += "\n\tTags:\t\t"
-							for k, v := range r.Tags {
-								printStat += fmt.Sprintf("[%s: %v] ", k, v)
-							}
-						}
-					}
-					printStat += "\n"
-					if r.Created != nil {
-						printStat += fmt.Sprintf("\tCreated:\t%s", r.Created)
-						printStat += "\n"
-					}
-					fmt.Println(printStat)
-
-					// dirty hack to fix aws_key_pair
-					if r.Attrs == nil {
-						r.Attrs = map[string]string{"public_key": ""}
-					}
+					r, reg := res[i], ordered.region(reg, i)
+					fmt.Fprintln(reg.out, formatResource(r))
 
 					s := &terraform.InstanceState{
 						ID:         r.ID,
-						Attributes: r.Attrs,
+						Attributes: resource.StateAttributes(r),
 					}
 
-					st, err := (*c.provider).Refresh(ii, s)
+					p, err := c.providerFor(reg, r)
 					if err != nil {
-						log.Fatal(err)
+						fmt.Fprintf(reg.out, "\t%s\n", err)
+						c.markDeleted(reg, r, err)
+						ordered.finish(i)
+						wg.Done()
+						continue
+					}
+					if p == nil {
+						// replayed runs have no provider to refresh the state with
+						ordered.finish(i)
+						wg.Done()
+						continue
+					}
+
+					st, err := (*p).Refresh(ii, s)
+					if err != nil {
+						fmt.Fprintf(reg.out, "\t%s\n", err)
+						c.markDeleted(reg, r, err)
+						ordered.finish(i)
+						wg.Done()
+						continue
+					}
+					if st == nil {
+						// the resource has been deleted since it was listed
+						fmt.Fprintf(reg.out, "\tAlready deleted\n")
+						c.markDeleted(reg, r, nil)
+						ordered.finish(i)
+						wg.Done()
+						continue
 					}
 
 					// doesn't hurt to always add some force attributes
 					st.Attributes["force_detach_policies"] = "true"
 					st.Attributes["force_destroy"] = "true"
 
+					if !c.dryRun && c.stopped() {
+						ordered.finish(i)
+						wg.Done()
+						continue
+					}
+
 					if !c.dryRun {
-						_, err = (*c.provider).Apply(ii, st, d)
+						span := c.tracer.Start(reg.span, "delete", "cloud.region", reg.name,
+							"resource.type", string(r.Type), "resource.id", r.ID)
+						if c.faults != nil {
+							err = c.faults.Delete(r)
+						} else {
+							if resource.SupportsEmpty(r.Type) {
+								// if emptying fails, the provider deletes what is left (as force_destroy is set)
+								client, err := c.clientFor(reg, r)
+								if err == nil {
+									err = client.Empty(r)
+								}
+								if err != nil {
+									fmt.Fprintf(reg.out, "\tWARN: %s\n", err)
+								}
+							}
+							err = pacer.Do(func() error {
+								_, err := (*p).Apply(ii, st, d)
+								return err
+							})
+							if err != nil && r.Type == resource.CloudformationStack {
+								fmt.Fprintf(reg.out, "\t%s\n", err)
+								err = c.remediateStack(reg, r, func() error {
+									_, err := (*p).Apply(ii, st, d)
+									return err
+								})
+							}
+							if resource.ClassifyError(err) == resource.ErrorProtected && c.filter.DisablesDeletionProtection(r) {
+								fmt.Fprintf(reg.out, "\t%s\n", err)
+								err = c.unprotect(reg, r, func() error {
+									_, err := (*p).Apply(ii, st, d)
+									return err
+								})
+							}
+						}
+						if resource.IsNotFound(err) {
+							err = nil
+						}
+						if err == nil && c.wait && c.faults == nil && resource.DeletesAsynchronously(r.Type) {
+							fmt.Fprintf(reg.out, "\tWaiting until deleted\n")
+							err = reg.client.WaitUntilDeleted(r)
+						}
+
+						span.End(err)
 
 						if err != nil {
-							fmt.Printf("\t%s\n", err)
+							fmt.Fprintf(reg.out, "\t%s\n", err)
 						}
+						c.markDeleted(reg, r, err)
+						c.emit(reg, r, err)
 					}
+					ordered.finish(i)
 					wg.Done()
 				} else {
 					return
@@ -156,13 +1261,233 @@ func (c *Wipe) wipe(res resource.Resources) {
 		}()
 	}
 
-	for _, r := range res {
-		chResources <- r
+	for i := range res {
+		chResources <- i
 	}
 	close(chResources)
 
 	wg.Wait()
-	fmt.Print("---\n\n")
+	fmt.Fprint(reg.out, "---\n\n")
+}
+
+// remediateStack reports the resources that a CloudFormation stack failed to delete, deletes them directly
+// (if the filter entry of the stack says so) and retries to delete the stack once. It returns the error of the retry.
+func (c *Wipe) remediateStack(reg *region, stack *resource.Resource, retry func() error) error {
+	failures, err := reg.client.StackFailures(stack.ID)
+	if err != nil {
+		fmt.Fprintf(reg.out, "\tWARN: %s\n", err)
+	}
+	for _, f := range failures {
+		fmt.Fprintf(reg.out, "\tBlocked by:\t%s %s (%s): %s\n", f.Type, f.PhysicalID, f.LogicalID, f.Reason)
+	}
+
+	for _, r := range c.filter.BlockingResources(stack, failures) {
+		err := c.deleteDirectly(reg, r)
+		if err != nil {
+			fmt.Fprintf(reg.out, "\tFailed to delete %s %s: %s\n", r.Type, r.ID, err)
+		} else {
+			fmt.Fprintf(reg.out, "\tDeleted %s %s\n", r.Type, r.ID)
+		}
+		c.markDeleted(reg, r, err)
+		c.emit(reg, r, err)
+	}
+
+	fmt.Fprintf(reg.out, "\tRetrying to delete stack\n")
+	return retry()
+}
+
+// unprotect disables the deletion protection of a resource and retries to delete it once.
+// It returns the error of disabling the protection or of the retry.
+func (c *Wipe) unprotect(reg *region, r *resource.Resource, retry func() error) error {
+	if err := reg.client.DisableDeletionProtection(r); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(reg.out, "\tDisabled deletion protection, retrying to delete\n")
+	return retry()
+}
+
+// deleteDirectly deletes a single resource via the Terraform provider, regardless of the filter
+// of its type (e.g., a resource that blocks the deletion of a CloudFormation stack).
+func (c *Wipe) deleteDirectly(reg *region, r *resource.Resource) error {
+	p, err := c.providerFor(reg, r)
+	if err != nil {
+		return err
+	}
+	ii := &terraform.InstanceInfo{
+		Type: string(resource.DeleteType(r.Type)),
+	}
+
+	st, err := (*p).Refresh(ii, &terraform.InstanceState{
+		ID:         r.ID,
+		Attributes: resource.StateAttributes(r),
+	})
+	if err != nil || st == nil {
+		return err
+	}
+	st.Attributes["force_detach_policies"] = "true"
+	st.Attributes["force_destroy"] = "true"
+
+	_, err = (*p).Apply(ii, st, &terraform.InstanceDiff{Destroy: true})
+	if resource.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// wipeBatch deletes a given (filtered) list of AWS resources via a bulk delete API.
+func (c *Wipe) wipeBatch(reg *region, res resource.Resources) {
+	for _, r := range res {
+		fmt.Fprintln(reg.out, formatResource(r))
+	}
+
+	if !c.dryRun && !c.stopped() {
+		var errs map[string]error
+		if c.faults != nil {
+			errs = map[string]error{}
+			for _, r := range res {
+				if err := c.faults.Delete(r); err != nil {
+					errs[r.ID] = err
+				}
+			}
+		} else {
+			errs = reg.client.BatchDelete(res)
+		}
+		for id, err := range errs {
+			if resource.IsNotFound(err) {
+				delete(errs, id)
+			}
+		}
+		for id, err := range errs {
+			fmt.Fprintf(reg.out, "\t%s: %s\n", id, err)
+		}
+		for _, r := range res {
+			c.markDeleted(reg, r, errs[r.ID])
+			c.emit(reg, r, errs[r.ID])
+		}
+	}
+	fmt.Fprint(reg.out, "---\n\n")
+}
+
+// markDeleted keeps track of a resource that has been deleted successfully (or didn't exist anymore),
+// or of the class of error it failed to be deleted with.
+func (c *Wipe) markDeleted(reg *region, r *resource.Resource, deleteErr error) {
+	if deleteErr != nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if c.failures == nil {
+			c.failures = map[resource.ErrorClass]int{}
+		}
+		c.failures[resource.ClassifyError(deleteErr)]++
+		c.deniedActions = append(c.deniedActions, resource.DeniedActions(r.Type, deleteErr)...)
+		return
+	}
+
+	c.mu.Lock()
+	if c.numDeleted == nil {
+		c.numDeleted = map[resource.TerraformResourceType]int{}
+	}
+	c.numDeleted[r.Type]++
+	c.mu.Unlock()
+
+	if c.deleted != nil {
+		c.deleted.Add(reg.name, r)
+	}
+}
+
+// emit sends an event about the deletion of a resource to the destinations in the events config (if any).
+// Failing to send an event doesn't stop the deletion of other resources.
+func (c *Wipe) emit(reg *region, r *resource.Resource, deleteErr error) {
+	cfg := c.filter.Events
+	if cfg == nil {
+		return
+	}
+
+	regionName := reg.name
+	if r.Region != "" {
+		regionName = r.Region
+	}
+	e := resource.NewDeletionEvent(r, regionName, deleteErr)
+
+	if cfg.Webhook != "" {
+		if err := resource.PostEvent(eventClient, cfg.Webhook, e); err != nil {
+			fmt.Fprintf(reg.out, "\tWARN: %s\n", err)
+		}
+	}
+	if cfg.EventBus != "" {
+		if err := reg.client.PutEvent(e); err != nil {
+			fmt.Fprintf(reg.out, "\tWARN: %s\n", err)
+		}
+	}
+}
+
+// checkCompliance reports all resources selected by the filter that don't have the required tags,
+// instead of deleting them. It returns a non-zero exit status if any such resource has been found.
+func (c *Wipe) checkCompliance() int {
+	c.UI.Output(fmt.Sprintf("INFO: This is a compliance check for required tags %v, nothing will be deleted!",
+		c.filter.RequiredTags))
+
+	var numViolations int64
+
+	c.forEachRegion(func(reg *region, resTypes []resource.TerraformResourceType) {
+		for _, resType := range resTypes {
+			rawResources, err := reg.client.RawResources(resType)
+			if err != nil {
+				resource.Fatal(err)
+			}
+
+			deletableResources, err := resource.DeletableResources(resType, rawResources)
+			if err != nil {
+				resource.Fatal(err)
+			}
+			c.fetchTags(reg, resType, deletableResources)
+
+			for _, res := range c.filter.Apply(resType, deletableResources, rawResources, reg.client) {
+				violations := c.filter.Violations(res)
+				if len(violations) == 0 {
+					continue
+				}
+
+				fmt.Fprintf(reg.out, "\n---\nType: %s\nNon-compliant: %d\n\n", violations[0].Type, len(violations))
+				for _, r := range violations {
+					fmt.Fprintln(reg.out, formatResource(r)+fmt.Sprintf("\tMissing tags:\t%v\n", c.filter.MissingTags(r)))
+				}
+				fmt.Fprint(reg.out, "---\n\n")
+
+				atomic.AddInt64(&numViolations, int64(len(violations)))
+			}
+		}
+	})
+
+	if c.fatalErr != nil || numViolations > 0 {
+		return 1
+	}
+	return 0
+}
+
+// formatResource returns the ID, tags and creation time of a resource as human-readable text.
+func formatResource(r *resource.Resource) string {
+	printStat := fmt.Sprintf("\tId:\t\t%s", r.ID)
+	if r.Tags != nil {
+		if len(r.Tags) > 0 {
+			printStat += "\n\tTags:\t\t"
+			var keys []string
+			for k := range r.Tags {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				printStat += fmt.Sprintf("[%s: %v] ", k, r.Tags[k])
+			}
+		}
+	}
+	printStat += "\n"
+	if r.Created != nil {
+		printStat += fmt.Sprintf("\tCreated:\t%s", r.Created)
+		printStat += "\n"
+	}
+	return printStat
 }
 
 // Help returns help information of this command
@@ -1,112 +1,521 @@
 package command
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
+	"strings"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/resourcetest"
 	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/mitchellh/cli"
-	"github.com/terraform-providers/terraform-provider-aws/aws"
+	tfaws "github.com/terraform-providers/terraform-provider-aws/aws"
+)
+
+var (
+	// version is the version of AWSweeper (set by the release build)
+	version = "0.1.1"
+	// releaseKey is the base64 encoded (armored) public key releases are signed with (set by the release build)
+	releaseKey = ""
 )
 
 // WrappedMain is the actual main function
 // that does not exit for acceptance testing purposes
 func WrappedMain() int {
+	return Run(os.Args[1:], nil)
+}
+
+// Run runs AWSweeper with the given command line arguments (without the name of the program) and returns
+// its exit status. Programs embedding AWSweeper can pass the sessions and clients to access AWS with via clients,
+// instead of having them created from --profile and the shared config (if clients is nil, they are).
+func Run(args []string, clients resource.ClientProvider) int {
 	app := "awsweeper"
-	version := "0.1.1"
 
 	set := flag.NewFlagSet(app, 0)
 	versionFlag := set.Bool("version", false, "Show version")
 	helpFlag := set.Bool("help", false, "Show help")
-	dryRunFlag := set.Bool("dry-run", false, "Don't delete anything, just show what would happen")
+	dryRunFlag := set.Bool("dry-run", false, "Don't delete anything, just show what would happen (default)")
+	noDryRunFlag := set.Bool("no-dry-run", false, "Delete resources (after confirmation)")
 	forceDeleteFlag := set.Bool("force", false, "Start deleting without asking for confirmation")
+	sampleFlag := set.String("sample", "", "Only delete a random subset of the selected resources (e.g., 10% or 5)")
+	complianceFlag := set.Bool("compliance", false, "Don't delete anything, but report resources missing required tags")
+	ownerReportFlag := set.String("owner-report", "", "Report the resources to delete missing required tags grouped by owner to the given file")
 	profile := set.String("profile", "", "Use a specific profile from your credential file")
-	region := set.String("region", "", "The region to use. Overrides config/env settings")
+	profileNameFlag := set.String("profile-name", "", "Use the filters of the given profile of the config")
+	regionFlag := set.String("region", "", "The region(s) to use (comma-separated). Overrides config/env settings")
+	allRegionsFlag := set.Bool("all-regions", false, "Use all regions that are enabled for the account")
+	cpuProfileFlag := set.String("cpu-profile", "", "Write a CPU profile to the given file")
+	traceFlag := set.String("trace", "", "Write an execution trace to the given file")
+	recordFlag := set.String("record", "", "Record the outputs of AWS API calls to the given file")
+	injectFailuresFlag := set.String("inject-failures", "", "Simulate deletions, of which the given rate fails (e.g., rate=0.1)")
+	resumeFlag := set.String("resume", "", "Keep track of deleted resources in the given file and skip them when resuming a run")
+	replayFlag := set.String("replay", "", "Replay the outputs of AWS API calls recorded to the given file (implies --dry-run)")
+	spillDirFlag := set.String("spill-dir", "", "Spill resource types that can be numerous to files in the given directory and sweep them in chunks")
+	waitFlag := set.Bool("wait", false, "Wait until resources that are deleted asynchronously are gone")
+	allowHighSeverityFlag := set.Bool("allow-high-severity", false, "Allow deleting resources of types with high severity (e.g., IAM, KMS)")
+	sortFlag := set.String("sort", "", "Sort the output by the given keys (default: type,region,created)")
+	tuiFlag := set.Bool("tui", false, "Review the resources to delete in a terminal UI and check the ones to delete")
 
 	log.SetFlags(0)
 	log.SetOutput(ioutil.Discard)
 
 	set.Usage = func() { fmt.Println(help()) }
-	set.Parse(os.Args[1:])
+	set.Parse(args)
 
 	if *versionFlag {
 		fmt.Println(version)
-		os.Exit(0)
+		return 0
 	}
 
 	if *helpFlag {
 		fmt.Println(help())
-		os.Exit(0)
+		return 0
+	}
+
+	if *dryRunFlag && *noDryRunFlag {
+		fmt.Println("err: --dry-run and --no-dry-run are mutually exclusive")
+		return 1
 	}
 
+	var dryRun *bool
+	if *dryRunFlag || *noDryRunFlag {
+		dryRun = aws.Bool(*dryRunFlag)
+	}
+
+	var faults *resource.FaultInjection
+	if *injectFailuresFlag != "" {
+		if *noDryRunFlag {
+			fmt.Println("err: --inject-failures and --no-dry-run are mutually exclusive")
+			return 1
+		}
+
+		var err error
+		if faults, err = resource.ParseFaultInjection(*injectFailuresFlag); err != nil {
+			fmt.Printf("err: %s\n", err)
+			return 1
+		}
+	}
+
+	var recorder *resourcetest.Recorder
+	if *recordFlag != "" {
+		recorder = resourcetest.NewRecorder()
+	}
+
+	var replayed resourcetest.Fixtures
+	if *replayFlag != "" {
+		if recorder != nil {
+			fmt.Println("err: --record and --replay are mutually exclusive")
+			return 1
+		}
+		if *noDryRunFlag {
+			fmt.Println("err: --replay only supports dry runs")
+			return 1
+		}
+		dryRun = aws.Bool(true)
+
+		var err error
+		if replayed, err = resourcetest.LoadFixtures(*replayFlag); err != nil {
+			fmt.Printf("err: %s\n", err)
+			return 1
+		}
+	}
+
+	var sample *resource.Sample
+	if *sampleFlag != "" {
+		var err error
+		if sample, err = resource.ParseSample(*sampleFlag); err != nil {
+			fmt.Printf("err: %s\n", err)
+			return 1
+		}
+		// spilled resources are swept in chunks, so that a sample would be taken per chunk
+		if *spillDirFlag != "" {
+			fmt.Println("err: --sample can't be used together with --spill-dir")
+			return 1
+		}
+	}
+
+	var sortOrder resource.SortOrder
+	if *sortFlag != "" {
+		var err error
+		if sortOrder, err = resource.ParseSortOrder(*sortFlag); err != nil {
+			fmt.Printf("err: %s\n", err)
+			return 1
+		}
+	}
+
+	stopProfiling, err := startProfiling(*cpuProfileFlag, *traceFlag)
+	if err != nil {
+		fmt.Printf("err: %s\n", err)
+		return 1
+	}
+	defer stopProfiling()
+
 	c := &cli.CLI{
 		Name:     app,
 		Version:  version,
 		HelpFunc: basicHelpFunc(app),
 	}
-	c.Args = append([]string{"wipe"}, set.Args()...)
-
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-		Profile:           *profile,
-	}))
-
-	if *region == "" {
-		region = sess.Config.Region
+	c.Args = set.Args()
+	if len(c.Args) == 0 || !isSubcommand(c.Args[0]) {
+		c.Args = append([]string{"wipe"}, c.Args...)
 	}
 
-	p := initAwsProvider(*profile, *region)
-
 	ui := &cli.BasicUi{
 		Reader:      os.Stdin,
 		Writer:      os.Stdout,
 		ErrorWriter: os.Stderr,
 	}
 
-	client := resource.NewAWS(sess)
+	// newWipe sets up the AWS clients, which is only needed by commands that access AWS
+	newWipe := func() (*Wipe, error) {
+		if replayed != nil {
+			return newReplayWipe(ui, replayed, *regionFlag, dryRun, *complianceFlag, sample, sortOrder), nil
+		}
+
+		provider := clients
+		// the Terraform provider is passed the credentials the AWS SDK can't retrieve by itself
+		// (or the ones of the sessions of the client provider)
+		var providerCreds *credentials.Credentials
+		var externalCreds *resource.ExternalCredentials
+		if provider == nil {
+			sess := session.Must(session.NewSessionWithOptions(session.Options{
+				SharedConfigState: session.SharedConfigEnable,
+				Profile:           *profile,
+			}))
+
+			var err error
+			externalCreds, err = resource.NewExternalCredentials(sess, *profile)
+			if err != nil {
+				return nil, err
+			}
+			if externalCreds != nil {
+				providerCreds = externalCreds.Credentials
+				sess = sess.Copy(&aws.Config{Credentials: providerCreds})
+			}
+			provider = resource.RegionalSessions(sess)
+		}
+
+		tracer, err := resource.NewTracerFromEnv()
+		if err != nil {
+			return nil, err
+		}
+
+		// sessions are copied before recording and tracing their API calls, so that the ones
+		// of the client provider are left as they are
+		newSession := func(region string) (*session.Session, error) {
+			sess, err := provider.Session(region)
+			if err != nil {
+				return nil, err
+			}
+			sess = sess.Copy()
+			if recorder != nil {
+				recorder.Attach(sess)
+			}
+			tracer.Attach(sess)
+			return sess, nil
+		}
+
+		sess, err := newSession("")
+		if err != nil {
+			return nil, err
+		}
+		credentialsExpiry, err := resource.CredentialsExpiry(sess.Config.Credentials)
+		if err != nil {
+			return nil, err
+		}
+		if externalCreds != nil {
+			// the clients refresh them, but the Terraform provider can't
+			credentialsExpiry = externalCreds.Expiration()
+		}
+
+		client, err := provider.Client("", sess)
+		if err != nil {
+			return nil, err
+		}
+		regionNames, err := regions(client, aws.StringValue(sess.Config.Region), *regionFlag, *allRegionsFlag)
+		if err != nil {
+			return nil, err
+		}
+		if len(regionNames) == 0 {
+			return nil, errors.New("none of the given regions is enabled for the account")
+		}
+
+		// the identity of the credentials is looked up once for all regions
+		identities := &resource.IdentityCache{}
+		creds := map[string]*credentials.Credentials{}
+
+		var regs []*region
+		for _, name := range regionNames {
+			regionSess, err := newSession(name)
+			if err != nil {
+				return nil, err
+			}
+			client, err := provider.Client(name, regionSess)
+			if err != nil {
+				return nil, err
+			}
+			client.Identities = identities
+
+			creds[name] = providerCreds
+			if clients != nil {
+				// the original session of the region (regionSess is a copy that records and traces API calls)
+				s, err := provider.Session(name)
+				if err != nil {
+					return nil, err
+				}
+				creds[name] = s.Config.Credentials
+			}
+
+			p, err := initAwsProvider(*profile, name, nil, creds[name])
+			if err != nil {
+				return nil, err
+			}
+
+			regs = append(regs, &region{
+				name:     name,
+				client:   client,
+				provider: p,
+				sess:     regionSess,
+			})
+		}
+
+		return &Wipe{
+			UI: &cli.ColoredUi{
+				Ui:          ui,
+				OutputColor: cli.UiColorBlue,
+			},
+			regions: regs,
+			clients: provider,
+			newProvider: func(region string, clientsConfig *resource.ClientsConfig) (*terraform.ResourceProvider, error) {
+				return initAwsProvider(*profile, region, clientsConfig, creds[region])
+			},
+			dryRunFlag:        dryRun,
+			profileName:       *profileNameFlag,
+			forceDelete:       *forceDeleteFlag,
+			compliance:        *complianceFlag,
+			ownerReport:       *ownerReportFlag,
+			sample:            sample,
+			resumeFile:        *resumeFlag,
+			faults:            faults,
+			credentialsExpiry: credentialsExpiry,
+			tracer:            tracer,
+			spillDir:          *spillDirFlag,
+			wait:              *waitFlag,
+			tui:               *tuiFlag,
+			sortOrder:         sortOrder,
+			allowHighSeverity: *allowHighSeverityFlag,
+		}, nil
+	}
 
 	c.Commands = map[string]cli.CommandFactory{
 		"wipe": func() (cli.Command, error) {
-			return &Wipe{
-				UI: &cli.ColoredUi{
-					Ui:          ui,
-					OutputColor: cli.UiColorBlue,
-				},
-				client:      client,
-				provider:    p,
-				dryRun:      *dryRunFlag,
-				forceDelete: *forceDeleteFlag,
-			}, nil
+			return newWipe()
+		},
+		"nuke": func() (cli.Command, error) {
+			w, err := newWipe()
+			return &Nuke{Wipe: w}, err
+		},
+		"preflight": func() (cli.Command, error) {
+			w, err := newWipe()
+			return &Preflight{Wipe: w}, err
+		},
+		"report": func() (cli.Command, error) {
+			w, err := newWipe()
+			return &Report{Wipe: w}, err
+		},
+		"iam-policy": func() (cli.Command, error) {
+			return &IamPolicy{UI: ui, profileName: *profileNameFlag}, nil
+		},
+		"serve": func() (cli.Command, error) {
+			return &Serve{UI: ui, newWipe: newWipe, profileName: *profileNameFlag}, nil
+		},
+		"self-update": func() (cli.Command, error) {
+			return &SelfUpdate{UI: ui}, nil
+		},
+		"migrate-config": func() (cli.Command, error) {
+			return &MigrateConfig{UI: ui}, nil
 		},
 	}
 
 	exitStatus, err := c.Run()
 	if err != nil {
-		log.Println(err)
+		fmt.Printf("err: %s\n", err)
+	}
+
+	if recorder != nil {
+		if err := recorder.Save(*recordFlag); err != nil {
+			fmt.Printf("err: %s\n", err)
+			return 1
+		}
 	}
 
 	return exitStatus
 }
 
+// regions returns the names of the regions to sweep. If more than a single region is requested,
+// regions that are not enabled for the account (i.e. not opted-in) are skipped.
+func regions(client *resource.AWS, defaultRegion string, regionFlag string, allRegions bool) ([]string, error) {
+	if !allRegions && !strings.Contains(regionFlag, ",") {
+		if regionFlag == "" {
+			return []string{defaultRegion}, nil
+		}
+		return []string{regionFlag}, nil
+	}
+
+	enabled, err := client.EnabledRegions()
+	if err != nil {
+		return nil, err
+	}
+
+	if allRegions {
+		return enabled, nil
+	}
+
+	selected, skipped := resource.SelectRegions(strings.Split(regionFlag, ","), enabled)
+	for _, r := range skipped {
+		fmt.Printf("WARN: Skipping region %s, which is not enabled for the account\n", r)
+	}
+	return selected, nil
+}
+
+// isSubcommand checks whether the given argument is the name of a command other than the default (wipe) command.
+func isSubcommand(arg string) bool {
+	switch arg {
+	case "nuke", "iam-policy", "migrate-config", "preflight", "report", "self-update", "serve":
+		return true
+	}
+	return false
+}
+
 func help() string {
 	return `Usage: awsweeper [options] <config.yaml>
+       awsweeper [options] nuke --account-id <id>
+       awsweeper iam-policy [--list-only] <config.yaml>
+       awsweeper migrate-config <config.yaml>
+       awsweeper self-update [--check] [--allow-downgrade] [--key <public-key.asc>]
+       awsweeper [options] serve [--listen <address>] <config.yaml>
+       awsweeper [options] preflight [<config.yaml>]
+       awsweeper [options] report [--weeks <n>] [--html <file>] <config.yaml>
 
   Delete AWS resources via a yaml configuration.
 
+Commands:
+  nuke			Delete all supported resources of an account,
+			except resources that AWS creates by default.
+			The account ID of the used credentials must match --account-id
+
+  preflight		Check credentials, region access, and reachability and
+			rate-limit headroom of the APIs needed for the config
+			(or all supported resource types)
+
+  report		Print the number of runs, deleted resources and failures
+			per week, aggregated from the run results stored in the
+			audit bucket of the config (the last 4 weeks by default).
+			With --html, write the report as HTML page to the given file
+
+  iam-policy		Print the IAM policy needed to sweep the resource types
+			of a config. With --list-only, the policy only allows
+			to list resources (sufficient for dry runs)
+
+  migrate-config	Print a config of an older format version
+			converted into the current format
+
+  serve			Serve an HTTP API to list (GET /v1/resources), plan
+			(POST /v1/plan) and delete (POST /v1/apply) the resources
+			of a config. Requests must authenticate with the token in
+			AWSWEEPER_TOKEN (Authorization: Bearer <token>)
+
+  self-update		Replace the binary with the one of the latest release,
+			after verifying the signature of its checksums. With
+			--check, only print whether a newer release exists.
+			Older releases only replace the binary with
+			--allow-downgrade
+
 Options:
   --profile		Use a specific profile from your credential file
 
-  --region		The region to use. Overrides config/env settings
+  --profile-name	Use the filters of the given profile of the config
+			(listed under profiles) instead of or in addition to
+			the resource types outside of profiles
+
+  --region		The region to use. Overrides config/env settings.
+			Multiple regions can be given as comma-separated list,
+			regions that are not enabled for the account are skipped
 
-  --dry-run		Don't delete anything, just show what would happen
+  --all-regions		Use all regions that are enabled for the account
+
+  --dry-run		Don't delete anything, just show what would happen.
+			This is the default, unless the config sets dry_run: false
+
+  --no-dry-run		Delete resources. Requires to confirm the deletion
+			by typing the ID of the account
 
   --force		Start deleting without asking for confirmation
+
+  --allow-high-severity	Allow deleting resources of types with high severity
+			(IAM, KMS, Route53 zones, S3 buckets, DynamoDB tables and
+			RDS clusters, unless overridden by severities in the config),
+			which is refused otherwise
+
+  --sort		Sort the resources in the output by the given comma-separated
+			keys out of type, region, created and id (default:
+			type,region,created). Output of multiple regions is grouped
+			by type, unless region comes before type
+
+  --tui			Review the resources selected for deletion in a table
+			in the terminal, which can be navigated and filtered, and
+			check the ones to delete before confirming the deletion.
+			Ignored in test runs
+
+  --sample		Only delete a random subset of the selected resources
+			per resource type, given as percentage (e.g., 10%)
+			or number of resources (e.g., 5)
+
+  --compliance		Don't delete anything, but report resources missing
+			the tags listed under required_tags in the config
+
+  --owner-report	Write the resources to delete that miss the tags listed
+			under required_tags to the given file, grouped by their
+			owner (inferred from the key pair, name prefix or creator)
+
+  --inject-failures	Simulate deletions (without calling AWS), of which a random
+			subset fails, given as comma-separated settings: rate=<0-1>
+			and (optionally) class=<not_found|in_use|access_denied|
+			throttled|unknown>, e.g. rate=0.1
+
+  --resume		Keep track of the deleted resources in the given file, so that
+			resources deleted by an interrupted run are skipped when
+			running again with the same file
+
+  --record		Record the outputs of the AWS API calls to the given file
+			(account IDs are replaced by a dummy ID)
+
+  --replay		Replay the outputs of AWS API calls recorded with --record
+			instead of calling AWS. Implies --dry-run
+
+  --spill-dir		Write the listed snapshots and log streams to temporary
+			files in the given directory and filter and delete them
+			in chunks of 1000, which keeps the memory usage low in
+			accounts with millions of them. Can't be used together
+			with --sample
+
+  --wait		Wait until resources that remain in a deleting state for a while
+			after they have been deleted (state machines) are gone,
+			so that their names can be reused right away
+
+  --cpu-profile		Write a CPU profile to the given file
+			(to be analyzed with go tool pprof)
+
+  --trace		Write an execution trace to the given file
+			(to be analyzed with go tool trace)
 `
 }
 
@@ -116,18 +525,39 @@ func basicHelpFunc(app string) cli.HelpFunc {
 	}
 }
 
-func initAwsProvider(profile string, region string) *terraform.ResourceProvider {
-	p := aws.Provider()
+// initAwsProvider initializes the Terraform AWS provider of a region. Of the client settings of the config (if not nil),
+// the provider only supports max_retries and the CA bundle. If creds is not nil, the provider uses the current
+// credentials instead of retrieving them by itself (e.g., of web identities, which it doesn't support).
+func initAwsProvider(profile string, region string, clients *resource.ClientsConfig,
+	creds *credentials.Credentials) (*terraform.ResourceProvider, error) {
+	p := tfaws.Provider()
 
 	cfg := map[string]interface{}{
 		"region":  region,
 		"profile": profile,
 	}
+	if creds != nil {
+		v, err := creds.Get()
+		if err != nil {
+			return nil, err
+		}
+		cfg["access_key"] = v.AccessKeyID
+		cfg["secret_key"] = v.SecretAccessKey
+		cfg["token"] = v.SessionToken
+	}
+	if clients != nil {
+		if clients.MaxRetries != nil {
+			cfg["max_retries"] = *clients.MaxRetries
+		}
+		if clients.CABundle != "" {
+			// the provider creates its session from the environment
+			os.Setenv("AWS_CA_BUNDLE", clients.CABundle)
+		}
+	}
 
 	rc, err := config.NewRawConfig(cfg)
 	if err != nil {
-		fmt.Printf("bad: %s\n", err)
-		os.Exit(1)
+		return nil, err
 	}
 	conf := terraform.NewResourceConfig(rc)
 
@@ -136,14 +566,12 @@ func initAwsProvider(profile string, region string) *terraform.ResourceProvider
 		fmt.Printf("warnings: %s\n", warns)
 	}
 	if len(errs) > 0 {
-		fmt.Printf("errors: %s\n", errs)
-		os.Exit(1)
+		return nil, fmt.Errorf("invalid provider config: %v", errs)
 	}
 
 	if err := p.Configure(conf); err != nil {
-		fmt.Printf("err: %s\n", err)
-		os.Exit(1)
+		return nil, err
 	}
 
-	return &p
+	return &p, nil
 }
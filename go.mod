@@ -20,7 +20,7 @@ require (
 	github.com/hashicorp/go-getter v0.0.0-20180123174312-285374cdfad6 // indirect
 	github.com/hashicorp/go-multierror v0.0.0-20171204182908-b7773ae21874 // indirect
 	github.com/hashicorp/go-uuid v0.0.0-20160717022140-64130c7a86d7 // indirect
-	github.com/hashicorp/go-version v0.0.0-20171129150820-4fe82ae3040f // indirect
+	github.com/hashicorp/go-version v0.0.0-20171129150820-4fe82ae3040f
 	github.com/hashicorp/hcl v0.0.0-20171017181929-23c074d0eceb // indirect
 	github.com/hashicorp/hcl2 v0.0.0-20180308163058-5f8ed954abd8 // indirect
 	github.com/hashicorp/hil v0.0.0-20170627220502-fa9f258a9250 // indirect
@@ -52,7 +52,7 @@ require (
 	github.com/terraform-providers/terraform-provider-tls v1.2.0 // indirect
 	github.com/ulikunitz/xz v0.5.4 // indirect
 	github.com/zclconf/go-cty v0.0.0-20180328152515-d006e4534bc4 // indirect
-	golang.org/x/crypto v0.0.0-20180126023034-0efb9460aaf8 // indirect
+	golang.org/x/crypto v0.0.0-20180126023034-0efb9460aaf8
 	gopkg.in/airbrake/gobrake.v2 v2.0.9 // indirect
 	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
 	gopkg.in/gemnasium/logrus-airbrake-hook.v2 v2.1.2 // indirect
@@ -0,0 +1,104 @@
+// Package arn builds and parses Amazon Resource Names (ARNs).
+package arn
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/pkg/errors"
+)
+
+// prefix is the prefix of every ARN.
+const prefix = "arn:"
+
+// ARN is an Amazon Resource Name (arn:<partition>:<service>:<region>:<account ID>:<resource>).
+// Region and account ID are empty for resources of some global services (e.g., S3 buckets)
+// and the account ID is "aws" for resources managed by AWS (e.g., IAM policies).
+type ARN struct {
+	Partition string
+	Service   string
+	Region    string
+	AccountID string
+	// Resource is the type and ID of the resource (e.g., role/service-role/ci), separated by '/' or ':'
+	Resource string
+}
+
+// New returns the ARN of a resource in a region (empty for global resources), of which the partition
+// is derived (e.g., aws-cn for cn-north-1). Resources in unknown regions are in the aws partition.
+func New(region, service, accountID, resource string) ARN {
+	return ARN{
+		Partition: Partition(region),
+		Service:   service,
+		Region:    region,
+		AccountID: accountID,
+		Resource:  resource,
+	}
+}
+
+// Partition returns the partition of a region (aws if the region is empty or unknown).
+func Partition(region string) string {
+	if region != "" {
+		if p, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); ok {
+			return p.ID()
+		}
+	}
+	return endpoints.AwsPartitionID
+}
+
+// Is returns whether a string is an ARN.
+func Is(s string) bool {
+	_, err := Parse(s)
+	return err == nil
+}
+
+// Parse parses an ARN.
+func Parse(s string) (ARN, error) {
+	if !strings.HasPrefix(s, prefix) {
+		return ARN{}, errors.Errorf("invalid ARN (must start with %s): %s", prefix, s)
+	}
+
+	parts := strings.SplitN(s, ":", 6)
+	if len(parts) < 6 {
+		return ARN{}, errors.Errorf("invalid ARN (not enough sections): %s", s)
+	}
+	if parts[1] == "" || parts[2] == "" || parts[5] == "" {
+		return ARN{}, errors.Errorf("invalid ARN (the partition, service and resource must not be empty): %s", s)
+	}
+
+	return ARN{
+		Partition: parts[1],
+		Service:   parts[2],
+		Region:    parts[3],
+		AccountID: parts[4],
+		Resource:  parts[5],
+	}, nil
+}
+
+// String returns the ARN in its string form.
+func (a ARN) String() string {
+	return prefix + strings.Join([]string{a.Partition, a.Service, a.Region, a.AccountID, a.Resource}, ":")
+}
+
+// ResourceID returns the last part of the resource (after the last '/' or ':'), which is the name or ID
+// of most resources (e.g., ci of role/service-role/ci).
+func (a ARN) ResourceID() string {
+	return a.Resource[strings.LastIndexAny(a.Resource, "/:")+1:]
+}
+
+// AccountID returns the ID of the account in an ARN (empty if it isn't an ARN or has no account).
+func AccountID(s string) string {
+	a, err := Parse(s)
+	if err != nil {
+		return ""
+	}
+	return a.AccountID
+}
+
+// ResourceID returns the name or ID of the resource in an ARN (the string itself if it isn't an ARN).
+func ResourceID(s string) string {
+	a, err := Parse(s)
+	if err != nil {
+		return s
+	}
+	return a.ResourceID()
+}
@@ -0,0 +1,64 @@
+package arn_test
+
+import (
+	"testing"
+
+	"github.com/cloudetc/awsweeper/internal/arn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	a, err := arn.Parse("arn:aws:iam::123456789012:role/service-role/ci")
+	require.NoError(t, err)
+
+	assert.Equal(t, arn.ARN{
+		Partition: "aws",
+		Service:   "iam",
+		AccountID: "123456789012",
+		Resource:  "role/service-role/ci",
+	}, a)
+	assert.Equal(t, "ci", a.ResourceID())
+	assert.Equal(t, "arn:aws:iam::123456789012:role/service-role/ci", a.String())
+}
+
+func TestParse_ResourceWithColons(t *testing.T) {
+	a, err := arn.Parse("arn:aws:ecs:us-east-1:123456789012:task-definition/web:3")
+	require.NoError(t, err)
+
+	assert.Equal(t, "us-east-1", a.Region)
+	assert.Equal(t, "task-definition/web:3", a.Resource)
+	assert.Equal(t, "3", a.ResourceID())
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, s := range []string{"", "i-1234", "arn:aws:s3", "arn::s3:::bucket", "arn:aws:iam::123456789012:"} {
+		_, err := arn.Parse(s)
+		assert.Error(t, err, s)
+		assert.False(t, arn.Is(s), s)
+	}
+	assert.True(t, arn.Is("arn:aws:s3:::bucket"))
+}
+
+func TestNew(t *testing.T) {
+	assert.Equal(t, "arn:aws:sns:us-east-1:123456789012:alerts",
+		arn.New("us-east-1", "sns", "123456789012", "alerts").String())
+	assert.Equal(t, "arn:aws-cn:sns:cn-north-1:123456789012:alerts",
+		arn.New("cn-north-1", "sns", "123456789012", "alerts").String())
+	assert.Equal(t, "arn:aws-us-gov:sns:us-gov-west-1:123456789012:alerts",
+		arn.New("us-gov-west-1", "sns", "123456789012", "alerts").String())
+	assert.Equal(t, "arn:aws:iam::123456789012:user/ci",
+		arn.New("", "iam", "123456789012", "user/ci").String())
+}
+
+func TestAccountID(t *testing.T) {
+	assert.Equal(t, "123456789012", arn.AccountID("arn:aws:rds:us-east-1:123456789012:snapshot:own"))
+	assert.Equal(t, "", arn.AccountID("arn:aws:s3:::bucket"))
+	assert.Equal(t, "", arn.AccountID("own"))
+}
+
+func TestResourceID(t *testing.T) {
+	assert.Equal(t, "bucket", arn.ResourceID("arn:aws:s3:::bucket"))
+	assert.Equal(t, "alerts", arn.ResourceID("arn:aws:sns:us-east-1:123456789012:alerts"))
+	assert.Equal(t, "i-1234", arn.ResourceID("i-1234"))
+}
@@ -1,7 +1,21 @@
 package main
 
 //go:generate mockgen -package mocks -destination resource/mocks/autoscaling.go -source=$GOPATH/pkg/mod/github.com/aws/aws-sdk-go@v1.15.61/service/autoscaling/autoscalingiface/interface.go
+//go:generate mockgen -package mocks -destination resource/mocks/cloudwatch.go -source=$GOPATH/pkg/mod/github.com/aws/aws-sdk-go@v1.15.61/service/cloudwatch/cloudwatchiface/interface.go
+//go:generate mockgen -package mocks -destination resource/mocks/cloudwatchevents.go -source=$GOPATH/pkg/mod/github.com/aws/aws-sdk-go@v1.15.61/service/cloudwatchevents/cloudwatcheventsiface/interface.go
+//go:generate mockgen -package mocks -destination resource/mocks/cloudwatchlogs.go -source=$GOPATH/pkg/mod/github.com/aws/aws-sdk-go@v1.15.61/service/cloudwatchlogs/cloudwatchlogsiface/interface.go
+//go:generate mockgen -package mocks -destination resource/mocks/dynamodb.go -source=$GOPATH/pkg/mod/github.com/aws/aws-sdk-go@v1.15.61/service/dynamodb/dynamodbiface/interface.go
 //go:generate mockgen -package mocks -destination resource/mocks/ec2.go -source=$GOPATH/pkg/mod/github.com/aws/aws-sdk-go@v1.15.61/service/ec2/ec2iface/interface.go
+//go:generate mockgen -package mocks -destination resource/mocks/elb.go -source=$GOPATH/pkg/mod/github.com/aws/aws-sdk-go@v1.15.61/service/elb/elbiface/interface.go
+//go:generate mockgen -package mocks -destination resource/mocks/iam.go -source=$GOPATH/pkg/mod/github.com/aws/aws-sdk-go@v1.15.61/service/iam/iamiface/interface.go
+//go:generate mockgen -package mocks -destination resource/mocks/kms.go -source=$GOPATH/pkg/mod/github.com/aws/aws-sdk-go@v1.15.61/service/kms/kmsiface/interface.go
+//go:generate mockgen -package mocks -destination resource/mocks/lambda.go -source=$GOPATH/pkg/mod/github.com/aws/aws-sdk-go@v1.15.61/service/lambda/lambdaiface/interface.go
+//go:generate mockgen -package mocks -destination resource/mocks/resourcegroupstaggingapi.go -source=$GOPATH/pkg/mod/github.com/aws/aws-sdk-go@v1.15.61/service/resourcegroupstaggingapi/resourcegroupstaggingapiiface/interface.go
+//go:generate mockgen -package mocks -destination resource/mocks/route53.go -source=$GOPATH/pkg/mod/github.com/aws/aws-sdk-go@v1.15.61/service/route53/route53iface/interface.go
+//go:generate mockgen -package mocks -destination resource/mocks/s3.go -source=$GOPATH/pkg/mod/github.com/aws/aws-sdk-go@v1.15.61/service/s3/s3iface/interface.go
+//go:generate mockgen -package mocks -destination resource/mocks/sfn.go -source=$GOPATH/pkg/mod/github.com/aws/aws-sdk-go@v1.15.61/service/sfn/sfniface/interface.go
+//go:generate mockgen -package mocks -destination resource/mocks/ssm.go -source=$GOPATH/pkg/mod/github.com/aws/aws-sdk-go@v1.15.61/service/ssm/ssmiface/interface.go
+//go:generate mockgen -package mocks -destination resource/mocks/support.go -source=$GOPATH/pkg/mod/github.com/aws/aws-sdk-go@v1.15.61/service/support/supportiface/interface.go
 //go:generate mockgen -package mocks -destination resource/mocks/sts.go -source=$GOPATH/pkg/mod/github.com/aws/aws-sdk-go@v1.15.61/service/sts/stsiface/interface.go
 
 import (
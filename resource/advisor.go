@@ -0,0 +1,99 @@
+package resource
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/support"
+	"github.com/pkg/errors"
+)
+
+// advisorRegion is the only region the Trusted Advisor (AWS Support) API is available in.
+const advisorRegion = "us-east-1"
+
+// advisorCheck is a Trusted Advisor check that flags resources of a type as idle or underutilized.
+type advisorCheck struct {
+	id      string
+	resType TerraformResourceType
+	// idIndex is the position of the resource ID in the metadata of a flagged resource
+	idIndex int
+}
+
+// advisorChecks are the supported Trusted Advisor checks by the name used in the config.
+var advisorChecks = map[string]advisorCheck{
+	"low_utilization_instances": {id: "Qch7DwouX1", resType: Instance, idIndex: 1},
+	"idle_load_balancers":       {id: "hjLMh88uM8", resType: Elb, idIndex: 1},
+	"underutilized_ebs_volumes": {id: "DAvU99Dc4C", resType: EbsVolume, idIndex: 1},
+}
+
+// validateAdvisorChecks checks whether all Trusted Advisor checks in the config are supported
+// and cover a resource type of the config.
+func (f Filter) validateAdvisorChecks() error {
+	for _, name := range f.TrustedAdvisor {
+		check, found := advisorChecks[name]
+		if !found {
+			return fmt.Errorf("unsupported Trusted Advisor check: %s", name)
+		}
+		if _, found := f.Cfg[check.resType]; !found {
+			return fmt.Errorf("Trusted Advisor check %s requires resource type %s in the config", name, check.resType)
+		}
+	}
+	return nil
+}
+
+// AdvisorFindings are the resources flagged by Trusted Advisor checks per resource type,
+// identified by region and ID.
+type AdvisorFindings map[TerraformResourceType]map[string]bool
+
+// TrustedAdvisorFindings looks up the resources flagged by the given Trusted Advisor checks.
+// Resources that are excluded from a check (i.e. suppressed) or satisfy it are not flagged.
+func (a *AWS) TrustedAdvisorFindings(checks []string) (AdvisorFindings, error) {
+	findings := AdvisorFindings{}
+
+	for _, name := range checks {
+		check, found := advisorChecks[name]
+		if !found {
+			return nil, errors.Errorf("unsupported Trusted Advisor check: %s", name)
+		}
+
+		output, err := a.DescribeTrustedAdvisorCheckResult(&support.DescribeTrustedAdvisorCheckResultInput{
+			CheckId:  aws.String(check.id),
+			Language: aws.String("en"),
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the result of Trusted Advisor check %s", name)
+		}
+
+		if findings[check.resType] == nil {
+			findings[check.resType] = map[string]bool{}
+		}
+		if output.Result == nil {
+			continue
+		}
+		for _, d := range output.Result.FlaggedResources {
+			if aws.BoolValue(d.IsSuppressed) || aws.StringValue(d.Status) == "ok" || len(d.Metadata) <= check.idIndex {
+				continue
+			}
+			id := aws.StringValue(d.Metadata[check.idIndex])
+			findings[check.resType][aws.StringValue(d.Region)+"/"+id] = true
+		}
+	}
+	return findings, nil
+}
+
+// Seed returns the resources of a type in a region that have been flagged by Trusted Advisor,
+// so that the filter is only applied to them. Resources of types not covered by any check are returned as is.
+func (af AdvisorFindings) Seed(resType TerraformResourceType, region string, res Resources) Resources {
+	flagged, found := af[resType]
+	if !found {
+		return res
+	}
+
+	var result Resources
+	for _, r := range res {
+		if flagged[region+"/"+r.ID] {
+			result = append(result, r)
+		}
+	}
+	return result
+}
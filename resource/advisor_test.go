@@ -0,0 +1,87 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/support"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustedAdvisorFindings(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockSupportAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		SupportAPI: mockObj,
+	}
+
+	mockObj.EXPECT().DescribeTrustedAdvisorCheckResult(gomock.Any()).DoAndReturn(
+		func(input *support.DescribeTrustedAdvisorCheckResultInput) (*support.DescribeTrustedAdvisorCheckResultOutput, error) {
+			assert.Equal(t, "Qch7DwouX1", *input.CheckId)
+			return &support.DescribeTrustedAdvisorCheckResultOutput{
+				Result: &support.TrustedAdvisorCheckResult{
+					FlaggedResources: []*support.TrustedAdvisorResourceDetail{
+						{
+							Region:   aws.String("us-west-2"),
+							Status:   aws.String("warning"),
+							Metadata: aws.StringSlice([]string{"us-west-2", "i-idle", "idle"}),
+						},
+						{
+							Region:       aws.String("us-west-2"),
+							Status:       aws.String("warning"),
+							IsSuppressed: aws.Bool(true),
+							Metadata:     aws.StringSlice([]string{"us-west-2", "i-suppressed", "suppressed"}),
+						},
+						{
+							Region:   aws.String("us-west-2"),
+							Status:   aws.String("ok"),
+							Metadata: aws.StringSlice([]string{"us-west-2", "i-busy", "busy"}),
+						},
+					},
+				},
+			}, nil
+		})
+
+	res := resource.Resources{
+		{Type: resource.Instance, ID: "i-idle"},
+		{Type: resource.Instance, ID: "i-suppressed"},
+		{Type: resource.Instance, ID: "i-busy"},
+	}
+
+	// when
+	findings, err := awsMock.TrustedAdvisorFindings([]string{"low_utilization_instances"})
+
+	// then
+	require.NoError(t, err)
+	seeded := findings.Seed(resource.Instance, "us-west-2", res)
+	require.Len(t, seeded, 1)
+	assert.Equal(t, "i-idle", seeded[0].ID)
+	assert.Empty(t, findings.Seed(resource.Instance, "eu-west-1", res))
+	assert.Len(t, findings.Seed(resource.Vpc, "us-west-2", res), 3)
+}
+
+func TestYamlFilter_Validate_TrustedAdvisor(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {},
+		},
+		TrustedAdvisor: []string{"idle_load_balancers"},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.EqualError(t, err, "Trusted Advisor check idle_load_balancers requires resource type aws_elb in the config")
+
+	f.TrustedAdvisor = []string{"unknown"}
+	assert.EqualError(t, f.Validate(), "unsupported Trusted Advisor check: unknown")
+}
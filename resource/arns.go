@@ -0,0 +1,185 @@
+package resource
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cloudetc/awsweeper/internal/arn"
+)
+
+// arnFieldNames are the fields of raw resources that contain their ARN (for types whose ID isn't an ARN).
+var arnFieldNames = []string{
+	"Arn",
+	"ARN",
+	"AliasArn",
+	"AlarmArn",
+	"AutoScalingGroupARN",
+	"ConfigRuleArn",
+	"DBClusterArn",
+	"DBClusterSnapshotArn",
+	"DBSnapshotArn",
+	"EnvironmentArn",
+	"FunctionArn",
+	"LaunchConfigurationARN",
+	"StackId",
+	"TableArn",
+	"TrailARN",
+}
+
+// arnFormat is how the ARN of a resource is built from its ID, for types which raw resources lack their ARN.
+type arnFormat struct {
+	service string
+	// prefix is prepended to the ID to get the resource part of the ARN (e.g., instance/)
+	prefix string
+	// global is true for ARNs without region (e.g., of S3 buckets)
+	global bool
+	// noAccount is true for ARNs without account ID (e.g., of AMIs and EBS snapshots, which can be shared)
+	noAccount bool
+	// id returns the part of the ID that is in the ARN (nil if it's the whole ID)
+	id func(id string) string
+}
+
+var arnFormats = map[TerraformResourceType]arnFormat{
+	Ami:                  {service: "ec2", prefix: "image/", noAccount: true},
+	ApiGatewayRestApi:    {service: "apigateway", prefix: "/restapis/", noAccount: true},
+	BeanstalkApplication: {service: "elasticbeanstalk", prefix: "application/"},
+	CloudwatchDashboard:  {service: "cloudwatch", prefix: "dashboard/", global: true},
+	EbsSnapshot:          {service: "ec2", prefix: "snapshot/", noAccount: true},
+	EbsVolume:            {service: "ec2", prefix: "volume/"},
+	EfsFileSystem:        {service: "elasticfilesystem", prefix: "file-system/"},
+	Eip:                  {service: "ec2", prefix: "elastic-ip/"},
+	Elb:                  {service: "elasticloadbalancing", prefix: "loadbalancer/"},
+	GlueCatalogDatabase: {service: "glue", prefix: "database/", id: func(id string) string {
+		return id[strings.Index(id, ":")+1:]
+	}},
+	GlueCrawler:      {service: "glue", prefix: "crawler/"},
+	GlueJob:          {service: "glue", prefix: "job/"},
+	Instance:         {service: "ec2", prefix: "instance/"},
+	InternetGateway:  {service: "ec2", prefix: "internet-gateway/"},
+	NatGateway:       {service: "ec2", prefix: "natgateway/"},
+	NetworkAcl:       {service: "ec2", prefix: "network-acl/"},
+	NetworkInterface: {service: "ec2", prefix: "network-interface/"},
+	RedshiftCluster:  {service: "redshift", prefix: "cluster:"},
+	Route53Zone: {service: "route53", prefix: "hostedzone/", global: true, noAccount: true, id: func(id string) string {
+		return strings.TrimPrefix(id, "/hostedzone/")
+	}},
+	RouteTable:        {service: "ec2", prefix: "route-table/"},
+	S3Bucket:          {service: "s3", global: true, noAccount: true},
+	SecurityGroup:     {service: "ec2", prefix: "security-group/"},
+	SesDomainIdentity: {service: "ses", prefix: "identity/"},
+	SesEmailIdentity:  {service: "ses", prefix: "identity/"},
+	// queues are identified by their URL (https://sqs.<region>.amazonaws.com/<account>/<name>)
+	SqsQueue: {service: "sqs", id: func(id string) string {
+		return id[strings.LastIndex(id, "/")+1:]
+	}},
+	Subnet:      {service: "ec2", prefix: "subnet/"},
+	Vpc:         {service: "ec2", prefix: "vpc/"},
+	VpcEndpoint: {service: "ec2", prefix: "vpc-endpoint/"},
+}
+
+// build returns the ARN of a resource of a region (the region is only used for the partition of global ARNs).
+func (f arnFormat) build(id, region, accountID string) string {
+	if f.id != nil {
+		id = f.id(id)
+	}
+
+	a := arn.New(region, f.service, accountID, f.prefix+id)
+	if f.global {
+		a.Region = ""
+	}
+	if f.noAccount {
+		a.AccountID = ""
+	}
+	return a.String()
+}
+
+// hasARN checks whether the ARNs of resources of a type are known.
+func hasARN(resType TerraformResourceType) bool {
+	if strings.HasSuffix(strings.ToLower(deleteIDs[resType]), "arn") {
+		return true
+	}
+	if _, found := arnFormats[resType]; found {
+		return true
+	}
+	return hasField(rawResourceTypes[resType], arnFieldNames)
+}
+
+// fullARN returns the ARN of the resource (empty if unknown).
+func (r *Resource) fullARN() string {
+	if r.arn != "" {
+		return r.arn
+	}
+	if arn.Is(r.ID) {
+		return r.ID
+	}
+	return ""
+}
+
+// usesArns checks whether any filter entry of a resource type selects resources by ARN.
+func (f Filter) usesArns(resType TerraformResourceType) bool {
+	for _, rtf := range f.Cfg[resType] {
+		if len(rtf.Arns) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// buildArns builds the ARNs of the resources which raw resources lack their ARN,
+// if the filter selects resources of the type by ARN.
+func (f Filter) buildArns(resType TerraformResourceType, res Resources, a *AWS) error {
+	format, found := arnFormats[resType]
+	if !found || !f.usesArns(resType) || len(res) == 0 || a == nil {
+		return nil
+	}
+
+	accountID := ""
+	if !format.noAccount {
+		var err error
+		if accountID, err = a.AccountID(); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range res {
+		if r.fullARN() == "" {
+			r.arn = format.build(r.ID, a.Region, accountID)
+		}
+	}
+	return nil
+}
+
+// validateArns checks whether resources of a type can be selected by ARN and the regexes are valid.
+func validateArns(resType TerraformResourceType, arns []string) error {
+	if len(arns) == 0 {
+		return nil
+	}
+	if !hasARN(resType) {
+		return fmt.Errorf("filtering by ARN is not supported for resource type: %s", resType)
+	}
+	for _, regex := range arns {
+		if _, err := regexp.Compile(regex); err != nil {
+			return fmt.Errorf("invalid regex of arns for resource type %s: %s", resType, err)
+		}
+	}
+	return nil
+}
+
+// matchArns checks whether the ARN of a resource matches any of the regexes of a filter entry, if required.
+func (rtf ResourceTypeFilter) matchArns(r *Resource) bool {
+	if len(rtf.Arns) == 0 {
+		return true
+	}
+
+	resourceARN := r.fullARN()
+	if resourceARN == "" {
+		return false
+	}
+	for _, regex := range rtf.Arns {
+		if matched, _ := regexp.MatchString(regex, resourceARN); matched {
+			return true
+		}
+	}
+	return false
+}
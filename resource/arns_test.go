@@ -0,0 +1,82 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/resourcetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Apply_Arns(t *testing.T) {
+	// given
+	roles := []*iam.Role{
+		{RoleName: aws.String("ci"), Arn: aws.String("arn:aws:iam::123456789012:role/service-role/ci")},
+		{RoleName: aws.String("admin"), Arn: aws.String("arn:aws:iam::123456789012:role/admin")},
+	}
+	res, err := resource.DeletableResources(resource.IamRole, roles)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.IamRole: {{Arns: []string{":role/service-role/"}}},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	result := f.Apply(resource.IamRole, res, roles, nil)
+
+	// then
+	require.Len(t, result[0], 1)
+	assert.Equal(t, "ci", result[0][0].ID)
+}
+
+func TestFilter_Apply_ArnsBuiltFromIDs(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"sts": {
+			"GetCallerIdentity": map[string]interface{}{"Account": "123456789012"},
+		},
+	})
+	a.Region = "cn-north-1"
+
+	instances := []*ec2.Instance{{InstanceId: aws.String("i-1")}}
+	res, err := resource.DeletableResources(resource.Instance, instances)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {{Arns: []string{"^arn:aws-cn:ec2:cn-north-1:123456789012:instance/i-1$"}}},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	result := f.Apply(resource.Instance, res, instances, a)
+
+	// then
+	require.Len(t, result[0], 1)
+	assert.Equal(t, "i-1", result[0][0].ID)
+}
+
+func TestYamlFilter_Validate_Arns(t *testing.T) {
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.KeyPair: {{Arns: []string{"ci"}}},
+		},
+	}
+	assert.EqualError(t, f.Validate(), "filtering by ARN is not supported for resource type: aws_key_pair")
+
+	f = &resource.Filter{
+		Cfg: resource.Config{
+			resource.IamRole: {{Arns: []string{"role/("}}},
+		},
+	}
+	assert.EqualError(t, f.Validate(),
+		"invalid regex of arns for resource type aws_iam_role: error parsing regexp: missing closing ): `role/(`")
+}
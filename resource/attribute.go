@@ -0,0 +1,309 @@
+package resource
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// AgeAttribute is the name of the attribute to compare the time since the creation of resources.
+const AgeAttribute = "age"
+
+// fetchedAttribute is an attribute of a model that requires additional API calls per resource,
+// which are only made if a filter uses the attribute.
+type fetchedAttribute struct {
+	// lookup sets the attribute on the models of the given resources
+	lookup  func(a *AWS, res Resources) error
+	actions []string
+}
+
+var fetchedAttributes = map[TerraformResourceType]map[string]fetchedAttribute{
+	Elb: {
+		"healthy_instance_count": {
+			lookup:  (*AWS).elbHealthyInstanceCounts,
+			actions: []string{"elasticloadbalancing:DescribeInstanceHealth"},
+		},
+	},
+}
+
+// fetchAttributes looks up the attributes of the given resources that the filter uses, but have not been
+// listed along with the resources.
+func (f Filter) fetchAttributes(resType TerraformResourceType, res Resources, a *AWS) error {
+	if len(res) == 0 {
+		return nil
+	}
+
+	for name, attr := range fetchedAttributes[resType] {
+		used := false
+		for _, rtf := range f.Cfg[resType] {
+			if _, found := rtf.Attributes[name]; found {
+				used = true
+			}
+		}
+		if !used {
+			continue
+		}
+
+		if err := attr.lookup(a, res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// timeType is the type of model fields that are compared by the time since (like age).
+var timeType = reflect.TypeOf(time.Time{})
+
+// operators are the supported comparison operators (longest first, so that they can be matched as prefix).
+var operators = []string{">=", "<=", "!=", ">", "<", "="}
+
+// comparison is the parsed value of an attribute filter, e.g. ">100".
+type comparison struct {
+	op    string
+	value string
+}
+
+// parseComparison splits an attribute filter into its operator and value.
+// Without operator, numbers and booleans are compared for equality and strings are matched as regex.
+func parseComparison(s string) comparison {
+	s = strings.TrimSpace(s)
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return comparison{op: op, value: strings.TrimSpace(strings.TrimPrefix(s, op))}
+		}
+	}
+	return comparison{value: s}
+}
+
+// compareOrdered evaluates the comparison for the result of comparing two ordered values (-1, 0 or 1).
+func (c comparison) compareOrdered(cmp int) bool {
+	switch c.op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case "!=":
+		return cmp != 0
+	default:
+		return cmp == 0
+	}
+}
+
+// parseDuration parses a Go duration, which can also be given in days (e.g., "30d").
+func parseDuration(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.ParseFloat(strings.TrimSuffix(s, "d"), 64)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+	return time.ParseDuration(s)
+}
+
+// snakeCase converts the name of a model field to the name of the attribute (e.g., SizeGB to size_gb).
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) &&
+			(unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// modelFields returns the typed fields of the model of a resource by attribute name.
+func modelFields(m Model) map[string]reflect.Value {
+	fields := map[string]reflect.Value{}
+	if m == nil {
+		return fields
+	}
+
+	v := reflect.ValueOf(m)
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if f.Anonymous || f.PkgPath != "" {
+			continue
+		}
+		fields[snakeCase(f.Name)] = v.Field(i)
+	}
+	return fields
+}
+
+// modelOf returns the (empty) model of a resource type.
+func modelOf(resType TerraformResourceType) Model {
+	raw, found := rawResourceTypes[resType]
+	if !found || reflect.TypeOf(raw).Kind() != reflect.Struct {
+		return newModel(&Resource{}, nil)
+	}
+	return newModel(&Resource{}, reflect.New(reflect.TypeOf(raw)).Interface())
+}
+
+// validateAttributes checks whether the attributes of a filter entry exist for a resource type
+// and their values can be parsed according to the type of the attributes.
+func validateAttributes(resType TerraformResourceType, attrs map[string]string) error {
+	fields := modelFields(modelOf(resType))
+
+	for name, value := range attrs {
+		c := parseComparison(value)
+
+		if name == AgeAttribute {
+			if !TypeCapabilities(resType).Created {
+				return fmt.Errorf("filtering by age is not supported for resource type: %s", resType)
+			}
+			if _, err := parseDuration(c.value); err != nil {
+				return fmt.Errorf("invalid duration of attribute %s for resource type %s: %s", name, resType, value)
+			}
+			continue
+		}
+
+		if name == LastUsedAttribute {
+			if _, found := lastUsedLookups[resType]; !found {
+				return fmt.Errorf("filtering by last use is not supported for resource type: %s", resType)
+			}
+			if _, err := parseDuration(c.value); err != nil {
+				return fmt.Errorf("invalid duration of attribute %s for resource type %s: %s", name, resType, value)
+			}
+			continue
+		}
+
+		field, found := fields[name]
+		if !found {
+			return fmt.Errorf("unknown attribute for resource type %s: %s", resType, name)
+		}
+
+		var err error
+		switch field.Kind() {
+		case reflect.Int, reflect.Int64:
+			_, err = strconv.ParseInt(c.value, 10, 64)
+		case reflect.Bool:
+			_, err = strconv.ParseBool(c.value)
+			if c.op != "" && c.op != "=" && c.op != "!=" {
+				err = fmt.Errorf("operator %s not supported", c.op)
+			}
+		case reflect.String, reflect.Slice:
+			if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.String {
+				err = fmt.Errorf("attributes of kind %s not supported", field.Kind())
+				break
+			}
+			_, err = regexp.Compile(c.value)
+			if c.op != "" && c.op != "=" && c.op != "!=" {
+				err = fmt.Errorf("operator %s not supported", c.op)
+			}
+		case reflect.Struct:
+			if field.Type() != timeType {
+				err = fmt.Errorf("attributes of type %s not supported", field.Type())
+				break
+			}
+			if _, err = parseDuration(c.value); err != nil {
+				return fmt.Errorf("invalid duration of attribute %s for resource type %s: %s", name, resType, value)
+			}
+		default:
+			err = fmt.Errorf("attributes of kind %s not supported", field.Kind())
+		}
+		if err != nil {
+			return fmt.Errorf("invalid value of attribute %s for resource type %s: %s", name, resType, value)
+		}
+	}
+	return nil
+}
+
+// matchAttributes checks whether the attributes of a resource match all the comparisons of the filter.
+func (rtf ResourceTypeFilter) matchAttributes(r *Resource) bool {
+	if len(rtf.Attributes) == 0 {
+		return true
+	}
+
+	fields := modelFields(r.Model)
+
+	for name, value := range rtf.Attributes {
+		c := parseComparison(value)
+
+		if name == AgeAttribute || name == LastUsedAttribute {
+			since := r.Created
+			if name == LastUsedAttribute {
+				since = lastUsedOrCreated(r)
+			}
+
+			expected, err := parseDuration(c.value)
+			if err != nil || since == nil {
+				return false
+			}
+			age := time.Since(*since)
+			if !c.compareOrdered(compareInt64(int64(age), int64(expected))) {
+				return false
+			}
+			continue
+		}
+
+		field, found := fields[name]
+		if !found || !c.matches(field) {
+			return false
+		}
+	}
+	return true
+}
+
+// matches evaluates the comparison for the value of a model field.
+func (c comparison) matches(field reflect.Value) bool {
+	switch field.Kind() {
+	case reflect.Int, reflect.Int64:
+		expected, err := strconv.ParseInt(c.value, 10, 64)
+		if err != nil {
+			return false
+		}
+		return c.compareOrdered(compareInt64(field.Int(), expected))
+	case reflect.Bool:
+		expected, err := strconv.ParseBool(c.value)
+		if err != nil {
+			return false
+		}
+		return (field.Bool() == expected) == (c.op != "!=")
+	case reflect.String:
+		matched, err := regexp.MatchString(c.value, field.String())
+		if err != nil {
+			return false
+		}
+		return matched == (c.op != "!=")
+	case reflect.Slice:
+		// lists of strings (e.g., the aliases of KMS keys) match if any of their elements matches
+		matched := false
+		for i := 0; i < field.Len(); i++ {
+			if m, err := regexp.MatchString(c.value, field.Index(i).String()); err == nil && m {
+				matched = true
+			}
+		}
+		return matched == (c.op != "!=")
+	case reflect.Struct:
+		// times (e.g., of the last modification) are compared by the time since, like the age
+		t, ok := field.Interface().(time.Time)
+		expected, err := parseDuration(c.value)
+		if !ok || t.IsZero() || err != nil {
+			return false
+		}
+		return c.compareOrdered(compareInt64(int64(time.Since(t)), int64(expected)))
+	}
+	return false
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
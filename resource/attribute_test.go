@@ -0,0 +1,193 @@
+package resource_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testVolumes = []*ec2.Volume{
+	{
+		VolumeId:   aws.String("vol-small"),
+		Size:       aws.Int64(8),
+		State:      aws.String("available"),
+		CreateTime: aws.Time(time.Now().Add(-time.Hour)),
+	},
+	{
+		VolumeId:    aws.String("vol-big"),
+		Size:        aws.Int64(500),
+		State:       aws.String("in-use"),
+		CreateTime:  aws.Time(time.Now().Add(-60 * 24 * time.Hour)),
+		Attachments: []*ec2.VolumeAttachment{{InstanceId: aws.String("i-0123")}},
+	},
+}
+
+func testApplyAttributes(t *testing.T, attrs map[string]string) []string {
+	res, err := resource.DeletableResources(resource.EbsVolume, testVolumes)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.EbsVolume: {{Attributes: attrs}},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	var ids []string
+	for _, r := range f.Apply(resource.EbsVolume, res, testVolumes, nil)[0] {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestFilter_Apply_NumericAttributes(t *testing.T) {
+	assert.Equal(t, []string{"vol-big"}, testApplyAttributes(t, map[string]string{"size_gb": ">100"}))
+	assert.Equal(t, []string{"vol-small"}, testApplyAttributes(t, map[string]string{"size_gb": "<=8"}))
+	assert.Equal(t, []string{"vol-small"}, testApplyAttributes(t, map[string]string{"size_gb": "8"}))
+	assert.Equal(t, []string{"vol-big"}, testApplyAttributes(t, map[string]string{"size_gb": "!=8"}))
+}
+
+func TestFilter_Apply_StringAndBoolAttributes(t *testing.T) {
+	assert.Equal(t, []string{"vol-small"}, testApplyAttributes(t, map[string]string{"state": "^available$"}))
+	assert.Equal(t, []string{"vol-big"}, testApplyAttributes(t, map[string]string{"state": "!=available"}))
+	assert.Equal(t, []string{"vol-small"}, testApplyAttributes(t, map[string]string{"attached": "false"}))
+}
+
+func TestFilter_Apply_Age(t *testing.T) {
+	assert.Equal(t, []string{"vol-big"}, testApplyAttributes(t, map[string]string{"age": ">720h"}))
+	assert.Equal(t, []string{"vol-small"}, testApplyAttributes(t, map[string]string{"age": "<1d"}))
+	assert.Equal(t, []string{"vol-big"}, testApplyAttributes(t, map[string]string{"age": ">30d", "size_gb": ">=100"}))
+}
+
+func TestFilter_Apply_IamPath(t *testing.T) {
+	// given
+	roles := []*iam.Role{
+		{RoleName: aws.String("ci-1234"), Path: aws.String("/ephemeral/ci/")},
+		{RoleName: aws.String("ci-nightly"), Path: aws.String("/ephemeral/ci/nightly/")},
+		{RoleName: aws.String("admin"), Path: aws.String("/")},
+	}
+	res, err := resource.DeletableResources(resource.IamRole, roles)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.IamRole: {{Attributes: map[string]string{"path": "^/ephemeral/ci/"}}},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	selected := f.Apply(resource.IamRole, res, roles, nil)[0]
+
+	// then
+	require.Len(t, selected, 2)
+	assert.Equal(t, "ci-1234", selected[0].ID)
+	assert.Equal(t, "ci-nightly", selected[1].ID)
+}
+
+func TestFilter_Apply_TimeAttributes(t *testing.T) {
+	// given
+	parameters := []*ssm.ParameterMetadata{
+		{Name: aws.String("/ci/stale"), LastModifiedDate: aws.Time(time.Now().Add(-100 * 24 * time.Hour))},
+		{Name: aws.String("/ci/fresh"), LastModifiedDate: aws.Time(time.Now().Add(-time.Hour))},
+		{Name: aws.String("/prod/stale"), LastModifiedDate: aws.Time(time.Now().Add(-100 * 24 * time.Hour))},
+	}
+	res, err := resource.DeletableResources(resource.SsmParameter, parameters)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.SsmParameter: {{ID: aws.String("^/ci/"), Attributes: map[string]string{"last_modified": ">90d"}}},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	selected := f.Apply(resource.SsmParameter, res, parameters, nil)[0]
+
+	// then
+	require.Len(t, selected, 1)
+	assert.Equal(t, "/ci/stale", selected[0].ID)
+}
+
+func TestYamlFilter_Validate_InvalidAttributes(t *testing.T) {
+	tests := []struct {
+		resType resource.TerraformResourceType
+		attrs   map[string]string
+		err     string
+	}{
+		{resource.EbsVolume, map[string]string{"foo": "1"},
+			"unknown attribute for resource type aws_ebs_volume: foo"},
+		{resource.EbsVolume, map[string]string{"size_gb": ">big"},
+			"invalid value of attribute size_gb for resource type aws_ebs_volume: >big"},
+		{resource.EbsVolume, map[string]string{"attached": ">true"},
+			"invalid value of attribute attached for resource type aws_ebs_volume: >true"},
+		{resource.EbsVolume, map[string]string{"age": ">1month"},
+			"invalid duration of attribute age for resource type aws_ebs_volume: >1month"},
+		{resource.SsmParameter, map[string]string{"last_modified": ">soon"},
+			"invalid duration of attribute last_modified for resource type aws_ssm_parameter: >soon"},
+		{resource.KeyPair, map[string]string{"age": ">1d"},
+			"filtering by age is not supported for resource type: aws_key_pair"},
+	}
+
+	for _, tc := range tests {
+		f := &resource.Filter{
+			Cfg: resource.Config{tc.resType: {{Attributes: tc.attrs}}},
+		}
+
+		assert.EqualError(t, f.Validate(), tc.err)
+	}
+}
+
+func TestFilter_Apply_FetchedAttributes(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockELBAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		ELBAPI: mockObj,
+	}
+
+	elbs := []*elb.LoadBalancerDescription{
+		{LoadBalancerName: aws.String("healthy"), Instances: []*elb.Instance{{InstanceId: aws.String("i-1")}}},
+		{LoadBalancerName: aws.String("unhealthy"), Instances: []*elb.Instance{{InstanceId: aws.String("i-2")}}},
+	}
+	res, err := resource.DeletableResources(resource.Elb, elbs)
+	require.NoError(t, err)
+
+	mockObj.EXPECT().DescribeInstanceHealth(&elb.DescribeInstanceHealthInput{
+		LoadBalancerName: aws.String("healthy"),
+	}).Return(&elb.DescribeInstanceHealthOutput{
+		InstanceStates: []*elb.InstanceState{{InstanceId: aws.String("i-1"), State: aws.String("InService")}},
+	}, nil)
+	mockObj.EXPECT().DescribeInstanceHealth(&elb.DescribeInstanceHealthInput{
+		LoadBalancerName: aws.String("unhealthy"),
+	}).Return(&elb.DescribeInstanceHealthOutput{
+		InstanceStates: []*elb.InstanceState{{InstanceId: aws.String("i-2"), State: aws.String("OutOfService")}},
+	}, nil)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Elb: {{Attributes: map[string]string{"instance_count": ">0", "healthy_instance_count": "0"}}},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	result := f.Apply(resource.Elb, res, elbs, awsMock)
+
+	// then
+	require.Len(t, result[0], 1)
+	assert.Equal(t, "unhealthy", result[0][0].ID)
+}
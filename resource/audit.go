@@ -0,0 +1,308 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// AuditConfig configures where the result of every run that deletes resources is stored,
+// so that runs can be reported on later (see AggregateWeekly).
+type AuditConfig struct {
+	// Bucket is the name of the S3 bucket the results of runs are written to.
+	Bucket string `yaml:",omitempty"`
+	// Prefix is prepended to the keys of the results (e.g., awsweeper/).
+	Prefix string `yaml:",omitempty"`
+}
+
+// validate checks if the audit config is valid (a nil config is valid, as no results are written at all).
+func (c *AuditConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if c.Bucket == "" {
+		return fmt.Errorf("audit requires a bucket")
+	}
+	return nil
+}
+
+// runsPrefix is the prefix of the keys of all run results.
+func (c *AuditConfig) runsPrefix() string {
+	return c.Prefix + "runs/"
+}
+
+// key is the key of the result of a run started at the given time. Keys sort in the order runs have been started.
+func (c *AuditConfig) key(start time.Time) string {
+	return c.runsPrefix() + start.UTC().Format(time.RFC3339) + ".json"
+}
+
+// RunResult is the outcome of a run that deleted resources.
+type RunResult struct {
+	Account string    `json:"account"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Regions []string  `json:"regions"`
+	// Deleted are the numbers of deleted resources per type.
+	Deleted map[TerraformResourceType]int `json:"deleted,omitempty"`
+	// Failures are the numbers of resources that failed to be deleted per class of error.
+	Failures map[ErrorClass]int `json:"failures,omitempty"`
+}
+
+// WriteRunResult stores the result of a run in the audit bucket.
+func (a *AWS) WriteRunResult(cfg *AuditConfig, result RunResult) error {
+	body, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	key := cfg.key(result.Start)
+	_, err = a.PutObject(&s3.PutObjectInput{
+		Bucket:      aws.String(cfg.Bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String("application/json"),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to write run result to s3://%s/%s", cfg.Bucket, key)
+	}
+	return nil
+}
+
+// RunResults reads the results of the runs started since the given time from the audit bucket
+// (sorted by the time runs have been started).
+func (a *AWS) RunResults(cfg *AuditConfig, since time.Time) ([]RunResult, error) {
+	var keys []string
+	err := a.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket:     aws.String(cfg.Bucket),
+		Prefix:     aws.String(cfg.runsPrefix()),
+		StartAfter: aws.String(cfg.key(since.Add(-time.Second))),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, o := range page.Contents {
+			keys = append(keys, aws.StringValue(o.Key))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list run results in s3://%s/%s", cfg.Bucket, cfg.runsPrefix())
+	}
+
+	var results []RunResult
+	for _, key := range keys {
+		result, err := a.runResult(cfg.Bucket, key)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Start.Before(results[j].Start)
+	})
+	return results, nil
+}
+
+func (a *AWS) runResult(bucket, key string) (RunResult, error) {
+	var result RunResult
+
+	output, err := a.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return result, errors.Wrapf(err, "failed to read run result s3://%s/%s", bucket, key)
+	}
+	defer output.Body.Close()
+
+	if err := json.NewDecoder(output.Body).Decode(&result); err != nil {
+		return result, errors.Wrapf(err, "invalid run result s3://%s/%s", bucket, key)
+	}
+	return result, nil
+}
+
+// WeeklyStats are the aggregated results of the runs started in a week.
+type WeeklyStats struct {
+	// Week is the start of the week (Monday, 00:00 UTC).
+	Week     time.Time
+	Runs     int
+	Deleted  map[TerraformResourceType]int
+	Failures map[ErrorClass]int
+}
+
+// TotalDeleted returns the number of resources deleted in the week.
+func (s WeeklyStats) TotalDeleted() int {
+	var total int
+	for _, n := range s.Deleted {
+		total += n
+	}
+	return total
+}
+
+// TotalFailures returns the number of resources that failed to be deleted in the week.
+func (s WeeklyStats) TotalFailures() int {
+	var total int
+	for _, n := range s.Failures {
+		total += n
+	}
+	return total
+}
+
+// WeekStart returns the start of the week (Monday, 00:00 UTC) a point in time falls into.
+func WeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+}
+
+// AggregateWeekly sums up the results of runs per week (sorted by week). Weeks without runs are left out.
+func AggregateWeekly(results []RunResult) []WeeklyStats {
+	byWeek := map[time.Time]*WeeklyStats{}
+	var weeks []time.Time
+
+	for _, r := range results {
+		week := WeekStart(r.Start)
+		s, found := byWeek[week]
+		if !found {
+			s = &WeeklyStats{
+				Week:     week,
+				Deleted:  map[TerraformResourceType]int{},
+				Failures: map[ErrorClass]int{},
+			}
+			byWeek[week] = s
+			weeks = append(weeks, week)
+		}
+
+		s.Runs++
+		for resType, n := range r.Deleted {
+			s.Deleted[resType] += n
+		}
+		for class, n := range r.Failures {
+			s.Failures[class] += n
+		}
+	}
+
+	sort.Slice(weeks, func(i, j int) bool { return weeks[i].Before(weeks[j]) })
+
+	stats := make([]WeeklyStats, 0, len(weeks))
+	for _, week := range weeks {
+		stats = append(stats, *byWeek[week])
+	}
+	return stats
+}
+
+// FormatWeeklyStats returns the weekly stats as human-readable text.
+func FormatWeeklyStats(stats []WeeklyStats) string {
+	var b strings.Builder
+	for _, s := range stats {
+		fmt.Fprintf(&b, "Week: %s\nRuns: %d\nDeleted: %d\nFailed: %d", s.Week.Format("2006-01-02"), s.Runs,
+			s.TotalDeleted(), s.TotalFailures())
+
+		var classes []string
+		for _, c := range sortedFailures(s) {
+			classes = append(classes, fmt.Sprintf("%s: %d", c.Name, c.Count))
+		}
+		if len(classes) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(classes, ", "))
+		}
+		b.WriteString("\n\n")
+
+		for _, c := range sortedDeleted(s) {
+			fmt.Fprintf(&b, "\t%s\t%d\n", c.Name, c.Count)
+		}
+		if len(s.Deleted) > 0 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// count is the number of resources deleted of a type, or failed to be deleted with a class of error.
+type count struct {
+	Name  string
+	Count int
+}
+
+// sortedDeleted returns the deleted resources per type (sorted by type).
+func sortedDeleted(s WeeklyStats) []count {
+	var counts []count
+	for resType, n := range s.Deleted {
+		counts = append(counts, count{Name: string(resType), Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i].Name < counts[j].Name })
+	return counts
+}
+
+// sortedFailures returns the failed deletions per class of error (sorted by class).
+func sortedFailures(s WeeklyStats) []count {
+	var counts []count
+	for class, n := range s.Failures {
+		counts = append(counts, count{Name: string(class), Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i].Name < counts[j].Name })
+	return counts
+}
+
+var weeklyStatsTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"deleted":  sortedDeleted,
+	"failures": sortedFailures,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>AWSweeper weekly report</title>
+</head>
+<body>
+<h1>AWSweeper weekly report</h1>
+<table>
+<tr><th>Week</th><th>Runs</th><th>Deleted</th><th>Failed</th></tr>
+{{- range .}}
+<tr><td>{{.Week.Format "2006-01-02"}}</td><td>{{.Runs}}</td><td>{{.TotalDeleted}}</td><td>{{.TotalFailures}}</td></tr>
+{{- end}}
+</table>
+{{- range .}}
+<h2>Week of {{.Week.Format "2006-01-02"}}</h2>
+<table>
+<tr><th>Resource type</th><th>Deleted</th></tr>
+{{- range deleted .}}
+<tr><td>{{.Name}}</td><td>{{.Count}}</td></tr>
+{{- end}}
+</table>
+{{- with failures .}}
+<table>
+<tr><th>Error</th><th>Failed</th></tr>
+{{- range .}}
+<tr><td>{{.Name}}</td><td>{{.Count}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// RenderWeeklyStatsHTML writes the weekly stats as an HTML page.
+func RenderWeeklyStatsHTML(w io.Writer, stats []WeeklyStats) error {
+	return weeklyStatsTemplate.Execute(w, stats)
+}
+
+// WriteWeeklyReport writes the weekly stats as an HTML page to a file.
+func WriteWeeklyReport(filename string, stats []WeeklyStats) error {
+	var b bytes.Buffer
+	if err := RenderWeeklyStatsHTML(&b, stats); err != nil {
+		return err
+	}
+	if err := afero.WriteFile(AppFs, filename, b.Bytes(), 0644); err != nil {
+		return errors.Wrapf(err, "failed to write weekly report: %s", filename)
+	}
+	return nil
+}
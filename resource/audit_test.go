@@ -0,0 +1,139 @@
+package resource_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWS_RunResults(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockS3API(mockCtrl)
+	awsMock := &resource.AWS{
+		S3API: mockObj,
+	}
+
+	objects := map[string][]byte{}
+	mockObj.EXPECT().PutObject(gomock.Any()).DoAndReturn(
+		func(input *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+			assert.Equal(t, "audit", *input.Bucket)
+			body, err := ioutil.ReadAll(input.Body)
+			require.NoError(t, err)
+			objects[*input.Key] = body
+			return &s3.PutObjectOutput{}, nil
+		}).Times(3)
+	mockObj.EXPECT().ListObjectsV2Pages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(input *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+			var keys []string
+			for key := range objects {
+				if strings.HasPrefix(key, *input.Prefix) && key > *input.StartAfter {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+
+			page := &s3.ListObjectsV2Output{}
+			for _, key := range keys {
+				page.Contents = append(page.Contents, &s3.Object{Key: aws.String(key)})
+			}
+			fn(page, true)
+			return nil
+		})
+	mockObj.EXPECT().GetObject(gomock.Any()).DoAndReturn(
+		func(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+			return &s3.GetObjectOutput{Body: ioutil.NopCloser(bytes.NewReader(objects[*input.Key]))}, nil
+		}).Times(2)
+
+	cfg := &resource.AuditConfig{Bucket: "audit", Prefix: "prod/"}
+	for _, start := range []time.Time{
+		time.Date(2018, 11, 5, 5, 0, 0, 0, time.UTC),
+		time.Date(2018, 11, 12, 5, 0, 0, 0, time.UTC),
+		time.Date(2018, 11, 13, 5, 0, 0, 0, time.UTC),
+	} {
+		require.NoError(t, awsMock.WriteRunResult(cfg, resource.RunResult{
+			Account: "123456789012",
+			Start:   start,
+			End:     start.Add(10 * time.Minute),
+			Regions: []string{"us-west-2"},
+			Deleted: map[resource.TerraformResourceType]int{resource.Instance: 2},
+		}))
+	}
+	assert.Contains(t, objects, "prod/runs/2018-11-12T05:00:00Z.json")
+
+	// when
+	results, err := awsMock.RunResults(cfg, time.Date(2018, 11, 12, 5, 0, 0, 0, time.UTC))
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, time.Date(2018, 11, 12, 5, 0, 0, 0, time.UTC), results[0].Start.UTC())
+	assert.Equal(t, 2, results[0].Deleted[resource.Instance])
+	assert.Equal(t, "123456789012", results[1].Account)
+}
+
+func TestAggregateWeekly(t *testing.T) {
+	// given
+	results := []resource.RunResult{
+		{
+			Start:   time.Date(2018, 11, 12, 5, 0, 0, 0, time.UTC), // Monday
+			Deleted: map[resource.TerraformResourceType]int{resource.Instance: 2, resource.EbsVolume: 1},
+		},
+		{
+			Start:    time.Date(2018, 11, 18, 23, 0, 0, 0, time.UTC), // Sunday
+			Deleted:  map[resource.TerraformResourceType]int{resource.Instance: 3},
+			Failures: map[resource.ErrorClass]int{resource.ErrorInUse: 1},
+		},
+		{
+			Start: time.Date(2018, 11, 5, 5, 0, 0, 0, time.UTC),
+		},
+	}
+
+	// when
+	stats := resource.AggregateWeekly(results)
+
+	// then
+	require.Len(t, stats, 2)
+	assert.Equal(t, time.Date(2018, 11, 5, 0, 0, 0, 0, time.UTC), stats[0].Week)
+	assert.Equal(t, 1, stats[0].Runs)
+	assert.Equal(t, 0, stats[0].TotalDeleted())
+
+	assert.Equal(t, time.Date(2018, 11, 12, 0, 0, 0, 0, time.UTC), stats[1].Week)
+	assert.Equal(t, 2, stats[1].Runs)
+	assert.Equal(t, 6, stats[1].TotalDeleted())
+	assert.Equal(t, 5, stats[1].Deleted[resource.Instance])
+	assert.Equal(t, 1, stats[1].TotalFailures())
+
+	assert.Equal(t, "Week: 2018-11-05\nRuns: 1\nDeleted: 0\nFailed: 0\n\n"+
+		"Week: 2018-11-12\nRuns: 2\nDeleted: 6\nFailed: 1 (in use: 1)\n\n"+
+		"\taws_ebs_volume\t1\n\taws_instance\t5\n\n",
+		resource.FormatWeeklyStats(stats))
+
+	var html bytes.Buffer
+	require.NoError(t, resource.RenderWeeklyStatsHTML(&html, stats))
+	assert.Contains(t, html.String(), "<tr><td>2018-11-12</td><td>2</td><td>6</td><td>1</td></tr>")
+	assert.Contains(t, html.String(), "<tr><td>in use</td><td>1</td></tr>")
+}
+
+func TestWeekStart(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+
+	assert.Equal(t, time.Date(2018, 11, 12, 0, 0, 0, 0, time.UTC),
+		resource.WeekStart(time.Date(2018, 11, 12, 0, 0, 0, 0, time.UTC)))
+	// still Sunday in UTC
+	assert.Equal(t, time.Date(2018, 11, 5, 0, 0, 0, 0, time.UTC),
+		resource.WeekStart(time.Date(2018, 11, 12, 1, 0, 0, 0, loc)))
+}
@@ -0,0 +1,100 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// BaselineConfig configures the resources that are expected to remain after a sweep
+// (e.g., the resources created by the Terraform module that bootstraps an account).
+type BaselineConfig struct {
+	// TerraformStates are files with Terraform states, which resources are part of the baseline
+	TerraformStates []string `yaml:"terraform_states,omitempty"`
+	// Resources are regexes of the IDs of the resources that are part of the baseline per type
+	Resources map[TerraformResourceType][]string `yaml:",omitempty"`
+}
+
+func (c *BaselineConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+
+	for resType, ids := range c.Resources {
+		if !SupportedResourceType(resType) {
+			return fmt.Errorf("unsupported resource type in baseline: %s", resType)
+		}
+		for _, id := range ids {
+			if _, err := regexp.Compile(id); err != nil {
+				return fmt.Errorf("invalid id regex in baseline for resource type %s: %s", resType, id)
+			}
+		}
+	}
+	return nil
+}
+
+// Baseline are the resources that are expected to remain after a sweep.
+type Baseline struct {
+	// resources are the resources of the Terraform states as <type>/<id>
+	resources map[string]bool
+	ids       map[TerraformResourceType][]*regexp.Regexp
+}
+
+// LoadBaseline reads the Terraform states of the baseline.
+func (c *BaselineConfig) LoadBaseline() (*Baseline, error) {
+	b := &Baseline{
+		resources: map[string]bool{},
+		ids:       map[TerraformResourceType][]*regexp.Regexp{},
+	}
+
+	for _, filename := range c.TerraformStates {
+		data, err := afero.ReadFile(AppFs, filename)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read Terraform state of baseline: %s", filename)
+		}
+
+		var state terraformState
+		if err := json.Unmarshal(data, &state); err != nil {
+			return nil, errors.Wrapf(err, "cannot unmarshal Terraform state of baseline: %s", filename)
+		}
+		for _, key := range state.resources() {
+			b.resources[key] = true
+		}
+	}
+
+	for resType, ids := range c.Resources {
+		for _, id := range ids {
+			b.ids[resType] = append(b.ids[resType], regexp.MustCompile(id))
+		}
+	}
+	return b, nil
+}
+
+// contains checks whether a resource is part of the baseline.
+func (b *Baseline) contains(r *Resource) bool {
+	if b.resources[string(r.Type)+"/"+r.ID] {
+		return true
+	}
+	for _, id := range b.ids[r.Type] {
+		if id.MatchString(r.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+// Drift returns the resources that are not part of the baseline. Resources that AWS creates by default
+// (e.g., default VPCs) are expected to remain and never drift.
+func (b *Baseline) Drift(res Resources) Resources {
+	result := Resources{}
+
+	for _, r := range res {
+		if !r.Default && !b.contains(r) {
+			result = append(result, r)
+		}
+	}
+	return result
+}
@@ -0,0 +1,77 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseline_Drift(t *testing.T) {
+	// given
+	resource.AppFs = afero.NewMemMapFs()
+	afero.WriteFile(resource.AppFs, "bootstrap.tfstate", []byte(`{"version": 4, "resources": [
+		{"mode": "managed", "type": "aws_vpc", "instances": [{"attributes": {"id": "vpc-bootstrap"}}]},
+		{"mode": "data", "type": "aws_iam_role", "instances": [{"attributes": {"id": "admin"}}]}
+	]}`), 0644)
+
+	cfg := &resource.BaselineConfig{
+		TerraformStates: []string{"bootstrap.tfstate"},
+		Resources: map[resource.TerraformResourceType][]string{
+			resource.IamRole: {"^OrganizationAccountAccessRole$"},
+		},
+	}
+	f := &resource.Filter{Cfg: resource.Config{}, Baseline: cfg}
+	require.NoError(t, f.Validate())
+
+	baseline, err := cfg.LoadBaseline()
+	require.NoError(t, err)
+
+	res := resource.Resources{
+		{Type: resource.Vpc, ID: "vpc-bootstrap"},
+		{Type: resource.Vpc, ID: "vpc-default", Default: true},
+		{Type: resource.Vpc, ID: "vpc-leftover"},
+		{Type: resource.IamRole, ID: "OrganizationAccountAccessRole"},
+		{Type: resource.IamRole, ID: "admin"},
+	}
+
+	// when
+	drifted := baseline.Drift(res)
+
+	// then
+	var ids []string
+	for _, r := range drifted {
+		ids = append(ids, r.ID)
+	}
+	assert.Equal(t, []string{"vpc-leftover", "admin"}, ids)
+}
+
+func TestBaselineConfig_LoadBaseline_MissingState(t *testing.T) {
+	// given
+	resource.AppFs = afero.NewMemMapFs()
+	cfg := &resource.BaselineConfig{TerraformStates: []string{"missing.tfstate"}}
+
+	// when
+	_, err := cfg.LoadBaseline()
+
+	// then
+	assert.Error(t, err)
+}
+
+func TestFilter_Validate_BaselineUnsupportedType(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{},
+		Baseline: &resource.BaselineConfig{
+			Resources: map[resource.TerraformResourceType][]string{"aws_foo": {"^bar$"}},
+		},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.EqualError(t, err, "unsupported resource type in baseline: aws_foo")
+}
@@ -0,0 +1,306 @@
+package resource
+
+import (
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/redshift"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/pkg/errors"
+)
+
+// batchDeleter deletes resources of a particular type via a bulk delete API,
+// which is much faster and issues far fewer API calls than deleting them one by one.
+type batchDeleter struct {
+	// size is the maximum number of resources that can be deleted with a single API call
+	size int
+	// delete deletes a batch of resources given by their IDs
+	delete func(a *AWS, ids []string) error
+}
+
+// batchDeleters are the resource types that are deleted via a bulk delete API instead of the Terraform provider.
+var batchDeleters = map[TerraformResourceType]batchDeleter{
+	CloudwatchDashboard: {
+		size: 100,
+		delete: func(a *AWS, ids []string) error {
+			_, err := a.DeleteDashboards(&cloudwatch.DeleteDashboardsInput{DashboardNames: aws.StringSlice(ids)})
+			return err
+		},
+	},
+	CloudwatchMetricAlarm: {
+		size: 100,
+		delete: func(a *AWS, ids []string) error {
+			_, err := a.DeleteAlarms(&cloudwatch.DeleteAlarmsInput{AlarmNames: aws.StringSlice(ids)})
+			return err
+		},
+	},
+	// the Terraform AWS provider has no resource for Redshift snapshots
+	RedshiftSnapshot: {
+		size: 1,
+		delete: func(a *AWS, ids []string) error {
+			_, err := a.DeleteClusterSnapshot(&redshift.DeleteClusterSnapshotInput{SnapshotIdentifier: aws.String(ids[0])})
+			return err
+		},
+	},
+	// parameters that don't exist anymore are returned as invalid parameters, which is no error
+	SsmParameter: {
+		size: 10,
+		delete: func(a *AWS, ids []string) error {
+			_, err := a.DeleteParameters(&ssm.DeleteParametersInput{Names: aws.StringSlice(ids)})
+			return err
+		},
+	},
+}
+
+// maxThrottleRetries is how often a throttled batch request is retried.
+const maxThrottleRetries = 8
+
+// route53ChangeBatchSize is the maximum number of record sets deleted with a single change batch.
+const route53ChangeBatchSize = 100
+
+// emptiers are the resource types which contents (objects of buckets, record sets of hosted zones, targets
+// registered with target groups) are deleted via a bulk API before the resources themselves are deleted via the
+// Terraform provider. The provider deletes objects and record sets page by page without retrying throttled requests
+// (and waits for every page of deleted record sets to be in sync), and doesn't deregister targets at all.
+var emptiers = map[TerraformResourceType]func(a *AWS, id string) error{
+	LbTargetGroup: (*AWS).deregisterTargets,
+	Route53Zone:   (*AWS).emptyHostedZone,
+	S3Bucket:      (*AWS).emptyBucket,
+}
+
+// SupportsBatchDelete checks whether resources of a type are deleted via a bulk delete API.
+func SupportsBatchDelete(resType TerraformResourceType) bool {
+	_, found := batchDeleters[resType]
+	return found
+}
+
+// BatchDelete deletes the given resources (of the same type) in chunks via a bulk delete API.
+// It returns the IDs of the resources that couldn't be deleted together with the error.
+func (a *AWS) BatchDelete(res Resources) map[string]error {
+	failed := map[string]error{}
+	if len(res) == 0 {
+		return failed
+	}
+
+	d, found := batchDeleters[res[0].Type]
+	if !found {
+		for _, r := range res {
+			failed[r.ID] = errors.Errorf("batch deletion not supported for resource type: %s", r.Type)
+		}
+		return failed
+	}
+
+	var ids []string
+	for _, r := range res {
+		ids = append(ids, r.ID)
+	}
+
+	for _, chunk := range chunks(ids, d.size) {
+		err := retryThrottled(func() error {
+			return d.delete(a, chunk)
+		})
+		if err != nil {
+			for _, id := range chunk {
+				failed[id] = err
+			}
+		}
+	}
+	return failed
+}
+
+// SupportsEmpty checks whether the contents of resources of a type are deleted via a bulk delete API
+// before the resources are deleted.
+func SupportsEmpty(resType TerraformResourceType) bool {
+	_, found := emptiers[resType]
+	return found
+}
+
+// Empty deletes the contents of a resource (e.g., all objects of a bucket) in chunks via a bulk delete API.
+func (a *AWS) Empty(r *Resource) error {
+	empty, found := emptiers[r.Type]
+	if !found {
+		return errors.Errorf("emptying not supported for resource type: %s", r.Type)
+	}
+	return errors.Wrapf(empty(a, r.ID), "failed to empty %s", r.ID)
+}
+
+// emptyBucket deletes all versions and delete markers of objects in a bucket, a page (of up to 1000) at a time,
+// which is the maximum number of objects deleted with a single request.
+func (a *AWS) emptyBucket(bucket string) error {
+	var deleteErr error
+	err := a.ListObjectVersionsPages(&s3.ListObjectVersionsInput{Bucket: aws.String(bucket)},
+		func(page *s3.ListObjectVersionsOutput, lastPage bool) bool {
+			var objects []*s3.ObjectIdentifier
+			for _, v := range page.Versions {
+				objects = append(objects, &s3.ObjectIdentifier{Key: v.Key, VersionId: v.VersionId})
+			}
+			for _, m := range page.DeleteMarkers {
+				objects = append(objects, &s3.ObjectIdentifier{Key: m.Key, VersionId: m.VersionId})
+			}
+			if len(objects) == 0 {
+				return true
+			}
+
+			deleteErr = retryThrottled(func() error {
+				output, err := a.DeleteObjects(&s3.DeleteObjectsInput{
+					Bucket: aws.String(bucket),
+					Delete: &s3.Delete{Objects: objects, Quiet: aws.Bool(true)},
+				})
+				if err != nil {
+					return err
+				}
+				if len(output.Errors) > 0 {
+					e := output.Errors[0]
+					return errors.Errorf("failed to delete %d objects (e.g., %s: %s)",
+						len(output.Errors), aws.StringValue(e.Key), aws.StringValue(e.Message))
+				}
+				return nil
+			})
+			return deleteErr == nil
+		})
+	if err != nil {
+		return err
+	}
+	return deleteErr
+}
+
+// emptyHostedZone deletes all record sets of a hosted zone except for the NS and SOA records of the zone itself,
+// which can't be deleted, and waits until the change batches are in sync, as the Terraform provider does.
+func (a *AWS) emptyHostedZone(id string) error {
+	zoneID := aws.String(strings.TrimPrefix(id, "/hostedzone/"))
+
+	var sets []*route53.ResourceRecordSet
+	err := a.ListResourceRecordSetsPages(&route53.ListResourceRecordSetsInput{HostedZoneId: zoneID},
+		func(page *route53.ListResourceRecordSetsOutput, lastPage bool) bool {
+			sets = append(sets, page.ResourceRecordSets...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// a zone has a single SOA record, named like the zone
+	zoneName := ""
+	for _, set := range sets {
+		if aws.StringValue(set.Type) == route53.RRTypeSoa {
+			zoneName = aws.StringValue(set.Name)
+		}
+	}
+
+	var changes []*route53.Change
+	for _, set := range sets {
+		t := aws.StringValue(set.Type)
+		if aws.StringValue(set.Name) == zoneName && (t == route53.RRTypeNs || t == route53.RRTypeSoa) {
+			continue
+		}
+		changes = append(changes, &route53.Change{
+			Action:            aws.String(route53.ChangeActionDelete),
+			ResourceRecordSet: set,
+		})
+	}
+
+	var changeIDs []*string
+	for len(changes) > 0 {
+		n := route53ChangeBatchSize
+		if n > len(changes) {
+			n = len(changes)
+		}
+		batch := changes[:n]
+		changes = changes[n:]
+
+		err := retryThrottled(func() error {
+			output, err := a.ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+				HostedZoneId: zoneID,
+				ChangeBatch:  &route53.ChangeBatch{Changes: batch},
+			})
+			if err != nil {
+				return err
+			}
+			if output.ChangeInfo != nil {
+				changeIDs = append(changeIDs, output.ChangeInfo.Id)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// the batches are in sync at about the same time, so that all but the first wait return immediately
+	for _, id := range changeIDs {
+		if err := a.WaitUntilResourceRecordSetsChanged(&route53.GetChangeInput{Id: id}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deregisterTargets deregisters all targets of a target group with a single request,
+// which starts draining their connections (the deletion of the target group doesn't wait for it).
+func (a *AWS) deregisterTargets(arn string) error {
+	var output *elbv2.DescribeTargetHealthOutput
+	err := retryThrottled(func() error {
+		var err error
+		output, err = a.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{TargetGroupArn: aws.String(arn)})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	var targets []*elbv2.TargetDescription
+	for _, d := range output.TargetHealthDescriptions {
+		targets = append(targets, d.Target)
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	return retryThrottled(func() error {
+		_, err := a.DeregisterTargets(&elbv2.DeregisterTargetsInput{
+			TargetGroupArn: aws.String(arn),
+			Targets:        targets,
+		})
+		return err
+	})
+}
+
+// chunks splits a list of IDs into chunks of the given maximum size.
+func chunks(ids []string, size int) [][]string {
+	var result [][]string
+
+	for size < len(ids) {
+		ids, result = ids[size:], append(result, ids[0:size:size])
+	}
+	if len(ids) > 0 {
+		result = append(result, ids)
+	}
+	return result
+}
+
+// retryThrottled calls fn until it succeeds, fails with an error other than throttling,
+// or the maximum number of retries is reached. The wait time between retries doubles with every retry.
+func retryThrottled(fn func() error) error {
+	wait := 500 * time.Millisecond
+
+	var err error
+	for i := 0; i <= maxThrottleRetries; i++ {
+		err = fn()
+		if !isThrottled(err) {
+			return err
+		}
+		time.Sleep(wait)
+		wait *= 2
+	}
+	return err
+}
+
+// isThrottled checks whether an error has been caused by exceeding the request rate of an API.
+func isThrottled(err error) bool {
+	return err != nil && ClassifyError(err) == ErrorThrottled
+}
@@ -0,0 +1,282 @@
+package resource_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/cloudetc/awsweeper/resource/resourcetest"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWS_Tag_Batches(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockEC2API(mockCtrl)
+	awsMock := &resource.AWS{
+		EC2API: mockObj,
+	}
+
+	res := resource.Resources{}
+	for i := 0; i < 250; i++ {
+		res = append(res, &resource.Resource{
+			Type: resource.EbsSnapshot,
+			ID:   "snap",
+		})
+	}
+
+	var batchSizes []int
+	mockObj.EXPECT().CreateTags(gomock.Any()).DoAndReturn(func(input *ec2.CreateTagsInput) (*ec2.CreateTagsOutput, error) {
+		batchSizes = append(batchSizes, len(input.Resources))
+		return &ec2.CreateTagsOutput{}, nil
+	}).Times(3)
+
+	// when
+	err := awsMock.Tag(res, map[string]string{"owner": "foo"})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []int{100, 100, 50}, batchSizes)
+}
+
+func TestAWS_BatchDelete_Unsupported(t *testing.T) {
+	// given
+	awsMock := &resource.AWS{}
+	res := resource.Resources{
+		{
+			Type: resource.Instance,
+			ID:   testInstanceID,
+		},
+	}
+
+	// when
+	failed := awsMock.BatchDelete(res)
+
+	// then
+	assert.False(t, resource.SupportsBatchDelete(resource.Instance))
+	require.Len(t, failed, 1)
+	assert.EqualError(t, failed[testInstanceID], "batch deletion not supported for resource type: aws_instance")
+}
+
+func TestAWS_BatchDelete_MetricAlarms(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockCloudWatchAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		CloudWatchAPI: mockObj,
+	}
+
+	res := resource.Resources{}
+	for i := 0; i < 150; i++ {
+		res = append(res, &resource.Resource{
+			Type: resource.CloudwatchMetricAlarm,
+			ID:   fmt.Sprintf("alarm-%d", i),
+		})
+	}
+
+	var batchSizes []int
+	mockObj.EXPECT().DeleteAlarms(gomock.Any()).DoAndReturn(func(input *cloudwatch.DeleteAlarmsInput) (*cloudwatch.DeleteAlarmsOutput, error) {
+		batchSizes = append(batchSizes, len(input.AlarmNames))
+		return &cloudwatch.DeleteAlarmsOutput{}, nil
+	}).Times(2)
+
+	// when
+	failed := awsMock.BatchDelete(res)
+
+	// then
+	assert.True(t, resource.SupportsBatchDelete(resource.CloudwatchMetricAlarm))
+	assert.Empty(t, failed)
+	assert.Equal(t, []int{100, 50}, batchSizes)
+}
+
+func TestAWS_BatchDelete_SsmParameters(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockSSMAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		SSMAPI: mockObj,
+	}
+
+	res := resource.Resources{}
+	for i := 0; i < 25; i++ {
+		res = append(res, &resource.Resource{
+			Type: resource.SsmParameter,
+			ID:   fmt.Sprintf("/ci/parameter-%d", i),
+		})
+	}
+
+	var batchSizes []int
+	mockObj.EXPECT().DeleteParameters(gomock.Any()).DoAndReturn(func(input *ssm.DeleteParametersInput) (*ssm.DeleteParametersOutput, error) {
+		batchSizes = append(batchSizes, len(input.Names))
+		return &ssm.DeleteParametersOutput{}, nil
+	}).Times(3)
+
+	// when
+	failed := awsMock.BatchDelete(res)
+
+	// then
+	assert.True(t, resource.SupportsBatchDelete(resource.SsmParameter))
+	assert.Empty(t, failed)
+	assert.Equal(t, []int{10, 10, 5}, batchSizes)
+}
+
+func TestAWS_Empty_S3Bucket(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockS3API(mockCtrl)
+	awsMock := &resource.AWS{
+		S3API: mockObj,
+	}
+
+	mockObj.EXPECT().ListObjectVersionsPages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(input *s3.ListObjectVersionsInput, fn func(*s3.ListObjectVersionsOutput, bool) bool) error {
+			fn(&s3.ListObjectVersionsOutput{
+				Versions:      []*s3.ObjectVersion{{Key: aws.String("a"), VersionId: aws.String("1")}},
+				DeleteMarkers: []*s3.DeleteMarkerEntry{{Key: aws.String("b"), VersionId: aws.String("2")}},
+			}, false)
+			fn(&s3.ListObjectVersionsOutput{
+				Versions: []*s3.ObjectVersion{{Key: aws.String("c"), VersionId: aws.String("3")}},
+			}, true)
+			return nil
+		})
+
+	var batchSizes []int
+	mockObj.EXPECT().DeleteObjects(gomock.Any()).DoAndReturn(func(input *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+		assert.Equal(t, "foo", aws.StringValue(input.Bucket))
+		batchSizes = append(batchSizes, len(input.Delete.Objects))
+		return &s3.DeleteObjectsOutput{}, nil
+	}).Times(2)
+
+	// when
+	err := awsMock.Empty(&resource.Resource{
+		Type: resource.S3Bucket,
+		ID:   "foo",
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.True(t, resource.SupportsEmpty(resource.S3Bucket))
+	assert.Equal(t, []int{2, 1}, batchSizes)
+}
+
+func TestAWS_Empty_S3BucketObjectErrors(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockS3API(mockCtrl)
+	awsMock := &resource.AWS{
+		S3API: mockObj,
+	}
+
+	mockObj.EXPECT().ListObjectVersionsPages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(input *s3.ListObjectVersionsInput, fn func(*s3.ListObjectVersionsOutput, bool) bool) error {
+			fn(&s3.ListObjectVersionsOutput{
+				Versions: []*s3.ObjectVersion{{Key: aws.String("a"), VersionId: aws.String("1")}},
+			}, true)
+			return nil
+		})
+	mockObj.EXPECT().DeleteObjects(gomock.Any()).Return(&s3.DeleteObjectsOutput{
+		Errors: []*s3.Error{{Key: aws.String("a"), Message: aws.String("Access Denied")}},
+	}, nil)
+
+	// when
+	err := awsMock.Empty(&resource.Resource{
+		Type: resource.S3Bucket,
+		ID:   "foo",
+	})
+
+	// then
+	assert.EqualError(t, err, "failed to empty foo: failed to delete 1 objects (e.g., a: Access Denied)")
+}
+
+func TestAWS_Empty_Route53Zone(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockRoute53API(mockCtrl)
+	awsMock := &resource.AWS{
+		Route53API: mockObj,
+	}
+
+	sets := []*route53.ResourceRecordSet{
+		{Name: aws.String("example.com."), Type: aws.String(route53.RRTypeNs)},
+		{Name: aws.String("example.com."), Type: aws.String(route53.RRTypeSoa)},
+		{Name: aws.String("sub.example.com."), Type: aws.String(route53.RRTypeNs)},
+	}
+	for i := 0; i < 150; i++ {
+		sets = append(sets, &route53.ResourceRecordSet{
+			Name: aws.String(fmt.Sprintf("host-%d.example.com.", i)),
+			Type: aws.String(route53.RRTypeA),
+		})
+	}
+
+	mockObj.EXPECT().ListResourceRecordSetsPages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(input *route53.ListResourceRecordSetsInput, fn func(*route53.ListResourceRecordSetsOutput, bool) bool) error {
+			assert.Equal(t, "Z123", aws.StringValue(input.HostedZoneId))
+			fn(&route53.ListResourceRecordSetsOutput{ResourceRecordSets: sets}, true)
+			return nil
+		})
+
+	var batchSizes []int
+	mockObj.EXPECT().ChangeResourceRecordSets(gomock.Any()).DoAndReturn(
+		func(input *route53.ChangeResourceRecordSetsInput) (*route53.ChangeResourceRecordSetsOutput, error) {
+			batchSizes = append(batchSizes, len(input.ChangeBatch.Changes))
+			return &route53.ChangeResourceRecordSetsOutput{
+				ChangeInfo: &route53.ChangeInfo{Id: aws.String(fmt.Sprintf("change-%d", len(batchSizes)))},
+			}, nil
+		}).Times(2)
+	mockObj.EXPECT().WaitUntilResourceRecordSetsChanged(&route53.GetChangeInput{Id: aws.String("change-1")}).Return(nil)
+	mockObj.EXPECT().WaitUntilResourceRecordSetsChanged(&route53.GetChangeInput{Id: aws.String("change-2")}).Return(nil)
+
+	// when
+	err := awsMock.Empty(&resource.Resource{
+		Type: resource.Route53Zone,
+		ID:   "/hostedzone/Z123",
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []int{100, 51}, batchSizes)
+}
+
+func TestAWS_Empty_LbTargetGroup(t *testing.T) {
+	// given
+	a, b := resourcetest.NewAWS(resourcetest.Fixtures{
+		"elasticloadbalancing": {
+			"DescribeTargetHealth": map[string]interface{}{
+				"TargetHealthDescriptions": []interface{}{
+					map[string]interface{}{"Target": map[string]interface{}{"Id": testInstanceID, "Port": 80}},
+				},
+			},
+		},
+	})
+
+	// when
+	err := a.Empty(&resource.Resource{
+		Type: resource.LbTargetGroup,
+		ID:   "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/ci/73e2d6bc24d8a067",
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"elasticloadbalancing:DescribeTargetHealth", "elasticloadbalancing:DeregisterTargets"}, b.Calls())
+}
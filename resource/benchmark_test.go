@@ -0,0 +1,69 @@
+package resource_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/cloudetc/awsweeper/resource"
+)
+
+// syntheticInventorySize is the number of resources the benchmarks list and filter.
+const syntheticInventorySize = 100000
+
+func syntheticInstances(n int) []*ec2.Instance {
+	launchTime := time.Date(2018, 10, 28, 12, 0, 0, 0, time.UTC)
+
+	instances := make([]*ec2.Instance, n)
+	for i := range instances {
+		instances[i] = &ec2.Instance{
+			InstanceId: aws.String(fmt.Sprintf("i-%017x", i)),
+			LaunchTime: aws.Time(launchTime.Add(time.Duration(i) * time.Minute)),
+			Tags: []*ec2.Tag{
+				{Key: aws.String("Name"), Value: aws.String(fmt.Sprintf("instance-%d", i))},
+				{Key: aws.String("Env"), Value: aws.String([]string{"dev", "staging", "prod"}[i%3])},
+			},
+		}
+	}
+	return instances
+}
+
+func BenchmarkDeletableResources(b *testing.B) {
+	instances := syntheticInstances(syntheticInventorySize)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := resource.DeletableResources(resource.Instance, instances); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFilter_Apply(b *testing.B) {
+	instances := syntheticInstances(syntheticInventorySize)
+	res, err := resource.DeletableResources(resource.Instance, instances)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {
+				{
+					ID:   aws.String("^i-0000000000000[0-9a-f]{4}$"),
+					Tags: map[string]string{"Env": "^(dev|staging)$"},
+					Created: &resource.Created{
+						Before: aws.Time(time.Date(2018, 12, 1, 0, 0, 0, 0, time.UTC)),
+					},
+				},
+			},
+		},
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		f.Apply(resource.Instance, res, instances, nil)
+	}
+}
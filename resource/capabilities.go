@@ -0,0 +1,166 @@
+package resource
+
+import (
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/athena"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cognitoidentity"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go/service/configservice"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/efs"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/redshift"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/ses"
+	"github.com/aws/aws-sdk-go/service/sfn"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+var (
+	// rawResourceTypes are the types of the (raw) resources listed via the AWS API per resource type,
+	// i.e. the element types of the lists returned by RawResources.
+	rawResourceTypes = map[TerraformResourceType]interface{}{
+		Ami:                     ec2.Image{},
+		ApiGatewayRestApi:       apigateway.RestApi{},
+		AthenaNamedQuery:        athena.NamedQuery{},
+		AutoscalingGroup:        autoscaling.Group{},
+		CloudformationStack:     cloudformation.Stack{},
+		CloudfrontDistribution:  cloudfront.DistributionSummary{},
+		Cloudtrail:              cloudtrail.Trail{},
+		CloudwatchDashboard:     cloudwatch.DashboardEntry{},
+		CloudwatchLogGroup:      cloudwatchlogs.LogGroup{},
+		CloudwatchLogStream:     logStream{},
+		CloudwatchMetricAlarm:   cloudwatch.MetricAlarm{},
+		CognitoIdentityPool:     cognitoidentity.IdentityPoolShortDescription{},
+		CognitoUserPool:         cognitoidentityprovider.UserPoolDescriptionType{},
+		ConfigConfigRule:        configservice.ConfigRule{},
+		ConfigRecorder:          configservice.ConfigurationRecorder{},
+		ConfigDelivery:          configservice.DeliveryChannel{},
+		DbClusterSnapshot:       rds.DBClusterSnapshot{},
+		DbSnapshot:              rds.DBSnapshot{},
+		DynamodbTable:           dynamodb.TableDescription{},
+		EbsSnapshot:             ec2.Snapshot{},
+		EbsVolume:               ec2.Volume{},
+		EcsCluster:              ecs.Cluster{},
+		EcsService:              ecs.Service{},
+		EcsTaskDefinition:       ecsTaskDefinitionRevision{},
+		EfsFileSystem:           efs.FileSystemDescription{},
+		Eip:                     ec2.Address{},
+		BeanstalkApplication:    elasticbeanstalk.ApplicationDescription{},
+		BeanstalkAppVersion:     elasticbeanstalk.ApplicationVersionDescription{},
+		BeanstalkEnvironment:    elasticbeanstalk.EnvironmentDescription{},
+		Elb:                     elb.LoadBalancerDescription{},
+		GlueCatalogDatabase:     glueDatabase{},
+		GlueCrawler:             glue.Crawler{},
+		GlueJob:                 glue.Job{},
+		IamAccessKey:            iam.AccessKeyMetadata{},
+		IamGroup:                iam.Group{},
+		IamInstanceProfile:      iam.InstanceProfile{},
+		IamPolicy:               iam.Policy{},
+		IamRole:                 iam.Role{},
+		IamRolePolicyAttachment: iamRelation{},
+		IamUser:                 iam.User{},
+		IamUserGroupMembership:  iamRelation{},
+		IamUserPolicyAttachment: iamRelation{},
+		Instance:                ec2.Instance{},
+		InternetGateway:         ec2.InternetGateway{},
+		KeyPair:                 ec2.KeyPairInfo{},
+		FirehoseDeliveryStream:  firehose.DeliveryStreamDescription{},
+		KinesisStream:           kinesis.StreamDescriptionSummary{},
+		KmsAlias:                kms.AliasListEntry{},
+		KmsKey:                  kmsKey{},
+		LambdaFunction:          lambda.FunctionConfiguration{},
+		LaunchConfiguration:     autoscaling.LaunchConfiguration{},
+		Lb:                      elbv2.LoadBalancer{},
+		LbListener:              elbv2.Listener{},
+		LbTargetGroup:           elbv2.TargetGroup{},
+		NatGateway:              ec2.NatGateway{},
+		NetworkAcl:              ec2.NetworkAcl{},
+		NetworkInterface:        ec2.NetworkInterface{},
+		RdsCluster:              rds.DBCluster{},
+		RedshiftCluster:         redshift.Cluster{},
+		RedshiftSnapshot:        redshift.Snapshot{},
+		Route53Zone:             route53.HostedZone{},
+		RouteTable:              ec2.RouteTable{},
+		S3Bucket:                s3.Bucket{},
+		SecurityGroup:           ec2.SecurityGroup{},
+		SesConfigurationSet:     ses.ConfigurationSet{},
+		SesDomainIdentity:       "",
+		SesEmailIdentity:        "",
+		SesReceiptRuleSet:       ses.ReceiptRuleSetMetadata{},
+		SfnStateMachine:         sfn.StateMachineListItem{},
+		SnsTopic:                sns.Topic{},
+		SnsTopicSubscription:    sns.Subscription{},
+		SqsQueue:                "",
+		SsmParameter:            ssm.ParameterMetadata{},
+		Subnet:                  ec2.Subnet{},
+		Vpc:                     ec2.Vpc{},
+		VpcEndpoint:             ec2.VpcEndpoint{},
+	}
+
+	// taggingAPITypes are resource types without native tags, which tags can be looked up via
+	// the Resource Groups Tagging API, by the filter of the tagging API for the resource type.
+	taggingAPITypes = map[TerraformResourceType]string{
+		ApiGatewayRestApi:     "apigateway",
+		BeanstalkApplication:  "elasticbeanstalk:application",
+		Cloudtrail:            "cloudtrail:trail",
+		CloudwatchMetricAlarm: "cloudwatch:alarm",
+		LambdaFunction:        "lambda:function",
+		SnsTopic:              "sns",
+	}
+)
+
+// Capabilities describe which filter criteria can be applied to resources of a particular type.
+type Capabilities struct {
+	// Tags is true if the tags of resources are known
+	Tags bool
+	// Created is true if the creation time of resources is known
+	Created bool
+}
+
+// TypeCapabilities returns the capabilities of a resource type.
+func TypeCapabilities(resType TerraformResourceType) Capabilities {
+	raw, found := rawResourceTypes[resType]
+	if !found {
+		return Capabilities{}
+	}
+
+	_, hasTagFetcher := tagFetchers[resType]
+
+	return Capabilities{
+		Tags:    hasField(raw, tagFieldNamesOf(resType)) || hasTagFetcher || taggingAPITypes[resType] != "",
+		Created: hasField(raw, creationTimeFieldNames),
+	}
+}
+
+// hasField checks whether a raw resource has any of the given fields.
+func hasField(raw interface{}, names []string) bool {
+	v := reflect.ValueOf(raw)
+	if v.Kind() != reflect.Struct {
+		return false
+	}
+
+	_, err := findField(names, v)
+	return err == nil
+}
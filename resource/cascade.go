@@ -0,0 +1,191 @@
+package resource
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+// cascadeTypes are the resource types which resources can be deleted together with the resources
+// that would otherwise be left behind.
+var cascadeTypes = map[TerraformResourceType]bool{
+	KmsAlias: true,
+	KmsKey:   true,
+	Lb:       true,
+}
+
+// cascadeActions are required to also delete the dependents of the resources of a particular type.
+var cascadeActions = map[TerraformResourceType]Permissions{
+	KmsAlias: {
+		List:   []string{"kms:DescribeKey"},
+		Delete: []string{"kms:ScheduleKeyDeletion"},
+	},
+	KmsKey: {
+		Delete: []string{"kms:DeleteAlias"},
+	},
+	Lb: {
+		List: []string{"elasticloadbalancing:DescribeRules", "elasticloadbalancing:DescribeTargetGroups"},
+		Delete: []string{"elasticloadbalancing:DeleteRule", "elasticloadbalancing:DeleteTargetGroup",
+			"elasticloadbalancing:DescribeTargetHealth", "elasticloadbalancing:DeregisterTargets"},
+	},
+}
+
+// validateCascade checks whether the resources of a type can be deleted together with their dependents.
+func validateCascade(resType TerraformResourceType, cascade bool) error {
+	if cascade && !cascadeTypes[resType] {
+		return fmt.Errorf("cascade is not supported for resource type: %s", resType)
+	}
+	return nil
+}
+
+// kmsAliasesOf returns the aliases of a selected KMS key as resources to be deleted before the key is scheduled
+// for deletion.
+func kmsAliasesOf(key *Resource) Resources {
+	var aliases Resources
+
+	if m, ok := key.Model.(KmsKeyModel); ok {
+		for _, name := range m.Aliases {
+			aliases = append(aliases, &Resource{
+				Type: KmsAlias,
+				ID:   name,
+			})
+		}
+	}
+	return aliases
+}
+
+// unreferencedKmsKeys returns the keys which aliases are all among the given ones (i.e., the keys that aren't referenced
+// anymore once the aliases are deleted) as resources to be scheduled for deletion. Keys managed by AWS and keys
+// pending deletion already are skipped.
+func unreferencedKmsKeys(aliases Resources, raw interface{}, c *AWS) Resources {
+	selected := map[string]*Resource{}
+	for _, r := range aliases {
+		selected[r.ID] = r
+	}
+
+	// whether all aliases per key are selected
+	referenced := map[string]bool{}
+	aliasesOf := map[string]Resources{}
+	var keyIDs []string
+	for _, alias := range raw.([]*kms.AliasListEntry) {
+		keyID := aws.StringValue(alias.TargetKeyId)
+		if keyID == "" || strings.HasPrefix(aws.StringValue(alias.AliasName), "alias/aws/") {
+			continue
+		}
+		if _, found := referenced[keyID]; !found {
+			referenced[keyID] = false
+			keyIDs = append(keyIDs, keyID)
+		}
+		if r, found := selected[aws.StringValue(alias.AliasName)]; found {
+			aliasesOf[keyID] = append(aliasesOf[keyID], r)
+		} else {
+			referenced[keyID] = true
+		}
+	}
+
+	var keys Resources
+	for _, keyID := range keyIDs {
+		if referenced[keyID] {
+			continue
+		}
+
+		var output *kms.DescribeKeyOutput
+		err := retryThrottled(func() error {
+			var err error
+			output, err = c.DescribeKey(&kms.DescribeKeyInput{KeyId: aws.String(keyID)})
+			return err
+		})
+		if err != nil {
+			continue
+		}
+		m := output.KeyMetadata
+		if aws.StringValue(m.KeyManager) == kms.KeyManagerTypeAws || aws.StringValue(m.KeyState) == kms.KeyStatePendingDeletion {
+			continue
+		}
+		keys = append(keys, &Resource{
+			Type:    KmsKey,
+			ID:      keyID,
+			parents: aliasesOf[keyID],
+		})
+	}
+	return keys
+}
+
+// lbListenerRulesOf returns the rules of the listeners of a selected load balancer (except for their default rules,
+// which are deleted together with the listeners) as resources to be deleted before the listeners.
+func lbListenerRulesOf(listeners []*elbv2.Listener, c *AWS) (Resources, error) {
+	var rules Resources
+
+	for _, l := range listeners {
+		input := &elbv2.DescribeRulesInput{ListenerArn: l.ListenerArn}
+		for {
+			output, err := c.DescribeRules(input)
+			if err != nil {
+				return nil, err
+			}
+			for _, rule := range output.Rules {
+				if aws.BoolValue(rule.IsDefault) {
+					continue
+				}
+				rules = append(rules, &Resource{
+					Type: "aws_lb_listener_rule",
+					ID:   aws.StringValue(rule.RuleArn),
+				})
+			}
+			if aws.StringValue(output.NextMarker) == "" {
+				break
+			}
+			input.Marker = output.NextMarker
+		}
+	}
+	return rules, nil
+}
+
+// orphanedLbTargetGroups returns the target groups which load balancers are all among the given ones (i.e., the
+// target groups that no load balancer forwards to anymore once the load balancers are deleted) as resources to be
+// deleted after the load balancers. Target groups without any load balancer are left as they are.
+func orphanedLbTargetGroups(lbs Resources, c *AWS) (Resources, error) {
+	if len(lbs) == 0 {
+		return nil, nil
+	}
+
+	selected := map[string]*Resource{}
+	for _, r := range lbs {
+		selected[r.ID] = r
+	}
+
+	groups, err := c.lbTargetGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	var orphaned []*elbv2.TargetGroup
+	for _, g := range groups.([]*elbv2.TargetGroup) {
+		if len(g.LoadBalancerArns) == 0 {
+			continue
+		}
+		referenced := false
+		for _, arn := range g.LoadBalancerArns {
+			if _, found := selected[aws.StringValue(arn)]; !found {
+				referenced = true
+			}
+		}
+		if !referenced {
+			orphaned = append(orphaned, g)
+		}
+	}
+
+	res, err := DeletableResources(LbTargetGroup, orphaned)
+	if err != nil {
+		return nil, err
+	}
+	for i, r := range res {
+		for _, arn := range orphaned[i].LoadBalancerArns {
+			r.parents = append(r.parents, selected[aws.StringValue(arn)])
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,89 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Apply_CascadeKmsAliases(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockKMSAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		KMSAPI: mockObj,
+	}
+
+	mockObj.EXPECT().DescribeKey(&kms.DescribeKeyInput{KeyId: aws.String("key-unreferenced")}).Return(
+		&kms.DescribeKeyOutput{
+			KeyMetadata: &kms.KeyMetadata{
+				KeyId:      aws.String("key-unreferenced"),
+				KeyManager: aws.String(kms.KeyManagerTypeCustomer),
+				KeyState:   aws.String(kms.KeyStateEnabled),
+			},
+		}, nil)
+
+	aliases := []*kms.AliasListEntry{
+		{AliasName: aws.String("alias/select-foo"), TargetKeyId: aws.String("key-unreferenced")},
+		{AliasName: aws.String("alias/select-bar"), TargetKeyId: aws.String("key-unreferenced")},
+		{AliasName: aws.String("alias/select-baz"), TargetKeyId: aws.String("key-referenced")},
+		{AliasName: aws.String("alias/keep"), TargetKeyId: aws.String("key-referenced")},
+	}
+	res, err := resource.DeletableResources(resource.KmsAlias, aliases)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.KmsAlias: {{ID: aws.String("^alias/select-"), Cascade: true}},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	result := f.Apply(resource.KmsAlias, res, aliases, awsMock)
+
+	// then
+	require.Len(t, result, 2)
+	assert.Len(t, result[0], 3)
+	require.Len(t, result[1], 1)
+	assert.Equal(t, resource.KmsKey, result[1][0].Type)
+	assert.Equal(t, "key-unreferenced", result[1][0].ID)
+}
+
+func TestFilter_Actions_Cascade(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.KmsKey: {{Cascade: true}},
+		},
+	}
+
+	// when
+	actions := f.Actions(false)
+
+	// then
+	assert.Contains(t, actions, "kms:DeleteAlias")
+}
+
+func TestYamlFilter_Validate_CascadeNotSupported(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {{Cascade: true}},
+		},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.EqualError(t, err, "cascade is not supported for resource type: aws_instance")
+}
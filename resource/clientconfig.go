@@ -0,0 +1,190 @@
+package resource
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// ClientsConfig tunes the clients of the AWS APIs (e.g., for runners behind a corporate proxy).
+type ClientsConfig struct {
+	// MaxRetries is how often failed and throttled requests are retried (the default of the SDK if nil).
+	MaxRetries *int `yaml:"max_retries,omitempty"`
+	// Timeout is how long an HTTP request may take until its response has been read (e.g., 30s).
+	// Requests don't time out if empty.
+	Timeout string `yaml:",omitempty"`
+	// CABundle is a file with the PEM encoded certificates of CAs that are trusted in addition to the ones
+	// of the system (e.g., of a proxy that intercepts TLS).
+	CABundle string `yaml:"ca_bundle,omitempty"`
+	// Proxy is the URL of the HTTP(S) or SOCKS5 proxy requests are sent through (e.g., http://proxy:3128
+	// or socks5://proxy:1080). The proxy of the HTTPS_PROXY (and NO_PROXY) environment variables is used if empty.
+	Proxy string `yaml:",omitempty"`
+	// Services override max_retries and timeout per service, by the name of its API endpoint (e.g., ec2, logs).
+	Services map[string]ServiceClientConfig `yaml:",omitempty"`
+}
+
+// ServiceClientConfig are the settings of the clients of a particular service.
+type ServiceClientConfig struct {
+	MaxRetries *int   `yaml:"max_retries,omitempty"`
+	Timeout    string `yaml:",omitempty"`
+}
+
+// validate checks if the clients config is valid (a nil config is valid, as the clients keep their defaults).
+func (c *ClientsConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if err := validateClientSettings("clients", c.MaxRetries, c.Timeout); err != nil {
+		return err
+	}
+	for name, svc := range c.Services {
+		if err := validateClientSettings("clients of "+name, svc.MaxRetries, svc.Timeout); err != nil {
+			return err
+		}
+	}
+
+	if c.Proxy != "" {
+		u, err := url.Parse(c.Proxy)
+		if err != nil || u.Host == "" {
+			return errors.Errorf("invalid proxy of clients: %s", c.Proxy)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return errors.Errorf("unsupported scheme of the proxy of clients (http, https or socks5): %s", c.Proxy)
+		}
+	}
+
+	if c.CABundle != "" {
+		if _, err := c.certPool(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateClientSettings(name string, maxRetries *int, timeout string) error {
+	if maxRetries != nil && *maxRetries < 0 {
+		return fmt.Errorf("max_retries of %s must not be negative", name)
+	}
+	if _, err := parseTimeout(timeout); err != nil {
+		return errors.Wrapf(err, "invalid timeout of %s", name)
+	}
+	return nil
+}
+
+// parseTimeout parses a timeout, which is 0 (no timeout) if empty.
+func parseTimeout(timeout string) (time.Duration, error) {
+	if timeout == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(timeout)
+	if err == nil && d <= 0 {
+		err = errors.Errorf("timeout must be positive: %s", timeout)
+	}
+	return d, err
+}
+
+// certPool returns the CAs of the system together with the ones of the CA bundle.
+func (c *ClientsConfig) certPool() (*x509.CertPool, error) {
+	data, err := afero.ReadFile(AppFs, c.CABundle)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read CA bundle of clients: %s", c.CABundle)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.Errorf("no PEM encoded certificates in CA bundle of clients: %s", c.CABundle)
+	}
+	return pool, nil
+}
+
+// transport returns the HTTP transport of the clients, which uses the proxy and trusts the CAs of the config.
+func (c *ClientsConfig) transport() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if c.Proxy != "" {
+		u, err := url.Parse(c.Proxy)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid proxy of clients: %s", c.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+
+	if c.CABundle != "" {
+		pool, err := c.certPool()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	return transport, nil
+}
+
+// Session returns a copy of the session which clients are configured by the config
+// (the session itself, if the config is nil).
+func (c *ClientsConfig) Session(s *session.Session) (*session.Session, error) {
+	if c == nil {
+		return s, nil
+	}
+
+	transport, err := c.transport()
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := parseTimeout(c.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &aws.Config{HTTPClient: &http.Client{Transport: transport, Timeout: timeout}}
+	if c.MaxRetries != nil {
+		cfg.MaxRetries = aws.Int(*c.MaxRetries)
+	}
+	result := s.Copy(cfg)
+
+	if len(c.Services) == 0 {
+		return result, nil
+	}
+
+	httpClients := map[string]*http.Client{}
+	for name, svc := range c.Services {
+		if svc.Timeout == "" {
+			continue
+		}
+		timeout, err := parseTimeout(svc.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		httpClients[name] = &http.Client{Transport: transport, Timeout: timeout}
+	}
+
+	result.Handlers.Validate.PushBackNamed(request.NamedHandler{
+		Name: "awsweeper.ServiceClientConfig",
+		Fn: func(r *request.Request) {
+			name := r.ClientInfo.ServiceName
+			if svc, found := c.Services[name]; found && svc.MaxRetries != nil {
+				r.Retryer = client.DefaultRetryer{NumMaxRetries: *svc.MaxRetries}
+			}
+			if httpClient, found := httpClients[name]; found {
+				r.Config.HTTPClient = httpClient
+			}
+		},
+	})
+	return result, nil
+}
@@ -0,0 +1,108 @@
+package resource_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSession(endpoint string) *session.Session {
+	return session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Endpoint:    aws.String(endpoint),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	}))
+}
+
+func TestClientsConfig_Session_Proxy(t *testing.T) {
+	// given
+	var requests int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		assert.Equal(t, "sts.example.com", r.Host)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer proxy.Close()
+
+	cfg := &resource.ClientsConfig{
+		Proxy:      proxy.URL,
+		MaxRetries: aws.Int(3),
+		Services: map[string]resource.ServiceClientConfig{
+			"sts": {MaxRetries: aws.Int(1)},
+		},
+	}
+	require.NoError(t, resource.Filter{Clients: cfg}.Validate())
+
+	// when
+	sess, err := cfg.Session(newTestSession("http://sts.example.com"))
+	require.NoError(t, err)
+	_, err = sts.New(sess).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+
+	// then
+	assert.Error(t, err)
+	// the request has been retried once
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
+
+func TestClientsConfig_Session_Timeout(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	cfg := &resource.ClientsConfig{
+		MaxRetries: aws.Int(0),
+		Services: map[string]resource.ServiceClientConfig{
+			"sts": {Timeout: "50ms"},
+		},
+	}
+	require.NoError(t, resource.Filter{Clients: cfg}.Validate())
+
+	// when
+	sess, err := cfg.Session(newTestSession(server.URL))
+	require.NoError(t, err)
+	start := time.Now()
+	_, err = sts.New(sess).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Timeout")
+	assert.True(t, time.Since(start) < 200*time.Millisecond)
+}
+
+func TestYamlFilter_Validate_Clients(t *testing.T) {
+	resource.AppFs = afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(resource.AppFs, "empty.pem", []byte("no certificates"), 0644))
+
+	tests := []struct {
+		clients *resource.ClientsConfig
+		err     string
+	}{
+		{&resource.ClientsConfig{MaxRetries: aws.Int(-1)}, "max_retries of clients must not be negative"},
+		{&resource.ClientsConfig{Timeout: "soon"}, `invalid timeout of clients: time: invalid duration "soon"`},
+		{&resource.ClientsConfig{Services: map[string]resource.ServiceClientConfig{"ec2": {Timeout: "-1s"}}},
+			"invalid timeout of clients of ec2: timeout must be positive: -1s"},
+		{&resource.ClientsConfig{Proxy: "ftp://proxy:21"},
+			"unsupported scheme of the proxy of clients (http, https or socks5): ftp://proxy:21"},
+		{&resource.ClientsConfig{CABundle: "empty.pem"}, "no PEM encoded certificates in CA bundle of clients: empty.pem"},
+	}
+
+	for _, tc := range tests {
+		err := resource.Filter{Clients: tc.clients}.Validate()
+		assert.EqualError(t, err, tc.err)
+	}
+
+	assert.NoError(t, resource.Filter{Clients: &resource.ClientsConfig{Proxy: "socks5://proxy:1080", Timeout: "30s"}}.Validate())
+}
@@ -0,0 +1,44 @@
+package resource
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// ClientProvider provides the sessions and clients to access an account with, per region. Programs embedding
+// AWSweeper can implement it to inject the sessions or clients they have built themselves (e.g., with credentials
+// from their own broker), instead of having them created from the command line flags and the shared config.
+//
+// A ClientProvider has no account dimension, as a run sweeps a single account: the run lock, the cached identity
+// of the credentials and the confirmation of the deletion all apply to one account. To sweep several accounts,
+// embedders run AWSweeper once per account, each time with a ClientProvider for that account.
+type ClientProvider interface {
+	// Session returns the session to access a region with (or the one of the default region,
+	// if region is empty). Its credentials are also used by the Terraform provider to delete resources.
+	Session(region string) (*session.Session, error)
+	// Client returns the clients to access a region with, given the session returned for the region.
+	Client(region string, sess *session.Session) (*AWS, error)
+}
+
+// SessionFunc is a ClientProvider that only provides sessions, from which the clients are created via NewAWS.
+type SessionFunc func(region string) (*session.Session, error)
+
+// Session returns f(region).
+func (f SessionFunc) Session(region string) (*session.Session, error) {
+	return f(region)
+}
+
+// Client creates the clients of a region from its session.
+func (f SessionFunc) Client(region string, sess *session.Session) (*AWS, error) {
+	return NewAWS(sess), nil
+}
+
+// RegionalSessions returns a ClientProvider whose sessions are copies of the given one, configured for a region.
+func RegionalSessions(sess *session.Session) SessionFunc {
+	return func(region string) (*session.Session, error) {
+		if region == "" {
+			return sess, nil
+		}
+		return sess.Copy(&aws.Config{Region: aws.String(region)}), nil
+	}
+}
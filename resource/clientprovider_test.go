@@ -0,0 +1,31 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegionalSessions(t *testing.T) {
+	// given
+	sess := newTestSession("http://localhost")
+	clients := resource.RegionalSessions(sess)
+
+	// when
+	defaultSess, err := clients.Session("")
+	require.NoError(t, err)
+	regionSess, err := clients.Session("eu-west-1")
+	require.NoError(t, err)
+	client, err := clients.Client("eu-west-1", regionSess)
+	require.NoError(t, err)
+
+	// then
+	assert.Equal(t, sess, defaultSess)
+	assert.Equal(t, "eu-west-1", aws.StringValue(regionSess.Config.Region))
+	assert.Equal(t, "us-east-1", aws.StringValue(sess.Config.Region))
+	assert.Equal(t, "eu-west-1", client.Region)
+	assert.Equal(t, regionSess.Config.Credentials, sess.Config.Credentials)
+}
@@ -0,0 +1,30 @@
+package resource
+
+// MissingTags returns the required tags of the filter that a resource doesn't have.
+// Resources for which tags are unknown (e.g., their type doesn't support tags) are compliant.
+func (f Filter) MissingTags(r *Resource) []string {
+	var missing []string
+
+	if r.Tags == nil {
+		return missing
+	}
+
+	for _, key := range f.RequiredTags {
+		if _, ok := r.Tags[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// Violations returns all resources that don't have the required tags of the filter.
+func (f Filter) Violations(res Resources) Resources {
+	result := Resources{}
+
+	for _, r := range res {
+		if len(f.MissingTags(r)) > 0 {
+			result = append(result, r)
+		}
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_MissingTags(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		RequiredTags: []string{"CostCenter", "Owner"},
+	}
+	r := &resource.Resource{
+		Type: resource.Instance,
+		ID:   "foo",
+		Tags: map[string]string{
+			"Owner": "bar",
+		},
+	}
+
+	// when
+	missing := f.MissingTags(r)
+
+	// then
+	assert.Equal(t, []string{"CostCenter"}, missing)
+}
+
+func TestFilter_Violations(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		RequiredTags: []string{"Owner"},
+	}
+	res := resource.Resources{
+		{
+			Type: resource.Instance,
+			ID:   "select-this",
+			Tags: map[string]string{},
+		},
+		{
+			Type: resource.Instance,
+			ID:   "do-not-select-this",
+			Tags: map[string]string{"Owner": "bar"},
+		},
+		{
+			Type: resource.KeyPair,
+			ID:   "do-not-select-this-either",
+		},
+	}
+
+	// when
+	result := f.Violations(res)
+
+	// then
+	require.Len(t, result, 1)
+	assert.Equal(t, "select-this", result[0].ID)
+}
@@ -0,0 +1,308 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// credentialExpirationVars are the environment variables credential helpers (e.g., aws-vault) set to the
+// expiration time of the temporary credentials they export.
+var credentialExpirationVars = []string{"AWS_CREDENTIAL_EXPIRATION", "AWS_SESSION_EXPIRATION"}
+
+// CredentialsExpiry returns when the given credentials expire, if they can't be refreshed (zero otherwise).
+// Credentials of roles assumed via the shared config are refreshed by the AWS SDK before they expire and
+// long-term credentials don't expire. Session tokens can't be refreshed, but their expiration time is only
+// known if the credential helper that exported them has also exported their expiration time.
+func CredentialsExpiry(creds *credentials.Credentials) (time.Time, error) {
+	v, err := creds.Get()
+	if err != nil {
+		// the error is reported by the first call of an API
+		return time.Time{}, nil
+	}
+
+	if v.SessionToken == "" || v.ProviderName == stscreds.ProviderName {
+		return time.Time{}, nil
+	}
+
+	for _, name := range credentialExpirationVars {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		expiry, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, errors.Wrapf(err, "invalid expiration time of credentials in %s", name)
+		}
+		return expiry, nil
+	}
+	return time.Time{}, nil
+}
+
+const (
+	// ProcessProviderName is the name of the provider of credentials sourced from the credential_process of a profile.
+	ProcessProviderName = "ProcessProvider"
+	// WebIdentityProviderName is the name of the provider of credentials of a role assumed with a web identity token.
+	WebIdentityProviderName = "WebIdentityProvider"
+
+	webIdentityTokenFileEnv = "AWS_WEB_IDENTITY_TOKEN_FILE"
+	roleARNEnv              = "AWS_ROLE_ARN"
+	roleSessionNameEnv      = "AWS_ROLE_SESSION_NAME"
+
+	// externalCredentialsWindow is how long before they expire external credentials are refreshed.
+	externalCredentialsWindow = time.Minute
+)
+
+// externalProvider is a provider of credentials the AWS SDK can't retrieve by itself.
+type externalProvider interface {
+	credentials.Provider
+	expiration() time.Time
+}
+
+// ExternalCredentials are credentials of a role assumed with a web identity token (e.g., of the service account of
+// an EKS pod) or sourced from the credential_process of a profile, which the AWS SDK doesn't support yet.
+type ExternalCredentials struct {
+	*credentials.Credentials
+	provider externalProvider
+}
+
+// Expiration returns when the last retrieved credentials expire (zero if they don't expire).
+func (c *ExternalCredentials) Expiration() time.Time {
+	return c.provider.expiration()
+}
+
+// NewExternalCredentials returns the credentials of a web identity, if AWS_WEB_IDENTITY_TOKEN_FILE and AWS_ROLE_ARN
+// are set (as done by EKS for pods of service accounts with an IAM role), or of the credential_process of the
+// profile. It returns nil if the credentials are neither, so that the AWS SDK retrieves them as usual.
+// Access keys of the environment or the profile take precedence, as they do in the AWS CLI.
+func NewExternalCredentials(sess *session.Session, profile string) (*ExternalCredentials, error) {
+	if os.Getenv("AWS_ACCESS_KEY_ID") != "" {
+		return nil, nil
+	}
+
+	if tokenFile := os.Getenv(webIdentityTokenFileEnv); tokenFile != "" {
+		roleARN := os.Getenv(roleARNEnv)
+		if roleARN == "" {
+			return nil, errors.Errorf("%s is set, but %s isn't", webIdentityTokenFileEnv, roleARNEnv)
+		}
+		sessionName := os.Getenv(roleSessionNameEnv)
+		if sessionName == "" {
+			sessionName = fmt.Sprintf("awsweeper-%d", time.Now().Unix())
+		}
+
+		// the role is assumed without signing the request, as the token is the only credential
+		cfg := &aws.Config{Credentials: credentials.AnonymousCredentials}
+		if aws.StringValue(sess.Config.Region) == "" {
+			cfg.Region = aws.String("us-east-1")
+		}
+		return newExternalCredentials(&webIdentityProvider{
+			client:      sts.New(sess, cfg),
+			tokenFile:   tokenFile,
+			roleARN:     roleARN,
+			sessionName: sessionName,
+		}), nil
+	}
+
+	settings, err := profileSettings(profile)
+	if err != nil {
+		return nil, err
+	}
+	if settings["aws_access_key_id"] != "" || settings["credential_process"] == "" {
+		return nil, nil
+	}
+	return newExternalCredentials(&processProvider{command: settings["credential_process"]}), nil
+}
+
+func newExternalCredentials(p externalProvider) *ExternalCredentials {
+	return &ExternalCredentials{Credentials: credentials.NewCredentials(p), provider: p}
+}
+
+// profileSettings returns the settings of a profile (AWS_PROFILE or default, if empty) in the shared config and
+// credentials files, of which the credentials file takes precedence.
+func profileSettings(profile string) (map[string]string, error) {
+	if profile == "" {
+		profile = os.Getenv("AWS_PROFILE")
+	}
+	if profile == "" {
+		profile = "default"
+	}
+
+	home, _ := os.UserHomeDir()
+	configFile := os.Getenv("AWS_CONFIG_FILE")
+	if configFile == "" {
+		configFile = filepath.Join(home, ".aws", "config")
+	}
+	credentialsFile := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if credentialsFile == "" {
+		credentialsFile = filepath.Join(home, ".aws", "credentials")
+	}
+
+	configSection := "profile " + profile
+	if profile == "default" {
+		configSection = profile
+	}
+
+	settings := map[string]string{}
+	for _, f := range []struct{ name, section string }{{configFile, configSection}, {credentialsFile, profile}} {
+		data, err := afero.ReadFile(AppFs, f.name)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read AWS config %s", f.name)
+		}
+		for k, v := range iniSection(string(data), f.section) {
+			settings[k] = v
+		}
+	}
+	return settings, nil
+}
+
+// iniSection returns the keys and values of a section of the INI format of the shared config and credentials files.
+func iniSection(data, section string) map[string]string {
+	values := map[string]string{}
+	current := ""
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			current = strings.Join(strings.Fields(line[1:len(line)-1]), " ")
+		case current == section:
+			if i := strings.Index(line, "="); i > 0 {
+				values[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+			}
+		}
+	}
+	return values
+}
+
+// processCredentials is the output of a credential process.
+type processCredentials struct {
+	Version         int
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string
+	SessionToken    string
+	Expiration      *time.Time
+}
+
+// processProvider retrieves credentials by running the credential_process of a profile.
+type processProvider struct {
+	credentials.Expiry
+	command string
+	expires time.Time
+	// retrieved is whether credentials have been retrieved successfully
+	retrieved bool
+}
+
+// Retrieve runs the credential process and parses its output.
+func (p *processProvider) Retrieve() (credentials.Value, error) {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd.exe", "/C"
+	}
+	cmd := exec.Command(shell, flag, p.command)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return credentials.Value{ProviderName: ProcessProviderName},
+			errors.Wrapf(err, "credential process failed: %s", p.command)
+	}
+
+	var creds processCredentials
+	if err := json.Unmarshal(out, &creds); err != nil {
+		return credentials.Value{ProviderName: ProcessProviderName},
+			errors.Wrapf(err, "invalid output of credential process: %s", p.command)
+	}
+	if creds.Version != 1 {
+		return credentials.Value{ProviderName: ProcessProviderName},
+			errors.Errorf("unsupported version %d of the output of credential process: %s", creds.Version, p.command)
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return credentials.Value{ProviderName: ProcessProviderName},
+			errors.Errorf("no AccessKeyId or SecretAccessKey in the output of credential process: %s", p.command)
+	}
+
+	p.retrieved = true
+	p.expires = time.Time{}
+	if creds.Expiration != nil {
+		p.expires = *creds.Expiration
+		p.SetExpiration(p.expires, externalCredentialsWindow)
+	}
+	return credentials.Value{
+		AccessKeyID:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+		SessionToken:    creds.SessionToken,
+		ProviderName:    ProcessProviderName,
+	}, nil
+}
+
+// IsExpired returns whether the credentials have to be retrieved again (credentials without expiration
+// are only retrieved once).
+func (p *processProvider) IsExpired() bool {
+	if p.expires.IsZero() {
+		return !p.retrieved
+	}
+	return p.Expiry.IsExpired()
+}
+
+func (p *processProvider) expiration() time.Time {
+	return p.expires
+}
+
+// webIdentityProvider retrieves credentials by assuming a role with the web identity token in a file,
+// which is read again on every refresh, as it is rotated (e.g., by EKS).
+type webIdentityProvider struct {
+	credentials.Expiry
+	client      stsiface.STSAPI
+	tokenFile   string
+	roleARN     string
+	sessionName string
+	expires     time.Time
+}
+
+// Retrieve assumes the role with the current token.
+func (p *webIdentityProvider) Retrieve() (credentials.Value, error) {
+	token, err := afero.ReadFile(AppFs, p.tokenFile)
+	if err != nil {
+		return credentials.Value{ProviderName: WebIdentityProviderName},
+			errors.Wrapf(err, "failed to read web identity token file %s", p.tokenFile)
+	}
+
+	res, err := p.client.AssumeRoleWithWebIdentity(&sts.AssumeRoleWithWebIdentityInput{
+		RoleArn:          aws.String(p.roleARN),
+		RoleSessionName:  aws.String(p.sessionName),
+		WebIdentityToken: aws.String(strings.TrimSpace(string(token))),
+	})
+	if err != nil {
+		return credentials.Value{ProviderName: WebIdentityProviderName},
+			errors.Wrapf(err, "failed to assume role %s with web identity", p.roleARN)
+	}
+
+	p.expires = aws.TimeValue(res.Credentials.Expiration)
+	p.SetExpiration(p.expires, externalCredentialsWindow)
+	return credentials.Value{
+		AccessKeyID:     aws.StringValue(res.Credentials.AccessKeyId),
+		SecretAccessKey: aws.StringValue(res.Credentials.SecretAccessKey),
+		SessionToken:    aws.StringValue(res.Credentials.SessionToken),
+		ProviderName:    WebIdentityProviderName,
+	}, nil
+}
+
+func (p *webIdentityProvider) expiration() time.Time {
+	return p.expires
+}
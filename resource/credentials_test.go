@@ -0,0 +1,131 @@
+package resource_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialsExpiry(t *testing.T) {
+	os.Setenv("AWS_CREDENTIAL_EXPIRATION", "2018-11-01T12:00:00Z")
+	defer os.Unsetenv("AWS_CREDENTIAL_EXPIRATION")
+
+	expiry, err := resource.CredentialsExpiry(credentials.NewStaticCredentials("id", "secret", "token"))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2018, 11, 1, 12, 0, 0, 0, time.UTC), expiry)
+
+	// long-term credentials don't expire
+	expiry, err = resource.CredentialsExpiry(credentials.NewStaticCredentials("id", "secret", ""))
+	require.NoError(t, err)
+	assert.True(t, expiry.IsZero())
+}
+
+func TestCredentialsExpiry_Invalid(t *testing.T) {
+	os.Setenv("AWS_SESSION_EXPIRATION", "tomorrow")
+	defer os.Unsetenv("AWS_SESSION_EXPIRATION")
+
+	_, err := resource.CredentialsExpiry(credentials.NewStaticCredentials("id", "secret", "token"))
+	assert.Error(t, err)
+}
+
+func TestNewExternalCredentials_Process(t *testing.T) {
+	// given
+	resource.AppFs = afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(resource.AppFs, "/aws/config", []byte(`[default]
+region = us-east-1
+
+[profile ci]
+credential_process = echo '{"Version": 1, "AccessKeyId": "id", "SecretAccessKey": "secret", "SessionToken": "token", "Expiration": "2030-01-01T00:00:00Z"}'
+
+[profile static]
+credential_process = false
+`), 0644))
+	require.NoError(t, afero.WriteFile(resource.AppFs, "/aws/credentials", []byte(`[static]
+aws_access_key_id = id
+aws_secret_access_key = secret
+`), 0644))
+
+	os.Setenv("AWS_CONFIG_FILE", "/aws/config")
+	defer os.Unsetenv("AWS_CONFIG_FILE")
+	os.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/aws/credentials")
+	defer os.Unsetenv("AWS_SHARED_CREDENTIALS_FILE")
+
+	// when
+	creds, err := resource.NewExternalCredentials(session.Must(session.NewSession()), "ci")
+	require.NoError(t, err)
+	require.NotNil(t, creds)
+	v, err := creds.Get()
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, credentials.Value{AccessKeyID: "id", SecretAccessKey: "secret", SessionToken: "token",
+		ProviderName: resource.ProcessProviderName}, v)
+	assert.Equal(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), creds.Expiration())
+
+	// access keys take precedence over the credential process
+	creds, err = resource.NewExternalCredentials(session.Must(session.NewSession()), "static")
+	require.NoError(t, err)
+	assert.Nil(t, creds)
+
+	creds, err = resource.NewExternalCredentials(session.Must(session.NewSession()), "default")
+	require.NoError(t, err)
+	assert.Nil(t, creds)
+}
+
+func TestNewExternalCredentials_WebIdentity(t *testing.T) {
+	// given
+	resource.AppFs = afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(resource.AppFs, "/var/run/secrets/token", []byte("eyJhbGciOi.jwt\n"), 0644))
+
+	sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "AssumeRoleWithWebIdentity", r.Form.Get("Action"))
+		assert.Equal(t, "arn:aws:iam::123456789012:role/sweeper", r.Form.Get("RoleArn"))
+		assert.Equal(t, "eyJhbGciOi.jwt", r.Form.Get("WebIdentityToken"))
+		assert.Empty(t, r.Header.Get("Authorization"))
+		w.Write([]byte(`<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>id</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>token</SessionToken>
+      <Expiration>2030-01-01T00:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`))
+	}))
+	defer sts.Close()
+
+	os.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", "/var/run/secrets/token")
+	defer os.Unsetenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+	os.Setenv("AWS_ROLE_ARN", "arn:aws:iam::123456789012:role/sweeper")
+	defer os.Unsetenv("AWS_ROLE_ARN")
+
+	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String("us-east-1"), Endpoint: aws.String(sts.URL)}))
+
+	// when
+	creds, err := resource.NewExternalCredentials(sess, "")
+	require.NoError(t, err)
+	require.NotNil(t, creds)
+	v, err := creds.Get()
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "id", v.AccessKeyID)
+	assert.Equal(t, resource.WebIdentityProviderName, v.ProviderName)
+	assert.Equal(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), creds.Expiration())
+
+	os.Unsetenv("AWS_ROLE_ARN")
+	_, err = resource.NewExternalCredentials(sess, "")
+	assert.EqualError(t, err, "AWS_WEB_IDENTITY_TOKEN_FILE is set, but AWS_ROLE_ARN isn't")
+}
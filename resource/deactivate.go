@@ -0,0 +1,42 @@
+package resource
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/pkg/errors"
+)
+
+// deactivateActions are required to deactivate resources of the types that support the deactivate action.
+var deactivateActions = map[TerraformResourceType][]string{
+	IamAccessKey: {"iam:UpdateAccessKey"},
+}
+
+// Deactivations returns the resources that match a filter entry with action deactivate.
+func (f Filter) Deactivations(res Resources) Resources {
+	var result Resources
+
+	for _, r := range res {
+		if rtf, found := f.matchingEntry(r); found && rtf.action() == ActionDeactivate {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// Deactivate deactivates a resource, so that it cannot be used anymore, but can still be reactivated.
+func (a *AWS) Deactivate(r *Resource) error {
+	switch r.Type {
+	case IamAccessKey:
+		m, _ := r.Model.(IamAccessKeyModel)
+		_, err := a.UpdateAccessKey(&iam.UpdateAccessKeyInput{
+			AccessKeyId: aws.String(r.ID),
+			UserName:    aws.String(m.User),
+			Status:      aws.String(iam.StatusTypeInactive),
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to deactivate access key %s", r.ID)
+		}
+		return nil
+	}
+	return errors.Errorf("deactivating resources is not supported for resource type: %s", r.Type)
+}
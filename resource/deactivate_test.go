@@ -0,0 +1,87 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testAccessKeys = []*iam.AccessKeyMetadata{
+	{AccessKeyId: aws.String("AKIA1"), UserName: aws.String("ci-runner"), Status: aws.String("Active")},
+	{AccessKeyId: aws.String("AKIA2"), UserName: aws.String("ci-runner"), Status: aws.String("Inactive")},
+	{AccessKeyId: aws.String("AKIA3"), UserName: aws.String("alice"), Status: aws.String("Active")},
+}
+
+func TestFilter_Deactivations(t *testing.T) {
+	// given
+	res, err := resource.DeletableResources(resource.IamAccessKey, testAccessKeys)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.IamAccessKey: {
+				{
+					Attributes: map[string]string{"user": "^ci-", "status": "Active"},
+					Action:     resource.ActionDeactivate,
+				},
+			},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	deactivations := f.Deactivations(res)
+
+	// then
+	require.Len(t, deactivations, 1)
+	assert.Equal(t, "AKIA1", deactivations[0].ID)
+	assert.Empty(t, f.Apply(resource.IamAccessKey, res, testAccessKeys, nil)[0])
+	assert.Equal(t, map[string]string{"user": "ci-runner"}, resource.StateAttributes(deactivations[0]))
+}
+
+func TestAWS_Deactivate(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockIAMAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		IAMAPI: mockObj,
+	}
+
+	res, err := resource.DeletableResources(resource.IamAccessKey, testAccessKeys)
+	require.NoError(t, err)
+
+	mockObj.EXPECT().UpdateAccessKey(&iam.UpdateAccessKeyInput{
+		AccessKeyId: aws.String("AKIA1"),
+		UserName:    aws.String("ci-runner"),
+		Status:      aws.String("Inactive"),
+	}).Return(&iam.UpdateAccessKeyOutput{}, nil)
+
+	// when
+	err = awsMock.Deactivate(res[0])
+
+	// then
+	assert.NoError(t, err)
+}
+
+func TestYamlFilter_Validate_DeactivateNotSupported(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.IamUser: {{Action: resource.ActionDeactivate}},
+		},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.EqualError(t, err, "action deactivate is not supported for resource type: aws_iam_user")
+}
@@ -0,0 +1,94 @@
+package resource
+
+import (
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+)
+
+// DeleteCache keeps track of the resources that have been deleted (or are confirmed to not exist anymore),
+// so that they are skipped when they are listed again (e.g., by a resumed run).
+type DeleteCache struct {
+	mu      sync.Mutex
+	deleted map[string]bool
+}
+
+// NewDeleteCache creates an empty cache.
+func NewDeleteCache() *DeleteCache {
+	return &DeleteCache{deleted: map[string]bool{}}
+}
+
+// LoadDeleteCache reads the resources deleted by a previous run from a yaml file.
+// The cache is empty if the file doesn't exist (yet).
+func LoadDeleteCache(filename string) (*DeleteCache, error) {
+	cache := NewDeleteCache()
+
+	data, err := afero.ReadFile(AppFs, filename)
+	if os.IsNotExist(err) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read deleted resources: %s", filename)
+	}
+
+	var keys []string
+	if err := yaml.Unmarshal(data, &keys); err != nil {
+		return nil, errors.Wrapf(err, "cannot unmarshal deleted resources: %s", filename)
+	}
+	for _, key := range keys {
+		cache.deleted[key] = true
+	}
+	return cache, nil
+}
+
+// Save writes the deleted resources to a yaml file, which can be loaded with LoadDeleteCache.
+func (c *DeleteCache) Save(filename string) error {
+	c.mu.Lock()
+	keys := make([]string, 0, len(c.deleted))
+	for key := range c.deleted {
+		keys = append(keys, key)
+	}
+	c.mu.Unlock()
+	sort.Strings(keys)
+
+	data, err := yaml.Marshal(keys)
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal deleted resources")
+	}
+	if err := afero.WriteFile(AppFs, filename, data, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write deleted resources: %s", filename)
+	}
+	return nil
+}
+
+// Add marks a resource listed in a region as deleted.
+func (c *DeleteCache) Add(region string, r *Resource) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.deleted[deleteCacheKey(region, r)] = true
+}
+
+// Contains checks whether a resource listed in a region has been deleted.
+func (c *DeleteCache) Contains(region string, r *Resource) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.deleted[deleteCacheKey(region, r)]
+}
+
+func deleteCacheKey(region string, r *Resource) string {
+	if r.Region != "" {
+		region = r.Region
+	}
+	return region + "/" + string(r.Type) + "/" + r.ID
+}
+
+// IsNotFound checks whether deleting a resource failed, because it doesn't exist (anymore).
+func IsNotFound(err error) bool {
+	return err != nil && ClassifyError(err) == ErrorNotFound
+}
@@ -0,0 +1,42 @@
+package resource_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteCache(t *testing.T) {
+	// given
+	resource.AppFs = afero.NewMemMapFs()
+	vpc := &resource.Resource{Type: resource.Vpc, ID: "vpc-1"}
+	bucket := &resource.Resource{Type: resource.S3Bucket, ID: "foo", Region: "eu-west-1"}
+
+	cache, err := resource.LoadDeleteCache("deleted.yml")
+	require.NoError(t, err)
+	cache.Add("us-east-1", vpc)
+	cache.Add("us-east-1", bucket)
+
+	// when
+	require.NoError(t, cache.Save("deleted.yml"))
+	resumed, err := resource.LoadDeleteCache("deleted.yml")
+	require.NoError(t, err)
+
+	// then
+	assert.True(t, resumed.Contains("us-east-1", vpc))
+	assert.False(t, resumed.Contains("us-west-2", vpc))
+	assert.True(t, resumed.Contains("us-west-2", bucket))
+	assert.False(t, resumed.Contains("us-east-1", &resource.Resource{Type: resource.Vpc, ID: "vpc-2"}))
+}
+
+func TestIsNotFound(t *testing.T) {
+	assert.True(t, resource.IsNotFound(awserr.New("InvalidVpcID.NotFound", "The vpc ID 'vpc-1' does not exist", nil)))
+	assert.True(t, resource.IsNotFound(errors.New("Error deleting S3 Bucket: NoSuchBucket: The specified bucket does not exist")))
+	assert.False(t, resource.IsNotFound(awserr.New("DependencyViolation", "The vpc 'vpc-1' has dependencies", nil)))
+	assert.False(t, resource.IsNotFound(nil))
+}
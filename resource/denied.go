@@ -0,0 +1,64 @@
+package resource
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// deniedActionPattern matches the action in the message of an AccessDenied error,
+// e.g., "User: arn:aws:iam::123456789012:user/foo is not authorized to perform: ec2:DeleteVpc".
+var deniedActionPattern = regexp.MustCompile(`not authorized to perform:? ([a-z0-9-]+:[A-Za-z0-9*]+)`)
+
+// DeniedActions returns the IAM actions that are missing to delete a resource of the given type,
+// which deletion failed with an access denied error. If the error doesn't name the actions,
+// all actions to delete resources of the type are returned.
+func DeniedActions(resType TerraformResourceType, err error) []string {
+	if err == nil || ClassifyError(err) != ErrorAccessDenied {
+		return nil
+	}
+
+	var actions []string
+	for _, match := range deniedActionPattern.FindAllStringSubmatch(err.Error(), -1) {
+		actions = append(actions, match[1])
+	}
+	if len(actions) == 0 {
+		return TypePermissions(resType).Delete
+	}
+	return actions
+}
+
+// DeniedActionsPolicy returns an IAM policy (as JSON) that allows the given actions, with a statement per service.
+func DeniedActionsPolicy(actions []string) ([]byte, error) {
+	actionsPerService := map[string]map[string]bool{}
+	for _, a := range actions {
+		service := strings.SplitN(a, ":", 2)[0]
+		if actionsPerService[service] == nil {
+			actionsPerService[service] = map[string]bool{}
+		}
+		actionsPerService[service][a] = true
+	}
+
+	services := make([]string, 0, len(actionsPerService))
+	for s := range actionsPerService {
+		services = append(services, s)
+	}
+	sort.Strings(services)
+
+	policy := PolicyDocument{Version: "2012-10-17"}
+	for _, s := range services {
+		statement := PolicyStatement{
+			Sid:      strings.Replace(strings.Title(s), "-", "", -1),
+			Effect:   "Allow",
+			Resource: "*",
+		}
+		for a := range actionsPerService[s] {
+			statement.Action = append(statement.Action, a)
+		}
+		sort.Strings(statement.Action)
+		policy.Statement = append(policy.Statement, statement)
+	}
+
+	return json.MarshalIndent(policy, "", "  ")
+}
@@ -0,0 +1,53 @@
+package resource_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeniedActions(t *testing.T) {
+	// given
+	err := errors.New("Error deleting VPC: UnauthorizedOperation: You are not authorized to perform this operation.")
+
+	// when
+	actions := resource.DeniedActions(resource.Vpc, err)
+
+	// then
+	assert.Equal(t, resource.TypePermissions(resource.Vpc).Delete, actions)
+}
+
+func TestDeniedActions_FromErrorMessage(t *testing.T) {
+	// given
+	err := awserr.New("AccessDenied", "User: arn:aws:iam::123456789012:user/foo is not authorized "+
+		"to perform: iam:DeleteRole on resource: role bar", nil)
+
+	// when
+	actions := resource.DeniedActions(resource.IamRole, err)
+
+	// then
+	assert.Equal(t, []string{"iam:DeleteRole"}, actions)
+}
+
+func TestDeniedActions_OtherError(t *testing.T) {
+	assert.Nil(t, resource.DeniedActions(resource.Vpc, awserr.New("DependencyViolation", "has dependencies", nil)))
+}
+
+func TestDeniedActionsPolicy(t *testing.T) {
+	// when
+	policy, err := resource.DeniedActionsPolicy([]string{"iam:DeleteRole", "ec2:DeleteVpc", "iam:DeleteRole", "ec2:DeleteSubnet"})
+
+	// then
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {"Sid": "Ec2", "Effect": "Allow", "Action": ["ec2:DeleteSubnet", "ec2:DeleteVpc"], "Resource": "*"},
+    {"Sid": "Iam", "Effect": "Allow", "Action": ["iam:DeleteRole"], "Resource": "*"}
+  ]
+}`, string(policy))
+}
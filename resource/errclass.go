@@ -0,0 +1,131 @@
+package resource
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/pkg/errors"
+)
+
+// ErrorClass is the kind of failure of an AWS API call, independent of the service that has been called.
+type ErrorClass string
+
+const (
+	// ErrorNotFound means that a resource doesn't exist (anymore).
+	ErrorNotFound ErrorClass = "not found"
+	// ErrorInUse means that a resource can't be deleted, because other resources depend on it.
+	ErrorInUse ErrorClass = "in use"
+	// ErrorAccessDenied means that the used credentials are not allowed to call an API.
+	ErrorAccessDenied ErrorClass = "access denied"
+	// ErrorProtected means that a resource is protected against deletion (e.g., by deletion protection).
+	ErrorProtected ErrorClass = "protected"
+	// ErrorThrottled means that the request rate of an API has been exceeded.
+	ErrorThrottled ErrorClass = "throttled"
+	// ErrorUnknown is any other kind of failure.
+	ErrorUnknown ErrorClass = "unknown"
+)
+
+// errorCodes are the error codes per service that don't follow the naming
+// of the generic ones (e.g., *NotFound, *InUse, AccessDenied*).
+var errorCodes = map[string]map[string]ErrorClass{
+	"apigateway": {
+		"TooManyRequestsException": ErrorThrottled,
+	},
+	"autoscaling": {
+		"ResourceInUse":             ErrorInUse,
+		"ScalingActivityInProgress": ErrorInUse,
+	},
+	"cloudfront": {
+		"DistributionNotDisabled": ErrorInUse,
+		"NoSuchDistribution":      ErrorNotFound,
+	},
+	"cognito-identity": {
+		"TooManyRequestsException": ErrorThrottled,
+	},
+	"cognito-idp": {
+		"TooManyRequestsException": ErrorThrottled,
+	},
+	"ec2": {
+		"DependencyViolation":   ErrorInUse,
+		"UnauthorizedOperation": ErrorAccessDenied,
+		"RequestLimitExceeded":  ErrorThrottled,
+	},
+	"iam": {
+		"DeleteConflict": ErrorInUse,
+		"NoSuchEntity":   ErrorNotFound,
+	},
+	"kms": {
+		"KMSInvalidStateException": ErrorInUse,
+	},
+	"route53": {
+		"HostedZoneNotEmpty": ErrorInUse,
+		"NoSuchHostedZone":   ErrorNotFound,
+	},
+	"s3": {
+		"BucketNotEmpty": ErrorInUse,
+		"NoSuchBucket":   ErrorNotFound,
+	},
+	"states": {
+		"StateMachineDoesNotExist": ErrorNotFound,
+	},
+}
+
+// errorCodePattern matches error codes in the messages of errors that are no awserr.Error
+// (e.g., the ones returned by the Terraform provider), such as "InvalidVpcID.NotFound: The vpc ID ...".
+var errorCodePattern = regexp.MustCompile(`\b([A-Z][A-Za-z0-9]+(?:\.[A-Za-z0-9]+)*):`)
+
+// protectedPattern matches the messages of errors of services that report protection against deletion
+// only by a generic error code (e.g., a ValidationException of DynamoDB).
+var protectedPattern = regexp.MustCompile(`(?i)protected against deletion|deletion protection`)
+
+// ClassifyError returns the class of an error of an AWS API call (ErrorUnknown if err is nil
+// or not an error of an AWS API call).
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ErrorUnknown
+	}
+
+	var codes []string
+	if aerr, ok := errors.Cause(err).(awserr.Error); ok {
+		if request.IsErrorThrottle(aerr) {
+			return ErrorThrottled
+		}
+		codes = append(codes, aerr.Code())
+	}
+	for _, match := range errorCodePattern.FindAllStringSubmatch(err.Error(), -1) {
+		codes = append(codes, match[1])
+	}
+
+	for _, code := range codes {
+		if class := classifyErrorCode(code); class != ErrorUnknown {
+			return class
+		}
+	}
+	if protectedPattern.MatchString(err.Error()) {
+		return ErrorProtected
+	}
+	return ErrorUnknown
+}
+
+func classifyErrorCode(code string) ErrorClass {
+	for _, codes := range errorCodes {
+		if class, found := codes[code]; found {
+			return class
+		}
+	}
+
+	switch {
+	case strings.HasSuffix(code, "NotFound"), strings.HasSuffix(code, "NotFoundException"),
+		strings.HasPrefix(code, "NoSuch"):
+		return ErrorNotFound
+	case strings.HasSuffix(code, "InUse"), strings.HasSuffix(code, "InUseException"):
+		return ErrorInUse
+	case strings.HasPrefix(code, "AccessDenied"), code == "Forbidden":
+		return ErrorAccessDenied
+	case strings.HasPrefix(code, "Throttling"), strings.HasSuffix(code, "ThrottledException"):
+		return ErrorThrottled
+	}
+	return ErrorUnknown
+}
@@ -0,0 +1,41 @@
+package resource_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected resource.ErrorClass
+	}{
+		{awserr.New("InvalidVpcID.NotFound", "The vpc ID 'vpc-1' does not exist", nil), resource.ErrorNotFound},
+		{awserr.New("NoSuchEntity", "The user with name foo cannot be found", nil), resource.ErrorNotFound},
+		{awserr.New("ResourceNotFoundException", "Requested resource not found", nil), resource.ErrorNotFound},
+		{awserr.New("DependencyViolation", "The vpc 'vpc-1' has dependencies", nil), resource.ErrorInUse},
+		{awserr.New("DeleteConflict", "Cannot delete entity, must detach all policies first", nil), resource.ErrorInUse},
+		{awserr.New("InvalidGroup.InUse", "Group sg-1 is used by sg-2", nil), resource.ErrorInUse},
+		{awserr.New("UnauthorizedOperation", "You are not authorized to perform this operation", nil), resource.ErrorAccessDenied},
+		{awserr.New("AccessDeniedException", "User is not authorized", nil), resource.ErrorAccessDenied},
+		{awserr.New("Throttling", "Rate exceeded", nil), resource.ErrorThrottled},
+		{awserr.New("RequestLimitExceeded", "Request limit exceeded", nil), resource.ErrorThrottled},
+		{awserr.New("LimitExceeded", "Cannot exceed quota for UsersPerAccount", nil), resource.ErrorUnknown},
+		{awserr.New("ValidationException", "Resource cannot be deleted as it is currently protected against deletion. "+
+			"Disable deletion protection first.", nil), resource.ErrorProtected},
+		// errors of the Terraform provider only contain the code in their message
+		{errors.New("Error deleting S3 Bucket (foo): BucketNotEmpty: The bucket you tried to delete is not empty"), resource.ErrorInUse},
+		{errors.New("1 error(s) occurred:\n\n* aws_subnet.foo: InvalidSubnetID.NotFound: The subnet ID 'subnet-1' does not exist"), resource.ErrorNotFound},
+		{errors.New("TooManyRequestsException: Too Many Requests"), resource.ErrorThrottled},
+		{errors.New("something went wrong"), resource.ErrorUnknown},
+		{nil, resource.ErrorUnknown},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, resource.ClassifyError(test.err), "%v", test.err)
+	}
+}
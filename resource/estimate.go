@@ -0,0 +1,131 @@
+package resource
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// DeleteWorkers is the number of resources of a type that are deleted in parallel.
+const DeleteWorkers = 10
+
+// deleteProfile describes how long deleting a resource of a particular type typically takes
+// and how many API calls it issues.
+type deleteProfile struct {
+	// latency is the time from refreshing the state of a resource until it is gone
+	latency time.Duration
+	// calls are the API calls to refresh the state, delete the resource (and its dependents, e.g., the objects
+	// of S3 buckets) and poll until it is gone
+	calls int
+}
+
+// defaultDeleteProfile applies to resources that are gone as soon as they are deleted.
+var defaultDeleteProfile = deleteProfile{latency: 2 * time.Second, calls: 3}
+
+// deleteProfiles are the profiles of resource types that take longer to delete than the default.
+var deleteProfiles = map[TerraformResourceType]deleteProfile{
+	AutoscalingGroup:       {latency: 3 * time.Minute, calls: 20},
+	BeanstalkEnvironment:   {latency: 5 * time.Minute, calls: 30},
+	CloudformationStack:    {latency: 3 * time.Minute, calls: 20},
+	CloudfrontDistribution: {latency: 20 * time.Minute, calls: 50},
+	DynamodbTable:          {latency: 30 * time.Second, calls: 8},
+	EcsService:             {latency: 2 * time.Minute, calls: 15},
+	EfsFileSystem:          {latency: 30 * time.Second, calls: 8},
+	FirehoseDeliveryStream: {latency: time.Minute, calls: 10},
+	IamRole:                {latency: 5 * time.Second, calls: 8},
+	IamUser:                {latency: 5 * time.Second, calls: 12},
+	Instance:               {latency: time.Minute, calls: 10},
+	KinesisStream:          {latency: 30 * time.Second, calls: 8},
+	NatGateway:             {latency: time.Minute, calls: 10},
+	RdsCluster:             {latency: 5 * time.Minute, calls: 30},
+	RdsClusterInstance:     {latency: 10 * time.Minute, calls: 60},
+	RedshiftCluster:        {latency: 5 * time.Minute, calls: 30},
+	S3Bucket:               {latency: 10 * time.Second, calls: 6},
+}
+
+// defaultRateLimit is the approximate number of requests per second an API accepts before throttling.
+const defaultRateLimit = 10.0
+
+// rateLimits are the approximate sustained rates (requests per second) of APIs which throttle
+// at rates deviating from the default.
+var rateLimits = map[string]float64{
+	"cloudformation": 2,
+	"ec2":            20,
+	"iam":            5,
+	"kinesis":        5,
+	"rds":            5,
+	"route53":        5,
+	"s3":             100,
+}
+
+// Estimate is the expected duration and number of API calls of deleting resources.
+type Estimate struct {
+	Resources int
+	APICalls  int
+	Duration  time.Duration
+}
+
+// EstimateDeletion estimates deleting the given numbers of resources per type within a region. Types are deleted
+// one after the other, with up to DeleteWorkers resources in parallel, but no faster than the rate limit of their API
+// (or the interval between delete requests of types with very low rate limits).
+func EstimateDeletion(counts map[TerraformResourceType]int) Estimate {
+	var e Estimate
+
+	for resType, n := range counts {
+		if n == 0 {
+			continue
+		}
+
+		p, found := deleteProfiles[resType]
+		if !found {
+			p = defaultDeleteProfile
+		}
+		calls := n * p.calls
+		waves := int(math.Ceil(float64(n) / DeleteWorkers))
+
+		// batches are deleted one after the other with a single call each
+		if d, found := batchDeleters[resType]; found {
+			calls = int(math.Ceil(float64(n) / float64(d.size)))
+			waves = calls
+		}
+
+		duration := time.Duration(waves) * p.latency
+		if paced := time.Duration(n) * DeleteInterval(resType); paced > duration {
+			duration = paced
+		}
+		if throttled := time.Duration(float64(calls) / rateLimit(resType) * float64(time.Second)); throttled > duration {
+			duration = throttled
+		}
+
+		e.Resources += n
+		e.APICalls += calls
+		e.Duration += duration
+	}
+	return e
+}
+
+// CombineEstimates combines the estimates of regions, which are swept in parallel.
+func CombineEstimates(estimates []Estimate) Estimate {
+	var e Estimate
+
+	for _, r := range estimates {
+		e.Resources += r.Resources
+		e.APICalls += r.APICalls
+		if r.Duration > e.Duration {
+			e.Duration = r.Duration
+		}
+	}
+	return e
+}
+
+// rateLimit returns the rate limit of the API resources of a type are deleted with.
+func rateLimit(resType TerraformResourceType) float64 {
+	actions := TypePermissions(resType).Delete
+	if len(actions) == 0 {
+		return defaultRateLimit
+	}
+	if limit, found := rateLimits[strings.SplitN(actions[0], ":", 2)[0]]; found {
+		return limit
+	}
+	return defaultRateLimit
+}
@@ -0,0 +1,71 @@
+package resource_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateDeletion(t *testing.T) {
+	// when
+	e := resource.EstimateDeletion(map[resource.TerraformResourceType]int{
+		resource.Instance: 25,
+		resource.KeyPair:  5,
+	})
+
+	// then
+	assert.Equal(t, 30, e.Resources)
+	assert.Equal(t, 25*10+5*3, e.APICalls)
+	// 3 waves of instances and a single one of key pairs
+	assert.Equal(t, 3*time.Minute+2*time.Second, e.Duration)
+}
+
+func TestEstimateDeletion_RateLimited(t *testing.T) {
+	// when
+	e := resource.EstimateDeletion(map[resource.TerraformResourceType]int{
+		resource.IamPolicy: 100,
+	})
+
+	// then
+	assert.Equal(t, 300, e.APICalls)
+	// the API is throttled at 5 requests per second
+	assert.Equal(t, time.Minute, e.Duration)
+}
+
+func TestEstimateDeletion_Batches(t *testing.T) {
+	// when
+	e := resource.EstimateDeletion(map[resource.TerraformResourceType]int{
+		resource.CloudwatchMetricAlarm: 250,
+	})
+
+	// then
+	assert.Equal(t, 250, e.Resources)
+	// alarms are deleted in batches of 100
+	assert.Equal(t, 3, e.APICalls)
+	assert.Equal(t, 3*2*time.Second, e.Duration)
+}
+
+func TestEstimateDeletion_Paced(t *testing.T) {
+	// when
+	e := resource.EstimateDeletion(map[resource.TerraformResourceType]int{
+		resource.ApiGatewayRestApi: 4,
+	})
+
+	// then
+	assert.Equal(t, 12, e.APICalls)
+	// a REST API can only be deleted every 30 seconds
+	assert.Equal(t, 2*time.Minute, e.Duration)
+}
+
+func TestCombineEstimates(t *testing.T) {
+	// when
+	e := resource.CombineEstimates([]resource.Estimate{
+		{Resources: 1, APICalls: 3, Duration: time.Minute},
+		{Resources: 2, APICalls: 6, Duration: 2 * time.Minute},
+	})
+
+	// then
+	assert.Equal(t, resource.Estimate{Resources: 3, APICalls: 9, Duration: 2 * time.Minute}, e)
+}
@@ -0,0 +1,158 @@
+package resource
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchevents"
+	"github.com/pkg/errors"
+)
+
+const (
+	// EventTypeDeleted is the type of events about successfully deleted resources.
+	EventTypeDeleted = "io.cloudetc.awsweeper.resource.deleted"
+	// EventTypeDeletionFailed is the type of events about resources that failed to be deleted.
+	EventTypeDeletionFailed = "io.cloudetc.awsweeper.resource.deletion_failed"
+
+	eventSource      = "awsweeper"
+	defaultEventBus  = "default"
+	cloudEventsMedia = "application/cloudevents+json"
+)
+
+// EventsConfig configures where to send an event per deleted resource.
+type EventsConfig struct {
+	// Webhook is the URL of an HTTP endpoint the events are posted to.
+	Webhook string `yaml:",omitempty"`
+	// EventBus is the name of the CloudWatch Events (EventBridge) bus the events are put on
+	// (in the region the resource has been listed in).
+	EventBus string `yaml:"event_bus,omitempty"`
+}
+
+// validate checks if the events config is valid (a nil config is valid, as no events are sent at all).
+func (c *EventsConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if c.Webhook == "" && c.EventBus == "" {
+		return fmt.Errorf("events requires a webhook or an event_bus")
+	}
+
+	// PutEvents of the used AWS SDK version doesn't support custom event buses
+	if c.EventBus != "" && c.EventBus != defaultEventBus {
+		return fmt.Errorf("only the %s event bus is supported, got: %s", defaultEventBus, c.EventBus)
+	}
+	return nil
+}
+
+// Event is a CloudEvent (https://cloudevents.io, version 1.0) in structured JSON format
+// about the deletion of a resource.
+type Event struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            EventData `json:"data"`
+}
+
+// EventData is the payload of an event about the deletion of a resource.
+type EventData struct {
+	ResourceType TerraformResourceType `json:"resource_type"`
+	ResourceID   string                `json:"resource_id"`
+	Region       string                `json:"region,omitempty"`
+	Tags         map[string]string     `json:"tags,omitempty"`
+	Created      *time.Time            `json:"created,omitempty"`
+	Error        string                `json:"error,omitempty"`
+}
+
+// NewDeletionEvent creates an event about the deletion of a resource in a region,
+// which failed if err is not nil.
+func NewDeletionEvent(r *Resource, region string, err error) Event {
+	e := Event{
+		SpecVersion:     "1.0",
+		ID:              newEventID(),
+		Source:          eventSource,
+		Type:            EventTypeDeleted,
+		Subject:         fmt.Sprintf("%s/%s", r.Type, r.ID),
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data: EventData{
+			ResourceType: r.Type,
+			ResourceID:   r.ID,
+			Region:       region,
+			Tags:         r.Tags,
+			Created:      r.Created,
+		},
+	}
+
+	if err != nil {
+		e.Type = EventTypeDeletionFailed
+		e.Data.Error = err.Error()
+	}
+	return e
+}
+
+// newEventID returns a random ID, which is unique per event.
+func newEventID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// PostEvent sends an event to an HTTP endpoint (structured content mode).
+func PostEvent(client *http.Client, url string, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Post(url, cloudEventsMedia, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to post event")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("failed to post event: %s", resp.Status)
+	}
+	return nil
+}
+
+// PutEvent puts an event on the default CloudWatch Events (EventBridge) bus.
+// The CloudEvent is the detail of the event.
+func (a *AWS) PutEvent(e Event) error {
+	detail, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	output, err := a.PutEvents(&cloudwatchevents.PutEventsInput{
+		Entries: []*cloudwatchevents.PutEventsRequestEntry{
+			{
+				Source:     aws.String(eventSource),
+				DetailType: aws.String(e.Type),
+				Detail:     aws.String(string(detail)),
+				Time:       aws.Time(e.Time),
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to put event")
+	}
+
+	if aws.Int64Value(output.FailedEntryCount) > 0 && len(output.Entries) > 0 {
+		return errors.Errorf("failed to put event: %s", aws.StringValue(output.Entries[0].ErrorMessage))
+	}
+	return nil
+}
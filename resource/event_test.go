@@ -0,0 +1,126 @@
+package resource_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchevents"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testEventResource = &resource.Resource{
+	Type: resource.Instance,
+	ID:   "i-0123",
+	Tags: map[string]string{"foo": "bar"},
+}
+
+func TestNewDeletionEvent(t *testing.T) {
+	// when
+	e := resource.NewDeletionEvent(testEventResource, "us-west-2", nil)
+
+	// then
+	assert.Equal(t, "1.0", e.SpecVersion)
+	assert.NotEmpty(t, e.ID)
+	assert.Equal(t, "awsweeper", e.Source)
+	assert.Equal(t, resource.EventTypeDeleted, e.Type)
+	assert.Equal(t, "aws_instance/i-0123", e.Subject)
+	assert.Equal(t, "us-west-2", e.Data.Region)
+	assert.Equal(t, testEventResource.Tags, e.Data.Tags)
+	assert.Empty(t, e.Data.Error)
+}
+
+func TestNewDeletionEvent_Failed(t *testing.T) {
+	// when
+	e := resource.NewDeletionEvent(testEventResource, "us-west-2", errors.New("some error"))
+
+	// then
+	assert.Equal(t, resource.EventTypeDeletionFailed, e.Type)
+	assert.Equal(t, "some error", e.Data.Error)
+}
+
+func TestPostEvent(t *testing.T) {
+	// given
+	var received resource.Event
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &received)
+	}))
+	defer server.Close()
+
+	e := resource.NewDeletionEvent(testEventResource, "us-west-2", nil)
+
+	// when
+	err := resource.PostEvent(server.Client(), server.URL, e)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "application/cloudevents+json", contentType)
+	assert.Equal(t, e.ID, received.ID)
+	assert.Equal(t, e.Data.ResourceID, received.Data.ResourceID)
+}
+
+func TestPostEvent_ErrorStatus(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	// when
+	err := resource.PostEvent(server.Client(), server.URL, resource.NewDeletionEvent(testEventResource, "us-west-2", nil))
+
+	// then
+	assert.EqualError(t, err, "failed to post event: 500 Internal Server Error")
+}
+
+func TestAWS_PutEvent(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockCloudWatchEventsAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		CloudWatchEventsAPI: mockObj,
+	}
+
+	e := resource.NewDeletionEvent(testEventResource, "us-west-2", nil)
+
+	mockObj.EXPECT().PutEvents(gomock.Any()).DoAndReturn(
+		func(input *cloudwatchevents.PutEventsInput) (*cloudwatchevents.PutEventsOutput, error) {
+			require.Len(t, input.Entries, 1)
+			assert.Equal(t, "awsweeper", *input.Entries[0].Source)
+			assert.Equal(t, resource.EventTypeDeleted, *input.Entries[0].DetailType)
+			return &cloudwatchevents.PutEventsOutput{FailedEntryCount: aws.Int64(0)}, nil
+		})
+
+	// when
+	err := awsMock.PutEvent(e)
+
+	// then
+	assert.NoError(t, err)
+}
+
+func TestYamlFilter_Validate_CustomEventBus(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg:    resource.Config{resource.Instance: {}},
+		Events: &resource.EventsConfig{EventBus: "custom"},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.EqualError(t, err, "only the default event bus is supported, got: custom")
+}
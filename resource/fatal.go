@@ -0,0 +1,16 @@
+package resource
+
+// FatalError is an error that a sweep can't recover from (e.g., the resources of a type failed to be listed).
+type FatalError struct {
+	Err error
+}
+
+func (e FatalError) Error() string {
+	return e.Err.Error()
+}
+
+// Fatal aborts the sweep of a region because of an error it can't recover from, by panicking with a FatalError.
+// Sweeps recover from it, so that the run (or the request of a server) fails instead of the process exiting.
+func Fatal(err error) {
+	panic(FatalError{Err: err})
+}
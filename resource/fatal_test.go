@@ -0,0 +1,19 @@
+package resource_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFatal(t *testing.T) {
+	// given
+	err := errors.New("AccessDenied")
+
+	// when / then
+	assert.PanicsWithValue(t, resource.FatalError{Err: err}, func() {
+		resource.Fatal(err)
+	})
+}
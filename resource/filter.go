@@ -2,6 +2,8 @@ package resource
 
 import (
 	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -24,8 +26,68 @@ type Config map[TerraformResourceType][]ResourceTypeFilter
 type ResourceTypeFilter struct {
 	ID   *string           `yaml:",omitempty"`
 	Tags map[string]string `yaml:",omitempty"`
+	// select resources whose ARN matches any of the regexes (e.g., IAM roles by path)
+	Arns []string `yaml:",omitempty"`
+	// select autoscaling groups by the tags they propagate to their instances (matched like tags)
+	PropagatedTags map[string]string `yaml:"propagated_tags,omitempty"`
 	// select resources by creation time
 	Created *Created `yaml:",omitempty"`
+	// select resources of regional types listed in one of the given regions
+	Regions []string `yaml:",omitempty"`
+	// select resources that support tags, but don't have any
+	Untagged bool `yaml:",omitempty"`
+	// select resources by comparing the typed attributes of their model (e.g., size_gb: ">100") or their age
+	Attributes map[string]string `yaml:",omitempty"`
+	// select resources that are not used by any other resource (e.g., security groups without network interfaces)
+	Unused bool `yaml:",omitempty"`
+	// select resources by the value of CloudWatch metrics over a period of time (e.g., to find idle resources)
+	Metrics []MetricFilter `yaml:",omitempty"`
+	// keep the latest N revisions per family (e.g., of an ECS task definition) and select all older ones
+	KeepLatest *int `yaml:"keep_latest,omitempty"`
+	// also delete the resources that would be left behind (e.g., the aliases of KMS keys or the keys of KMS aliases)
+	Cascade bool `yaml:",omitempty"`
+	// snapshot the selected resources (e.g., EBS volumes) before deleting them
+	FinalSnapshot bool `yaml:"final_snapshot,omitempty"`
+	// delete the selected resources (e.g., Redshift clusters) without taking a final snapshot
+	SkipFinalSnapshot bool `yaml:"skip_final_snapshot,omitempty"`
+	// delete the resources a stack failed to delete directly, before retrying to delete the stack
+	DeleteBlockingResources bool `yaml:"delete_blocking_resources,omitempty"`
+	// disable the deletion protection of the selected resources (e.g., Cognito user pools) if it prevents
+	// deleting them
+	DisableDeletionProtection bool `yaml:"disable_deletion_protection,omitempty"`
+	// what to do with the selected resources (default: delete them)
+	Action string `yaml:",omitempty"`
+	// tags to write onto the selected resources if the action is apply_tags
+	ApplyTags map[string]string `yaml:"apply_tags,omitempty"`
+	// keys (or regexes matching whole keys) of the tags to remove from the selected resources
+	// if the action is remove_tags
+	RemoveTags []string `yaml:"remove_tags,omitempty"`
+	// number of days to keep the log events of the selected log groups if the action is set_retention
+	RetentionDays int `yaml:"retention_days,omitempty"`
+	// only report the selected resources (and the ones deleted together with them) instead of deleting them,
+	// even if the run isn't a dry run
+	DryRun bool `yaml:"dry_run,omitempty"`
+}
+
+const (
+	// ActionDelete deletes selected resources.
+	ActionDelete = "delete"
+	// ActionApplyTags writes tags onto selected resources instead of deleting them.
+	ActionApplyTags = "apply_tags"
+	// ActionRemoveTags removes tags from selected resources instead of deleting them.
+	ActionRemoveTags = "remove_tags"
+	// ActionDeactivate deactivates selected resources (e.g., access keys) instead of deleting them.
+	ActionDeactivate = "deactivate"
+	// ActionSetRetention sets a retention on selected log groups that never expire instead of deleting them.
+	ActionSetRetention = "set_retention"
+)
+
+// action returns the configured action of the filter entry.
+func (rtf ResourceTypeFilter) action() string {
+	if rtf.Action == "" {
+		return ActionDelete
+	}
+	return rtf.Action
 }
 
 type Created struct {
@@ -33,27 +95,238 @@ type Created struct {
 	After  *time.Time `yaml:",omitempty"`
 }
 
+// configFile represents the content of a yaml config file, i.e. the filters per resource type
+// and the settings that apply to all resource types.
+type configFile struct {
+	// Version is the version of the format of the config (see ConfigVersion).
+	Version int `yaml:",omitempty"`
+	// Preset is the name of a built-in config. Resource types that appear in
+	// the config file replace the ones of the preset.
+	Preset string `yaml:",omitempty"`
+	// Specs are files with definitions of additional resource types.
+	Specs []string `yaml:",omitempty"`
+	// SkipUnknownTypes set to true skips resource types that are not supported (with a warning) instead of failing,
+	// so that configs can be shared with builds of AWSweeper that support more resource types.
+	SkipUnknownTypes bool `yaml:"skip_unknown_types,omitempty"`
+	// DryRun set to false allows deleting resources without the --no-dry-run flag (e.g., for automation).
+	DryRun *bool `yaml:"dry_run,omitempty"`
+	// RequiredTags are the tag keys every resource must have (checked in compliance mode).
+	RequiredTags []string `yaml:"required_tags,omitempty"`
+	// Events configures where to send an event per deleted resource.
+	Events *EventsConfig `yaml:",omitempty"`
+	// Hooks configures commands and Lambda functions that run before and after the sweep.
+	Hooks *HooksConfig `yaml:",omitempty"`
+	// AllowedWindows are the periods of time in which resources are allowed to be deleted.
+	AllowedWindows []Window `yaml:"allowed_windows,omitempty"`
+	// Leases configures where leases are looked up besides the tags of resources.
+	Leases *LeaseConfig `yaml:",omitempty"`
+	// Lock configures a lock that prevents concurrent runs from deleting resources.
+	Lock *LockConfig `yaml:",omitempty"`
+	// Inventory configures where to export all discovered resources.
+	Inventory *InventoryConfig `yaml:",omitempty"`
+	// Audit configures where the results of runs are stored.
+	Audit *AuditConfig `yaml:",omitempty"`
+	// TrustedAdvisor are the names of the Trusted Advisor checks that seed the resources the filters are applied to.
+	TrustedAdvisor []string `yaml:"trusted_advisor,omitempty"`
+	// TerraformCloud configures which resources are managed by Terraform Cloud/Enterprise workspaces.
+	TerraformCloud *TerraformCloudConfig `yaml:"terraform_cloud,omitempty"`
+	// ClustersToSweep are the names of the Kubernetes clusters which resources may be deleted.
+	ClustersToSweep []string `yaml:"clusters_to_sweep,omitempty"`
+	// MaxResourceAge is the age (e.g., 14d) after which resources of any type are deleted.
+	MaxResourceAge string `yaml:"max_resource_age,omitempty"`
+	// Allowlist selects resources that are never deleted.
+	Allowlist []AllowlistEntry `yaml:",omitempty"`
+	// Baseline configures the resources that are expected to remain after a sweep.
+	Baseline *BaselineConfig `yaml:",omitempty"`
+	// RemoveSharing set to true removes the permissions of other accounts on snapshots and AMIs before deleting them.
+	RemoveSharing bool `yaml:"remove_sharing,omitempty"`
+	// Clients tunes the clients of the AWS APIs (retries, timeouts, CAs and proxy).
+	Clients *ClientsConfig `yaml:",omitempty"`
+	// Severities override the severities of resource types (low, medium or high).
+	Severities map[TerraformResourceType]Severity `yaml:",omitempty"`
+	// Profiles are named sets of filters per resource type (e.g., nightly, panic), of which one can be selected
+	// per run. Resource types of the selected profile replace the ones listed outside of profiles.
+	Profiles map[string]map[string][]ResourceTypeFilter `yaml:",omitempty"`
+	Types    map[string][]ResourceTypeFilter            `yaml:",inline"`
+}
+
 // Filter selects resources based on a given yaml config.
 type Filter struct {
 	Cfg Config
+	// ProtectDefaults excludes resources that exist in every account by default
+	// or are managed by AWS (e.g., default VPCs, AWS managed policies).
+	ProtectDefaults bool
+	// RequiredTags are the tag keys every selected resource must have to be compliant.
+	RequiredTags []string
+	// DryRun is the dry-run mode requested by the config (nil if not set).
+	DryRun *bool
+	// Events configures where to send an event per deleted resource (nil if not set).
+	Events *EventsConfig
+	// Hooks configures commands and Lambda functions that run before and after the sweep (nil if not set).
+	Hooks *HooksConfig
+	// Inventory configures where to export all discovered resources (nil if not set).
+	Inventory *InventoryConfig
+	// Audit configures where the results of runs are stored (nil if not set).
+	Audit *AuditConfig
+	// AllowedWindows are the periods of time in which resources are allowed to be deleted
+	// (resources can be deleted at any time if empty).
+	AllowedWindows []Window
+	// Leases configures where leases are looked up besides the tags of resources (nil if not set).
+	Leases *LeaseConfig
+	// Lock configures a lock that prevents concurrent runs from deleting resources (nil if not set).
+	Lock *LockConfig
+	// TrustedAdvisor are the names of the Trusted Advisor checks that flag the resources of a type
+	// the filters are applied to (all resources of types without a check are filtered).
+	TrustedAdvisor []string
+	// TerraformCloud configures which resources are managed by Terraform Cloud/Enterprise workspaces,
+	// which are excluded from or the target of deletion (nil if not set).
+	TerraformCloud *TerraformCloudConfig
+	// ClustersToSweep are the names of the Kubernetes clusters which resources may be deleted
+	// (resources of all other clusters are protected).
+	ClustersToSweep []string
+	// MaxResourceAge is the age after which resources of all types with known creation times are deleted,
+	// regardless of the filters of their type (no maximum if 0).
+	MaxResourceAge time.Duration
+	// Allowlist selects resources that are never deleted.
+	Allowlist []AllowlistEntry
+	// Baseline configures the resources that are expected to remain after a sweep, anything else
+	// that remains is reported as drift (nil if not set).
+	Baseline *BaselineConfig
+	// RemoveSharing removes the permissions of other accounts on snapshots and AMIs before they are deleted,
+	// reporting which accounts had access.
+	RemoveSharing bool
+	// Clients tunes the clients of the AWS APIs (nil to keep their defaults).
+	Clients *ClientsConfig
+	// Severities override the default severities of resource types. Deleting resources of types
+	// with high severity has to be allowed explicitly.
+	Severities map[TerraformResourceType]Severity
+
+	// skipDryRunEntries makes resources which first matching entry has dry_run not match at all
+	skipDryRunEntries bool
 }
 
 // NewFilter creates a new filter based on a config given via a yaml file.
 func NewFilter(yamlFile string) *Filter {
+	return NewProfileFilter(yamlFile, "")
+}
+
+// NewProfileFilter creates a new filter based on a profile of a config given via a yaml file
+// (only the resource types outside of profiles are filtered if the profile is empty).
+func NewProfileFilter(yamlFile, profile string) *Filter {
+	cfgFile := read(yamlFile)
+
+	for _, spec := range cfgFile.Specs {
+		if err := LoadSpecs(spec); err != nil {
+			logrus.WithError(err).Fatalf("Invalid config: %s", yamlFile)
+		}
+	}
+
+	cfg := Config{}
+	if cfgFile.Preset != "" {
+		presetCfg, err := preset(cfgFile.Preset)
+		if err != nil {
+			logrus.WithError(err).Fatalf("Invalid config: %s", yamlFile)
+		}
+		cfg = presetCfg
+	}
+
+	addTypes(cfg, cfgFile.Types, cfgFile.SkipUnknownTypes)
+	if profile != "" {
+		types, found := cfgFile.Profiles[profile]
+		if !found {
+			logrus.Fatalf("Unknown profile %s in config %s (profiles: %s)", profile, yamlFile,
+				strings.Join(profileNames(cfgFile.Profiles), ", "))
+		}
+		addTypes(cfg, types, cfgFile.SkipUnknownTypes)
+	}
+
+	var maxResourceAge time.Duration
+	if cfgFile.MaxResourceAge != "" {
+		var err error
+		maxResourceAge, err = parseDuration(cfgFile.MaxResourceAge)
+		if err != nil {
+			logrus.WithError(err).Fatalf("Invalid max_resource_age in config: %s", yamlFile)
+		}
+	}
+
+	return &Filter{
+		Cfg: cfg,
+		// presets are meant to be safe to use in any account
+		ProtectDefaults: cfgFile.Preset != "",
+		RequiredTags:    cfgFile.RequiredTags,
+		DryRun:          cfgFile.DryRun,
+		Events:          cfgFile.Events,
+		Hooks:           cfgFile.Hooks,
+		Inventory:       cfgFile.Inventory,
+		Audit:           cfgFile.Audit,
+		AllowedWindows:  cfgFile.AllowedWindows,
+		Leases:          cfgFile.Leases,
+		Lock:            cfgFile.Lock,
+		TrustedAdvisor:  cfgFile.TrustedAdvisor,
+		TerraformCloud:  cfgFile.TerraformCloud,
+		ClustersToSweep: cfgFile.ClustersToSweep,
+		MaxResourceAge:  maxResourceAge,
+		Allowlist:       cfgFile.Allowlist,
+		Baseline:        cfgFile.Baseline,
+		RemoveSharing:   cfgFile.RemoveSharing,
+		Clients:         cfgFile.Clients,
+		Severities:      cfgFile.Severities,
+	}
+}
+
+// addTypes adds the filters of resource types to a config, replacing the filters of types already in the config.
+func addTypes(cfg Config, types map[string][]ResourceTypeFilter, skipUnknownTypes bool) {
+	for resType, filters := range types {
+		if skipUnknownTypes && !SupportedResourceType(TerraformResourceType(resType)) {
+			logrus.Warnf("Skipping unsupported resource type found in yaml config: %s", resType)
+			continue
+		}
+		cfg[TerraformResourceType(resType)] = filters
+	}
+}
+
+// profileNames returns the names of the profiles of a config in alphabetical order.
+func profileNames(profiles map[string]map[string][]ResourceTypeFilter) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewNukeFilter creates a filter that selects all resources of every supported type,
+// except the resources AWS creates by default.
+func NewNukeFilter() *Filter {
+	cfg := Config{}
+	for _, resType := range SupportedResourceTypes() {
+		cfg[resType] = []ResourceTypeFilter{}
+	}
+
 	return &Filter{
-		Cfg: read(yamlFile),
+		Cfg:             cfg,
+		ProtectDefaults: true,
 	}
 }
 
 // read reads a filter from a yaml file.
-func read(filename string) Config {
-	var cfg Config
+func read(filename string) configFile {
+	var cfg configFile
 
 	data, err := afero.ReadFile(AppFs, filename)
 	if err != nil {
 		logrus.WithError(err).Fatalf("Failed to read config file: %s", filename)
 	}
 
+	data, version, err := MigrateConfig(data)
+	if err != nil {
+		logrus.WithError(err).Fatalf("Cannot migrate config: %s", filename)
+	}
+	if version != ConfigVersion {
+		logrus.Warnf("Config %s has the outdated format of version %d, run `awsweeper migrate-config %s` to update it",
+			filename, version, filename)
+	}
+
 	err = yaml.UnmarshalStrict([]byte(data), &cfg)
 	if err != nil {
 		logrus.WithError(err).Fatalf("Cannot unmarshal config: %s", filename)
@@ -62,24 +335,151 @@ func read(filename string) Config {
 	return cfg
 }
 
-// Validate checks if all resource types appearing in the config are currently supported.
+// Validate checks if all resource types appearing in the config are currently supported,
+// the configured criteria can be applied to them and the configured actions are valid.
 func (f Filter) Validate() error {
+	if err := f.Events.validate(); err != nil {
+		return err
+	}
+	if err := f.Hooks.validate(); err != nil {
+		return err
+	}
+	if err := f.Inventory.validate(); err != nil {
+		return err
+	}
+	if err := f.Audit.validate(); err != nil {
+		return err
+	}
+	if err := f.Leases.validate(); err != nil {
+		return err
+	}
+	if err := f.Lock.validate(); err != nil {
+		return err
+	}
+	if err := f.TerraformCloud.validate(); err != nil {
+		return err
+	}
+	if err := f.Baseline.validate(); err != nil {
+		return err
+	}
+	if err := f.Clients.validate(); err != nil {
+		return err
+	}
+	if err := f.validateAdvisorChecks(); err != nil {
+		return err
+	}
+	if err := f.validateMaxResourceAge(); err != nil {
+		return err
+	}
+	if err := f.validateSeverities(); err != nil {
+		return err
+	}
+	for _, w := range f.AllowedWindows {
+		if err := w.validate(); err != nil {
+			return err
+		}
+	}
+
 	for _, resType := range f.Types() {
 		if !SupportedResourceType(resType) {
 			return fmt.Errorf("unsupported resource type found in yaml config: %s", resType)
 		}
+
+		caps := TypeCapabilities(resType)
+		for _, rtf := range f.Cfg[resType] {
+			if (rtf.Tags != nil || rtf.Untagged) && !caps.Tags {
+				return fmt.Errorf("filtering by tags is not supported for resource type: %s", resType)
+			}
+			if rtf.PropagatedTags != nil && resType != AutoscalingGroup {
+				return fmt.Errorf("filtering by propagated tags is not supported for resource type: %s", resType)
+			}
+			if rtf.Created != nil && !caps.Created {
+				return fmt.Errorf("filtering by creation time is not supported for resource type: %s", resType)
+			}
+			if err := validateArns(resType, rtf.Arns); err != nil {
+				return err
+			}
+			if err := validateAttributes(resType, rtf.Attributes); err != nil {
+				return err
+			}
+			if err := validateMetrics(resType, rtf.Metrics); err != nil {
+				return err
+			}
+			if err := validateUnused(resType, rtf.Unused); err != nil {
+				return err
+			}
+			if err := validateKeepLatest(resType, rtf.KeepLatest); err != nil {
+				return err
+			}
+			if err := validateCascade(resType, rtf.Cascade); err != nil {
+				return err
+			}
+			if err := validateRegions(resType, rtf.Regions); err != nil {
+				return err
+			}
+			if err := validateFinalSnapshot(resType, rtf); err != nil {
+				return err
+			}
+			if err := validateSkipFinalSnapshot(resType, rtf); err != nil {
+				return err
+			}
+			if err := validateDeleteBlockingResources(resType, rtf); err != nil {
+				return err
+			}
+			if err := validateDisableDeletionProtection(resType, rtf); err != nil {
+				return err
+			}
+
+			if rtf.DryRun && rtf.action() != ActionDelete {
+				return fmt.Errorf("dry_run requires action %s for resource type: %s", ActionDelete, resType)
+			}
+
+			switch rtf.action() {
+			case ActionDelete:
+			case ActionApplyTags:
+				if len(rtf.ApplyTags) == 0 {
+					return fmt.Errorf("action %s requires apply_tags for resource type: %s", ActionApplyTags, resType)
+				}
+			case ActionRemoveTags:
+				if err := validateRemoveTags(resType, rtf); err != nil {
+					return err
+				}
+			case ActionDeactivate:
+				if _, found := deactivateActions[resType]; !found {
+					return fmt.Errorf("action %s is not supported for resource type: %s", ActionDeactivate, resType)
+				}
+			case ActionSetRetention:
+				if err := validateSetRetention(resType, rtf); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("unknown action for resource type %s: %s", resType, rtf.Action)
+			}
+		}
 	}
 	return nil
 }
 
-// Types returns all the resource types in the config.
+// Types returns all the resource types in the config in the order they are swept, which is alphabetical,
+// except that types whose resources are used by resources of other types come last.
 func (f Filter) Types() []TerraformResourceType {
 	resTypes := make([]TerraformResourceType, 0, len(f.Cfg))
 
 	for k := range f.Cfg {
 		resTypes = append(resTypes, k)
 	}
+	for _, resType := range f.maxResourceAgeTypes() {
+		if _, found := f.Cfg[resType]; !found {
+			resTypes = append(resTypes, resType)
+		}
+	}
 
+	sort.Slice(resTypes, func(i, j int) bool {
+		if deletedLast[resTypes[i]] != deletedLast[resTypes[j]] {
+			return deletedLast[resTypes[j]]
+		}
+		return resTypes[i] < resTypes[j]
+	})
 	return resTypes
 }
 
@@ -102,11 +502,31 @@ func (rtf ResourceTypeFilter) matchID(resType TerraformResourceType, id string)
 // MatchesTags checks whether a resource (given by its type and findTags)
 // matches the filter. The keys must match exactly, whereas the tag value is checked against a regex.
 func (rtf ResourceTypeFilter) matchTags(resType TerraformResourceType, tags map[string]string) bool {
-	if rtf.Tags == nil {
+	return tagsMatch(rtf.Tags, tags)
+}
+
+// matchPropagatedTags checks whether the tags an autoscaling group propagates to its instances match the filter,
+// so that groups can be selected by the same tags as the instances they launch.
+func (rtf ResourceTypeFilter) matchPropagatedTags(r *Resource) bool {
+	if rtf.PropagatedTags == nil {
+		return true
+	}
+
+	m, ok := r.Model.(AutoscalingGroupModel)
+	if !ok {
+		return false
+	}
+	return tagsMatch(rtf.PropagatedTags, m.PropagatedTags)
+}
+
+// tagsMatch checks whether tags contain all the keys of the filter with values matching the regex of the filter
+// (a nil filter matches any tags).
+func tagsMatch(filter map[string]string, tags map[string]string) bool {
+	if filter == nil {
 		return true
 	}
 
-	for cfgTagKey, regex := range rtf.Tags {
+	for cfgTagKey, regex := range filter {
 		if tagVal, ok := tags[cfgTagKey]; ok {
 			if matched, err := regexp.MatchString(regex, tagVal); !matched {
 				if err != nil {
@@ -144,21 +564,109 @@ func (rtf ResourceTypeFilter) matchCreated(resType TerraformResourceType, creati
 	return createdAfter && createdBefore
 }
 
-// matches checks whether a resource matches the filter criteria.
+// UsesTags checks whether the filter needs to know the tags of resources of the given type
+// (which is always the case for types of resources that can belong to Kubernetes clusters, and for types whose
+// tags aren't returned by their list API, as lease tags are always honored).
+func (f Filter) UsesTags(resType TerraformResourceType) bool {
+	if len(f.RequiredTags) > 0 || kubernetesTypes[resType] || f.usesAllowlistTags(resType) {
+		return true
+	}
+
+	if _, found := tagFetchers[resType]; found || taggingAPITypes[resType] != "" {
+		return true
+	}
+
+	for _, rtf := range f.Cfg[resType] {
+		if rtf.Tags != nil || rtf.Untagged || rtf.action() == ActionRemoveTags {
+			return true
+		}
+	}
+	return false
+}
+
+// matchUntagged checks whether a resource has no tags, if required by the filter.
+// Resources for which tags are unknown (nil) never match.
+func (rtf ResourceTypeFilter) matchUntagged(tags map[string]string) bool {
+	if !rtf.Untagged {
+		return true
+	}
+
+	return tags != nil && len(tags) == 0
+}
+
+// matches checks whether a resource matches the filter criteria and should be deleted.
 func (f Filter) matches(r *Resource) bool {
+	rtf, found := f.matchingEntry(r)
+
+	return found && rtf.action() == ActionDelete
+}
+
+// matchingEntry returns the first filter entry in the config that matches a resource.
+// An empty entry is returned for resource types without any filter entries, as all resources match,
+// and for resources older than the max resource age that no entry matches.
+func (f Filter) matchingEntry(r *Resource) (ResourceTypeFilter, bool) {
+	if f.allowlisted(r) {
+		return ResourceTypeFilter{}, false
+	}
+
+	if f.ProtectDefaults && r.Default {
+		return ResourceTypeFilter{}, false
+	}
+
+	if f.protectedByCluster(r) {
+		return ResourceTypeFilter{}, false
+	}
+
 	resTypeFilters, found := f.Cfg[r.Type]
 	if !found {
-		return false
+		return ResourceTypeFilter{}, f.exceedsMaxResourceAge(r)
 	}
 
 	if len(resTypeFilters) == 0 {
-		return true
+		return ResourceTypeFilter{}, true
 	}
 
 	for _, rtf := range resTypeFilters {
-		if rtf.matchTags(r.Type, r.Tags) && rtf.matchID(r.Type, r.ID) && rtf.matchCreated(r.Type, r.Created) {
+		if rtf.matchTags(r.Type, r.Tags) && rtf.matchID(r.Type, r.ID) && rtf.matchArns(r) && rtf.matchCreated(r.Type, r.Created) && rtf.matchRegions(r) &&
+			rtf.matchUntagged(r.Tags) && rtf.matchAttributes(r) && rtf.matchMetrics(r) &&
+			rtf.matchUnused(r) && rtf.matchPropagatedTags(r) && rtf.matchKeepLatest(r) {
+			if rtf.DryRun && f.skipDryRunEntries {
+				return ResourceTypeFilter{}, false
+			}
+			return rtf, true
+		}
+	}
+	return ResourceTypeFilter{}, f.exceedsMaxResourceAge(r)
+}
+
+// hasDryRunEntries checks whether any filter entry of a resource type only reports the resources it selects.
+func (f Filter) hasDryRunEntries(resType TerraformResourceType) bool {
+	for _, rtf := range f.Cfg[resType] {
+		if rtf.DryRun {
 			return true
 		}
 	}
 	return false
 }
+
+// Tagging is a resource together with the tags to write onto it.
+type Tagging struct {
+	Resource *Resource
+	Tags     map[string]string
+}
+
+// Taggings returns the resources that match a filter entry with action apply_tags,
+// together with the tags of the matching entry.
+func (f Filter) Taggings(res Resources) []Tagging {
+	var result []Tagging
+
+	for _, r := range res {
+		if rtf, found := f.matchingEntry(r); found && rtf.action() == ActionApplyTags {
+			result = append(result, Tagging{
+				Resource: r,
+				Tags:     rtf.ApplyTags,
+			})
+		}
+	}
+	return result
+}
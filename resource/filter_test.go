@@ -3,8 +3,12 @@ package resource_test
 import (
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/cloudetc/awsweeper/resource"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestYamlFilter_Validate(t *testing.T) {
@@ -71,3 +75,308 @@ func TestYamlFilter_Types(t *testing.T) {
 	assert.Contains(t, resTypes, resource.Vpc)
 	assert.Contains(t, resTypes, resource.Instance)
 }
+
+func TestFilter_Types_DeletionOrder(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.LbTargetGroup: {},
+			resource.Vpc:           {},
+			resource.Lb:            {},
+		},
+	}
+
+	// when
+	resTypes := f.Types()
+
+	// then
+	assert.Equal(t, []resource.TerraformResourceType{resource.Lb, resource.Vpc, resource.LbTargetGroup}, resTypes)
+}
+
+func TestNewNukeFilter(t *testing.T) {
+	// when
+	f := resource.NewNukeFilter()
+
+	// then
+	assert.True(t, f.ProtectDefaults)
+	assert.NoError(t, f.Validate())
+	assert.Len(t, f.Types(), len(resource.SupportedResourceTypes()))
+}
+
+func TestYamlFilter_Validate_ApplyTagsWithoutTags(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {
+				{
+					Action: resource.ActionApplyTags,
+				},
+			},
+		},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.EqualError(t, err, "action apply_tags requires apply_tags for resource type: aws_instance")
+}
+
+func TestYamlFilter_Validate_UnknownAction(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {
+				{
+					Action: "explode",
+				},
+			},
+		},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.EqualError(t, err, "unknown action for resource type aws_instance: explode")
+}
+
+func TestYamlFilter_Validate_UnsupportedCriterion(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.KeyPair: {
+				{
+					Tags: map[string]string{"foo": "bar"},
+				},
+			},
+		},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.EqualError(t, err, "filtering by tags is not supported for resource type: aws_key_pair")
+}
+
+func TestNewFilter_DryRun(t *testing.T) {
+	// given
+	resource.AppFs = afero.NewMemMapFs()
+	afero.WriteFile(resource.AppFs, "config.yml", []byte(`
+dry_run: false
+aws_instance:
+`), 0644)
+
+	// when
+	f := resource.NewFilter("config.yml")
+
+	// then
+	require.NoError(t, f.Validate())
+	require.NotNil(t, f.DryRun)
+	assert.False(t, *f.DryRun)
+	assert.Equal(t, []resource.TerraformResourceType{resource.Instance}, f.Types())
+}
+
+func TestNewFilter_DryRunNotSet(t *testing.T) {
+	// given
+	resource.AppFs = afero.NewMemMapFs()
+	afero.WriteFile(resource.AppFs, "config.yml", []byte(`
+aws_instance:
+`), 0644)
+
+	// when
+	f := resource.NewFilter("config.yml")
+
+	// then
+	assert.Nil(t, f.DryRun)
+}
+
+func TestNewFilter_SkipUnknownTypes(t *testing.T) {
+	// given
+	resource.AppFs = afero.NewMemMapFs()
+	afero.WriteFile(resource.AppFs, "config.yml", []byte(`
+skip_unknown_types: true
+aws_instance:
+aws_not_supported:
+`), 0644)
+
+	// when
+	f := resource.NewFilter("config.yml")
+
+	// then
+	require.NoError(t, f.Validate())
+	assert.Equal(t, []resource.TerraformResourceType{resource.Instance}, f.Types())
+}
+
+func TestNewFilter_UnknownTypes(t *testing.T) {
+	// given
+	resource.AppFs = afero.NewMemMapFs()
+	afero.WriteFile(resource.AppFs, "config.yml", []byte(`
+aws_instance:
+aws_not_supported:
+`), 0644)
+
+	// when
+	f := resource.NewFilter("config.yml")
+
+	// then
+	assert.EqualError(t, f.Validate(), "unsupported resource type found in yaml config: aws_not_supported")
+}
+
+func TestNewProfileFilter(t *testing.T) {
+	// given
+	resource.AppFs = afero.NewMemMapFs()
+	afero.WriteFile(resource.AppFs, "config.yml", []byte(`
+required_tags:
+  - Owner
+aws_key_pair:
+aws_instance:
+  - tags:
+      Environment: ci
+profiles:
+  weekly:
+    aws_instance:
+      - tags:
+          Environment: ci|dev
+    aws_ebs_volume:
+  panic:
+    aws_autoscaling_group:
+`), 0644)
+
+	// when
+	f := resource.NewProfileFilter("config.yml", "weekly")
+
+	// then
+	require.NoError(t, f.Validate())
+	assert.Equal(t, []resource.TerraformResourceType{resource.EbsVolume, resource.Instance, resource.KeyPair}, f.Types())
+	assert.Equal(t, "ci|dev", f.Cfg[resource.Instance][0].Tags["Environment"])
+	assert.Equal(t, []string{"Owner"}, f.RequiredTags)
+}
+
+func TestNewProfileFilter_NoProfile(t *testing.T) {
+	// given
+	resource.AppFs = afero.NewMemMapFs()
+	afero.WriteFile(resource.AppFs, "config.yml", []byte(`
+aws_instance:
+profiles:
+  panic:
+    aws_autoscaling_group:
+`), 0644)
+
+	// when
+	f := resource.NewProfileFilter("config.yml", "")
+
+	// then
+	require.NoError(t, f.Validate())
+	assert.Equal(t, []resource.TerraformResourceType{resource.Instance}, f.Types())
+}
+
+func TestFilter_Apply_PropagatedTags(t *testing.T) {
+	// given
+	groups := []*autoscaling.Group{
+		{
+			AutoScalingGroupName:    aws.String("propagating"),
+			LaunchConfigurationName: aws.String("web-lc"),
+			Tags: []*autoscaling.TagDescription{
+				{Key: aws.String("Env"), Value: aws.String("dev"), PropagateAtLaunch: aws.Bool(true)},
+			},
+		},
+		{
+			AutoScalingGroupName: aws.String("not-propagating"),
+			LaunchTemplate:       &autoscaling.LaunchTemplateSpecification{LaunchTemplateName: aws.String("web-lt")},
+			Tags: []*autoscaling.TagDescription{
+				{Key: aws.String("Env"), Value: aws.String("dev"), PropagateAtLaunch: aws.Bool(false)},
+			},
+		},
+	}
+	res, err := resource.DeletableResources(resource.AutoscalingGroup, groups)
+	require.NoError(t, err)
+
+	apply := func(rtf resource.ResourceTypeFilter) []string {
+		f := &resource.Filter{
+			Cfg: resource.Config{resource.AutoscalingGroup: {rtf}},
+		}
+		require.NoError(t, f.Validate())
+
+		var names []string
+		for _, r := range f.Apply(resource.AutoscalingGroup, res, groups, nil)[0] {
+			names = append(names, r.ID)
+		}
+		return names
+	}
+
+	// when / then
+	assert.Equal(t, []string{"propagating", "not-propagating"},
+		apply(resource.ResourceTypeFilter{Tags: map[string]string{"Env": "dev"}}))
+	assert.Equal(t, []string{"propagating"},
+		apply(resource.ResourceTypeFilter{PropagatedTags: map[string]string{"Env": "dev"}}))
+	assert.Equal(t, []string{"not-propagating"},
+		apply(resource.ResourceTypeFilter{Attributes: map[string]string{"launch_template_name": "^web-"}}))
+	assert.Equal(t, []string{"propagating"},
+		apply(resource.ResourceTypeFilter{Attributes: map[string]string{"launch_configuration_name": "^web-"}}))
+}
+
+func TestYamlFilter_Validate_PropagatedTagsNotSupported(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {{PropagatedTags: map[string]string{"Env": "dev"}}},
+		},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.EqualError(t, err, "filtering by propagated tags is not supported for resource type: aws_instance")
+}
+
+func TestFilter_Apply_DryRunEntries(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {
+				{ID: aws.String("^trial-"), DryRun: true},
+				{ID: aws.String("-ci$")},
+			},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	res := resource.Resources{
+		{Type: resource.Instance, ID: "web-ci"},
+		{Type: resource.Instance, ID: "trial-ci"},
+		{Type: resource.Instance, ID: "prod"},
+	}
+
+	// when
+	result := f.Apply(resource.Instance, res, nil, nil)
+
+	// then
+	require.Len(t, result[0], 2)
+	assert.Equal(t, "web-ci", result[0][0].ID)
+	assert.False(t, result[0][0].DryRun)
+	// the first matching entry decides
+	assert.Equal(t, "trial-ci", result[0][1].ID)
+	assert.True(t, result[0][1].DryRun)
+}
+
+func TestYamlFilter_Validate_DryRunRequiresActionDelete(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {{
+				Action:    resource.ActionApplyTags,
+				ApplyTags: map[string]string{"expiry": "2018-12-31"},
+				DryRun:    true,
+			}},
+		},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.EqualError(t, err, "dry_run requires action delete for resource type: aws_instance")
+}
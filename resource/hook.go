@@ -0,0 +1,152 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/pkg/errors"
+)
+
+const (
+	// HookBefore is the phase of hooks that run before resources are deleted.
+	HookBefore = "before"
+	// HookAfter is the phase of hooks that run after resources have been deleted.
+	HookAfter = "after"
+)
+
+// HooksConfig configures commands and Lambda functions that run before and after the sweep
+// (e.g., to drain a Kubernetes cluster before its autoscaling group is deleted).
+type HooksConfig struct {
+	Before []Hook `yaml:",omitempty"`
+	After  []Hook `yaml:",omitempty"`
+}
+
+// Hook is a command or Lambda function that runs before or after the sweep.
+type Hook struct {
+	// Type restricts the hook to run (per region) before or after the selected resources of a type are deleted
+	// (once before or after the whole sweep if empty)
+	Type TerraformResourceType `yaml:",omitempty"`
+	// Command is a shell command, which gets the HookEvent passed as environment variables
+	Command string `yaml:",omitempty"`
+	// Lambda is the name or ARN of a function, which is invoked synchronously with the HookEvent as payload
+	Lambda string `yaml:",omitempty"`
+}
+
+// HookEvent describes when a hook runs.
+type HookEvent struct {
+	Phase string `json:"phase"`
+	// Type is the resource type the hook runs for (empty for hooks of the whole sweep)
+	Type   TerraformResourceType `json:"resource_type,omitempty"`
+	Region string                `json:"region,omitempty"`
+	// IDs are the IDs of the resources of the type that are (or have been) deleted
+	IDs []string `json:"resource_ids,omitempty"`
+}
+
+// validate checks if the hooks can be run (a nil config is valid, as no hooks run at all).
+func (c *HooksConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+
+	for _, h := range append(c.Before, c.After...) {
+		if (h.Command == "") == (h.Lambda == "") {
+			return fmt.Errorf("a hook requires either a command or a lambda")
+		}
+		if h.Type != "" && !SupportedResourceType(h.Type) {
+			return fmt.Errorf("unsupported resource type of hook: %s", h.Type)
+		}
+	}
+	return nil
+}
+
+// Hooks returns the hooks of a phase that run for a resource type (the hooks of the whole sweep if empty).
+func (c *HooksConfig) Hooks(phase string, resType TerraformResourceType) []Hook {
+	if c == nil {
+		return nil
+	}
+
+	hooks := c.Before
+	if phase == HookAfter {
+		hooks = c.After
+	}
+
+	var result []Hook
+	for _, h := range hooks {
+		if h.Type == resType {
+			result = append(result, h)
+		}
+	}
+	return result
+}
+
+// usesLambda checks whether any hook invokes a Lambda function.
+func (c *HooksConfig) usesLambda() bool {
+	if c == nil {
+		return false
+	}
+
+	for _, h := range append(c.Before, c.After...) {
+		if h.Lambda != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the command or the Lambda function of the hook.
+func (h Hook) String() string {
+	if h.Command != "" {
+		return h.Command
+	}
+	return "lambda " + h.Lambda
+}
+
+// Run runs the hook and writes the output of commands to out. A hook fails if its command exits
+// with a non-zero status or its Lambda function returns an error.
+func (h Hook) Run(a *AWS, e HookEvent, out io.Writer) error {
+	if h.Command != "" {
+		return h.runCommand(e, out)
+	}
+	return h.invokeLambda(a, e)
+}
+
+func (h Hook) runCommand(e HookEvent, out io.Writer) error {
+	cmd := exec.Command("sh", "-c", h.Command)
+	cmd.Env = append(os.Environ(),
+		"AWSWEEPER_PHASE="+e.Phase,
+		"AWSWEEPER_RESOURCE_TYPE="+string(e.Type),
+		"AWSWEEPER_REGION="+e.Region,
+		"AWSWEEPER_RESOURCE_IDS="+strings.Join(e.IDs, "\n"))
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(err, "hook failed: %s", h)
+	}
+	return nil
+}
+
+func (h Hook) invokeLambda(a *AWS, e HookEvent) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	output, err := a.Invoke(&lambda.InvokeInput{
+		FunctionName: aws.String(h.Lambda),
+		Payload:      payload,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "hook failed: %s", h)
+	}
+	if output.FunctionError != nil {
+		return errors.Errorf("hook failed: %s: %s: %s", h, aws.StringValue(output.FunctionError), output.Payload)
+	}
+	return nil
+}
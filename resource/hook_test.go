@@ -0,0 +1,111 @@
+package resource_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/resourcetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHooksConfig_Hooks(t *testing.T) {
+	// given
+	cfg := &resource.HooksConfig{
+		Before: []resource.Hook{
+			{Command: "./notify.sh"},
+			{Type: resource.AutoscalingGroup, Command: "./drain.sh"},
+		},
+		After: []resource.Hook{
+			{Lambda: "terraform-apply"},
+		},
+	}
+
+	// then
+	assert.Equal(t, []resource.Hook{{Command: "./notify.sh"}}, cfg.Hooks(resource.HookBefore, ""))
+	assert.Equal(t, []resource.Hook{{Type: resource.AutoscalingGroup, Command: "./drain.sh"}},
+		cfg.Hooks(resource.HookBefore, resource.AutoscalingGroup))
+	assert.Empty(t, cfg.Hooks(resource.HookAfter, resource.AutoscalingGroup))
+	assert.Equal(t, []resource.Hook{{Lambda: "terraform-apply"}}, cfg.Hooks(resource.HookAfter, ""))
+}
+
+func TestFilter_Validate_Hooks(t *testing.T) {
+	tests := []struct {
+		name string
+		hook resource.Hook
+		err  string
+	}{
+		{"command", resource.Hook{Command: "true"}, ""},
+		{"neither", resource.Hook{}, "a hook requires either a command or a lambda"},
+		{"both", resource.Hook{Command: "true", Lambda: "fn"}, "a hook requires either a command or a lambda"},
+		{"unsupported type", resource.Hook{Type: "aws_foo", Command: "true"}, "unsupported resource type of hook: aws_foo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &resource.Filter{
+				Cfg:   resource.Config{},
+				Hooks: &resource.HooksConfig{After: []resource.Hook{tt.hook}},
+			}
+
+			err := f.Validate()
+
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestHook_Run_Command(t *testing.T) {
+	// given
+	h := resource.Hook{Command: `echo "$AWSWEEPER_PHASE $AWSWEEPER_RESOURCE_TYPE $AWSWEEPER_REGION $AWSWEEPER_RESOURCE_IDS"`}
+	e := resource.HookEvent{
+		Phase:  resource.HookBefore,
+		Type:   resource.AutoscalingGroup,
+		Region: "us-east-1",
+		IDs:    []string{"asg-1", "asg-2"},
+	}
+	var out bytes.Buffer
+
+	// when
+	err := h.Run(nil, e, &out)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "before aws_autoscaling_group us-east-1 asg-1\nasg-2\n", out.String())
+}
+
+func TestHook_Run_CommandFails(t *testing.T) {
+	// given
+	h := resource.Hook{Command: "exit 3"}
+
+	// when
+	err := h.Run(nil, resource.HookEvent{Phase: resource.HookAfter}, &bytes.Buffer{})
+
+	// then
+	assert.EqualError(t, err, "hook failed: exit 3: exit status 3")
+}
+
+func TestHook_Run_LambdaFunctionError(t *testing.T) {
+	// given
+	a, backend := resourcetest.NewAWS(resourcetest.Fixtures{
+		"lambda": {
+			"Invoke": map[string]interface{}{
+				"FunctionError": "Unhandled",
+				"Payload":       "eyJlcnJvck1lc3NhZ2UiOiJib29tIn0=",
+			},
+		},
+	})
+	h := resource.Hook{Lambda: "terraform-apply"}
+
+	// when
+	err := h.Run(a, resource.HookEvent{Phase: resource.HookAfter}, &bytes.Buffer{})
+
+	// then
+	assert.EqualError(t, err, `hook failed: lambda terraform-apply: Unhandled: {"errorMessage":"boom"}`)
+	assert.Equal(t, []string{"lambda:Invoke"}, backend.Calls())
+}
@@ -0,0 +1,108 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createIamAuthorizationDetailsMock(mockCtrl *gomock.Controller, entityType string) *resource.AWS {
+	mockObj := mocks.NewMockIAMAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		IAMAPI: mockObj,
+	}
+
+	mockObj.EXPECT().GetAccountAuthorizationDetailsPages(&iam.GetAccountAuthorizationDetailsInput{
+		Filter: aws.StringSlice([]string{entityType}),
+	}, gomock.Any()).DoAndReturn(
+		func(input *iam.GetAccountAuthorizationDetailsInput,
+			fn func(*iam.GetAccountAuthorizationDetailsOutput, bool) bool) error {
+			fn(&iam.GetAccountAuthorizationDetailsOutput{
+				UserDetailList: []*iam.UserDetail{
+					{
+						UserName:  aws.String("alice"),
+						GroupList: aws.StringSlice([]string{"admins", "devs"}),
+						AttachedManagedPolicies: []*iam.AttachedPolicy{
+							{PolicyArn: aws.String("arn:aws:iam::aws:policy/ReadOnlyAccess")},
+						},
+					},
+				},
+				RoleDetailList: []*iam.RoleDetail{
+					{
+						RoleName: aws.String("ci"),
+						AttachedManagedPolicies: []*iam.AttachedPolicy{
+							{PolicyArn: aws.String("arn:aws:iam::aws:policy/PowerUserAccess")},
+						},
+					},
+				},
+			}, true)
+			return nil
+		})
+
+	return awsMock
+}
+
+func TestIamUserGroupMemberships(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	awsMock := createIamAuthorizationDetailsMock(mockCtrl, iam.EntityTypeUser)
+
+	// when
+	raw, err := awsMock.RawResources(resource.IamUserGroupMembership)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.IamUserGroupMembership, raw)
+	require.NoError(t, err)
+
+	// then
+	require.Len(t, res, 2)
+	assert.Equal(t, "alice/admins", res[0].ID)
+	assert.Equal(t, "alice/devs", res[1].ID)
+	assert.Equal(t, map[string]string{
+		"user":              "alice",
+		"groups.#":          "1",
+		"groups.1802848373": "devs",
+	}, resource.StateAttributes(res[1]))
+}
+
+func TestIamPolicyAttachments(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	for entityType, resType := range map[string]resource.TerraformResourceType{
+		iam.EntityTypeUser: resource.IamUserPolicyAttachment,
+		iam.EntityTypeRole: resource.IamRolePolicyAttachment,
+	} {
+		// given
+		awsMock := createIamAuthorizationDetailsMock(mockCtrl, entityType)
+
+		// when
+		raw, err := awsMock.RawResources(resType)
+		require.NoError(t, err)
+		res, err := resource.DeletableResources(resType, raw)
+		require.NoError(t, err)
+
+		// then
+		require.Len(t, res, 1)
+		if resType == resource.IamUserPolicyAttachment {
+			assert.Equal(t, "alice/arn:aws:iam::aws:policy/ReadOnlyAccess", res[0].ID)
+			assert.Equal(t, map[string]string{
+				"user":       "alice",
+				"policy_arn": "arn:aws:iam::aws:policy/ReadOnlyAccess",
+			}, resource.StateAttributes(res[0]))
+		} else {
+			assert.Equal(t, "ci/arn:aws:iam::aws:policy/PowerUserAccess", res[0].ID)
+			assert.Equal(t, map[string]string{
+				"role":       "ci",
+				"policy_arn": "arn:aws:iam::aws:policy/PowerUserAccess",
+			}, resource.StateAttributes(res[0]))
+		}
+	}
+}
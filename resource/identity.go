@@ -0,0 +1,60 @@
+package resource
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// Identity is the principal of the currently used credentials.
+type Identity struct {
+	Account string
+	Arn     string
+}
+
+// IdentityCache holds the identity of the credentials once it has been looked up. As the identity is the same
+// in all regions, a cache can be shared between the clients of all regions of a run.
+type IdentityCache struct {
+	mu       sync.Mutex
+	identity *Identity
+}
+
+// get returns the cached identity or looks it up (failed lookups aren't cached, so they are retried).
+func (c *IdentityCache) get(lookup func() (*Identity, error)) (*Identity, error) {
+	if c == nil {
+		return lookup()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.identity == nil {
+		identity, err := lookup()
+		if err != nil {
+			return nil, err
+		}
+		c.identity = identity
+	}
+	return c.identity, nil
+}
+
+// Identity returns the account ID and ARN of the currently used credentials.
+func (a *AWS) Identity() (*Identity, error) {
+	return a.Identities.get(func() (*Identity, error) {
+		res, err := a.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+		if err != nil {
+			return nil, err
+		}
+		return &Identity{Account: aws.StringValue(res.Account), Arn: aws.StringValue(res.Arn)}, nil
+	})
+}
+
+// AccountID returns the ID of the AWS account for the currently used credentials.
+func (a *AWS) AccountID() (string, error) {
+	identity, err := a.Identity()
+	if err != nil {
+		return "", err
+	}
+	return identity.Account, nil
+}
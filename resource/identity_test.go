@@ -0,0 +1,64 @@
+package resource_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWS_AccountID_SharedCache(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockSTSAPI(mockCtrl)
+	identities := &resource.IdentityCache{}
+	clients := []*resource.AWS{
+		{STSAPI: mockObj, Region: "eu-west-1", Identities: identities},
+		{STSAPI: mockObj, Region: "us-east-1", Identities: identities},
+	}
+
+	mockObj.EXPECT().GetCallerIdentity(&sts.GetCallerIdentityInput{}).Return(&sts.GetCallerIdentityOutput{
+		Account: aws.String("123456789012"),
+		Arn:     aws.String("arn:aws:iam::123456789012:user/ci"),
+	}, nil).Times(1)
+
+	// when
+	for _, a := range clients {
+		accountID, err := a.AccountID()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "123456789012", accountID)
+	}
+	identity, err := clients[1].Identity()
+	require.NoError(t, err)
+	assert.Equal(t, "arn:aws:iam::123456789012:user/ci", identity.Arn)
+}
+
+func TestAWS_RawResources_CallerIdentityError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockSTSAPI(mockCtrl)
+	a := &resource.AWS{STSAPI: mockObj, Identities: &resource.IdentityCache{}}
+
+	// failed lookups aren't cached
+	mockObj.EXPECT().GetCallerIdentity(gomock.Any()).Return(nil, errors.New("ExpiredToken")).Times(2)
+
+	for _, resType := range []resource.TerraformResourceType{resource.Ami, resource.EbsSnapshot} {
+		// when
+		_, err := a.RawResources(resType)
+
+		// then
+		assert.EqualError(t, err, "ExpiredToken")
+	}
+}
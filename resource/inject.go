@@ -0,0 +1,79 @@
+package resource
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+// injectedErrorCodes are the codes of the errors injected per class.
+var injectedErrorCodes = map[ErrorClass]string{
+	ErrorNotFound:     "InjectedFailure.NotFound",
+	ErrorInUse:        "InjectedFailure.InUse",
+	ErrorAccessDenied: "AccessDenied",
+	ErrorThrottled:    "Throttling",
+	ErrorUnknown:      "InjectedFailure",
+}
+
+// FaultInjection simulates deleting resources (without calling AWS), of which a random subset fails.
+type FaultInjection struct {
+	// Rate is the probability of a deletion to fail (between 0 and 1)
+	Rate float64
+	// Class is the class of the errors deletions fail with
+	Class ErrorClass
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// ParseFaultInjection parses a fault injection given as comma-separated list of settings,
+// e.g., "rate=0.1" or "rate=0.5,class=in_use".
+func ParseFaultInjection(s string) (*FaultInjection, error) {
+	f := &FaultInjection{
+		Class: ErrorUnknown,
+		rnd:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+
+	for _, setting := range strings.Split(s, ",") {
+		kv := strings.SplitN(setting, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid fault injection setting: %s", setting)
+		}
+
+		switch kv[0] {
+		case "rate":
+			rate, err := strconv.ParseFloat(kv[1], 64)
+			if err != nil || rate < 0 || rate > 1 {
+				return nil, fmt.Errorf("invalid fault injection rate (must be between 0 and 1): %s", kv[1])
+			}
+			f.Rate = rate
+		case "class":
+			class := ErrorClass(strings.Replace(kv[1], "_", " ", -1))
+			if _, found := injectedErrorCodes[class]; !found {
+				return nil, fmt.Errorf("invalid fault injection class: %s", kv[1])
+			}
+			f.Class = class
+		default:
+			return nil, fmt.Errorf("unknown fault injection setting: %s", kv[0])
+		}
+	}
+	return f, nil
+}
+
+// Delete simulates deleting a resource. It returns an (injected) error for the given rate of deletions.
+func (f *FaultInjection) Delete(r *Resource) error {
+	f.mu.Lock()
+	fail := f.rnd.Float64() < f.Rate
+	f.mu.Unlock()
+
+	if !fail {
+		return nil
+	}
+	return awserr.New(injectedErrorCodes[f.Class],
+		fmt.Sprintf("injected failure of deleting %s (%s)", r.ID, r.Type), nil)
+}
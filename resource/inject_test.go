@@ -0,0 +1,45 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFaultInjection(t *testing.T) {
+	f, err := resource.ParseFaultInjection("rate=0.1")
+	require.NoError(t, err)
+	assert.Equal(t, 0.1, f.Rate)
+	assert.Equal(t, resource.ErrorUnknown, f.Class)
+
+	f, err = resource.ParseFaultInjection("rate=1,class=access_denied")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, f.Rate)
+	assert.Equal(t, resource.ErrorAccessDenied, f.Class)
+}
+
+func TestParseFaultInjection_Invalid(t *testing.T) {
+	for _, s := range []string{"0.1", "rate=2", "rate=foo", "class=bar", "foo=bar"} {
+		_, err := resource.ParseFaultInjection(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestFaultInjection_Delete(t *testing.T) {
+	// given
+	r := &resource.Resource{Type: resource.Vpc, ID: "vpc-1"}
+
+	// when
+	never, err := resource.ParseFaultInjection("rate=0")
+	require.NoError(t, err)
+	always, err := resource.ParseFaultInjection("rate=1,class=in_use")
+	require.NoError(t, err)
+
+	// then
+	assert.NoError(t, never.Delete(r))
+	err = always.Delete(r)
+	assert.EqualError(t, err, "InjectedFailure.InUse: injected failure of deleting vpc-1 (aws_vpc)")
+	assert.Equal(t, resource.ErrorInUse, resource.ClassifyError(err))
+}
@@ -0,0 +1,106 @@
+package resource
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/pkg/errors"
+)
+
+// dynamoDBBatchSize is the maximum number of items per BatchWriteItem request.
+const dynamoDBBatchSize = 25
+
+// InventoryConfig configures where to export all the resources discovered in a run.
+type InventoryConfig struct {
+	// DynamoDBTable is the name of a table with partition key "resource" and sort key "run_time" (both strings).
+	DynamoDBTable string `yaml:"dynamodb_table,omitempty"`
+}
+
+// validate checks if the inventory config is valid (a nil config is valid, as no inventory is exported at all).
+func (c *InventoryConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if c.DynamoDBTable == "" {
+		return fmt.Errorf("inventory requires a dynamodb_table")
+	}
+	return nil
+}
+
+// InventoryItem is a resource discovered in a particular region during a run.
+type InventoryItem struct {
+	Resource *Resource
+	Region   string
+	// Matched is true if the resource has been selected by the filter.
+	Matched bool
+}
+
+// inventoryRecord is the representation of an inventory item in DynamoDB. Every run adds
+// a record per resource, so the table keeps the history of each resource.
+type inventoryRecord struct {
+	Resource string            `dynamodbav:"resource"`
+	RunTime  string            `dynamodbav:"run_time"`
+	Type     string            `dynamodbav:"type"`
+	ID       string            `dynamodbav:"id"`
+	Region   string            `dynamodbav:"region"`
+	Tags     map[string]string `dynamodbav:"tags,omitempty"`
+	Created  *time.Time        `dynamodbav:"created,omitempty"`
+	Matched  bool              `dynamodbav:"matched"`
+}
+
+// WriteInventory writes the given items of a run (identified by the time it has been started)
+// into a DynamoDB table.
+func (a *AWS) WriteInventory(table string, runTime time.Time, items []InventoryItem) error {
+	var requests []*dynamodb.WriteRequest
+
+	for _, item := range items {
+		av, err := dynamodbattribute.MarshalMap(inventoryRecord{
+			Resource: fmt.Sprintf("%s/%s/%s", item.Resource.Type, item.Region, item.Resource.ID),
+			RunTime:  runTime.UTC().Format(time.RFC3339),
+			Type:     string(item.Resource.Type),
+			ID:       item.Resource.ID,
+			Region:   item.Region,
+			Tags:     item.Resource.Tags,
+			Created:  item.Resource.Created,
+			Matched:  item.Matched,
+		})
+		if err != nil {
+			return err
+		}
+		requests = append(requests, &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{Item: av}})
+	}
+
+	for len(requests) > 0 {
+		n := dynamoDBBatchSize
+		if len(requests) < n {
+			n = len(requests)
+		}
+		pending := requests[:n]
+		requests = requests[n:]
+
+		err := retryThrottled(func() error {
+			output, err := a.BatchWriteItem(&dynamodb.BatchWriteItemInput{
+				RequestItems: map[string][]*dynamodb.WriteRequest{table: pending},
+			})
+			if err != nil {
+				return err
+			}
+
+			// items are left unprocessed if the provisioned throughput of the table is exceeded
+			pending = output.UnprocessedItems[table]
+			if len(pending) > 0 {
+				return awserr.New(dynamodb.ErrCodeProvisionedThroughputExceededException,
+					fmt.Sprintf("%d items unprocessed", len(pending)), nil)
+			}
+			return nil
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to write inventory into table %s", table)
+		}
+	}
+	return nil
+}
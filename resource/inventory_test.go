@@ -0,0 +1,96 @@
+package resource_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWS_WriteInventory(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockDynamoDBAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		DynamoDBAPI: mockObj,
+	}
+
+	var items []resource.InventoryItem
+	for i := 0; i < 30; i++ {
+		items = append(items, resource.InventoryItem{
+			Resource: &resource.Resource{Type: resource.Instance, ID: "i-" + string(rune('a'+i))},
+			Region:   "us-west-2",
+			Matched:  i%2 == 0,
+		})
+	}
+
+	var written []map[string]*dynamodb.AttributeValue
+	mockObj.EXPECT().BatchWriteItem(gomock.Any()).DoAndReturn(
+		func(input *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+			requests := input.RequestItems["inventory"]
+			assert.True(t, len(requests) <= 25)
+			for _, r := range requests {
+				written = append(written, r.PutRequest.Item)
+			}
+			return &dynamodb.BatchWriteItemOutput{}, nil
+		}).Times(2)
+
+	// when
+	err := awsMock.WriteInventory("inventory", time.Date(2018, 11, 17, 5, 0, 0, 0, time.UTC), items)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, written, 30)
+	assert.Equal(t, "aws_instance/us-west-2/i-a", *written[0]["resource"].S)
+	assert.Equal(t, "2018-11-17T05:00:00Z", *written[0]["run_time"].S)
+	assert.True(t, *written[0]["matched"].BOOL)
+	assert.False(t, *written[1]["matched"].BOOL)
+}
+
+func TestAWS_WriteInventory_UnprocessedItems(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockDynamoDBAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		DynamoDBAPI: mockObj,
+	}
+
+	items := []resource.InventoryItem{
+		{Resource: &resource.Resource{Type: resource.Instance, ID: "i-a"}, Region: "us-west-2"},
+		{Resource: &resource.Resource{Type: resource.Instance, ID: "i-b"}, Region: "us-west-2"},
+	}
+
+	gomock.InOrder(
+		mockObj.EXPECT().BatchWriteItem(gomock.Any()).DoAndReturn(
+			func(input *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+				assert.Len(t, input.RequestItems["inventory"], 2)
+				return &dynamodb.BatchWriteItemOutput{
+					UnprocessedItems: map[string][]*dynamodb.WriteRequest{
+						"inventory": input.RequestItems["inventory"][1:],
+					},
+				}, nil
+			}),
+		mockObj.EXPECT().BatchWriteItem(gomock.Any()).DoAndReturn(
+			func(input *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+				require.Len(t, input.RequestItems["inventory"], 1)
+				assert.Equal(t, "aws_instance/us-west-2/i-b",
+					*input.RequestItems["inventory"][0].PutRequest.Item["resource"].S)
+				return &dynamodb.BatchWriteItemOutput{}, nil
+			}),
+	)
+
+	// when
+	err := awsMock.WriteInventory("inventory", time.Now(), items)
+
+	// then
+	assert.NoError(t, err)
+}
@@ -0,0 +1,73 @@
+package resource
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	// kubernetesClusterTagPrefix is the prefix of the tag key that the Kubernetes cloud controllers
+	// put onto resources with the name of the cluster (e.g., kubernetes.io/cluster/prod: owned).
+	kubernetesClusterTagPrefix = "kubernetes.io/cluster/"
+)
+
+// kubernetesClusterTags are the tags other controllers put onto resources with the name of the cluster as value.
+var kubernetesClusterTags = []string{
+	"KubernetesCluster",
+	"elbv2.k8s.aws/cluster",
+	"eks:cluster-name",
+}
+
+// kubernetesTypes are the resource types that the Kubernetes cloud controllers create resources of, i.e.
+// the types of which resources belonging to clusters are protected (their tags are always looked up).
+var kubernetesTypes = map[TerraformResourceType]bool{
+	AutoscalingGroup: true,
+	EbsSnapshot:      true,
+	EbsVolume:        true,
+	Eip:              true,
+	Elb:              true,
+	Instance:         true,
+	Lb:               true,
+	LbTargetGroup:    true,
+	NetworkInterface: true,
+	SecurityGroup:    true,
+}
+
+// KubernetesClusters returns the names of the Kubernetes clusters a resource belongs to, according to its tags.
+func KubernetesClusters(tags map[string]string) []string {
+	var clusters []string
+
+	for k := range tags {
+		if strings.HasPrefix(k, kubernetesClusterTagPrefix) {
+			clusters = append(clusters, strings.TrimPrefix(k, kubernetesClusterTagPrefix))
+		}
+	}
+	for _, k := range kubernetesClusterTags {
+		if v, found := tags[k]; found && v != "" {
+			clusters = append(clusters, v)
+		}
+	}
+	sort.Strings(clusters)
+	return clusters
+}
+
+// protectedByCluster checks whether a resource belongs to a Kubernetes cluster that is not listed
+// to be swept, so that live clusters don't break.
+func (f Filter) protectedByCluster(r *Resource) bool {
+	if !kubernetesTypes[r.Type] {
+		return false
+	}
+
+	for _, cluster := range KubernetesClusters(r.Tags) {
+		sweep := false
+		for _, c := range f.ClustersToSweep {
+			if c == cluster {
+				sweep = true
+			}
+		}
+		if !sweep {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKubernetesClusters(t *testing.T) {
+	assert.Equal(t, []string{"dev", "prod"}, resource.KubernetesClusters(map[string]string{
+		"kubernetes.io/cluster/prod": "owned",
+		"elbv2.k8s.aws/cluster":      "dev",
+		"Name":                       "foo",
+	}))
+	assert.Empty(t, resource.KubernetesClusters(map[string]string{"Name": "foo"}))
+}
+
+func TestFilter_Apply_ProtectsKubernetesClusters(t *testing.T) {
+	// given
+	res := resource.Resources{
+		{Type: resource.Instance, ID: "i-prod", Tags: map[string]string{"kubernetes.io/cluster/prod": "owned"}},
+		{Type: resource.Instance, ID: "i-dev", Tags: map[string]string{"KubernetesCluster": "dev"}},
+		{Type: resource.Instance, ID: "i-other", Tags: map[string]string{"Name": "other"}},
+	}
+	f := &resource.Filter{
+		Cfg:             resource.Config{resource.Instance: {}},
+		ClustersToSweep: []string{"dev"},
+	}
+
+	// when
+	result := f.Apply(resource.Instance, res, nil, nil)
+
+	// then
+	var ids []string
+	for _, r := range result[0] {
+		ids = append(ids, r.ID)
+	}
+	assert.Equal(t, []string{"i-dev", "i-other"}, ids)
+}
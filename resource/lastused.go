@@ -0,0 +1,165 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/csv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/pkg/errors"
+)
+
+// LastUsedAttribute is the name of the attribute to compare the time since resources have been used the last time.
+// Resources that have never been used are compared by the time since their creation.
+const LastUsedAttribute = "last_used"
+
+const (
+	credentialReportAttempts = 10
+	credentialReportInterval = 2 * time.Second
+)
+
+// lastUsedLookups look up when the given resources of a type have been used the last time.
+var lastUsedLookups = map[TerraformResourceType]func(a *AWS, res Resources) error{
+	CloudwatchLogStream: (*AWS).logStreamsLastUsed,
+	IamAccessKey:        (*AWS).iamAccessKeysLastUsed,
+	IamUser:             (*AWS).iamUsersLastUsed,
+}
+
+// lastUsedActions are the actions needed to look up when resources of a type have been used the last time.
+var lastUsedActions = map[TerraformResourceType][]string{
+	IamAccessKey: {"iam:GetAccessKeyLastUsed"},
+	IamUser:      {"iam:GenerateCredentialReport", "iam:GetCredentialReport"},
+}
+
+// usesLastUsed checks whether the filter compares resources of the given type by the time of their last use.
+func (f Filter) usesLastUsed(resType TerraformResourceType) bool {
+	for _, rtf := range f.Cfg[resType] {
+		if _, found := rtf.Attributes[LastUsedAttribute]; found {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchLastUsed looks up when the given resources have been used the last time, if the filter needs to know.
+func (f Filter) fetchLastUsed(resType TerraformResourceType, res Resources, a *AWS) error {
+	lookup, found := lastUsedLookups[resType]
+	if !found || !f.usesLastUsed(resType) || len(res) == 0 {
+		return nil
+	}
+	return lookup(a, res)
+}
+
+// lastUsedOrCreated returns the time a resource has been used the last time or,
+// if it has never been used, the time it has been created.
+func lastUsedOrCreated(r *Resource) *time.Time {
+	if r.lastUsed != nil {
+		return r.lastUsed
+	}
+	return r.Created
+}
+
+// logStreamsLastUsed sets the time of the latest event of log streams as the time they have been used,
+// which has already been listed along with the streams.
+func (a *AWS) logStreamsLastUsed(res Resources) error {
+	for _, r := range res {
+		if m, ok := r.Model.(LogStreamModel); ok {
+			r.lastUsed = m.lastEvent
+		}
+	}
+	return nil
+}
+
+// iamUsersLastUsed sets the time IAM users have been used the last time, which is the latest use
+// of their password or any of their access keys according to the credential report.
+func (a *AWS) iamUsersLastUsed(res Resources) error {
+	report, err := a.credentialReport()
+	if err != nil {
+		return err
+	}
+
+	for _, r := range res {
+		r.lastUsed = report[r.ID]
+	}
+	return nil
+}
+
+// iamAccessKeysLastUsed sets the time access keys have been used the last time.
+func (a *AWS) iamAccessKeysLastUsed(res Resources) error {
+	for _, r := range res {
+		var output *iam.GetAccessKeyLastUsedOutput
+		err := retryThrottled(func() error {
+			var err error
+			output, err = a.GetAccessKeyLastUsed(&iam.GetAccessKeyLastUsedInput{
+				AccessKeyId: aws.String(r.ID),
+			})
+			return err
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get last use of access key %s", r.ID)
+		}
+		if output.AccessKeyLastUsed != nil {
+			r.lastUsed = output.AccessKeyLastUsed.LastUsedDate
+		}
+	}
+	return nil
+}
+
+// credentialReport returns the latest use of the credentials per IAM user name, as given by the
+// credential report of the account (which is generated if none exists or it is outdated).
+func (a *AWS) credentialReport() (map[string]*time.Time, error) {
+	for i := 0; ; i++ {
+		output, err := a.GenerateCredentialReport(&iam.GenerateCredentialReportInput{})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to generate credential report")
+		}
+		if aws.StringValue(output.State) == iam.ReportStateTypeComplete {
+			break
+		}
+		if i == credentialReportAttempts-1 {
+			return nil, errors.New("credential report has not been generated in time")
+		}
+		time.Sleep(credentialReportInterval)
+	}
+
+	output, err := a.GetCredentialReport(&iam.GetCredentialReportInput{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get credential report")
+	}
+
+	rows, err := csv.NewReader(bytes.NewReader(output.Content)).ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse credential report")
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("credential report is empty")
+	}
+
+	columns := map[string]int{}
+	for i, name := range rows[0] {
+		columns[name] = i
+	}
+	lastUsedColumns := []string{"password_last_used", "access_key_1_last_used_date", "access_key_2_last_used_date"}
+
+	report := map[string]*time.Time{}
+	for _, row := range rows[1:] {
+		var latest *time.Time
+		for _, name := range lastUsedColumns {
+			i, found := columns[name]
+			if !found || i >= len(row) {
+				continue
+			}
+			// values are either a timestamp, N/A or no_information
+			t, err := time.Parse(time.RFC3339, row[i])
+			if err != nil {
+				continue
+			}
+			if latest == nil || t.After(*latest) {
+				latest = &t
+			}
+		}
+		report[row[columns["user"]]] = latest
+	}
+	return report, nil
+}
@@ -0,0 +1,130 @@
+package resource_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/cloudetc/awsweeper/resource/resourcetest"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Apply_LastUsed(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockIAMAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		IAMAPI: mockObj,
+	}
+
+	recently := time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339)
+	longAgo := time.Now().Add(-365 * 24 * time.Hour).UTC().Format(time.RFC3339)
+
+	mockObj.EXPECT().GenerateCredentialReport(gomock.Any()).Return(&iam.GenerateCredentialReportOutput{
+		State: aws.String(iam.ReportStateTypeComplete),
+	}, nil)
+	mockObj.EXPECT().GetCredentialReport(gomock.Any()).Return(&iam.GetCredentialReportOutput{
+		Content: []byte("user,arn,password_last_used,access_key_1_last_used_date,access_key_2_last_used_date\n" +
+			"active,arn:aws:iam::123456789012:user/active,N/A," + longAgo + "," + recently + "\n" +
+			"stale,arn:aws:iam::123456789012:user/stale," + longAgo + ",N/A,N/A\n" +
+			"new,arn:aws:iam::123456789012:user/new,no_information,N/A,N/A\n" +
+			"never,arn:aws:iam::123456789012:user/never,no_information,N/A,N/A\n"),
+	}, nil)
+	mockObj.EXPECT().ListUserPolicies(gomock.Any()).Return(&iam.ListUserPoliciesOutput{}, nil).AnyTimes()
+	mockObj.EXPECT().ListAttachedUserPolicies(gomock.Any()).Return(&iam.ListAttachedUserPoliciesOutput{}, nil).AnyTimes()
+
+	users := []*iam.User{
+		{UserName: aws.String("active"), CreateDate: aws.Time(time.Now().Add(-400 * 24 * time.Hour))},
+		{UserName: aws.String("stale"), CreateDate: aws.Time(time.Now().Add(-400 * 24 * time.Hour))},
+		{UserName: aws.String("new"), CreateDate: aws.Time(time.Now().Add(-time.Hour))},
+		{UserName: aws.String("never"), CreateDate: aws.Time(time.Now().Add(-400 * 24 * time.Hour))},
+	}
+	res, err := resource.DeletableResources(resource.IamUser, users)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.IamUser: {{Attributes: map[string]string{"last_used": ">180d"}}},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	result := f.Apply(resource.IamUser, res, users, awsMock)
+
+	// then
+	var ids []string
+	for _, r := range result[2] {
+		ids = append(ids, r.ID)
+	}
+	assert.Equal(t, []string{"stale", "never"}, ids)
+}
+
+func TestYamlFilter_Validate_LastUsedNotSupported(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Vpc: {{Attributes: map[string]string{"last_used": ">180d"}}},
+		},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.EqualError(t, err, "filtering by last use is not supported for resource type: aws_vpc")
+}
+
+func TestFilter_Apply_LogStreamsLastUsed(t *testing.T) {
+	// given
+	millis := func(d time.Duration) int64 {
+		return time.Now().Add(-d).UnixNano() / int64(time.Millisecond)
+	}
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"logs": {
+			"DescribeLogGroups": map[string]interface{}{
+				"LogGroups": []interface{}{
+					map[string]interface{}{"LogGroupName": "/aws/lambda/foo"},
+				},
+			},
+			"DescribeLogStreams": map[string]interface{}{
+				"LogStreams": []interface{}{
+					map[string]interface{}{"LogStreamName": "dead", "CreationTime": millis(400 * 24 * time.Hour),
+						"LastEventTimestamp": millis(60 * 24 * time.Hour)},
+					map[string]interface{}{"LogStreamName": "live", "CreationTime": millis(400 * 24 * time.Hour),
+						"LastEventTimestamp": millis(time.Hour)},
+					map[string]interface{}{"LogStreamName": "empty", "CreationTime": millis(40 * 24 * time.Hour)},
+				},
+			},
+		},
+	})
+	raw, err := a.RawResources(resource.CloudwatchLogStream)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.CloudwatchLogStream, raw)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.CloudwatchLogStream: {{Attributes: map[string]string{"log_group": "^/aws/lambda/", "last_used": ">30d"}}},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	result := f.Apply(resource.CloudwatchLogStream, res, raw, a)
+
+	// then
+	var ids []string
+	for _, r := range result[0] {
+		ids = append(ids, r.ID)
+	}
+	assert.Equal(t, []string{"dead", "empty"}, ids)
+	assert.Equal(t, map[string]string{"log_group_name": "/aws/lambda/foo"}, resource.StateAttributes(result[0][0]))
+}
@@ -0,0 +1,94 @@
+package resource
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/pkg/errors"
+)
+
+// LeaseTagKey is the key of the tag that protects a resource from deletion until the time
+// given as tag value (in RFC 3339 format, e.g., 2018-11-17T05:00:00Z).
+const LeaseTagKey = "awsweeper:lease-until"
+
+// LeaseConfig configures where leases are looked up besides the tags of resources.
+type LeaseConfig struct {
+	// SSMPrefix is the path of the SSM parameters that hold leases. The name of
+	// a parameter is <prefix>/<resource type>/<resource id>, the value is the time the lease ends.
+	SSMPrefix string `yaml:"ssm_prefix,omitempty"`
+}
+
+// validate checks if the lease config is valid (a nil config is valid, as leases are only looked up in tags).
+func (c *LeaseConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if !strings.HasPrefix(c.SSMPrefix, "/") {
+		return fmt.Errorf("leases requires an ssm_prefix starting with /, got: %s", c.SSMPrefix)
+	}
+	return nil
+}
+
+// Leases are the times leases end at per resource type and ID (as stored in SSM parameters).
+type Leases map[TerraformResourceType]map[string]string
+
+// SSMLeases reads all leases stored as SSM parameters under the given path.
+func (a *AWS) SSMLeases(prefix string) (Leases, error) {
+	prefix = strings.TrimSuffix(prefix, "/") + "/"
+	leases := Leases{}
+
+	err := a.GetParametersByPathPages(&ssm.GetParametersByPathInput{
+		Path:      aws.String(prefix),
+		Recursive: aws.Bool(true),
+	}, func(page *ssm.GetParametersByPathOutput, lastPage bool) bool {
+		for _, p := range page.Parameters {
+			typeAndID := strings.SplitN(strings.TrimPrefix(aws.StringValue(p.Name), prefix), "/", 2)
+			if len(typeAndID) != 2 {
+				continue
+			}
+
+			resType := TerraformResourceType(typeAndID[0])
+			if leases[resType] == nil {
+				leases[resType] = map[string]string{}
+			}
+			leases[resType][typeAndID[1]] = aws.StringValue(p.Value)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read leases from SSM parameters under %s", prefix)
+	}
+	return leases, nil
+}
+
+// Leased checks whether a resource is protected from deletion at the given point in time by a lease,
+// i.e. by the tag awsweeper:lease-until or an SSM parameter. Leases that cannot be parsed never end,
+// so that resources are not deleted by mistake. For the same reason, resources whose tags failed to be looked up
+// are leased.
+func Leased(r *Resource, leases Leases, now time.Time) bool {
+	if r.tagsFailed {
+		return true
+	}
+
+	if until, found := r.Tags[LeaseTagKey]; found && leaseActive(until, now) {
+		return true
+	}
+
+	if until, found := leases[r.Type][r.ID]; found && leaseActive(until, now) {
+		return true
+	}
+	return false
+}
+
+// leaseActive checks whether a lease ending at the given time is still active.
+func leaseActive(until string, now time.Time) bool {
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(until))
+	if err != nil {
+		return true
+	}
+	return now.Before(t)
+}
@@ -0,0 +1,112 @@
+package resource_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testLeaseNow = time.Date(2018, 11, 17, 5, 0, 0, 0, time.UTC)
+
+func TestLeased_Tag(t *testing.T) {
+	r := &resource.Resource{
+		Type: resource.Instance,
+		ID:   "i-0123",
+		Tags: map[string]string{resource.LeaseTagKey: "2018-11-17T06:00:00Z"},
+	}
+
+	assert.True(t, resource.Leased(r, nil, testLeaseNow))
+	assert.False(t, resource.Leased(r, nil, testLeaseNow.Add(2*time.Hour)))
+}
+
+func TestLeased_InvalidTimestamp(t *testing.T) {
+	r := &resource.Resource{
+		Type: resource.Instance,
+		ID:   "i-0123",
+		Tags: map[string]string{resource.LeaseTagKey: "tomorrow"},
+	}
+
+	assert.True(t, resource.Leased(r, nil, testLeaseNow))
+}
+
+func TestLeased_NoLease(t *testing.T) {
+	r := &resource.Resource{
+		Type: resource.Instance,
+		ID:   "i-0123",
+	}
+
+	assert.False(t, resource.Leased(r, resource.Leases{}, testLeaseNow))
+}
+
+func TestLeased_TagsFailed(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockS3API(mockCtrl)
+	awsMock := &resource.AWS{S3API: mockObj}
+
+	mockObj.EXPECT().GetBucketTagging(gomock.Any()).Return(nil, errors.New("AccessDenied"))
+	res := resource.Resources{{Type: resource.S3Bucket, ID: "leased-maybe"}}
+
+	// when
+	err := awsMock.FetchTags(res)
+
+	// then
+	assert.Error(t, err)
+	assert.True(t, resource.Leased(res[0], nil, testLeaseNow))
+}
+
+func TestFilter_UsesTags_Leases(t *testing.T) {
+	// the tags of buckets aren't listed, but they may have a lease tag
+	assert.True(t, resource.Filter{}.UsesTags(resource.S3Bucket))
+	assert.True(t, resource.Filter{}.UsesTags(resource.LambdaFunction))
+}
+
+func TestAWS_SSMLeases(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockSSMAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		SSMAPI: mockObj,
+	}
+
+	mockObj.EXPECT().GetParametersByPathPages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(input *ssm.GetParametersByPathInput, fn func(*ssm.GetParametersByPathOutput, bool) bool) error {
+			assert.Equal(t, "/awsweeper/leases/", *input.Path)
+			fn(&ssm.GetParametersByPathOutput{
+				Parameters: []*ssm.Parameter{
+					{
+						Name:  aws.String("/awsweeper/leases/aws_instance/i-0123"),
+						Value: aws.String("2018-11-17T06:00:00Z"),
+					},
+					{
+						Name:  aws.String("/awsweeper/leases/invalid"),
+						Value: aws.String("2018-11-17T06:00:00Z"),
+					},
+				},
+			}, true)
+			return nil
+		})
+
+	// when
+	leases, err := awsMock.SSMLeases("/awsweeper/leases")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, resource.Leases{
+		resource.Instance: {"i-0123": "2018-11-17T06:00:00Z"},
+	}, leases)
+	assert.True(t, resource.Leased(&resource.Resource{Type: resource.Instance, ID: "i-0123"}, leases, testLeaseNow))
+	assert.False(t, resource.Leased(&resource.Resource{Type: resource.Instance, ID: "i-4567"}, leases, testLeaseNow))
+}
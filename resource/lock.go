@@ -0,0 +1,183 @@
+package resource
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultLockDuration is how long a run lock is held without being renewed.
+const defaultLockDuration = 10 * time.Minute
+
+// ErrLocked is returned if the run lock is held by another run.
+var ErrLocked = errors.New("the run lock is held by another run")
+
+// LockConfig configures a lock that prevents concurrent runs against the same account from deleting resources
+// at the same time.
+type LockConfig struct {
+	// DynamoDBTable is the name of a table with partition key "lock_id" (string).
+	DynamoDBTable string `yaml:"dynamodb_table,omitempty"`
+	// Duration is how long the lock is held if the run holding it dies without releasing it (default: 10m).
+	Duration string `yaml:",omitempty"`
+}
+
+// validate checks if the lock config is valid (a nil config is valid, as no lock is used at all).
+func (c *LockConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if c.DynamoDBTable == "" {
+		return fmt.Errorf("lock requires a dynamodb_table")
+	}
+	if c.Duration != "" {
+		if d, err := time.ParseDuration(c.Duration); err != nil || d <= 0 {
+			return fmt.Errorf("invalid lock duration: %s", c.Duration)
+		}
+	}
+	return nil
+}
+
+// LockDuration returns how long the lock is held without being renewed.
+func (c *LockConfig) LockDuration() time.Duration {
+	if c.Duration == "" {
+		return defaultLockDuration
+	}
+
+	d, err := time.ParseDuration(c.Duration)
+	if err != nil {
+		return defaultLockDuration
+	}
+	return d
+}
+
+// RunLock is a lock held in a DynamoDB table, which is renewed in the background until it is released.
+type RunLock struct {
+	a        *AWS
+	table    string
+	id       string
+	owner    string
+	duration time.Duration
+
+	// lost is set once the lock failed to be renewed, after which it isn't renewed anymore
+	lost int32
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// AcquireLock acquires the lock with the given ID (e.g., the account ID) in a DynamoDB table.
+// It returns ErrLocked if the lock is held by another run and hasn't expired yet.
+func (a *AWS) AcquireLock(table, id string, duration time.Duration) (*RunLock, error) {
+	hostname, _ := os.Hostname()
+
+	l := &RunLock{
+		a:        a,
+		table:    table,
+		id:       id,
+		owner:    fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), newEventID()),
+		duration: duration,
+		stop:     make(chan struct{}),
+	}
+
+	if err := l.put(false); err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return nil, ErrLocked
+		}
+		return nil, errors.Wrapf(err, "failed to acquire lock in table %s", table)
+	}
+
+	l.wg.Add(1)
+	go l.renew()
+
+	return l, nil
+}
+
+// put writes the lock item, if the lock is free (or has expired).
+// If renew is set, the lock is also written if it's held by this run.
+func (l *RunLock) put(renew bool) error {
+	now := time.Now()
+
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String(l.table),
+		Item: map[string]*dynamodb.AttributeValue{
+			"lock_id": {S: aws.String(l.id)},
+			"owner":   {S: aws.String(l.owner)},
+			"expires": {N: aws.String(strconv.FormatInt(now.Add(l.duration).Unix(), 10))},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(lock_id) OR expires < :now"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":now": {N: aws.String(strconv.FormatInt(now.Unix(), 10))},
+		},
+	}
+
+	if renew {
+		// owner is a reserved word
+		input.ConditionExpression = aws.String("#owner = :owner OR expires < :now")
+		input.ExpressionAttributeNames = map[string]*string{"#owner": aws.String("owner")}
+		input.ExpressionAttributeValues[":owner"] = &dynamodb.AttributeValue{S: aws.String(l.owner)}
+	}
+
+	_, err := l.a.PutItem(input)
+	return err
+}
+
+// renew extends the lock regularly until it is released or fails to be renewed.
+func (l *RunLock) renew() {
+	defer l.wg.Done()
+
+	ticker := time.NewTicker(l.duration / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			if err := l.put(true); err != nil {
+				logrus.WithError(err).Warn("Failed to renew run lock")
+				atomic.StoreInt32(&l.lost, 1)
+				return
+			}
+		}
+	}
+}
+
+// Lost checks whether the lock failed to be renewed, in which case it might have expired and another run might
+// delete resources at the same time. A nil lock is never lost.
+func (l *RunLock) Lost() bool {
+	return l != nil && atomic.LoadInt32(&l.lost) == 1
+}
+
+// Release stops renewing the lock and deletes it, unless another run has taken it over in the meantime.
+func (l *RunLock) Release() error {
+	close(l.stop)
+	l.wg.Wait()
+
+	_, err := l.a.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName: aws.String(l.table),
+		Key: map[string]*dynamodb.AttributeValue{
+			"lock_id": {S: aws.String(l.id)},
+		},
+		ConditionExpression: aws.String("#owner = :owner"),
+		ExpressionAttributeNames: map[string]*string{
+			"#owner": aws.String("owner"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":owner": {S: aws.String(l.owner)},
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to release lock in table %s", l.table)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package resource_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWS_AcquireLock(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockDynamoDBAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		DynamoDBAPI: mockObj,
+	}
+
+	var owner string
+	mockObj.EXPECT().PutItem(gomock.Any()).DoAndReturn(
+		func(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			assert.Equal(t, "locks", *input.TableName)
+			assert.Equal(t, "123456789012", *input.Item["lock_id"].S)
+			assert.Equal(t, "attribute_not_exists(lock_id) OR expires < :now", *input.ConditionExpression)
+			owner = *input.Item["owner"].S
+			return &dynamodb.PutItemOutput{}, nil
+		})
+	mockObj.EXPECT().DeleteItem(gomock.Any()).DoAndReturn(
+		func(input *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+			assert.Equal(t, "123456789012", *input.Key["lock_id"].S)
+			assert.Equal(t, owner, *input.ExpressionAttributeValues[":owner"].S)
+			return &dynamodb.DeleteItemOutput{}, nil
+		})
+
+	// when
+	lock, err := awsMock.AcquireLock("locks", "123456789012", time.Hour)
+	require.NoError(t, err)
+
+	// then
+	assert.NoError(t, lock.Release())
+}
+
+func TestAWS_AcquireLock_Locked(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockDynamoDBAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		DynamoDBAPI: mockObj,
+	}
+
+	mockObj.EXPECT().PutItem(gomock.Any()).Return(nil,
+		awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil))
+
+	// when
+	_, err := awsMock.AcquireLock("locks", "123456789012", time.Hour)
+
+	// then
+	assert.Equal(t, resource.ErrLocked, err)
+}
+
+func TestAWS_AcquireLock_Renew(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockDynamoDBAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		DynamoDBAPI: mockObj,
+	}
+
+	renewed := make(chan *dynamodb.PutItemInput, 10)
+	mockObj.EXPECT().PutItem(gomock.Any()).Return(&dynamodb.PutItemOutput{}, nil)
+	mockObj.EXPECT().PutItem(gomock.Any()).DoAndReturn(
+		func(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			renewed <- input
+			return &dynamodb.PutItemOutput{}, nil
+		}).MinTimes(1)
+	mockObj.EXPECT().DeleteItem(gomock.Any()).Return(&dynamodb.DeleteItemOutput{}, nil)
+
+	// when
+	lock, err := awsMock.AcquireLock("locks", "123456789012", 30*time.Millisecond)
+	require.NoError(t, err)
+	input := <-renewed
+	require.NoError(t, lock.Release())
+
+	// then
+	assert.Equal(t, "#owner = :owner OR expires < :now", *input.ConditionExpression)
+}
+
+func TestAWS_AcquireLock_RenewFails(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockDynamoDBAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		DynamoDBAPI: mockObj,
+	}
+
+	failed := make(chan struct{})
+	mockObj.EXPECT().PutItem(gomock.Any()).Return(&dynamodb.PutItemOutput{}, nil)
+	mockObj.EXPECT().PutItem(gomock.Any()).DoAndReturn(
+		func(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			defer close(failed)
+			return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+		})
+	mockObj.EXPECT().DeleteItem(gomock.Any()).Return(&dynamodb.DeleteItemOutput{}, nil)
+
+	// when
+	lock, err := awsMock.AcquireLock("locks", "123456789012", 30*time.Millisecond)
+	require.NoError(t, err)
+	assert.False(t, lock.Lost())
+	<-failed
+	require.NoError(t, lock.Release())
+
+	// then
+	assert.True(t, lock.Lost())
+}
+
+func TestYamlFilter_Validate_InvalidLockDuration(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg:  resource.Config{resource.Instance: {}},
+		Lock: &resource.LockConfig{DynamoDBTable: "locks", Duration: "forever"},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.EqualError(t, err, "invalid lock duration: forever")
+}
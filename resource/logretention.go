@@ -0,0 +1,67 @@
+package resource
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/pkg/errors"
+)
+
+// setRetentionActions are required to set the retention of log groups.
+var setRetentionActions = []string{"logs:PutRetentionPolicy"}
+
+// retentionDays are the numbers of days CloudWatch Logs accepts as retention.
+var retentionDays = map[int]bool{
+	1: true, 3: true, 5: true, 7: true, 14: true, 30: true, 60: true, 90: true, 120: true, 150: true,
+	180: true, 365: true, 400: true, 545: true, 731: true, 1827: true, 3653: true,
+}
+
+// validateSetRetention checks whether the retention of the selected resources can be set as configured.
+func validateSetRetention(resType TerraformResourceType, rtf ResourceTypeFilter) error {
+	if resType != CloudwatchLogGroup {
+		return fmt.Errorf("action %s is not supported for resource type: %s", ActionSetRetention, resType)
+	}
+	if !retentionDays[rtf.RetentionDays] {
+		return fmt.Errorf("action %s requires retention_days to be one of 1, 3, 5, 7, 14, 30, 60, 90, 120, 150, "+
+			"180, 365, 400, 545, 731, 1827 or 3653 for resource type: %s", ActionSetRetention, resType)
+	}
+	return nil
+}
+
+// Retention is a log group together with the number of days to keep its log events.
+type Retention struct {
+	Resource *Resource
+	Days     int
+}
+
+// Retentions returns the log groups that never expire and match a filter entry with action set_retention,
+// together with the retention of the matching entry.
+func (f Filter) Retentions(res Resources) []Retention {
+	var result []Retention
+
+	for _, r := range res {
+		if m, ok := r.Model.(LogGroupModel); !ok || m.RetentionDays != 0 {
+			continue
+		}
+		if rtf, found := f.matchingEntry(r); found && rtf.action() == ActionSetRetention {
+			result = append(result, Retention{
+				Resource: r,
+				Days:     rtf.RetentionDays,
+			})
+		}
+	}
+	return result
+}
+
+// SetRetention sets the number of days after which the log events of a log group expire.
+func (a *AWS) SetRetention(r *Resource, days int) error {
+	_, err := a.PutRetentionPolicy(&cloudwatchlogs.PutRetentionPolicyInput{
+		LogGroupName:    aws.String(r.ID),
+		RetentionInDays: aws.Int64(int64(days)),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to set retention of log group %s", r.ID)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testLogGroups = []*cloudwatchlogs.LogGroup{
+	{LogGroupName: aws.String("/aws/lambda/foo"), CreationTime: aws.Int64(1540729719000)},
+	{LogGroupName: aws.String("/aws/lambda/bar"), RetentionInDays: aws.Int64(7)},
+	{LogGroupName: aws.String("/ecs/web")},
+}
+
+func TestFilter_Retentions(t *testing.T) {
+	// given
+	res, err := resource.DeletableResources(resource.CloudwatchLogGroup, testLogGroups)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.CloudwatchLogGroup: {
+				{
+					ID:            aws.String("^/aws/lambda/"),
+					Action:        resource.ActionSetRetention,
+					RetentionDays: 30,
+				},
+			},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	retentions := f.Retentions(res)
+
+	// then
+	require.Len(t, retentions, 1)
+	assert.Equal(t, "/aws/lambda/foo", retentions[0].Resource.ID)
+	assert.Equal(t, 30, retentions[0].Days)
+	assert.Equal(t, int64(1540729719), retentions[0].Resource.Created.Unix())
+	assert.Empty(t, f.Apply(resource.CloudwatchLogGroup, res, testLogGroups, nil)[0])
+}
+
+func TestYamlFilter_Validate_SetRetention(t *testing.T) {
+	validate := func(resType resource.TerraformResourceType, days int) error {
+		f := &resource.Filter{
+			Cfg: resource.Config{
+				resType: {{Action: resource.ActionSetRetention, RetentionDays: days}},
+			},
+		}
+		return f.Validate()
+	}
+
+	assert.NoError(t, validate(resource.CloudwatchLogGroup, 14))
+	assert.EqualError(t, validate(resource.CloudwatchLogGroup, 31), "action set_retention requires retention_days "+
+		"to be one of 1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1827 or 3653 for resource type: "+
+		"aws_cloudwatch_log_group")
+	assert.EqualError(t, validate(resource.Instance, 30), "action set_retention is not supported for resource type: aws_instance")
+}
+
+func TestAWS_SetRetention(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockCloudWatchLogsAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		CloudWatchLogsAPI: mockObj,
+	}
+
+	res, err := resource.DeletableResources(resource.CloudwatchLogGroup, testLogGroups)
+	require.NoError(t, err)
+
+	mockObj.EXPECT().PutRetentionPolicy(&cloudwatchlogs.PutRetentionPolicyInput{
+		LogGroupName:    aws.String("/aws/lambda/foo"),
+		RetentionInDays: aws.Int64(30),
+	}).Return(&cloudwatchlogs.PutRetentionPolicyOutput{}, nil)
+
+	// when
+	err = awsMock.SetRetention(res[0], 30)
+
+	// then
+	assert.NoError(t, err)
+}
@@ -0,0 +1,95 @@
+package resource
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// AllowlistEntry selects resources that are never deleted, neither because of their age nor by any filter
+// of their type.
+type AllowlistEntry struct {
+	// Type restricts the entry to resources of a type (any type if empty)
+	Type TerraformResourceType `yaml:",omitempty"`
+	ID   *string               `yaml:",omitempty"`
+	Tags map[string]string     `yaml:",omitempty"`
+}
+
+// matches checks whether a resource is selected by the entry.
+func (e AllowlistEntry) matches(r *Resource) bool {
+	if e.Type != "" && e.Type != r.Type {
+		return false
+	}
+	if e.ID != nil {
+		if matched, _ := regexp.MatchString(*e.ID, r.ID); !matched {
+			return false
+		}
+	}
+	return tagsMatch(e.Tags, r.Tags)
+}
+
+// validateMaxResourceAge checks whether the max resource age and the allowlist entries can be applied.
+func (f Filter) validateMaxResourceAge() error {
+	if f.MaxResourceAge < 0 {
+		return fmt.Errorf("max_resource_age must not be negative")
+	}
+	for _, e := range f.Allowlist {
+		if e.Type != "" && !SupportedResourceType(e.Type) {
+			return fmt.Errorf("unsupported resource type in allowlist: %s", e.Type)
+		}
+		if e.ID != nil {
+			if _, err := regexp.Compile(*e.ID); err != nil {
+				return fmt.Errorf("invalid id regex in allowlist: %s", *e.ID)
+			}
+		}
+		if e.Tags != nil && e.Type != "" && !TypeCapabilities(e.Type).Tags {
+			return fmt.Errorf("filtering by tags is not supported for resource type in allowlist: %s", e.Type)
+		}
+	}
+	return nil
+}
+
+// allowlisted checks whether a resource is protected by an entry of the allowlist.
+func (f Filter) allowlisted(r *Resource) bool {
+	for _, e := range f.Allowlist {
+		if e.matches(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// exceedsMaxResourceAge checks whether a resource is older than the max resource age of the filter.
+// Resources AWS creates by default and resources which creation time is unknown never exceed it.
+func (f Filter) exceedsMaxResourceAge(r *Resource) bool {
+	if f.MaxResourceAge == 0 || r.Default || r.Created == nil {
+		return false
+	}
+	return time.Since(*r.Created) > f.MaxResourceAge
+}
+
+// maxResourceAgeTypes are the resource types which resources are deleted once they exceed the max resource age,
+// which are all supported types with known creation times.
+func (f Filter) maxResourceAgeTypes() []TerraformResourceType {
+	if f.MaxResourceAge == 0 {
+		return nil
+	}
+
+	var result []TerraformResourceType
+	for _, resType := range SupportedResourceTypes() {
+		if TypeCapabilities(resType).Created {
+			result = append(result, resType)
+		}
+	}
+	return result
+}
+
+// usesAllowlistTags checks whether the allowlist needs to know the tags of resources of a type.
+func (f Filter) usesAllowlistTags(resType TerraformResourceType) bool {
+	for _, e := range f.Allowlist {
+		if e.Tags != nil && (e.Type == "" || e.Type == resType) {
+			return true
+		}
+	}
+	return false
+}
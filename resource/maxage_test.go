@@ -0,0 +1,123 @@
+package resource_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Apply_MaxResourceAge(t *testing.T) {
+	// given
+	old := time.Now().Add(-15 * 24 * time.Hour)
+	recent := time.Now().Add(-time.Hour)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {{ID: aws.String("^select-")}},
+		},
+		MaxResourceAge: 14 * 24 * time.Hour,
+		Allowlist: []resource.AllowlistEntry{
+			{Tags: map[string]string{"sandbox:keep": "true"}},
+			{Type: resource.Instance, ID: aws.String("^bastion$")},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	res := resource.Resources{
+		{Type: resource.Instance, ID: "select-recent", Created: &recent, Tags: map[string]string{}},
+		{Type: resource.Instance, ID: "old", Created: &old, Tags: map[string]string{}},
+		{Type: resource.Instance, ID: "recent", Created: &recent, Tags: map[string]string{}},
+		{Type: resource.Instance, ID: "old-kept", Created: &old, Tags: map[string]string{"sandbox:keep": "true"}},
+		{Type: resource.Instance, ID: "bastion", Created: &old, Tags: map[string]string{}},
+	}
+
+	// when
+	result := f.Apply(resource.Instance, res, nil, nil)
+
+	// then
+	var selected []string
+	for _, r := range result[0] {
+		selected = append(selected, r.ID)
+	}
+	assert.Equal(t, []string{"select-recent", "old"}, selected)
+}
+
+func TestFilter_Types_MaxResourceAge(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.KeyPair: {},
+		},
+		MaxResourceAge: 14 * 24 * time.Hour,
+	}
+
+	// when
+	resTypes := f.Types()
+
+	// then
+	assert.Contains(t, resTypes, resource.KeyPair)
+	assert.Contains(t, resTypes, resource.Instance)
+	assert.Contains(t, resTypes, resource.IamRole)
+	// the age of resources without creation time is unknown
+	assert.NotContains(t, resTypes, resource.SesEmailIdentity)
+}
+
+func TestFilter_Apply_MaxResourceAgeSkipsDefaults(t *testing.T) {
+	// given
+	old := time.Now().Add(-365 * 24 * time.Hour)
+	f := &resource.Filter{
+		Cfg:            resource.Config{},
+		MaxResourceAge: 14 * 24 * time.Hour,
+	}
+
+	res := resource.Resources{
+		{Type: resource.Vpc, ID: "vpc-default", Created: &old, Default: true},
+		{Type: resource.Vpc, ID: "vpc-1"},
+	}
+
+	// when
+	result := f.Apply(resource.Vpc, res, nil, nil)
+
+	// then
+	assert.Empty(t, result[0])
+}
+
+func TestNewFilter_MaxResourceAge(t *testing.T) {
+	// given
+	resource.AppFs = afero.NewMemMapFs()
+	afero.WriteFile(resource.AppFs, "config.yml", []byte(`version: 2
+max_resource_age: 14d
+allowlist:
+  - type: aws_iam_role
+    id: ^OrganizationAccountAccessRole$
+`), 0644)
+
+	// when
+	f := resource.NewFilter("config.yml")
+
+	// then
+	require.NoError(t, f.Validate())
+	assert.Equal(t, 14*24*time.Hour, f.MaxResourceAge)
+	assert.Equal(t, []resource.AllowlistEntry{
+		{Type: resource.IamRole, ID: aws.String("^OrganizationAccountAccessRole$")},
+	}, f.Allowlist)
+}
+
+func TestFilter_Validate_AllowlistUnsupportedType(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg:       resource.Config{},
+		Allowlist: []resource.AllowlistEntry{{Type: "aws_foo"}},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.EqualError(t, err, "unsupported resource type in allowlist: aws_foo")
+}
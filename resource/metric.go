@@ -0,0 +1,260 @@
+package resource
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/pkg/errors"
+)
+
+const (
+	// metricQueryBatchSize is the maximum number of metric queries per GetMetricData request.
+	metricQueryBatchSize = 100
+	defaultMetricPeriod  = "14d"
+	defaultStatistic     = "Average"
+)
+
+// metricDimension is the namespace and the name of the dimension that identifies a resource in CloudWatch metrics.
+type metricDimension struct {
+	namespace string
+	dimension string
+}
+
+var (
+	metricDimensions = map[TerraformResourceType]metricDimension{
+		AutoscalingGroup: {"AWS/EC2", "AutoScalingGroupName"},
+		EbsVolume:        {"AWS/EBS", "VolumeId"},
+		EfsFileSystem:    {"AWS/EFS", "FileSystemId"},
+		Elb:              {"AWS/ELB", "LoadBalancerName"},
+		Instance:         {"AWS/EC2", "InstanceId"},
+		LambdaFunction:   {"AWS/Lambda", "FunctionName"},
+		NatGateway:       {"AWS/NATGateway", "NatGatewayId"},
+	}
+
+	statistics = map[string]bool{
+		"Average":     true,
+		"Sum":         true,
+		"Minimum":     true,
+		"Maximum":     true,
+		"SampleCount": true,
+	}
+)
+
+// MetricFilter selects resources by the value of a CloudWatch metric aggregated over a period of time,
+// e.g. instances with an average CPU utilization below 2% over the last 14 days.
+type MetricFilter struct {
+	// Name is the name of the metric (e.g., CPUUtilization)
+	Name string
+	// Statistic is how the datapoints are aggregated (default: Average)
+	Statistic string `yaml:",omitempty"`
+	// Period is how far back from now the datapoints are aggregated (default: 14d)
+	Period string `yaml:",omitempty"`
+	// Value is the comparison of the aggregated value (e.g., "<2")
+	Value string
+}
+
+func (m MetricFilter) statistic() string {
+	if m.Statistic == "" {
+		return defaultStatistic
+	}
+	return m.Statistic
+}
+
+func (m MetricFilter) period() time.Duration {
+	p := m.Period
+	if p == "" {
+		p = defaultMetricPeriod
+	}
+	d, err := parseDuration(p)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// key identifies the aggregated value of a metric filter.
+func (m MetricFilter) key() string {
+	return fmt.Sprintf("%s/%s/%s", m.Name, m.statistic(), m.period())
+}
+
+// validateMetrics checks whether resources of a type can be selected by the given metric filters.
+func validateMetrics(resType TerraformResourceType, metrics []MetricFilter) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	if _, found := metricDimensions[resType]; !found {
+		return fmt.Errorf("filtering by metrics is not supported for resource type: %s", resType)
+	}
+
+	for _, m := range metrics {
+		if m.Name == "" {
+			return fmt.Errorf("metric filter without name for resource type: %s", resType)
+		}
+		if !statistics[m.statistic()] {
+			return fmt.Errorf("invalid statistic of metric %s for resource type %s: %s", m.Name, resType, m.Statistic)
+		}
+		if m.period() < time.Minute {
+			return fmt.Errorf("invalid period of metric %s for resource type %s: %s", m.Name, resType, m.Period)
+		}
+		if _, err := strconv.ParseFloat(parseComparison(m.Value).value, 64); err != nil {
+			return fmt.Errorf("invalid value of metric %s for resource type %s: %s", m.Name, resType, m.Value)
+		}
+	}
+	return nil
+}
+
+// matchMetrics checks whether the aggregated metrics of a resource match all metric filters.
+func (rtf ResourceTypeFilter) matchMetrics(r *Resource) bool {
+	for _, m := range rtf.Metrics {
+		value, found := r.metrics[m.key()]
+		if !found {
+			return false
+		}
+
+		c := parseComparison(m.Value)
+		expected, err := strconv.ParseFloat(c.value, 64)
+		if err != nil {
+			return false
+		}
+
+		cmp := 0
+		if value < expected {
+			cmp = -1
+		} else if value > expected {
+			cmp = 1
+		}
+		if !c.compareOrdered(cmp) {
+			return false
+		}
+	}
+	return true
+}
+
+// fetchMetrics looks up the aggregated values of all metrics the filter of a resource type uses.
+func (f Filter) fetchMetrics(resType TerraformResourceType, res Resources, a *AWS) error {
+	var metrics []MetricFilter
+	for _, rtf := range f.Cfg[resType] {
+		metrics = append(metrics, rtf.Metrics...)
+	}
+	if len(metrics) == 0 || len(res) == 0 {
+		return nil
+	}
+
+	for _, m := range metrics {
+		if err := a.AggregateMetric(resType, res, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AggregateMetric looks up the value of a metric (aggregated over the period of the filter) for each
+// of the given resources. Resources without any datapoints (e.g., load balancers without requests)
+// get a value of 0.
+func (a *AWS) AggregateMetric(resType TerraformResourceType, res Resources, m MetricFilter) error {
+	dim, found := metricDimensions[resType]
+	if !found {
+		return errors.Errorf("filtering by metrics is not supported for resource type: %s", resType)
+	}
+
+	now := time.Now()
+	start := now.Add(-m.period())
+	// a single datapoint per resource (the period must be a multiple of 60 seconds)
+	period := int64(m.period()/time.Minute) * 60
+
+	for i := 0; i < len(res); i += metricQueryBatchSize {
+		end := i + metricQueryBatchSize
+		if end > len(res) {
+			end = len(res)
+		}
+		batch := res[i:end]
+
+		var queries []*cloudwatch.MetricDataQuery
+		for j, r := range batch {
+			queries = append(queries, &cloudwatch.MetricDataQuery{
+				Id: aws.String(fmt.Sprintf("m%d", j)),
+				MetricStat: &cloudwatch.MetricStat{
+					Metric: &cloudwatch.Metric{
+						Namespace:  aws.String(dim.namespace),
+						MetricName: aws.String(m.Name),
+						Dimensions: []*cloudwatch.Dimension{
+							{Name: aws.String(dim.dimension), Value: aws.String(r.ID)},
+						},
+					},
+					Period: aws.Int64(period),
+					Stat:   aws.String(m.statistic()),
+				},
+			})
+		}
+
+		values := map[string][]float64{}
+		var nextToken *string
+		for {
+			var output *cloudwatch.GetMetricDataOutput
+			err := retryThrottled(func() error {
+				var err error
+				output, err = a.GetMetricData(&cloudwatch.GetMetricDataInput{
+					MetricDataQueries: queries,
+					StartTime:         aws.Time(start),
+					EndTime:           aws.Time(now),
+					NextToken:         nextToken,
+				})
+				return err
+			})
+			if err != nil {
+				return errors.Wrapf(err, "failed to get metric %s of %s resources", m.Name, resType)
+			}
+
+			for _, result := range output.MetricDataResults {
+				id := aws.StringValue(result.Id)
+				values[id] = append(values[id], aws.Float64ValueSlice(result.Values)...)
+			}
+
+			if output.NextToken == nil {
+				break
+			}
+			nextToken = output.NextToken
+		}
+
+		for j, r := range batch {
+			if r.metrics == nil {
+				r.metrics = map[string]float64{}
+			}
+			r.metrics[m.key()] = aggregate(m.statistic(), values[fmt.Sprintf("m%d", j)])
+		}
+	}
+	return nil
+}
+
+// aggregate combines the values of the datapoints of a metric (if the period has been split into
+// more than one datapoint) according to the statistic.
+func aggregate(statistic string, values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	result := values[0]
+	for _, v := range values[1:] {
+		switch statistic {
+		case "Minimum":
+			if v < result {
+				result = v
+			}
+		case "Maximum":
+			if v > result {
+				result = v
+			}
+		default:
+			result += v
+		}
+	}
+
+	if statistic == "Average" {
+		result /= float64(len(values))
+	}
+	return result
+}
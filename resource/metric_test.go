@@ -0,0 +1,122 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Apply_Metrics(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockCloudWatchAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		CloudWatchAPI: mockObj,
+	}
+
+	instances := []*ec2.Instance{
+		{InstanceId: aws.String("i-idle")},
+		{InstanceId: aws.String("i-busy")},
+		{InstanceId: aws.String("i-nodata")},
+	}
+	res, err := resource.DeletableResources(resource.Instance, instances)
+	require.NoError(t, err)
+
+	mockObj.EXPECT().GetMetricData(gomock.Any()).DoAndReturn(
+		func(input *cloudwatch.GetMetricDataInput) (*cloudwatch.GetMetricDataOutput, error) {
+			require.Len(t, input.MetricDataQueries, 3)
+			q := input.MetricDataQueries[0]
+			assert.Equal(t, "AWS/EC2", *q.MetricStat.Metric.Namespace)
+			assert.Equal(t, "CPUUtilization", *q.MetricStat.Metric.MetricName)
+			assert.Equal(t, "i-idle", *q.MetricStat.Metric.Dimensions[0].Value)
+			assert.Equal(t, "Average", *q.MetricStat.Stat)
+			assert.Equal(t, int64(7*24*60*60), *q.MetricStat.Period)
+
+			return &cloudwatch.GetMetricDataOutput{
+				MetricDataResults: []*cloudwatch.MetricDataResult{
+					{Id: q.Id, Values: aws.Float64Slice([]float64{1.5})},
+					{Id: input.MetricDataQueries[1].Id, Values: aws.Float64Slice([]float64{40})},
+				},
+			}, nil
+		})
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {
+				{
+					Metrics: []resource.MetricFilter{
+						{Name: "CPUUtilization", Period: "7d", Value: "<2"},
+					},
+				},
+			},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	var ids []string
+	for _, r := range f.Apply(resource.Instance, res, instances, awsMock)[0] {
+		ids = append(ids, r.ID)
+	}
+
+	// then
+	assert.Equal(t, []string{"i-idle", "i-nodata"}, ids)
+}
+
+func TestYamlFilter_Validate_Metrics(t *testing.T) {
+	tests := []struct {
+		resType resource.TerraformResourceType
+		metric  resource.MetricFilter
+		err     string
+	}{
+		{
+			resType: resource.Vpc,
+			metric:  resource.MetricFilter{Name: "CPUUtilization", Value: "<2"},
+			err:     "filtering by metrics is not supported for resource type: aws_vpc",
+		},
+		{
+			resType: resource.Instance,
+			metric:  resource.MetricFilter{Value: "<2"},
+			err:     "metric filter without name for resource type: aws_instance",
+		},
+		{
+			resType: resource.Instance,
+			metric:  resource.MetricFilter{Name: "CPUUtilization", Statistic: "Median", Value: "<2"},
+			err:     "invalid statistic of metric CPUUtilization for resource type aws_instance: Median",
+		},
+		{
+			resType: resource.Instance,
+			metric:  resource.MetricFilter{Name: "CPUUtilization", Period: "soon", Value: "<2"},
+			err:     "invalid period of metric CPUUtilization for resource type aws_instance: soon",
+		},
+		{
+			resType: resource.Instance,
+			metric:  resource.MetricFilter{Name: "CPUUtilization", Value: "<low"},
+			err:     "invalid value of metric CPUUtilization for resource type aws_instance: <low",
+		},
+	}
+
+	for _, tc := range tests {
+		// given
+		f := &resource.Filter{
+			Cfg: resource.Config{
+				tc.resType: {{Metrics: []resource.MetricFilter{tc.metric}}},
+			},
+		}
+
+		// when
+		err := f.Validate()
+
+		// then
+		assert.EqualError(t, err, tc.err)
+	}
+}
@@ -0,0 +1,151 @@
+package resource
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigVersion is the version of the current format of yaml configs.
+//
+// Version 1 configs have a single filter per resource type with a list of ID regexes:
+//
+//	aws_instance:
+//	  ids:
+//	    - ^foo
+//	  tags:
+//	    bla: blub
+//
+// Version 2 configs have a list of filters per resource type with a single ID regex each.
+const ConfigVersion = 2
+
+// configVersion returns the version of the format of a config. Configs without version
+// are version 1 if any resource type has a single filter (instead of a list of filters).
+func configVersion(cfg yaml.MapSlice) (int, error) {
+	for _, item := range cfg {
+		if item.Key != "version" {
+			continue
+		}
+		version, ok := item.Value.(int)
+		if !ok || version < 1 || version > ConfigVersion {
+			return 0, errors.Errorf("unsupported config version: %v (latest is %d)", item.Value, ConfigVersion)
+		}
+		return version, nil
+	}
+
+	settings := configSettings()
+	for _, item := range cfg {
+		if _, isMap := item.Value.(yaml.MapSlice); isMap && !settings[fmt.Sprint(item.Key)] {
+			return 1, nil
+		}
+	}
+	return ConfigVersion, nil
+}
+
+// configSettings returns the keys of the settings of a config, i.e. the top-level keys besides resource types.
+func configSettings() map[string]bool {
+	settings := map[string]bool{"version": true}
+
+	t := reflect.TypeOf(configFile{})
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("yaml"), ",")
+		if len(tag) > 1 && tag[1] == "inline" {
+			continue
+		}
+		name := tag[0]
+		if name == "" {
+			name = strings.ToLower(t.Field(i).Name)
+		}
+		settings[name] = true
+	}
+	return settings
+}
+
+// MigrateConfig converts a yaml config of any older version into the current version.
+// It returns the migrated config and the version of the given config.
+func MigrateConfig(data []byte) ([]byte, int, error) {
+	var cfg yaml.MapSlice
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, 0, err
+	}
+
+	version, err := configVersion(cfg)
+	if err != nil {
+		return nil, 0, err
+	}
+	if version == ConfigVersion {
+		return data, version, nil
+	}
+
+	migrated := yaml.MapSlice{{Key: "version", Value: ConfigVersion}}
+	settings := configSettings()
+	for _, item := range cfg {
+		if item.Key == "version" {
+			continue
+		}
+
+		v1Filter, isMap := item.Value.(yaml.MapSlice)
+		if !isMap || settings[fmt.Sprint(item.Key)] {
+			migrated = append(migrated, item)
+			continue
+		}
+
+		filters, err := migrateV1Filter(v1Filter)
+		if err != nil {
+			return nil, 0, errors.Wrapf(err, "cannot migrate filter of resource type %s", item.Key)
+		}
+		migrated = append(migrated, yaml.MapItem{Key: item.Key, Value: filters})
+	}
+
+	result, err := yaml.Marshal(migrated)
+	if err != nil {
+		return nil, 0, err
+	}
+	return result, version, nil
+}
+
+// migrateV1Filter converts the filter of a resource type in a version 1 config into a list of filters,
+// one per ID regex, as resources matched any of the IDs (and all of the tags).
+func migrateV1Filter(v1Filter yaml.MapSlice) ([]yaml.MapSlice, error) {
+	var ids []interface{}
+	var tags interface{}
+
+	for _, item := range v1Filter {
+		switch strings.ToLower(fmt.Sprint(item.Key)) {
+		case "ids":
+			list, ok := item.Value.([]interface{})
+			if !ok {
+				return nil, errors.New("ids must be a list")
+			}
+			ids = list
+		case "tags":
+			tags = item.Value
+		default:
+			return nil, errors.Errorf("unknown field: %s", item.Key)
+		}
+	}
+
+	newFilter := func(id interface{}) yaml.MapSlice {
+		f := yaml.MapSlice{}
+		if id != nil {
+			f = append(f, yaml.MapItem{Key: "id", Value: id})
+		}
+		if tags != nil {
+			f = append(f, yaml.MapItem{Key: "tags", Value: tags})
+		}
+		return f
+	}
+
+	if len(ids) == 0 {
+		return []yaml.MapSlice{newFilter(nil)}, nil
+	}
+
+	var filters []yaml.MapSlice
+	for _, id := range ids {
+		filters = append(filters, newFilter(id))
+	}
+	return filters, nil
+}
@@ -0,0 +1,107 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateConfig_V1(t *testing.T) {
+	// given
+	cfg := `
+dry_run: false
+aws_instance:
+  ids:
+    - ^foo
+    - ^bar
+  tags:
+    bla: blub
+aws_vpc:
+  tags:
+    foo: bar
+aws_iam_role:
+`
+
+	// when
+	migrated, version, err := resource.MigrateConfig([]byte(cfg))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 1, version)
+	assert.Equal(t, `version: 2
+dry_run: false
+aws_instance:
+- id: ^foo
+  tags:
+    bla: blub
+- id: ^bar
+  tags:
+    bla: blub
+aws_vpc:
+- tags:
+    foo: bar
+aws_iam_role: null
+`, string(migrated))
+}
+
+func TestMigrateConfig_CurrentVersion(t *testing.T) {
+	// given
+	cfg := `
+aws_instance:
+  - id: ^foo
+events:
+  webhook: https://example.com
+`
+
+	// when
+	migrated, version, err := resource.MigrateConfig([]byte(cfg))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, resource.ConfigVersion, version)
+	assert.Equal(t, cfg, string(migrated))
+}
+
+func TestMigrateConfig_UnsupportedVersion(t *testing.T) {
+	// when
+	_, _, err := resource.MigrateConfig([]byte("version: 3\n"))
+
+	// then
+	assert.EqualError(t, err, "unsupported config version: 3 (latest is 2)")
+}
+
+func TestMigrateConfig_UnknownV1Field(t *testing.T) {
+	// given
+	cfg := `
+aws_instance:
+  names:
+    - foo
+`
+
+	// when
+	_, _, err := resource.MigrateConfig([]byte(cfg))
+
+	// then
+	assert.EqualError(t, err, "cannot migrate filter of resource type aws_instance: unknown field: names")
+}
+
+func TestNewFilter_V1Config(t *testing.T) {
+	// given
+	resource.AppFs = afero.NewMemMapFs()
+	afero.WriteFile(resource.AppFs, "config.yml", []byte(`
+aws_instance:
+  ids:
+    - ^foo
+`), 0644)
+
+	// when
+	f := resource.NewFilter("config.yml")
+
+	// then
+	require.NoError(t, f.Validate())
+	assert.Equal(t, []resource.ResourceTypeFilter{{ID: aws.String("^foo")}}, f.Cfg[resource.Instance])
+}
@@ -0,0 +1,893 @@
+package resource
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/athena"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cognitoidentity"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/redshift"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/cloudetc/awsweeper/internal/arn"
+	"github.com/hashicorp/terraform/helper/hashcode"
+)
+
+// Model is the common interface of the typed representations of resources. Each resource type can
+// have its own model with typed fields (e.g., the size of volumes), which filters can compare without
+// parsing strings.
+type Model interface {
+	ID() string
+	Tags() map[string]string
+	CreatedAt() *time.Time
+	// Region is the region the resource has to be deleted in, if it differs from the one it has been listed in.
+	Region() string
+}
+
+// stater is implemented by models of resources that need attributes (besides the ID)
+// in their Terraform state to be deleted.
+type stater interface {
+	StateAttributes() map[string]string
+}
+
+// BaseModel is the model of resource types without any fields besides the common ones.
+type BaseModel struct {
+	r *Resource
+}
+
+// ID returns the ID of the resource.
+func (m BaseModel) ID() string {
+	return m.r.ID
+}
+
+// Tags returns the tags of the resource (nil if unknown).
+func (m BaseModel) Tags() map[string]string {
+	return m.r.Tags
+}
+
+// CreatedAt returns the creation time of the resource (nil if unknown).
+func (m BaseModel) CreatedAt() *time.Time {
+	return m.r.Created
+}
+
+// Region returns the region the resource has to be deleted in, if it differs from the one it has been listed in.
+func (m BaseModel) Region() string {
+	return m.r.Region
+}
+
+// InstanceModel is the model of EC2 instances.
+type InstanceModel struct {
+	BaseModel
+	InstanceType string
+	State        string
+
+	// keyName is the name of the key pair the instance has been launched with
+	keyName string
+}
+
+// VolumeModel is the model of EBS volumes.
+type VolumeModel struct {
+	BaseModel
+	SizeGB     int64
+	State      string
+	VolumeType string
+	// Attached is true if the volume is attached to any instance
+	Attached bool
+	// InstanceID and Device are the instance the volume is attached to and the device name it is exposed as (if any)
+	InstanceID string
+	Device     string
+}
+
+// SnapshotModel is the model of EBS snapshots.
+type SnapshotModel struct {
+	BaseModel
+	SizeGB   int64
+	VolumeID string
+}
+
+// AutoscalingGroupModel is the model of autoscaling groups.
+type AutoscalingGroupModel struct {
+	BaseModel
+	InstanceCount   int64
+	DesiredCapacity int64
+	// LaunchConfigurationName is the name of the launch configuration instances are launched with (if any)
+	LaunchConfigurationName string
+	// LaunchTemplateName is the name of the launch template instances are launched with (if any)
+	LaunchTemplateName string
+	// PropagatedTags are the tags of the group that are propagated to the instances it launches
+	PropagatedTags map[string]string
+}
+
+// ElbModel is the model of classic load balancers.
+type ElbModel struct {
+	BaseModel
+	// InstanceCount is the number of registered instances
+	InstanceCount int64
+	// HealthyInstanceCount is the number of registered instances that are in service
+	// (only looked up if a filter uses it)
+	HealthyInstanceCount int64
+}
+
+// KeyPairModel is the model of EC2 key pairs.
+type KeyPairModel struct {
+	BaseModel
+	// Fingerprint is the fingerprint of the public key
+	Fingerprint string
+}
+
+// StateAttributes returns the attributes the Terraform provider needs to delete the key pair.
+func (m KeyPairModel) StateAttributes() map[string]string {
+	return map[string]string{"public_key": ""}
+}
+
+// IamAccessKeyModel is the model of access keys of IAM users.
+type IamAccessKeyModel struct {
+	BaseModel
+	User string
+	// Status is either Active or Inactive
+	Status string
+}
+
+// StateAttributes returns the attributes the Terraform provider needs to delete the access key.
+func (m IamAccessKeyModel) StateAttributes() map[string]string {
+	return map[string]string{"user": m.User}
+}
+
+// IamEntityModel is the model of IAM users, roles, groups, policies and instance profiles.
+type IamEntityModel struct {
+	BaseModel
+	// Path is the path the entity has been created with (e.g., /ephemeral/ci/), which is / by default
+	Path string
+}
+
+// IamUserGroupMembershipModel is the model of the membership of an IAM user in a group.
+type IamUserGroupMembershipModel struct {
+	BaseModel
+	User  string
+	Group string
+}
+
+// StateAttributes returns the attributes the Terraform provider needs to remove the user from the group.
+func (m IamUserGroupMembershipModel) StateAttributes() map[string]string {
+	return map[string]string{
+		"user":     m.User,
+		"groups.#": "1",
+		// elements of sets are keyed by their hash code
+		fmt.Sprintf("groups.%d", hashcode.String(m.Group)): m.Group,
+	}
+}
+
+// IamRolePolicyAttachmentModel is the model of a managed policy attached to an IAM role.
+type IamRolePolicyAttachmentModel struct {
+	BaseModel
+	Role      string
+	PolicyArn string
+}
+
+// StateAttributes returns the attributes the Terraform provider needs to detach the policy.
+func (m IamRolePolicyAttachmentModel) StateAttributes() map[string]string {
+	return map[string]string{
+		"role":       m.Role,
+		"policy_arn": m.PolicyArn,
+	}
+}
+
+// IamUserPolicyAttachmentModel is the model of a managed policy attached to an IAM user.
+type IamUserPolicyAttachmentModel struct {
+	BaseModel
+	User      string
+	PolicyArn string
+}
+
+// StateAttributes returns the attributes the Terraform provider needs to detach the policy.
+func (m IamUserPolicyAttachmentModel) StateAttributes() map[string]string {
+	return map[string]string{
+		"user":       m.User,
+		"policy_arn": m.PolicyArn,
+	}
+}
+
+// IamPolicyAttachmentModel is the model of all attachments of a managed policy to users, groups and roles.
+type IamPolicyAttachmentModel struct {
+	BaseModel
+	PolicyArn string
+	Name      string
+	Users     []string
+	Roles     []string
+	Groups    []string
+}
+
+// StateAttributes returns the attributes the Terraform provider needs to detach the policy.
+func (m IamPolicyAttachmentModel) StateAttributes() map[string]string {
+	return map[string]string{
+		"policy_arn": m.PolicyArn,
+		"name":       m.Name,
+		"users":      strings.Join(m.Users, "."),
+		"roles":      strings.Join(m.Roles, "."),
+		"groups":     strings.Join(m.Groups, "."),
+	}
+}
+
+// FirehoseDeliveryStreamModel is the model of Kinesis Firehose delivery streams.
+type FirehoseDeliveryStreamModel struct {
+	BaseModel
+	Name string
+	// DeliveryStreamType is either DirectPut or KinesisStreamAsSource
+	DeliveryStreamType string
+	Status             string
+	// S3Bucket is the name of the bucket records are delivered to (empty for other destinations)
+	S3Bucket string
+}
+
+// StateAttributes returns the attributes the Terraform provider needs to delete the delivery stream.
+func (m FirehoseDeliveryStreamModel) StateAttributes() map[string]string {
+	return map[string]string{"name": m.Name}
+}
+
+// AthenaNamedQueryModel is the model of Athena named queries.
+type AthenaNamedQueryModel struct {
+	BaseModel
+	Name string
+	// Database is the database the query runs against
+	Database string
+}
+
+// SsmParameterModel is the model of SSM parameters.
+type SsmParameterModel struct {
+	BaseModel
+	// Type is String, StringList or SecureString
+	Type    string
+	Version int64
+	// LastModified is when the value of the parameter has been changed the last time
+	LastModified time.Time
+}
+
+// CloudfrontDistributionModel is the model of CloudFront distributions.
+type CloudfrontDistributionModel struct {
+	BaseModel
+	Enabled bool
+	// Status is InProgress while changes of the distribution are deployed and Deployed afterwards
+	Status     string
+	DomainName string
+	Comment    string
+	// Aliases are the alternate domain names (CNAMEs) of the distribution
+	Aliases []string
+	// LastModified is when the distribution has been changed the last time
+	LastModified time.Time
+}
+
+// CognitoUserPoolModel is the model of Cognito user pools.
+type CognitoUserPoolModel struct {
+	BaseModel
+	Name string
+	// Status is either Enabled or Disabled
+	Status string
+	// LastModified is when the user pool has been changed the last time
+	LastModified time.Time
+}
+
+// CognitoIdentityPoolModel is the model of Cognito identity pools.
+type CognitoIdentityPoolModel struct {
+	BaseModel
+	Name string
+}
+
+// GlueCrawlerModel is the model of Glue crawlers.
+type GlueCrawlerModel struct {
+	BaseModel
+	// DatabaseName is the database of the data catalog the crawler writes its results to
+	DatabaseName string
+	// State is either READY, RUNNING or STOPPING
+	State string
+}
+
+// GlueJobModel is the model of Glue jobs.
+type GlueJobModel struct {
+	BaseModel
+	// Command is the type of the job (glueetl or pythonshell)
+	Command string
+	Role    string
+}
+
+// KinesisStreamModel is the model of Kinesis data streams.
+type KinesisStreamModel struct {
+	BaseModel
+	Name   string
+	Status string
+	// OpenShardCount is the number of shards that accept data
+	OpenShardCount       int64
+	RetentionPeriodHours int64
+}
+
+// StateAttributes returns the attributes the Terraform provider needs to delete the stream.
+func (m KinesisStreamModel) StateAttributes() map[string]string {
+	return map[string]string{"name": m.Name}
+}
+
+// KmsKeyModel is the model of KMS keys.
+type KmsKeyModel struct {
+	BaseModel
+	// KeyState is one of Enabled, Disabled, PendingDeletion, PendingImport and Unavailable
+	KeyState string
+	// KeyManager is either CUSTOMER or AWS
+	KeyManager  string
+	Description string
+	KeyUsage    string
+	// Aliases are the names of the aliases of the key (e.g., alias/foo)
+	Aliases []string
+}
+
+// DynamodbTableModel is the model of DynamoDB tables.
+type DynamodbTableModel struct {
+	BaseModel
+	// ItemCount and SizeBytes are updated by DynamoDB about every six hours
+	ItemCount int64
+	SizeBytes int64
+	Status    string
+
+	arn string
+}
+
+// RdsClusterModel is the model of RDS (Aurora) clusters.
+type RdsClusterModel struct {
+	BaseModel
+	Engine        string
+	EngineVersion string
+	Status        string
+	// MemberCount is the number of DB instances in the cluster
+	MemberCount        int64
+	DeletionProtection bool
+
+	arn     string
+	members []string
+}
+
+// StateAttributes returns the attributes the Terraform provider needs to delete the cluster
+// (without a final snapshot).
+func (m RdsClusterModel) StateAttributes() map[string]string {
+	return map[string]string{"skip_final_snapshot": "true"}
+}
+
+// RedshiftClusterModel is the model of Redshift clusters.
+type RedshiftClusterModel struct {
+	BaseModel
+	NodeType      string
+	NumberOfNodes int64
+	Status        string
+
+	// finalSnapshotID is the identifier of the snapshot taken when the cluster is deleted
+	// (empty if the cluster is deleted without a final snapshot)
+	finalSnapshotID string
+}
+
+// StateAttributes returns the attributes the Terraform provider needs to delete the cluster
+// (with or without a final snapshot).
+func (m RedshiftClusterModel) StateAttributes() map[string]string {
+	if m.finalSnapshotID == "" {
+		return map[string]string{"skip_final_snapshot": "true"}
+	}
+	return map[string]string{
+		"skip_final_snapshot":       "false",
+		"final_snapshot_identifier": m.finalSnapshotID,
+	}
+}
+
+// RedshiftSnapshotModel is the model of manual snapshots of Redshift clusters.
+type RedshiftSnapshotModel struct {
+	BaseModel
+	// SizeMb is the size of the snapshot (including incremental backups)
+	SizeMb  int64
+	Cluster string
+	Status  string
+}
+
+// DbSnapshotModel is the model of manual snapshots of RDS DB instances.
+type DbSnapshotModel struct {
+	BaseModel
+	// SizeGb is the allocated storage of the DB instance
+	SizeGb   int64
+	Instance string
+	Engine   string
+	Status   string
+
+	arn string
+}
+
+// DbClusterSnapshotModel is the model of manual snapshots of RDS (Aurora) clusters.
+type DbClusterSnapshotModel struct {
+	BaseModel
+	SizeGb  int64
+	Cluster string
+	Engine  string
+	Status  string
+
+	arn string
+}
+
+// LambdaFunctionModel is the model of Lambda functions.
+type LambdaFunctionModel struct {
+	BaseModel
+	Runtime string
+	// MemorySize is the memory available to the function in MB
+	MemorySize int64
+	// CodeSize is the size of the deployment package in bytes
+	CodeSize int64
+}
+
+// StateAttributes returns the attributes the Terraform provider needs to delete the function.
+func (m LambdaFunctionModel) StateAttributes() map[string]string {
+	return map[string]string{"function_name": m.r.ID}
+}
+
+// LogGroupModel is the model of CloudWatch log groups.
+type LogGroupModel struct {
+	BaseModel
+	// RetentionDays is the number of days log events are kept (0 if they never expire)
+	RetentionDays int64
+	StoredBytes   int64
+}
+
+// StateAttributes returns the attributes the Terraform provider needs to delete the log group.
+func (m LogGroupModel) StateAttributes() map[string]string {
+	return map[string]string{"name": m.r.ID}
+}
+
+// LogStreamModel is the model of streams of CloudWatch log groups.
+type LogStreamModel struct {
+	BaseModel
+	LogGroup    string
+	StoredBytes int64
+
+	// lastEvent is the time of the latest event of the stream (nil if the stream is empty)
+	lastEvent *time.Time
+}
+
+// StateAttributes returns the attributes the Terraform provider needs to delete the log stream.
+func (m LogStreamModel) StateAttributes() map[string]string {
+	return map[string]string{"log_group_name": m.LogGroup}
+}
+
+// SnsSubscriptionModel is the model of subscriptions of SNS topics.
+type SnsSubscriptionModel struct {
+	BaseModel
+	// Topic is the name of the topic of the subscription
+	Topic    string
+	Protocol string
+	Endpoint string
+}
+
+// MetricAlarmModel is the model of CloudWatch metric alarms.
+type MetricAlarmModel struct {
+	BaseModel
+	// State is OK, ALARM or INSUFFICIENT_DATA (e.g., if the alarmed resource doesn't exist anymore)
+	State          string
+	Namespace      string
+	MetricName     string
+	ActionsEnabled bool
+}
+
+// EcsClusterModel is the model of ECS clusters.
+type EcsClusterModel struct {
+	BaseModel
+	Status string
+	// ServiceCount is the number of active services of the cluster, which are deleted before the cluster
+	ServiceCount           int64
+	RunningTaskCount       int64
+	ContainerInstanceCount int64
+
+	name string
+}
+
+// StateAttributes returns the attributes the Terraform provider needs to delete the cluster.
+func (m EcsClusterModel) StateAttributes() map[string]string {
+	return map[string]string{"name": m.name}
+}
+
+// EcsServiceModel is the model of services of ECS clusters.
+type EcsServiceModel struct {
+	BaseModel
+	// Cluster is the name of the cluster the service runs in
+	Cluster      string
+	LaunchType   string
+	DesiredCount int64
+	RunningCount int64
+
+	clusterArn string
+}
+
+// StateAttributes returns the attributes the Terraform provider needs to delete the service
+// (the provider scales the service down to zero tasks before it is deleted).
+func (m EcsServiceModel) StateAttributes() map[string]string {
+	return map[string]string{"cluster": m.clusterArn}
+}
+
+// EcsTaskDefinitionModel is the model of revisions of ECS task definitions.
+type EcsTaskDefinitionModel struct {
+	BaseModel
+	Family   string
+	Revision int64
+}
+
+// StateAttributes returns the attributes the Terraform provider needs to deregister the revision.
+func (m EcsTaskDefinitionModel) StateAttributes() map[string]string {
+	return map[string]string{"arn": m.r.ID}
+}
+
+func (m EcsTaskDefinitionModel) revisionOf() (string, int64) {
+	return m.Family, m.Revision
+}
+
+// BeanstalkEnvironmentModel is the model of Elastic Beanstalk environments.
+type BeanstalkEnvironmentModel struct {
+	BaseModel
+	Name        string
+	Application string
+	Status      string
+	Health      string
+
+	arn string
+}
+
+// StateAttributes returns the attributes the Terraform provider needs to terminate the environment and
+// wait until it is terminated (the provider doesn't apply the default timeout to imported state).
+func (m BeanstalkEnvironmentModel) StateAttributes() map[string]string {
+	return map[string]string{
+		"wait_for_ready_timeout": "20m",
+		"poll_interval":          "10s",
+	}
+}
+
+// BeanstalkAppVersionModel is the model of versions of Elastic Beanstalk applications.
+type BeanstalkAppVersionModel struct {
+	BaseModel
+	Application string
+	Status      string
+}
+
+// StateAttributes returns the attributes the Terraform provider needs to delete the version
+// (which fails if the version is deployed to an environment).
+func (m BeanstalkAppVersionModel) StateAttributes() map[string]string {
+	return map[string]string{
+		"application":  m.Application,
+		"force_delete": "false",
+	}
+}
+
+func (m BeanstalkAppVersionModel) revisionOf() (string, int64) {
+	if m.r.Created == nil {
+		return m.Application, 0
+	}
+	return m.Application, m.r.Created.UnixNano()
+}
+
+// firehoseS3Bucket returns the name of the bucket of the first S3 destination of a delivery stream
+// (empty if it has none).
+func firehoseS3Bucket(destinations []*firehose.DestinationDescription) string {
+	for _, d := range destinations {
+		switch {
+		case d.ExtendedS3DestinationDescription != nil:
+			return arn.ResourceID(aws.StringValue(d.ExtendedS3DestinationDescription.BucketARN))
+		case d.S3DestinationDescription != nil:
+			return arn.ResourceID(aws.StringValue(d.S3DestinationDescription.BucketARN))
+		}
+	}
+	return ""
+}
+
+// newModel creates the typed model of a resource from the raw resource listed via the AWS API.
+func newModel(r *Resource, raw interface{}) Model {
+	base := BaseModel{r: r}
+
+	switch res := raw.(type) {
+	case *ec2.Instance:
+		m := InstanceModel{
+			BaseModel:    base,
+			InstanceType: aws.StringValue(res.InstanceType),
+			keyName:      aws.StringValue(res.KeyName),
+		}
+		if res.State != nil {
+			m.State = aws.StringValue(res.State.Name)
+		}
+		return m
+	case *ec2.Volume:
+		m := VolumeModel{
+			BaseModel:  base,
+			SizeGB:     aws.Int64Value(res.Size),
+			State:      aws.StringValue(res.State),
+			VolumeType: aws.StringValue(res.VolumeType),
+			Attached:   len(res.Attachments) > 0,
+		}
+		if len(res.Attachments) > 0 {
+			m.InstanceID = aws.StringValue(res.Attachments[0].InstanceId)
+			m.Device = aws.StringValue(res.Attachments[0].Device)
+		}
+		return m
+	case *ec2.Snapshot:
+		return SnapshotModel{
+			BaseModel: base,
+			SizeGB:    aws.Int64Value(res.VolumeSize),
+			VolumeID:  aws.StringValue(res.VolumeId),
+		}
+	case *ec2.KeyPairInfo:
+		return KeyPairModel{BaseModel: base, Fingerprint: aws.StringValue(res.KeyFingerprint)}
+	case *autoscaling.Group:
+		m := AutoscalingGroupModel{
+			BaseModel:               base,
+			InstanceCount:           int64(len(res.Instances)),
+			DesiredCapacity:         aws.Int64Value(res.DesiredCapacity),
+			LaunchConfigurationName: aws.StringValue(res.LaunchConfigurationName),
+			PropagatedTags:          map[string]string{},
+		}
+		if res.LaunchTemplate != nil {
+			m.LaunchTemplateName = aws.StringValue(res.LaunchTemplate.LaunchTemplateName)
+		}
+		for _, t := range res.Tags {
+			if aws.BoolValue(t.PropagateAtLaunch) {
+				m.PropagatedTags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+			}
+		}
+		return m
+	case *elb.LoadBalancerDescription:
+		return ElbModel{BaseModel: base, InstanceCount: int64(len(res.Instances))}
+	case *iamRelation:
+		principal, target := aws.StringValue(res.Principal), aws.StringValue(res.Target)
+		switch r.Type {
+		case IamUserGroupMembership:
+			return IamUserGroupMembershipModel{BaseModel: base, User: principal, Group: target}
+		case IamRolePolicyAttachment:
+			return IamRolePolicyAttachmentModel{BaseModel: base, Role: principal, PolicyArn: target}
+		case IamUserPolicyAttachment:
+			return IamUserPolicyAttachmentModel{BaseModel: base, User: principal, PolicyArn: target}
+		}
+	case *firehose.DeliveryStreamDescription:
+		return FirehoseDeliveryStreamModel{
+			BaseModel:          base,
+			Name:               aws.StringValue(res.DeliveryStreamName),
+			DeliveryStreamType: aws.StringValue(res.DeliveryStreamType),
+			Status:             aws.StringValue(res.DeliveryStreamStatus),
+			S3Bucket:           firehoseS3Bucket(res.Destinations),
+		}
+	case *athena.NamedQuery:
+		return AthenaNamedQueryModel{
+			BaseModel: base,
+			Name:      aws.StringValue(res.Name),
+			Database:  aws.StringValue(res.Database),
+		}
+	case *glue.Crawler:
+		return GlueCrawlerModel{
+			BaseModel:    base,
+			DatabaseName: aws.StringValue(res.DatabaseName),
+			State:        aws.StringValue(res.State),
+		}
+	case *glue.Job:
+		m := GlueJobModel{BaseModel: base, Role: aws.StringValue(res.Role)}
+		if res.Command != nil {
+			m.Command = aws.StringValue(res.Command.Name)
+		}
+		return m
+	case *kinesis.StreamDescriptionSummary:
+		return KinesisStreamModel{
+			BaseModel:            base,
+			Name:                 aws.StringValue(res.StreamName),
+			Status:               aws.StringValue(res.StreamStatus),
+			OpenShardCount:       aws.Int64Value(res.OpenShardCount),
+			RetentionPeriodHours: aws.Int64Value(res.RetentionPeriodHours),
+		}
+	case *kmsKey:
+		return KmsKeyModel{
+			BaseModel:   base,
+			KeyState:    aws.StringValue(res.KeyState),
+			KeyManager:  aws.StringValue(res.KeyManager),
+			Description: aws.StringValue(res.Description),
+			KeyUsage:    aws.StringValue(res.KeyUsage),
+			Aliases:     aws.StringValueSlice(res.Aliases),
+		}
+	case *dynamodb.TableDescription:
+		return DynamodbTableModel{
+			BaseModel: base,
+			ItemCount: aws.Int64Value(res.ItemCount),
+			SizeBytes: aws.Int64Value(res.TableSizeBytes),
+			Status:    aws.StringValue(res.TableStatus),
+			arn:       aws.StringValue(res.TableArn),
+		}
+	case *rds.DBCluster:
+		var members []string
+		for _, member := range res.DBClusterMembers {
+			members = append(members, aws.StringValue(member.DBInstanceIdentifier))
+		}
+		return RdsClusterModel{
+			BaseModel:          base,
+			Engine:             aws.StringValue(res.Engine),
+			EngineVersion:      aws.StringValue(res.EngineVersion),
+			Status:             aws.StringValue(res.Status),
+			MemberCount:        int64(len(members)),
+			DeletionProtection: aws.BoolValue(res.DeletionProtection),
+			arn:                aws.StringValue(res.DBClusterArn),
+			members:            members,
+		}
+	case *redshift.Cluster:
+		return RedshiftClusterModel{
+			BaseModel:       base,
+			NodeType:        aws.StringValue(res.NodeType),
+			NumberOfNodes:   aws.Int64Value(res.NumberOfNodes),
+			Status:          aws.StringValue(res.ClusterStatus),
+			finalSnapshotID: finalClusterSnapshotID(aws.StringValue(res.ClusterIdentifier), time.Now()),
+		}
+	case *redshift.Snapshot:
+		return RedshiftSnapshotModel{
+			BaseModel: base,
+			SizeMb:    int64(aws.Float64Value(res.TotalBackupSizeInMegaBytes)),
+			Cluster:   aws.StringValue(res.ClusterIdentifier),
+			Status:    aws.StringValue(res.Status),
+		}
+	case *rds.DBSnapshot:
+		return DbSnapshotModel{
+			BaseModel: base,
+			SizeGb:    aws.Int64Value(res.AllocatedStorage),
+			Instance:  aws.StringValue(res.DBInstanceIdentifier),
+			Engine:    aws.StringValue(res.Engine),
+			Status:    aws.StringValue(res.Status),
+			arn:       aws.StringValue(res.DBSnapshotArn),
+		}
+	case *rds.DBClusterSnapshot:
+		return DbClusterSnapshotModel{
+			BaseModel: base,
+			SizeGb:    aws.Int64Value(res.AllocatedStorage),
+			Cluster:   aws.StringValue(res.DBClusterIdentifier),
+			Engine:    aws.StringValue(res.Engine),
+			Status:    aws.StringValue(res.Status),
+			arn:       aws.StringValue(res.DBClusterSnapshotArn),
+		}
+	case *lambda.FunctionConfiguration:
+		return LambdaFunctionModel{
+			BaseModel:  base,
+			Runtime:    aws.StringValue(res.Runtime),
+			MemorySize: aws.Int64Value(res.MemorySize),
+			CodeSize:   aws.Int64Value(res.CodeSize),
+		}
+	case *cloudwatchlogs.LogGroup:
+		return LogGroupModel{
+			BaseModel:     base,
+			RetentionDays: aws.Int64Value(res.RetentionInDays),
+			StoredBytes:   aws.Int64Value(res.StoredBytes),
+		}
+	case *cloudwatch.MetricAlarm:
+		return MetricAlarmModel{
+			BaseModel:      base,
+			State:          aws.StringValue(res.StateValue),
+			Namespace:      aws.StringValue(res.Namespace),
+			MetricName:     aws.StringValue(res.MetricName),
+			ActionsEnabled: aws.BoolValue(res.ActionsEnabled),
+		}
+	case *logStream:
+		return LogStreamModel{
+			BaseModel:   base,
+			LogGroup:    aws.StringValue(res.LogGroupName),
+			StoredBytes: aws.Int64Value(res.StoredBytes),
+			lastEvent:   toTime(res.LastEventTimestamp),
+		}
+	case *ecs.Cluster:
+		return EcsClusterModel{
+			BaseModel:              base,
+			Status:                 aws.StringValue(res.Status),
+			ServiceCount:           aws.Int64Value(res.ActiveServicesCount),
+			RunningTaskCount:       aws.Int64Value(res.RunningTasksCount),
+			ContainerInstanceCount: aws.Int64Value(res.RegisteredContainerInstancesCount),
+			name:                   aws.StringValue(res.ClusterName),
+		}
+	case *sns.Subscription:
+		topicArn := aws.StringValue(res.TopicArn)
+		return SnsSubscriptionModel{
+			BaseModel: base,
+			Topic:     arn.ResourceID(topicArn),
+			Protocol:  aws.StringValue(res.Protocol),
+			Endpoint:  aws.StringValue(res.Endpoint),
+		}
+	case *ecs.Service:
+		clusterArn := aws.StringValue(res.ClusterArn)
+		return EcsServiceModel{
+			BaseModel:    base,
+			Cluster:      arn.ResourceID(clusterArn),
+			LaunchType:   aws.StringValue(res.LaunchType),
+			DesiredCount: aws.Int64Value(res.DesiredCount),
+			RunningCount: aws.Int64Value(res.RunningCount),
+			clusterArn:   clusterArn,
+		}
+	case *ecsTaskDefinitionRevision:
+		return EcsTaskDefinitionModel{
+			BaseModel: base,
+			Family:    aws.StringValue(res.Family),
+			Revision:  aws.Int64Value(res.Revision),
+		}
+	case *elasticbeanstalk.EnvironmentDescription:
+		return BeanstalkEnvironmentModel{
+			BaseModel:   base,
+			Name:        aws.StringValue(res.EnvironmentName),
+			Application: aws.StringValue(res.ApplicationName),
+			Status:      aws.StringValue(res.Status),
+			Health:      aws.StringValue(res.Health),
+			arn:         aws.StringValue(res.EnvironmentArn),
+		}
+	case *elasticbeanstalk.ApplicationVersionDescription:
+		return BeanstalkAppVersionModel{
+			BaseModel:   base,
+			Application: aws.StringValue(res.ApplicationName),
+			Status:      aws.StringValue(res.Status),
+		}
+	case *iam.User:
+		return IamEntityModel{BaseModel: base, Path: aws.StringValue(res.Path)}
+	case *iam.Role:
+		return IamEntityModel{BaseModel: base, Path: aws.StringValue(res.Path)}
+	case *iam.Group:
+		return IamEntityModel{BaseModel: base, Path: aws.StringValue(res.Path)}
+	case *iam.Policy:
+		return IamEntityModel{BaseModel: base, Path: aws.StringValue(res.Path)}
+	case *iam.InstanceProfile:
+		return IamEntityModel{BaseModel: base, Path: aws.StringValue(res.Path)}
+	case *cloudfront.DistributionSummary:
+		model := CloudfrontDistributionModel{
+			BaseModel:    base,
+			Enabled:      aws.BoolValue(res.Enabled),
+			Status:       aws.StringValue(res.Status),
+			DomainName:   aws.StringValue(res.DomainName),
+			Comment:      aws.StringValue(res.Comment),
+			LastModified: aws.TimeValue(res.LastModifiedTime),
+		}
+		if res.Aliases != nil {
+			model.Aliases = aws.StringValueSlice(res.Aliases.Items)
+		}
+		return model
+	case *cognitoidentityprovider.UserPoolDescriptionType:
+		return CognitoUserPoolModel{
+			BaseModel:    base,
+			Name:         aws.StringValue(res.Name),
+			Status:       aws.StringValue(res.Status),
+			LastModified: aws.TimeValue(res.LastModifiedDate),
+		}
+	case *cognitoidentity.IdentityPoolShortDescription:
+		return CognitoIdentityPoolModel{BaseModel: base, Name: aws.StringValue(res.IdentityPoolName)}
+	case *ssm.ParameterMetadata:
+		return SsmParameterModel{
+			BaseModel:    base,
+			Type:         aws.StringValue(res.Type),
+			Version:      aws.Int64Value(res.Version),
+			LastModified: aws.TimeValue(res.LastModifiedDate),
+		}
+	case *iam.AccessKeyMetadata:
+		return IamAccessKeyModel{
+			BaseModel: base,
+			User:      aws.StringValue(res.UserName),
+			Status:    aws.StringValue(res.Status),
+		}
+	}
+	return base
+}
+
+// StateAttributes returns the attributes of the Terraform state of a resource, which are required
+// (besides the ID) to delete it.
+func StateAttributes(r *Resource) map[string]string {
+	if s, ok := r.Model.(stater); ok {
+		return s.StateAttributes()
+	}
+
+	// the Terraform provider expects a non-empty state
+	return map[string]string{"public_key": ""}
+}
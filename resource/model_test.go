@@ -0,0 +1,77 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWS_DeletableResources_Model(t *testing.T) {
+	// given
+	rawResources := []*ec2.Volume{
+		{
+			VolumeId:    aws.String("vol-0123"),
+			Size:        aws.Int64(100),
+			State:       aws.String("available"),
+			VolumeType:  aws.String("gp2"),
+			Attachments: []*ec2.VolumeAttachment{},
+		},
+	}
+
+	// when
+	res, err := resource.DeletableResources(resource.EbsVolume, rawResources)
+	require.NoError(t, err)
+
+	// then
+	require.Len(t, res, 1)
+	m, ok := res[0].Model.(resource.VolumeModel)
+	require.True(t, ok)
+	assert.Equal(t, "vol-0123", m.ID())
+	assert.Equal(t, int64(100), m.SizeGB)
+	assert.Equal(t, "available", m.State)
+	assert.False(t, m.Attached)
+}
+
+func TestModel_ReflectsResource(t *testing.T) {
+	// given
+	rawResources := []*ec2.Instance{
+		{
+			InstanceId:   &testInstanceID,
+			InstanceType: aws.String("t2.micro"),
+		},
+	}
+	res, err := resource.DeletableResources(resource.Instance, rawResources)
+	require.NoError(t, err)
+
+	// when
+	res[0].Tags = map[string]string{"foo": "bar"}
+	res[0].Region = "eu-west-1"
+
+	// then
+	m := res[0].Model
+	assert.Equal(t, "t2.micro", m.(resource.InstanceModel).InstanceType)
+	assert.Equal(t, map[string]string{"foo": "bar"}, m.Tags())
+	assert.Equal(t, "eu-west-1", m.Region())
+}
+
+func TestStateAttributes(t *testing.T) {
+	// given
+	rawResources := []*ec2.KeyPairInfo{
+		{
+			KeyName: aws.String("foo"),
+		},
+	}
+	res, err := resource.DeletableResources(resource.KeyPair, rawResources)
+	require.NoError(t, err)
+
+	// when
+	attrs := resource.StateAttributes(res[0])
+
+	// then
+	assert.Equal(t, map[string]string{"public_key": ""}, attrs)
+	assert.NotNil(t, resource.StateAttributes(&resource.Resource{Type: resource.Vpc, ID: "vpc-0123"}))
+}
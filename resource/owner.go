@@ -0,0 +1,117 @@
+package resource
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	// OwnerFromKeyPair means that the owner is the name of the key pair an instance has been launched with.
+	OwnerFromKeyPair = "key_pair"
+	// OwnerFromNamePrefix means that the owner is the prefix of the name of a resource (e.g., "alice" of "alice-dev").
+	OwnerFromNamePrefix = "name_prefix"
+	// OwnerFromCreator means that the owner is the principal that created a resource according to CloudTrail.
+	OwnerFromCreator = "creator"
+	// UnknownOwner is the owner of resources for which no owner could be inferred.
+	UnknownOwner = "unknown"
+)
+
+var (
+	// generatedIDPattern matches IDs generated by AWS (e.g., i-0123456789abcdef0), which have no meaningful prefix.
+	generatedIDPattern = regexp.MustCompile(`^[a-z]+-[0-9a-f]{8,17}$`)
+	// namePrefixPattern matches the prefix of a name up to the first separator.
+	namePrefixPattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9]*)[-_.]`)
+)
+
+// Ownership is a resource missing required tags together with its inferred owner.
+type Ownership struct {
+	Resource *Resource
+	Region   string
+	Owner    string
+	// Source is how the owner has been inferred (e.g., OwnerFromCreator)
+	Source      string
+	MissingTags []string
+}
+
+// InferOwner infers who owns a resource, taking (in this order) the key pair an instance has been launched with,
+// the prefix of the name of the resource (its Name tag or ID) and the principal that created it according to
+// the CloudTrail event history (which covers the last 90 days).
+func (a *AWS) InferOwner(r *Resource) (string, string) {
+	if m, ok := r.Model.(InstanceModel); ok && m.keyName != "" {
+		return m.keyName, OwnerFromKeyPair
+	}
+	if prefix := namePrefix(r); prefix != "" {
+		return prefix, OwnerFromNamePrefix
+	}
+	if creator, err := a.creator(r); err == nil && creator != "" {
+		return creator, OwnerFromCreator
+	}
+	return UnknownOwner, UnknownOwner
+}
+
+// namePrefix returns the prefix of the name of a resource, which is its Name tag or, if the ID isn't
+// generated by AWS, the last part of its ID (e.g., the name of a role).
+func namePrefix(r *Resource) string {
+	name := r.Tags["Name"]
+	if name == "" && !generatedIDPattern.MatchString(r.ID) {
+		name = r.ID[strings.LastIndexAny(r.ID, "/:")+1:]
+	}
+
+	m := namePrefixPattern.FindStringSubmatch(name)
+	if m == nil {
+		return ""
+	}
+	return strings.ToLower(m[1])
+}
+
+// FormatOwnerReport returns the resources missing cost allocation tags as human-readable text grouped
+// by their owner (sorted by name, resources of unknown owners last).
+func FormatOwnerReport(ownerships []Ownership) string {
+	byOwner := map[string][]Ownership{}
+	var owners []string
+	for _, o := range ownerships {
+		if _, found := byOwner[o.Owner]; !found && o.Owner != UnknownOwner {
+			owners = append(owners, o.Owner)
+		}
+		byOwner[o.Owner] = append(byOwner[o.Owner], o)
+	}
+	sort.Strings(owners)
+	if _, found := byOwner[UnknownOwner]; found {
+		owners = append(owners, UnknownOwner)
+	}
+
+	var b strings.Builder
+	for _, owner := range owners {
+		group := byOwner[owner]
+		sort.SliceStable(group, func(i, j int) bool {
+			if group[i].Resource.Type != group[j].Resource.Type {
+				return group[i].Resource.Type < group[j].Resource.Type
+			}
+			return group[i].Resource.ID < group[j].Resource.ID
+		})
+
+		fmt.Fprintf(&b, "Owner: %s\nResources: %d\n\n", owner, len(group))
+		for _, o := range group {
+			fmt.Fprintf(&b, "\t%s\t%s\t%s", o.Resource.Type, o.Resource.ID, o.Region)
+			if o.Source != UnknownOwner {
+				fmt.Fprintf(&b, "\tinferred from %s", o.Source)
+			}
+			fmt.Fprintf(&b, "\tmissing tags: %s\n", strings.Join(o.MissingTags, ", "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// WriteOwnerReport writes the report of resources missing cost allocation tags to a file.
+func WriteOwnerReport(filename string, ownerships []Ownership) error {
+	if err := afero.WriteFile(AppFs, filename, []byte(FormatOwnerReport(ownerships)), 0644); err != nil {
+		return errors.Wrapf(err, "failed to write owner report: %s", filename)
+	}
+	return nil
+}
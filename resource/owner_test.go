@@ -0,0 +1,101 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/resourcetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWS_InferOwner(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"ec2": {
+			"DescribeInstances": map[string]interface{}{
+				"Reservations": []interface{}{
+					map[string]interface{}{
+						"Instances": []interface{}{
+							map[string]interface{}{"InstanceId": "i-0123456789abcdef0", "KeyName": "alice"},
+							map[string]interface{}{"InstanceId": "i-0123456789abcdef1",
+								"Tags": []interface{}{map[string]interface{}{"Key": "Name", "Value": "Bob-dev-web"}}},
+							map[string]interface{}{"InstanceId": "i-0123456789abcdef2"},
+						},
+					},
+				},
+			},
+		},
+		"cloudtrail": {
+			"LookupEvents": map[string]interface{}{
+				"Events": []interface{}{
+					map[string]interface{}{"EventName": "TerminateInstances", "Username": "ops"},
+					map[string]interface{}{"EventName": "RunInstances", "Username": "carol"},
+				},
+			},
+		},
+	})
+	raw, err := a.RawResources(resource.Instance)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.Instance, raw)
+	require.NoError(t, err)
+	require.Len(t, res, 3)
+
+	// when
+	var owners, sources []string
+	for _, r := range res {
+		owner, source := a.InferOwner(r)
+		owners = append(owners, owner)
+		sources = append(sources, source)
+	}
+
+	// then
+	assert.Equal(t, []string{"alice", "bob", "carol"}, owners)
+	assert.Equal(t, []string{resource.OwnerFromKeyPair, resource.OwnerFromNamePrefix, resource.OwnerFromCreator}, sources)
+}
+
+func TestFormatOwnerReport(t *testing.T) {
+	// given
+	ownerships := []resource.Ownership{
+		{
+			Resource:    &resource.Resource{Type: resource.IamRole, ID: "zoe-ci"},
+			Region:      "us-east-1",
+			Owner:       resource.UnknownOwner,
+			Source:      resource.UnknownOwner,
+			MissingTags: []string{"CostCenter"},
+		},
+		{
+			Resource:    &resource.Resource{Type: resource.Instance, ID: "i-2"},
+			Region:      "eu-west-1",
+			Owner:       "bob",
+			Source:      resource.OwnerFromNamePrefix,
+			MissingTags: []string{"CostCenter", "Owner"},
+		},
+		{
+			Resource:    &resource.Resource{Type: resource.EbsVolume, ID: "vol-1"},
+			Region:      "us-east-1",
+			Owner:       "bob",
+			Source:      resource.OwnerFromCreator,
+			MissingTags: []string{"Owner"},
+		},
+		{
+			Resource:    &resource.Resource{Type: resource.Instance, ID: "i-1"},
+			Region:      "us-east-1",
+			Owner:       "alice",
+			Source:      resource.OwnerFromKeyPair,
+			MissingTags: []string{"Owner"},
+		},
+	}
+
+	// when
+	report := resource.FormatOwnerReport(ownerships)
+
+	// then
+	assert.Equal(t, "Owner: alice\nResources: 1\n\n"+
+		"\taws_instance\ti-1\tus-east-1\tinferred from key_pair\tmissing tags: Owner\n\n"+
+		"Owner: bob\nResources: 2\n\n"+
+		"\taws_ebs_volume\tvol-1\tus-east-1\tinferred from creator\tmissing tags: Owner\n"+
+		"\taws_instance\ti-2\teu-west-1\tinferred from name_prefix\tmissing tags: CostCenter, Owner\n\n"+
+		"Owner: unknown\nResources: 1\n\n"+
+		"\taws_iam_role\tzoe-ci\tus-east-1\tmissing tags: CostCenter\n\n", report)
+}
@@ -0,0 +1,66 @@
+package resource
+
+import (
+	"sync"
+	"time"
+)
+
+// deleteIntervals are the minimum intervals between two delete requests of resource types
+// whose delete API accepts only a few requests per minute (per account and region).
+var deleteIntervals = map[TerraformResourceType]time.Duration{
+	// DeleteRestApi accepts 1 request every 30 seconds
+	ApiGatewayRestApi: 30 * time.Second,
+}
+
+// DeleteInterval returns the minimum interval between deleting two resources of a type
+// (0 if resources can be deleted as fast as the workers manage).
+func DeleteInterval(resType TerraformResourceType) time.Duration {
+	return deleteIntervals[resType]
+}
+
+// Pacer spaces out the requests to an API with a very low rate limit, so that a sweep doesn't
+// fail halfway through because of throttling.
+type Pacer struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewPacer creates a pacer that issues at most one request per interval.
+// Requests aren't paced at all if the interval is 0.
+func NewPacer(interval time.Duration) *Pacer {
+	return &Pacer{interval: interval}
+}
+
+// wait blocks until the next request is allowed to be issued.
+func (p *Pacer) wait() {
+	p.mu.Lock()
+	now := time.Now()
+	start := p.next
+	if start.Before(now) {
+		start = now
+	}
+	p.next = start.Add(p.interval)
+	p.mu.Unlock()
+
+	time.Sleep(start.Sub(now))
+}
+
+// Do calls fn as soon as the interval since the previous request has passed. If the request is throttled anyway
+// (e.g., because someone else uses the API at the same time), it is retried in the next interval
+// until the maximum number of retries is reached.
+func (p *Pacer) Do(fn func() error) error {
+	if p.interval == 0 {
+		return fn()
+	}
+
+	var err error
+	for i := 0; i <= maxThrottleRetries; i++ {
+		p.wait()
+		err = fn()
+		if !isThrottled(err) {
+			return err
+		}
+	}
+	return err
+}
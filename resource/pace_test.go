@@ -0,0 +1,67 @@
+package resource_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPacer_Do(t *testing.T) {
+	// given
+	p := resource.NewPacer(20 * time.Millisecond)
+	start := time.Now()
+
+	// when
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Do(func() error { return nil })
+		}()
+	}
+	wg.Wait()
+
+	// then
+	assert.True(t, time.Since(start) >= 40*time.Millisecond)
+}
+
+func TestPacer_Do_RetriesThrottled(t *testing.T) {
+	// given
+	p := resource.NewPacer(time.Millisecond)
+	calls := 0
+
+	// when
+	err := p.Do(func() error {
+		calls++
+		if calls < 3 {
+			return awserr.New("TooManyRequestsException", "Too Many Requests", nil)
+		}
+		return nil
+	})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestPacer_Do_Unpaced(t *testing.T) {
+	// given
+	p := resource.NewPacer(0)
+	calls := 0
+
+	// when
+	err := p.Do(func() error {
+		calls++
+		return errors.New("Throttling: Rate exceeded")
+	})
+
+	// then
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,521 @@
+package resource
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// Permissions are the IAM actions required to list and delete resources of a particular type.
+type Permissions struct {
+	// List are the actions to list resources (including tags)
+	// and to refresh their state before deletion
+	List []string
+	// Delete are the actions the Terraform AWS provider uses to delete resources
+	Delete []string
+}
+
+var (
+	// commonActions are required independently of resource types,
+	// e.g., to check the account ID and the enabled regions.
+	commonActions = []string{
+		"ec2:DescribeRegions",
+		"sts:GetCallerIdentity",
+	}
+
+	permissions = map[TerraformResourceType]Permissions{
+		Ami: {
+			List:   []string{"ec2:DescribeImages"},
+			Delete: []string{"ec2:DeregisterImage", "ec2:DeleteSnapshot"},
+		},
+		ApiGatewayRestApi: {
+			List:   []string{"apigateway:GET", "tag:GetResources"},
+			Delete: []string{"apigateway:DELETE"},
+		},
+		AthenaNamedQuery: {
+			List:   []string{"athena:ListNamedQueries", "athena:BatchGetNamedQuery"},
+			Delete: []string{"athena:DeleteNamedQuery", "athena:GetNamedQuery"},
+		},
+		AutoscalingGroup: {
+			List:   []string{"autoscaling:DescribeAutoScalingGroups"},
+			Delete: []string{"autoscaling:DeleteAutoScalingGroup", "autoscaling:UpdateAutoScalingGroup"},
+		},
+		CloudformationStack: {
+			List:   []string{"cloudformation:DescribeStacks"},
+			Delete: []string{"cloudformation:DeleteStack", "cloudformation:DescribeStackEvents"},
+		},
+		CloudfrontDistribution: {
+			List: []string{"cloudfront:ListDistributions", "cloudfront:ListTagsForResource"},
+			// distributions are disabled and deployed before they can be deleted
+			Delete: []string{"cloudfront:GetDistribution", "cloudfront:UpdateDistribution",
+				"cloudfront:DeleteDistribution"},
+		},
+		Cloudtrail: {
+			List:   []string{"cloudtrail:DescribeTrails", "cloudtrail:GetTrailStatus", "tag:GetResources"},
+			Delete: []string{"cloudtrail:DeleteTrail"},
+		},
+		CloudwatchDashboard: {
+			List:   []string{"cloudwatch:ListDashboards"},
+			Delete: []string{"cloudwatch:DeleteDashboards"},
+		},
+		CloudwatchLogGroup: {
+			List:   []string{"logs:DescribeLogGroups", "logs:ListTagsLogGroup"},
+			Delete: []string{"logs:DeleteLogGroup"},
+		},
+		CloudwatchLogStream: {
+			List:   []string{"logs:DescribeLogGroups", "logs:DescribeLogStreams"},
+			Delete: []string{"logs:DeleteLogStream"},
+		},
+		CloudwatchMetricAlarm: {
+			List:   []string{"cloudwatch:DescribeAlarms", "tag:GetResources"},
+			Delete: []string{"cloudwatch:DeleteAlarms"},
+		},
+		CognitoIdentityPool: {
+			List:   []string{"cognito-identity:ListIdentityPools"},
+			Delete: []string{"cognito-identity:DescribeIdentityPool", "cognito-identity:DeleteIdentityPool"},
+		},
+		CognitoUserPool: {
+			List:   []string{"cognito-idp:ListUserPools", "cognito-idp:DescribeUserPool"},
+			Delete: []string{"cognito-idp:DescribeUserPool", "cognito-idp:DeleteUserPool"},
+		},
+		ConfigConfigRule: {
+			List:   []string{"config:DescribeConfigRules"},
+			Delete: []string{"config:DeleteConfigRule"},
+		},
+		ConfigRecorder: {
+			List:   []string{"config:DescribeConfigurationRecorders", "config:DescribeConfigurationRecorderStatus"},
+			Delete: []string{"config:DeleteConfigurationRecorder", "config:StopConfigurationRecorder"},
+		},
+		ConfigDelivery: {
+			List:   []string{"config:DescribeDeliveryChannels"},
+			Delete: []string{"config:DeleteDeliveryChannel"},
+		},
+		DbClusterSnapshot: {
+			List:   []string{"rds:DescribeDBClusterSnapshots", "rds:ListTagsForResource", "sts:GetCallerIdentity"},
+			Delete: []string{"rds:DeleteDBClusterSnapshot"},
+		},
+		DbSnapshot: {
+			List:   []string{"rds:DescribeDBSnapshots", "rds:ListTagsForResource", "sts:GetCallerIdentity"},
+			Delete: []string{"rds:DeleteDBSnapshot"},
+		},
+		DynamodbTable: {
+			List:   []string{"dynamodb:ListTables", "dynamodb:DescribeTable", "dynamodb:ListTagsOfResource"},
+			Delete: []string{"dynamodb:DeleteTable"},
+		},
+		EbsSnapshot: {
+			List:   []string{"ec2:DescribeSnapshots"},
+			Delete: []string{"ec2:DeleteSnapshot"},
+		},
+		EbsVolume: {
+			List:   []string{"ec2:DescribeVolumes"},
+			Delete: []string{"ec2:DeleteVolume"},
+		},
+		EcsCluster: {
+			List: []string{"ecs:ListClusters", "ecs:DescribeClusters", "ecs:ListServices",
+				"ecs:DescribeServices"},
+			Delete: []string{"ecs:DeleteCluster", "ecs:UpdateService", "ecs:DeleteService"},
+		},
+		EcsService: {
+			List:   []string{"ecs:ListClusters", "ecs:ListServices", "ecs:DescribeServices"},
+			Delete: []string{"ecs:UpdateService", "ecs:DeleteService"},
+		},
+		EcsTaskDefinition: {
+			List:   []string{"ecs:ListTaskDefinitions", "ecs:DescribeTaskDefinition"},
+			Delete: []string{"ecs:DeregisterTaskDefinition"},
+		},
+		EfsFileSystem: {
+			List: []string{"elasticfilesystem:DescribeFileSystems", "elasticfilesystem:DescribeTags",
+				"elasticfilesystem:DescribeMountTargets"},
+			Delete: []string{"elasticfilesystem:DeleteFileSystem", "elasticfilesystem:DeleteMountTarget"},
+		},
+		Eip: {
+			List:   []string{"ec2:DescribeAddresses"},
+			Delete: []string{"ec2:ReleaseAddress", "ec2:DisassociateAddress"},
+		},
+		BeanstalkApplication: {
+			List:   []string{"elasticbeanstalk:DescribeApplications", "tag:GetResources"},
+			Delete: []string{"elasticbeanstalk:DeleteApplication", "elasticbeanstalk:DescribeEnvironments"},
+		},
+		BeanstalkAppVersion: {
+			List:   []string{"elasticbeanstalk:DescribeApplicationVersions"},
+			Delete: []string{"elasticbeanstalk:DeleteApplicationVersion", "elasticbeanstalk:DescribeEnvironments"},
+		},
+		BeanstalkEnvironment: {
+			List: []string{"elasticbeanstalk:DescribeEnvironments", "elasticbeanstalk:ListTagsForResource"},
+			Delete: []string{"elasticbeanstalk:TerminateEnvironment", "elasticbeanstalk:DescribeEnvironments",
+				"elasticbeanstalk:DescribeEvents"},
+		},
+		Elb: {
+			List: []string{"elasticloadbalancing:DescribeLoadBalancers", "elasticloadbalancing:DescribeTags",
+				"elasticloadbalancing:DescribeLoadBalancerAttributes"},
+			Delete: []string{"elasticloadbalancing:DeleteLoadBalancer"},
+		},
+		GlueCatalogDatabase: {
+			List:   []string{"glue:GetDatabases", "sts:GetCallerIdentity"},
+			Delete: []string{"glue:DeleteDatabase", "glue:GetDatabase"},
+		},
+		GlueCrawler: {
+			List:   []string{"glue:GetCrawlers"},
+			Delete: []string{"glue:DeleteCrawler", "glue:GetCrawler"},
+		},
+		GlueJob: {
+			List:   []string{"glue:GetJobs"},
+			Delete: []string{"glue:DeleteJob", "glue:GetJob"},
+		},
+		IamAccessKey: {
+			List:   []string{"iam:ListUsers", "iam:ListAccessKeys"},
+			Delete: []string{"iam:DeleteAccessKey"},
+		},
+		IamGroup: {
+			List:   []string{"iam:ListGroups", "iam:GetGroup"},
+			Delete: []string{"iam:DeleteGroup"},
+		},
+		IamInstanceProfile: {
+			List:   []string{"iam:ListInstanceProfiles", "iam:GetInstanceProfile"},
+			Delete: []string{"iam:DeleteInstanceProfile", "iam:RemoveRoleFromInstanceProfile"},
+		},
+		IamPolicy: {
+			List:   []string{"iam:ListPolicies", "iam:GetPolicy", "iam:GetPolicyVersion"},
+			Delete: []string{"iam:DeletePolicy", "iam:ListPolicyVersions", "iam:DeletePolicyVersion"},
+		},
+		IamRole: {
+			List: []string{"iam:ListRoles", "iam:GetRole"},
+			Delete: []string{"iam:DeleteRole", "iam:ListInstanceProfilesForRole", "iam:RemoveRoleFromInstanceProfile",
+				"iam:ListAttachedRolePolicies", "iam:DetachRolePolicy", "iam:ListRolePolicies", "iam:DeleteRolePolicy"},
+		},
+		IamRolePolicyAttachment: {
+			List:   []string{"iam:GetAccountAuthorizationDetails"},
+			Delete: []string{"iam:ListAttachedRolePolicies", "iam:DetachRolePolicy"},
+		},
+		IamUser: {
+			List: []string{"iam:ListUsers", "iam:GetUser"},
+			Delete: []string{"iam:DeleteUser", "iam:ListAccessKeys", "iam:DeleteAccessKey", "iam:DeleteLoginProfile",
+				"iam:ListGroupsForUser", "iam:RemoveUserFromGroup", "iam:ListMFADevices", "iam:DeactivateMFADevice",
+				"iam:ListSSHPublicKeys", "iam:DeleteSSHPublicKey"},
+		},
+		IamUserGroupMembership: {
+			List:   []string{"iam:GetAccountAuthorizationDetails"},
+			Delete: []string{"iam:ListGroupsForUser", "iam:RemoveUserFromGroup"},
+		},
+		IamUserPolicyAttachment: {
+			List:   []string{"iam:GetAccountAuthorizationDetails"},
+			Delete: []string{"iam:ListAttachedUserPolicies", "iam:DetachUserPolicy"},
+		},
+		Instance: {
+			List:   []string{"ec2:DescribeInstances", "ec2:DescribeInstanceAttribute"},
+			Delete: []string{"ec2:TerminateInstances"},
+		},
+		InternetGateway: {
+			List:   []string{"ec2:DescribeInternetGateways", "ec2:DescribeVpcs"},
+			Delete: []string{"ec2:DeleteInternetGateway", "ec2:DetachInternetGateway"},
+		},
+		KeyPair: {
+			List:   []string{"ec2:DescribeKeyPairs"},
+			Delete: []string{"ec2:DeleteKeyPair"},
+		},
+		FirehoseDeliveryStream: {
+			List: []string{"firehose:ListDeliveryStreams", "firehose:DescribeDeliveryStream",
+				"firehose:ListTagsForDeliveryStream"},
+			Delete: []string{"firehose:DeleteDeliveryStream", "firehose:DescribeDeliveryStream"},
+		},
+		KinesisStream: {
+			List:   []string{"kinesis:ListStreams", "kinesis:DescribeStreamSummary", "kinesis:ListTagsForStream"},
+			Delete: []string{"kinesis:DeleteStream", "kinesis:DescribeStream"},
+		},
+		KmsAlias: {
+			List:   []string{"kms:ListAliases"},
+			Delete: []string{"kms:DeleteAlias"},
+		},
+		KmsKey: {
+			List: []string{"kms:ListKeys", "kms:DescribeKey", "kms:ListAliases", "kms:ListResourceTags",
+				"kms:GetKeyPolicy", "kms:GetKeyRotationStatus"},
+			Delete: []string{"kms:ScheduleKeyDeletion"},
+		},
+		LambdaFunction: {
+			List: []string{"lambda:ListFunctions", "lambda:GetFunction", "lambda:ListEventSourceMappings",
+				"lambda:GetEventSourceMapping", "tag:GetResources"},
+			Delete: []string{"lambda:DeleteFunction", "lambda:DeleteEventSourceMapping"},
+		},
+		LaunchConfiguration: {
+			List:   []string{"autoscaling:DescribeLaunchConfigurations"},
+			Delete: []string{"autoscaling:DeleteLaunchConfiguration"},
+		},
+		Lb: {
+			List: []string{"elasticloadbalancing:DescribeLoadBalancers", "elasticloadbalancing:DescribeTags",
+				"elasticloadbalancing:DescribeLoadBalancerAttributes"},
+			Delete: []string{"elasticloadbalancing:DeleteLoadBalancer", "ec2:DescribeNetworkInterfaces"},
+		},
+		LbListener: {
+			List:   []string{"elasticloadbalancing:DescribeLoadBalancers", "elasticloadbalancing:DescribeListeners"},
+			Delete: []string{"elasticloadbalancing:DeleteListener"},
+		},
+		LbTargetGroup: {
+			List: []string{"elasticloadbalancing:DescribeTargetGroups", "elasticloadbalancing:DescribeTags",
+				"elasticloadbalancing:DescribeTargetGroupAttributes"},
+			Delete: []string{"elasticloadbalancing:DeleteTargetGroup", "elasticloadbalancing:DescribeTargetHealth",
+				"elasticloadbalancing:DeregisterTargets"},
+		},
+		NatGateway: {
+			List:   []string{"ec2:DescribeNatGateways"},
+			Delete: []string{"ec2:DeleteNatGateway"},
+		},
+		NetworkAcl: {
+			List:   []string{"ec2:DescribeNetworkAcls"},
+			Delete: []string{"ec2:DeleteNetworkAcl", "ec2:ReplaceNetworkAclAssociation"},
+		},
+		NetworkInterface: {
+			List:   []string{"ec2:DescribeNetworkInterfaces"},
+			Delete: []string{"ec2:DeleteNetworkInterface", "ec2:DetachNetworkInterface"},
+		},
+		RdsCluster: {
+			List:   []string{"rds:DescribeDBClusters", "rds:DescribeDBInstances", "rds:ListTagsForResource"},
+			Delete: []string{"rds:DeleteDBCluster", "rds:DeleteDBInstance"},
+		},
+		RedshiftCluster: {
+			List: []string{"redshift:DescribeClusters"},
+			// a final snapshot is created, unless skip_final_snapshot is set
+			Delete: []string{"redshift:DeleteCluster", "redshift:CreateClusterSnapshot"},
+		},
+		RedshiftSnapshot: {
+			List:   []string{"redshift:DescribeClusterSnapshots", "sts:GetCallerIdentity"},
+			Delete: []string{"redshift:DeleteClusterSnapshot"},
+		},
+		Route53Zone: {
+			List: []string{"route53:ListHostedZones", "route53:GetHostedZone", "route53:ListTagsForResources",
+				"route53:ListResourceRecordSets"},
+			Delete: []string{"route53:DeleteHostedZone", "route53:ChangeResourceRecordSets", "route53:GetChange"},
+		},
+		RouteTable: {
+			List:   []string{"ec2:DescribeRouteTables"},
+			Delete: []string{"ec2:DeleteRouteTable", "ec2:DisassociateRouteTable"},
+		},
+		S3Bucket: {
+			List:   []string{"s3:ListAllMyBuckets", "s3:GetBucketLocation", "s3:GetBucketTagging", "s3:ListBucket"},
+			Delete: []string{"s3:DeleteBucket", "s3:ListBucketVersions", "s3:DeleteObject", "s3:DeleteObjectVersion"},
+		},
+		SecurityGroup: {
+			List: []string{"ec2:DescribeSecurityGroups"},
+			Delete: []string{"ec2:DeleteSecurityGroup", "ec2:RevokeSecurityGroupIngress",
+				"ec2:RevokeSecurityGroupEgress"},
+		},
+		SesConfigurationSet: {
+			List:   []string{"ses:ListConfigurationSets"},
+			Delete: []string{"ses:DeleteConfigurationSet"},
+		},
+		SesDomainIdentity: {
+			List:   []string{"ses:ListIdentities", "ses:GetIdentityVerificationAttributes"},
+			Delete: []string{"ses:DeleteIdentity"},
+		},
+		SesEmailIdentity: {
+			List:   []string{"ses:ListIdentities", "ses:GetIdentityVerificationAttributes"},
+			Delete: []string{"ses:DeleteIdentity"},
+		},
+		SesReceiptRuleSet: {
+			List:   []string{"ses:ListReceiptRuleSets", "ses:DescribeReceiptRuleSet"},
+			Delete: []string{"ses:DeleteReceiptRuleSet"},
+		},
+		SfnStateMachine: {
+			List:   []string{"states:ListStateMachines"},
+			Delete: []string{"states:DeleteStateMachine", "states:DescribeStateMachine"},
+		},
+		SnsTopic: {
+			List: []string{"sns:ListTopics", "sns:GetTopicAttributes", "sns:ListSubscriptionsByTopic",
+				"tag:GetResources"},
+			Delete: []string{"sns:DeleteTopic", "sns:Unsubscribe"},
+		},
+		SnsTopicSubscription: {
+			List:   []string{"sns:ListSubscriptions", "sns:GetSubscriptionAttributes"},
+			Delete: []string{"sns:Unsubscribe"},
+		},
+		SqsQueue: {
+			List:   []string{"sqs:ListQueues", "sqs:ListQueueTags", "sqs:GetQueueAttributes"},
+			Delete: []string{"sqs:DeleteQueue"},
+		},
+		SsmParameter: {
+			List:   []string{"ssm:DescribeParameters", "ssm:ListTagsForResource"},
+			Delete: []string{"ssm:DeleteParameters"},
+		},
+		Subnet: {
+			List:   []string{"ec2:DescribeSubnets"},
+			Delete: []string{"ec2:DeleteSubnet"},
+		},
+		Vpc: {
+			List:   []string{"ec2:DescribeVpcs", "ec2:DescribeVpcAttribute"},
+			Delete: []string{"ec2:DeleteVpc"},
+		},
+		VpcEndpoint: {
+			List:   []string{"ec2:DescribeVpcEndpoints"},
+			Delete: []string{"ec2:DeleteVpcEndpoints"},
+		},
+	}
+
+	// applyTagsActions are required to apply tags onto resources of a particular type.
+	applyTagsActions = map[TerraformResourceType][]string{
+		AutoscalingGroup: {"autoscaling:CreateOrUpdateTags"},
+	}
+)
+
+// TypePermissions returns the IAM actions required to list and delete resources of a particular type.
+func TypePermissions(resType TerraformResourceType) Permissions {
+	return permissions[resType]
+}
+
+// PolicyDocument is an IAM policy document.
+type PolicyDocument struct {
+	Version   string
+	Statement []PolicyStatement
+}
+
+// PolicyStatement is a statement of an IAM policy document.
+type PolicyStatement struct {
+	Sid      string `json:",omitempty"`
+	Effect   string
+	Action   []string
+	Resource string
+}
+
+// Actions returns the (sorted) IAM actions required to sweep the resource types in the filter.
+// If listOnly is set, only the actions required for a dry run are returned.
+func (f Filter) Actions(listOnly bool) []string {
+	actions := map[string]bool{}
+	add := func(as []string) {
+		for _, a := range as {
+			actions[a] = true
+		}
+	}
+
+	add(commonActions)
+	if !listOnly && f.Events != nil && f.Events.EventBus != "" {
+		add([]string{"events:PutEvents"})
+	}
+	// hooks don't run in dry runs
+	if !listOnly && f.Hooks.usesLambda() {
+		add([]string{"lambda:InvokeFunction"})
+	}
+	if !listOnly && f.Lock != nil {
+		add([]string{"dynamodb:PutItem", "dynamodb:DeleteItem"})
+	}
+	if f.Leases != nil {
+		add([]string{"ssm:GetParametersByPath"})
+	}
+	if len(f.TrustedAdvisor) > 0 {
+		add([]string{"support:DescribeTrustedAdvisorCheckResult"})
+	}
+	if !listOnly && f.Audit != nil {
+		add([]string{"s3:PutObject"})
+	}
+	// the inventory is also exported in dry runs
+	if f.Inventory != nil {
+		add([]string{"dynamodb:BatchWriteItem"})
+	}
+	for _, resType := range f.Types() {
+		p := TypePermissions(resType)
+		add(p.List)
+		// the accounts a resource is shared with are also reported in dry runs
+		if f.RemoveSharing {
+			add(sharingActions[resType].List)
+		}
+		for _, rtf := range f.Cfg[resType] {
+			if len(rtf.Metrics) > 0 {
+				add([]string{"cloudwatch:GetMetricData"})
+			}
+			if rtf.Unused {
+				add(usageActions[resType])
+			}
+			if rtf.Cascade {
+				add(cascadeActions[resType].List)
+			}
+			if _, found := rtf.Attributes[LastUsedAttribute]; found {
+				add(lastUsedActions[resType])
+			}
+			for name, attr := range fetchedAttributes[resType] {
+				if _, found := rtf.Attributes[name]; found {
+					add(attr.actions)
+				}
+			}
+		}
+
+		if listOnly {
+			continue
+		}
+		add(p.Delete)
+		if f.RemoveSharing {
+			add(sharingActions[resType].Delete)
+		}
+
+		for _, rtf := range f.Cfg[resType] {
+			if rtf.action() == ActionDeactivate {
+				add(deactivateActions[resType])
+			}
+			if rtf.action() == ActionSetRetention {
+				add(setRetentionActions)
+			}
+			if rtf.Cascade {
+				add(cascadeActions[resType].Delete)
+			}
+			if rtf.FinalSnapshot {
+				add(finalSnapshotActions)
+			}
+			if rtf.DisableDeletionProtection {
+				add(disableDeletionProtectionActions[resType])
+			}
+			if rtf.DeleteBlockingResources {
+				for _, blockingType := range stackResourceTypes {
+					add(TypePermissions(blockingType).List)
+					add(TypePermissions(blockingType).Delete)
+				}
+			}
+			if rtf.action() == ActionRemoveTags {
+				if ec2Types[resType] {
+					add([]string{"ec2:DeleteTags"})
+				}
+				add(removeTagsActions[resType])
+			}
+			if rtf.action() != ActionApplyTags {
+				continue
+			}
+			if ec2Types[resType] {
+				add([]string{"ec2:CreateTags"})
+			}
+			add(applyTagsActions[resType])
+			for _, v := range rtf.ApplyTags {
+				if strings.Contains(v, CreatorPlaceholder) {
+					add([]string{"cloudtrail:LookupEvents"})
+				}
+			}
+		}
+	}
+
+	// allow to probe the services with the preflight command
+	services := map[string]bool{}
+	for a := range actions {
+		services[strings.SplitN(a, ":", 2)[0]] = true
+	}
+	for s := range services {
+		if p, found := probes[s]; found {
+			actions[p.action] = true
+		}
+	}
+
+	result := make([]string, 0, len(actions))
+	for a := range actions {
+		result = append(result, a)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// Policy returns an IAM policy (as JSON) that allows to sweep the resource types in the filter.
+func (f Filter) Policy(listOnly bool) ([]byte, error) {
+	return json.MarshalIndent(PolicyDocument{
+		Version: "2012-10-17",
+		Statement: []PolicyStatement{
+			{
+				Effect:   "Allow",
+				Action:   f.Actions(listOnly),
+				Resource: "*",
+			},
+		},
+	}, "", "  ")
+}
@@ -0,0 +1,79 @@
+package resource_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypePermissions_AllSupportedTypes(t *testing.T) {
+	for _, resType := range resource.SupportedResourceTypes() {
+		p := resource.TypePermissions(resType)
+		assert.NotEmpty(t, p.List, "no list permissions for %s", resType)
+		assert.NotEmpty(t, p.Delete, "no delete permissions for %s", resType)
+	}
+}
+
+func TestFilter_Actions_ListOnly(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.KeyPair: {},
+		},
+	}
+
+	// when
+	actions := f.Actions(true)
+
+	// then
+	assert.Equal(t, []string{"ec2:DescribeAccountAttributes", "ec2:DescribeKeyPairs", "ec2:DescribeRegions",
+		"sts:GetCallerIdentity"}, actions)
+}
+
+func TestFilter_Actions_ApplyTags(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {
+				{
+					Action:    resource.ActionApplyTags,
+					ApplyTags: map[string]string{"Owner": resource.CreatorPlaceholder},
+				},
+			},
+		},
+	}
+
+	// when
+	actions := f.Actions(false)
+
+	// then
+	assert.Contains(t, actions, "ec2:TerminateInstances")
+	assert.Contains(t, actions, "ec2:CreateTags")
+	assert.Contains(t, actions, "cloudtrail:LookupEvents")
+}
+
+func TestFilter_Policy(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.KeyPair: {},
+		},
+	}
+
+	// when
+	policy, err := f.Policy(false)
+	require.NoError(t, err)
+
+	// then
+	var doc resource.PolicyDocument
+	require.NoError(t, json.Unmarshal(policy, &doc))
+	assert.Equal(t, "2012-10-17", doc.Version)
+	require.Len(t, doc.Statement, 1)
+	assert.Equal(t, "Allow", doc.Statement[0].Effect)
+	assert.Equal(t, "*", doc.Statement[0].Resource)
+	assert.Equal(t, []string{"ec2:DeleteKeyPair", "ec2:DescribeAccountAttributes", "ec2:DescribeKeyPairs",
+		"ec2:DescribeRegions", "sts:GetCallerIdentity"}, doc.Statement[0].Action)
+}
@@ -0,0 +1,279 @@
+package resource
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/athena"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatchevents"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cognitoidentity"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go/service/configservice"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/efs"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/redshift"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/ses"
+	"github.com/aws/aws-sdk-go/service/sfn"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/support"
+)
+
+// probeAttempts is the number of times each probe is called in quick succession,
+// to check whether there is headroom left before hitting the rate limit of an API.
+const probeAttempts = 3
+
+// probe is a cheap, read-only call to the API of a service.
+type probe struct {
+	action string
+	call   func(a *AWS) error
+}
+
+var (
+	probes = map[string]probe{
+		"apigateway": {"apigateway:GET", func(a *AWS) error {
+			_, err := a.GetRestApis(&apigateway.GetRestApisInput{Limit: aws.Int64(1)})
+			return err
+		}},
+		"athena": {"athena:ListNamedQueries", func(a *AWS) error {
+			_, err := a.ListNamedQueries(&athena.ListNamedQueriesInput{MaxResults: aws.Int64(1)})
+			return err
+		}},
+		"autoscaling": {"autoscaling:DescribeAccountLimits", func(a *AWS) error {
+			_, err := a.AutoScalingAPI.DescribeAccountLimits(&autoscaling.DescribeAccountLimitsInput{})
+			return err
+		}},
+		"cloudformation": {"cloudformation:DescribeAccountLimits", func(a *AWS) error {
+			_, err := a.CloudFormationAPI.DescribeAccountLimits(&cloudformation.DescribeAccountLimitsInput{})
+			return err
+		}},
+		"cloudfront": {"cloudfront:ListDistributions", func(a *AWS) error {
+			_, err := a.ListDistributions(&cloudfront.ListDistributionsInput{MaxItems: aws.Int64(1)})
+			return err
+		}},
+		"cloudtrail": {"cloudtrail:DescribeTrails", func(a *AWS) error {
+			_, err := a.DescribeTrails(&cloudtrail.DescribeTrailsInput{})
+			return err
+		}},
+		"cloudwatch": {"cloudwatch:ListMetrics", func(a *AWS) error {
+			_, err := a.ListMetrics(&cloudwatch.ListMetricsInput{})
+			return err
+		}},
+		"cognito-identity": {"cognito-identity:ListIdentityPools", func(a *AWS) error {
+			_, err := a.ListIdentityPools(&cognitoidentity.ListIdentityPoolsInput{MaxResults: aws.Int64(1)})
+			return err
+		}},
+		"cognito-idp": {"cognito-idp:ListUserPools", func(a *AWS) error {
+			_, err := a.ListUserPools(&cognitoidentityprovider.ListUserPoolsInput{MaxResults: aws.Int64(1)})
+			return err
+		}},
+		"config": {"config:DescribeConfigurationRecorderStatus", func(a *AWS) error {
+			_, err := a.DescribeConfigurationRecorderStatus(&configservice.DescribeConfigurationRecorderStatusInput{})
+			return err
+		}},
+		"dynamodb": {"dynamodb:DescribeLimits", func(a *AWS) error {
+			_, err := a.DynamoDBAPI.DescribeLimits(&dynamodb.DescribeLimitsInput{})
+			return err
+		}},
+		"ec2": {"ec2:DescribeAccountAttributes", func(a *AWS) error {
+			_, err := a.EC2API.DescribeAccountAttributes(&ec2.DescribeAccountAttributesInput{})
+			return err
+		}},
+		"ecs": {"ecs:ListClusters", func(a *AWS) error {
+			_, err := a.ListClusters(&ecs.ListClustersInput{MaxResults: aws.Int64(1)})
+			return err
+		}},
+		"elasticbeanstalk": {"elasticbeanstalk:DescribeAccountAttributes", func(a *AWS) error {
+			_, err := a.ElasticBeanstalkAPI.DescribeAccountAttributes(&elasticbeanstalk.DescribeAccountAttributesInput{})
+			return err
+		}},
+		"elasticfilesystem": {"elasticfilesystem:DescribeFileSystems", func(a *AWS) error {
+			_, err := a.DescribeFileSystems(&efs.DescribeFileSystemsInput{MaxItems: aws.Int64(1)})
+			return err
+		}},
+		"elasticloadbalancing": {"elasticloadbalancing:DescribeAccountLimits", func(a *AWS) error {
+			_, err := a.ELBAPI.DescribeAccountLimits(&elb.DescribeAccountLimitsInput{})
+			return err
+		}},
+		"events": {"events:ListRules", func(a *AWS) error {
+			_, err := a.ListRules(&cloudwatchevents.ListRulesInput{Limit: aws.Int64(1)})
+			return err
+		}},
+		"firehose": {"firehose:ListDeliveryStreams", func(a *AWS) error {
+			_, err := a.ListDeliveryStreams(&firehose.ListDeliveryStreamsInput{Limit: aws.Int64(1)})
+			return err
+		}},
+		"glue": {"glue:GetJobs", func(a *AWS) error {
+			_, err := a.GetJobs(&glue.GetJobsInput{MaxResults: aws.Int64(1)})
+			return err
+		}},
+		"iam": {"iam:GetAccountSummary", func(a *AWS) error {
+			_, err := a.GetAccountSummary(&iam.GetAccountSummaryInput{})
+			return err
+		}},
+		"kinesis": {"kinesis:DescribeLimits", func(a *AWS) error {
+			_, err := a.KinesisAPI.DescribeLimits(&kinesis.DescribeLimitsInput{})
+			return err
+		}},
+		"kms": {"kms:ListKeys", func(a *AWS) error {
+			_, err := a.ListKeys(&kms.ListKeysInput{Limit: aws.Int64(1)})
+			return err
+		}},
+		"lambda": {"lambda:GetAccountSettings", func(a *AWS) error {
+			_, err := a.GetAccountSettings(&lambda.GetAccountSettingsInput{})
+			return err
+		}},
+		"logs": {"logs:DescribeLogGroups", func(a *AWS) error {
+			_, err := a.DescribeLogGroups(&cloudwatchlogs.DescribeLogGroupsInput{Limit: aws.Int64(1)})
+			return err
+		}},
+		"rds": {"rds:DescribeAccountAttributes", func(a *AWS) error {
+			_, err := a.RDSAPI.DescribeAccountAttributes(&rds.DescribeAccountAttributesInput{})
+			return err
+		}},
+		"redshift": {"redshift:DescribeClusters", func(a *AWS) error {
+			_, err := a.RedshiftAPI.DescribeClusters(&redshift.DescribeClustersInput{MaxRecords: aws.Int64(20)})
+			return err
+		}},
+		"route53": {"route53:GetHostedZoneCount", func(a *AWS) error {
+			_, err := a.GetHostedZoneCount(&route53.GetHostedZoneCountInput{})
+			return err
+		}},
+		"s3": {"s3:ListAllMyBuckets", func(a *AWS) error {
+			_, err := a.ListBuckets(&s3.ListBucketsInput{})
+			return err
+		}},
+		"ses": {"ses:GetSendQuota", func(a *AWS) error {
+			_, err := a.GetSendQuota(&ses.GetSendQuotaInput{})
+			return err
+		}},
+		"sns": {"sns:ListTopics", func(a *AWS) error {
+			_, err := a.ListTopics(&sns.ListTopicsInput{})
+			return err
+		}},
+		"sqs": {"sqs:ListQueues", func(a *AWS) error {
+			_, err := a.ListQueues(&sqs.ListQueuesInput{})
+			return err
+		}},
+		"ssm": {"ssm:DescribeParameters", func(a *AWS) error {
+			_, err := a.DescribeParameters(&ssm.DescribeParametersInput{MaxResults: aws.Int64(1)})
+			return err
+		}},
+		"states": {"states:ListStateMachines", func(a *AWS) error {
+			_, err := a.ListStateMachines(&sfn.ListStateMachinesInput{MaxResults: aws.Int64(1)})
+			return err
+		}},
+		"sts": {"sts:GetCallerIdentity", func(a *AWS) error {
+			_, err := a.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+			return err
+		}},
+		"support": {"support:DescribeTrustedAdvisorChecks", func(a *AWS) error {
+			_, err := a.DescribeTrustedAdvisorChecks(&support.DescribeTrustedAdvisorChecksInput{
+				Language: aws.String("en"),
+			})
+			return err
+		}},
+		"tag": {"tag:GetResources", func(a *AWS) error {
+			_, err := a.ResourceGroupsTaggingAPIAPI.GetResources(&resourcegroupstaggingapi.GetResourcesInput{
+				ResourcesPerPage: aws.Int64(1),
+			})
+			return err
+		}},
+	}
+
+	// globalServices are the services that are the same in every region, so they are probed only once.
+	globalServices = map[string]bool{
+		"iam":     true,
+		"route53": true,
+		"s3":      true,
+		"support": true,
+	}
+)
+
+// ProbeResult is the outcome of probing the API of a service.
+type ProbeResult struct {
+	Service string
+	// Latency is the average duration of the probe calls
+	Latency time.Duration
+	// Throttled is true if the rate limit of the API has been hit by the probe calls
+	Throttled bool
+	Err       error
+}
+
+// Services returns the (sorted) services whose APIs are called when sweeping the resource types in the filter.
+func (f Filter) Services() []string {
+	services := map[string]bool{}
+	for _, action := range f.Actions(true) {
+		services[strings.SplitN(action, ":", 2)[0]] = true
+	}
+
+	result := make([]string, 0, len(services))
+	for s := range services {
+		result = append(result, s)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// IsGlobalService checks whether the API of a service is the same in every region.
+func IsGlobalService(service string) bool {
+	return globalServices[service]
+}
+
+// Probe checks whether the API of a service can be reached with the used credentials
+// and whether there is headroom before hitting its rate limit.
+func (a *AWS) Probe(service string) ProbeResult {
+	result := ProbeResult{Service: service}
+
+	p, found := probes[service]
+	if !found {
+		return result
+	}
+
+	var total time.Duration
+	calls := 0
+	for i := 0; i < probeAttempts; i++ {
+		start := time.Now()
+		err := p.call(a)
+		total += time.Since(start)
+		calls++
+
+		if isThrottled(err) {
+			result.Throttled = true
+			continue
+		}
+		if err != nil {
+			result.Err = err
+			break
+		}
+	}
+	result.Latency = total / time.Duration(calls)
+
+	return result
+}
@@ -0,0 +1,94 @@
+package resource_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter_Services(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {},
+			resource.IamRole:  {},
+		},
+	}
+
+	// when
+	services := f.Services()
+
+	// then
+	assert.Equal(t, []string{"ec2", "iam", "sts"}, services)
+}
+
+func TestAWS_Probe(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockEC2API(mockCtrl)
+	awsMock := &resource.AWS{
+		EC2API: mockObj,
+	}
+
+	mockObj.EXPECT().DescribeAccountAttributes(gomock.Any()).Return(&ec2.DescribeAccountAttributesOutput{}, nil).Times(3)
+
+	// when
+	res := awsMock.Probe("ec2")
+
+	// then
+	assert.NoError(t, res.Err)
+	assert.False(t, res.Throttled)
+	assert.Equal(t, "ec2", res.Service)
+}
+
+func TestAWS_Probe_Throttled(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockEC2API(mockCtrl)
+	awsMock := &resource.AWS{
+		EC2API: mockObj,
+	}
+
+	gomock.InOrder(
+		mockObj.EXPECT().DescribeAccountAttributes(gomock.Any()).Return(&ec2.DescribeAccountAttributesOutput{}, nil),
+		mockObj.EXPECT().DescribeAccountAttributes(gomock.Any()).Return(nil,
+			awserr.New("RequestLimitExceeded", "Request limit exceeded.", nil)),
+		mockObj.EXPECT().DescribeAccountAttributes(gomock.Any()).Return(&ec2.DescribeAccountAttributesOutput{}, nil),
+	)
+
+	// when
+	res := awsMock.Probe("ec2")
+
+	// then
+	assert.NoError(t, res.Err)
+	assert.True(t, res.Throttled)
+}
+
+func TestAWS_Probe_Error(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockEC2API(mockCtrl)
+	awsMock := &resource.AWS{
+		EC2API: mockObj,
+	}
+
+	mockObj.EXPECT().DescribeAccountAttributes(gomock.Any()).Return(nil, errors.New("access denied"))
+
+	// when
+	res := awsMock.Probe("ec2")
+
+	// then
+	assert.EqualError(t, res.Err, "access denied")
+}
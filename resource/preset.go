@@ -0,0 +1,105 @@
+package resource
+
+import (
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+)
+
+// presets are curated configs shipped with the binary, which can be selected in the yaml config by name.
+// They are built on demand, as some of them select resources relative to the current time.
+var presets = map[string]func() Config{
+	// resources created by CI pipelines or tests (by naming convention), which are older than a day
+	"ci-leftovers": func() Config {
+		prefix := "^(ci|test|tmp)-"
+		created := &Created{Before: aws.Time(time.Now().Add(-24 * time.Hour))}
+
+		cfg := Config{}
+		for _, resType := range typesWith(Capabilities{Created: true}) {
+			cfg[resType] = []ResourceTypeFilter{{ID: aws.String(prefix), Created: created}}
+			if TypeCapabilities(resType).Tags {
+				cfg[resType] = append(cfg[resType], ResourceTypeFilter{
+					Tags:    map[string]string{"Name": prefix},
+					Created: created,
+				})
+			}
+		}
+		return cfg
+	},
+
+	// resources without any tags, which are older than 30 days
+	"untagged-older-than-30d": func() Config {
+		cfg := Config{}
+		for _, resType := range typesWith(Capabilities{Tags: true, Created: true}) {
+			cfg[resType] = []ResourceTypeFilter{
+				{
+					Untagged: true,
+					Created:  &Created{Before: aws.Time(time.Now().Add(-30 * 24 * time.Hour))},
+				},
+			}
+		}
+		return cfg
+	},
+
+	// final snapshots of volumes deleted by AWSweeper, which are older than 30 days
+	"final-snapshots-older-than-30d": func() Config {
+		return Config{EbsSnapshot: {FinalSnapshotFilter(30 * 24 * time.Hour)}}
+	},
+
+	// all (non-default) network resources and what runs inside of them
+	"vpc-teardown": func() Config {
+		cfg := Config{}
+		for _, resType := range []TerraformResourceType{
+			Eip,
+			Elb,
+			Instance,
+			InternetGateway,
+			Lb,
+			LbTargetGroup,
+			NatGateway,
+			NetworkAcl,
+			NetworkInterface,
+			RouteTable,
+			SecurityGroup,
+			Subnet,
+			Vpc,
+			VpcEndpoint,
+		} {
+			cfg[resType] = []ResourceTypeFilter{}
+		}
+		return cfg
+	},
+}
+
+// typesWith returns all supported resource types that have (at least) the given capabilities.
+func typesWith(required Capabilities) []TerraformResourceType {
+	var result []TerraformResourceType
+	for _, resType := range SupportedResourceTypes() {
+		caps := TypeCapabilities(resType)
+		if (caps.Tags || !required.Tags) && (caps.Created || !required.Created) {
+			result = append(result, resType)
+		}
+	}
+	return result
+}
+
+// preset returns the config of a preset given by its name.
+func preset(name string) (Config, error) {
+	p, found := presets[name]
+	if !found {
+		return nil, errors.Errorf("unknown preset: %s (available presets: %s)", name, PresetNames())
+	}
+	return p(), nil
+}
+
+// PresetNames returns the names of all presets in alphabetical order.
+func PresetNames() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
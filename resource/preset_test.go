@@ -0,0 +1,52 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFilter_Preset(t *testing.T) {
+	// given
+	resource.AppFs = afero.NewMemMapFs()
+	afero.WriteFile(resource.AppFs, "config.yml", []byte(`
+preset: vpc-teardown
+aws_vpc:
+  - id: ^foo
+aws_iam_role:
+`), 0644)
+
+	// when
+	f := resource.NewFilter("config.yml")
+
+	// then
+	require.NoError(t, f.Validate())
+	assert.True(t, f.ProtectDefaults)
+	assert.Contains(t, f.Types(), resource.Subnet)
+	assert.Contains(t, f.Types(), resource.IamRole)
+	require.Len(t, f.Cfg[resource.Vpc], 1)
+	assert.Equal(t, "^foo", *f.Cfg[resource.Vpc][0].ID)
+}
+
+func TestNewFilter_NoPreset(t *testing.T) {
+	// given
+	resource.AppFs = afero.NewMemMapFs()
+	afero.WriteFile(resource.AppFs, "config.yml", []byte(`
+aws_vpc:
+`), 0644)
+
+	// when
+	f := resource.NewFilter("config.yml")
+
+	// then
+	assert.False(t, f.ProtectDefaults)
+	assert.Equal(t, []resource.TerraformResourceType{resource.Vpc}, f.Types())
+}
+
+func TestPresetNames(t *testing.T) {
+	assert.Equal(t, []string{"ci-leftovers", "final-snapshots-older-than-30d", "untagged-older-than-30d", "vpc-teardown"},
+		resource.PresetNames())
+}
@@ -0,0 +1,63 @@
+package resource
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+	"github.com/pkg/errors"
+)
+
+// disableDeletionProtectionActions are required to disable the deletion protection of resources of a type.
+var disableDeletionProtectionActions = map[TerraformResourceType][]string{
+	CognitoUserPool: {"cognito-idp:UpdateUserPool"},
+}
+
+// validateDisableDeletionProtection checks whether the deletion protection of the resources of a type can be disabled.
+func validateDisableDeletionProtection(resType TerraformResourceType, rtf ResourceTypeFilter) error {
+	if !rtf.DisableDeletionProtection {
+		return nil
+	}
+	if _, found := disableDeletionProtectionActions[resType]; !found {
+		return fmt.Errorf("disable_deletion_protection is not supported for resource type: %s", resType)
+	}
+	if rtf.action() != ActionDelete {
+		return fmt.Errorf("disable_deletion_protection requires action %s for resource type: %s", ActionDelete, resType)
+	}
+	return nil
+}
+
+// DisablesDeletionProtection checks whether a resource has been selected by a filter entry with
+// disable_deletion_protection, i.e. its deletion protection is disabled if it fails to be deleted because of it.
+func (f Filter) DisablesDeletionProtection(r *Resource) bool {
+	rtf, found := f.matchingEntry(r)
+	return found && rtf.DisableDeletionProtection
+}
+
+// updateUserPoolInput is the input of UpdateUserPool including the deletion protection of the user pool,
+// which the SDK doesn't know about yet.
+type updateUserPoolInput struct {
+	_ struct{} `type:"structure"`
+
+	UserPoolId         *string `min:"1" type:"string" required:"true"`
+	DeletionProtection *string `type:"string"`
+}
+
+// DisableDeletionProtection disables the deletion protection of a resource, so that it can be deleted.
+// Note that updating a Cognito user pool resets all of its settings that aren't given, which doesn't matter
+// as the user pool is deleted right afterwards.
+func (a *AWS) DisableDeletionProtection(r *Resource) error {
+	switch r.Type {
+	case CognitoUserPool:
+		req, _ := a.UpdateUserPoolRequest(&cognitoidentityprovider.UpdateUserPoolInput{UserPoolId: aws.String(r.ID)})
+		req.Params = &updateUserPoolInput{
+			UserPoolId:         aws.String(r.ID),
+			DeletionProtection: aws.String("INACTIVE"),
+		}
+		if err := req.Send(); err != nil {
+			return errors.Wrapf(err, "failed to disable deletion protection of user pool %s", r.ID)
+		}
+		return nil
+	}
+	return fmt.Errorf("disable_deletion_protection is not supported for resource type: %s", r.Type)
+}
@@ -0,0 +1,62 @@
+package resource_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWS_DisableDeletionProtection(t *testing.T) {
+	// given
+	var target string
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target = r.Header.Get("X-Amz-Target")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	a := resource.NewAWS(newTestSession(server.URL))
+
+	// when
+	err := a.DisableDeletionProtection(&resource.Resource{Type: resource.CognitoUserPool, ID: "us-east-1_abc123"})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "AWSCognitoIdentityProviderService.UpdateUserPool", target)
+	assert.Equal(t, map[string]interface{}{"UserPoolId": "us-east-1_abc123", "DeletionProtection": "INACTIVE"}, body)
+}
+
+func TestFilter_DisablesDeletionProtection(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.CognitoUserPool: {
+				{ID: aws.String("^us-east-1_protected"), DisableDeletionProtection: true},
+				{},
+			},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// then
+	assert.True(t, f.DisablesDeletionProtection(&resource.Resource{Type: resource.CognitoUserPool, ID: "us-east-1_protected"}))
+	assert.False(t, f.DisablesDeletionProtection(&resource.Resource{Type: resource.CognitoUserPool, ID: "us-east-1_other"}))
+	assert.Contains(t, f.Actions(false), "cognito-idp:UpdateUserPool")
+}
+
+func TestFilter_Validate_DisableDeletionProtection_UnsupportedType(t *testing.T) {
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.DynamodbTable: {{DisableDeletionProtection: true}},
+		},
+	}
+	assert.Error(t, f.Validate())
+}
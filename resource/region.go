@@ -0,0 +1,108 @@
+package resource
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// EnabledRegions returns the names of all regions that are enabled for the account,
+// i.e. regions that require opting in are only returned if the account has opted in.
+func (a *AWS) EnabledRegions() ([]string, error) {
+	output, err := a.DescribeRegions(&ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	var regions []string
+	for _, r := range output.Regions {
+		regions = append(regions, *r.RegionName)
+	}
+	sort.Strings(regions)
+
+	return regions, nil
+}
+
+// SelectRegions splits the requested regions into the ones that are enabled and the ones that are skipped.
+func SelectRegions(requested []string, enabled []string) (selected []string, skipped []string) {
+	isEnabled := map[string]bool{}
+	for _, r := range enabled {
+		isEnabled[r] = true
+	}
+
+	for _, r := range requested {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+		if isEnabled[r] {
+			selected = append(selected, r)
+		} else {
+			skipped = append(skipped, r)
+		}
+	}
+	return selected, skipped
+}
+
+// regionPattern matches the names of regions (e.g., eu-west-1 or us-gov-west-1).
+var regionPattern = regexp.MustCompile(`^[a-z]{2}(-gov)?-[a-z]+-\d$`)
+
+// validateRegions checks whether resources of a type can be selected by the region they are listed in.
+// Resources of global types are listed only once, independently of the swept regions, so restricting them to
+// regions would select them in some runs and not in others. Regional types can't be restricted to pseudo-regions
+// for global services (e.g., aws-global).
+func validateRegions(resType TerraformResourceType, regions []string) error {
+	if len(regions) == 0 {
+		return nil
+	}
+	if IsGlobal(resType) {
+		return fmt.Errorf("filtering by regions is not supported for global resource type %s, "+
+			"which resources are listed once, independently of the swept regions (remove regions)", resType)
+	}
+	for _, name := range regions {
+		if !regionPattern.MatchString(name) {
+			return fmt.Errorf("invalid region for resource type %s: %s (resources of %s belong to a region "+
+				"like us-east-1, only the ones of global types like IAM and Route53 don't)", resType, name, resType)
+		}
+	}
+	return nil
+}
+
+// matchRegions checks whether a resource has been listed in one of the regions of a filter entry, if required.
+func (rtf ResourceTypeFilter) matchRegions(r *Resource) bool {
+	if len(rtf.Regions) == 0 {
+		return true
+	}
+	for _, name := range rtf.Regions {
+		if name == r.listedIn {
+			return true
+		}
+	}
+	return false
+}
+
+// UnsweptRegions returns the regions per resource type that filter entries are restricted to, but which are not
+// among the swept regions, i.e. the regions the entries can't select any resources in.
+func (f Filter) UnsweptRegions(swept []string) map[TerraformResourceType][]string {
+	isSwept := map[string]bool{}
+	for _, name := range swept {
+		isSwept[name] = true
+	}
+
+	result := map[TerraformResourceType][]string{}
+	for resType, rtfs := range f.Cfg {
+		seen := map[string]bool{}
+		for _, rtf := range rtfs {
+			for _, name := range rtf.Regions {
+				if !isSwept[name] && !seen[name] {
+					seen[name] = true
+					result[resType] = append(result[resType], name)
+				}
+			}
+		}
+	}
+	return result
+}
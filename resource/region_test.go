@@ -0,0 +1,122 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWS_EnabledRegions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockEC2API(mockCtrl)
+	awsMock := &resource.AWS{
+		EC2API: mockObj,
+	}
+
+	mockObj.EXPECT().DescribeRegions(&ec2.DescribeRegionsInput{}).Return(&ec2.DescribeRegionsOutput{
+		Regions: []*ec2.Region{
+			{RegionName: aws.String("us-west-2")},
+			{RegionName: aws.String("eu-west-1")},
+		},
+	}, nil)
+
+	// when
+	regions, err := awsMock.EnabledRegions()
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"eu-west-1", "us-west-2"}, regions)
+}
+
+func TestSelectRegions(t *testing.T) {
+	// when
+	selected, skipped := resource.SelectRegions(
+		[]string{"us-west-2", " eu-west-1", "ap-east-1", ""},
+		[]string{"eu-west-1", "us-west-2"})
+
+	// then
+	assert.Equal(t, []string{"us-west-2", "eu-west-1"}, selected)
+	assert.Equal(t, []string{"ap-east-1"}, skipped)
+}
+
+func TestFilter_Apply_Regions(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.KeyPair: {{Regions: []string{"eu-west-1"}}},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	res := func() resource.Resources {
+		return resource.Resources{{Type: resource.KeyPair, ID: "foo"}}
+	}
+
+	// when
+	selected := f.Apply(resource.KeyPair, res(), nil, &resource.AWS{Region: "eu-west-1"})
+	notSelected := f.Apply(resource.KeyPair, res(), nil, &resource.AWS{Region: "us-east-1"})
+
+	// then
+	assert.Len(t, selected[0], 1)
+	assert.Len(t, notSelected[0], 0)
+}
+
+func TestYamlFilter_Validate_RegionsOfGlobalType(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.IamRole: {{Regions: []string{"eu-west-1"}}},
+		},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.EqualError(t, err, "filtering by regions is not supported for global resource type aws_iam_role, "+
+		"which resources are listed once, independently of the swept regions (remove regions)")
+}
+
+func TestYamlFilter_Validate_GlobalRegionOfRegionalType(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {{Regions: []string{"aws-global"}}},
+		},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid region for resource type aws_instance: aws-global")
+}
+
+func TestFilter_UnsweptRegions(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {{Regions: []string{"eu-west-1", "eu-central-1"}}, {Regions: []string{"eu-central-1"}}},
+			resource.KeyPair:  {{Regions: []string{"eu-west-1"}}},
+			resource.Vpc:      {},
+		},
+	}
+
+	// when
+	unswept := f.UnsweptRegions([]string{"eu-west-1"})
+
+	// then
+	assert.Equal(t, map[resource.TerraformResourceType][]string{
+		resource.Instance: {"eu-central-1"},
+	}, unswept)
+}
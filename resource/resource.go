@@ -2,8 +2,14 @@ package resource
 
 import (
 	"reflect"
+	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/cloudetc/awsweeper/internal/arn"
 	"github.com/sirupsen/logrus"
 
 	"github.com/pkg/errors"
@@ -21,12 +27,23 @@ func DeletableResources(resType TerraformResourceType, resources interface{}) (R
 			return nil, err
 		}
 
+		// some APIs list resources only by their identifier (e.g., SES identities)
+		if reflect.Indirect(reflectResources.Index(i)).Kind() == reflect.String {
+			r := &Resource{
+				Type: resType,
+				ID:   reflect.Indirect(reflectResources.Index(i)).String(),
+			}
+			r.Model = newModel(r, nil)
+			deletableResources = append(deletableResources, r)
+			continue
+		}
+
 		deleteIDField, err := getField(deleteID, reflect.Indirect(reflectResources.Index(i)))
 		if err != nil {
 			return nil, errors.Wrapf(err, "Field with delete ID required for deleting resource")
 		}
 
-		tags, err := findTags(reflectResources.Index(i))
+		tags, err := findTags(reflectResources.Index(i), tagFieldNamesOf(resType))
 		if err != nil {
 			logrus.WithError(err).Debug()
 		}
@@ -34,20 +51,124 @@ func DeletableResources(resType TerraformResourceType, resources interface{}) (R
 		var creationTime *time.Time
 		creationTimeField, err := findField(creationTimeFieldNames, reflect.Indirect(reflectResources.Index(i)))
 		if err == nil {
-			creationTime = creationTimeField.Interface().(*time.Time)
+			creationTime = toTime(creationTimeField.Interface())
 		}
 
-		deletableResources = append(deletableResources, &Resource{
+		r := &Resource{
 			Type:    resType,
 			ID:      deleteIDField.Elem().String(),
 			Tags:    tags,
 			Created: creationTime,
-		})
+			Default: isDefault(reflectResources.Index(i).Interface()),
+		}
+		if arnField, err := findField(arnFieldNames, reflect.Indirect(reflectResources.Index(i))); err == nil {
+			if s, ok := arnField.Interface().(*string); ok {
+				r.arn = aws.StringValue(s)
+			}
+		}
+		r.Model = newModel(r, reflectResources.Index(i).Interface())
+		deletableResources = append(deletableResources, r)
 	}
 
 	return deletableResources, nil
 }
 
+// toTime converts the value of a creation time field, which is either a time,
+// a timestamp string (e.g., the creation date of AMIs) or milliseconds since the epoch
+// (e.g., the creation time of log groups).
+func toTime(v interface{}) *time.Time {
+	switch t := v.(type) {
+	case *time.Time:
+		return t
+	case *int64:
+		if t == nil {
+			return nil
+		}
+		created := time.Unix(0, *t*int64(time.Millisecond))
+		return &created
+	case *string:
+		if t == nil {
+			return nil
+		}
+		parsed, err := time.Parse(time.RFC3339, *t)
+		if err != nil {
+			logrus.WithError(err).Debug()
+			return nil
+		}
+		return &parsed
+	}
+	return nil
+}
+
+// isDefault checks whether a resource has been created by AWS for every account or region
+// (e.g., the default VPC and its security group) or is managed by AWS.
+func isDefault(res interface{}) bool {
+	switch r := res.(type) {
+	case *ec2.Vpc:
+		return aws.BoolValue(r.IsDefault)
+	case *ec2.Subnet:
+		return aws.BoolValue(r.DefaultForAz)
+	case *ec2.NetworkAcl:
+		return aws.BoolValue(r.IsDefault)
+	case *ec2.SecurityGroup:
+		return aws.StringValue(r.GroupName) == "default"
+	case *ec2.RouteTable:
+		for _, a := range r.Associations {
+			if aws.BoolValue(a.Main) {
+				return true
+			}
+		}
+	case *iam.Policy:
+		parsed, err := arn.Parse(aws.StringValue(r.Arn))
+		return err == nil && parsed.AccountID == "aws"
+	case *iam.Role:
+		return strings.HasPrefix(aws.StringValue(r.Path), "/aws-service-role/")
+	case *kms.AliasListEntry:
+		return strings.HasPrefix(aws.StringValue(r.AliasName), "alias/aws/")
+	case *kmsKey:
+		return aws.StringValue(r.KeyManager) == kms.KeyManagerTypeAws
+	}
+	return false
+}
+
+// MarkDefaults marks the resources that AWS has attached to the default VPC of the region as default
+// (e.g., its internet gateway). Unlike the default VPC itself, these can't be told apart by their own
+// attributes, but only by the VPC they are attached to.
+func (a *AWS) MarkDefaults(resType TerraformResourceType, res Resources, raw interface{}) error {
+	igws, ok := raw.([]*ec2.InternetGateway)
+	if a == nil || resType != InternetGateway || !ok || len(igws) == 0 {
+		return nil
+	}
+
+	output, err := a.DescribeVpcs(&ec2.DescribeVpcsInput{
+		Filters: []*ec2.Filter{{Name: aws.String("isDefault"), Values: aws.StringSlice([]string{"true"})}},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to find the default VPC")
+	}
+	defaultVpcs := map[string]bool{}
+	for _, vpc := range output.Vpcs {
+		if aws.BoolValue(vpc.IsDefault) {
+			defaultVpcs[aws.StringValue(vpc.VpcId)] = true
+		}
+	}
+
+	attached := map[string]bool{}
+	for _, igw := range igws {
+		for _, attachment := range igw.Attachments {
+			if defaultVpcs[aws.StringValue(attachment.VpcId)] {
+				attached[aws.StringValue(igw.InternetGatewayId)] = true
+			}
+		}
+	}
+	for _, r := range res {
+		if attached[r.ID] {
+			r.Default = true
+		}
+	}
+	return nil
+}
+
 func getField(name string, v reflect.Value) (reflect.Value, error) {
 	field := v.FieldByName(name)
 
@@ -68,10 +189,10 @@ func findField(names []string, v reflect.Value) (reflect.Value, error) {
 }
 
 // findTags finds findTags via reflection in the describe output.
-func findTags(res reflect.Value) (map[string]string, error) {
+func findTags(res reflect.Value, names []string) (map[string]string, error) {
 	tags := map[string]string{}
 
-	ts, err := findField(tagFieldNames, reflect.Indirect(res))
+	ts, err := findField(names, reflect.Indirect(res))
 	if err != nil {
 		return nil, errors.Wrap(err, "No tags found")
 	}
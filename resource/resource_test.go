@@ -9,6 +9,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/resourcetest"
 	"github.com/stretchr/testify/require"
 )
 
@@ -51,3 +52,96 @@ func TestAWS_DeletableResources_Created(t *testing.T) {
 	require.Equal(t, testLaunchTime, res[0].Created)
 
 }
+
+func TestAWS_DeletableResources_StringIDs(t *testing.T) {
+	// given
+	rawResources := []*string{
+		aws.String("example.com"),
+		aws.String("foo@example.com"),
+	}
+
+	// when
+	res, err := resource.DeletableResources(resource.SesEmailIdentity, rawResources)
+	require.NoError(t, err)
+
+	// then
+	require.Len(t, res, 2)
+	require.Equal(t, "example.com", res[0].ID)
+	require.Equal(t, "foo@example.com", res[1].ID)
+	require.Equal(t, resource.SesDomainIdentity, resource.DeleteType(res[1].Type))
+}
+
+func TestAWS_DeletableResources_Default(t *testing.T) {
+	// given
+	rawResources := []*ec2.Vpc{
+		{
+			VpcId:     aws.String("default-vpc"),
+			IsDefault: aws.Bool(true),
+		},
+		{
+			VpcId:     aws.String("custom-vpc"),
+			IsDefault: aws.Bool(false),
+		},
+	}
+
+	// when
+	res, err := resource.DeletableResources(resource.Vpc, rawResources)
+	require.NoError(t, err)
+
+	// then
+	require.Len(t, res, 2)
+	require.True(t, res[0].Default)
+	require.False(t, res[1].Default)
+}
+
+func TestAWS_DeletableResources_CreatedString(t *testing.T) {
+	// given
+	rawResources := []*ec2.Image{
+		{
+			ImageId:      &testAmiName,
+			CreationDate: aws.String("2018-11-17T05:00:00.000Z"),
+		},
+	}
+
+	// when
+	res, err := resource.DeletableResources(resource.Ami, rawResources)
+	require.NoError(t, err)
+
+	// then
+	require.Len(t, res, 1)
+	require.Equal(t, time.Date(2018, 11, 17, 5, 0, 0, 0, time.UTC), *res[0].Created)
+}
+
+func TestAWS_MarkDefaults_InternetGateway(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"ec2": {
+			"DescribeVpcs": map[string]interface{}{
+				"Vpcs": []interface{}{
+					map[string]interface{}{"VpcId": "default-vpc", "IsDefault": true},
+				},
+			},
+		},
+	})
+	rawResources := []*ec2.InternetGateway{
+		{
+			InternetGatewayId: aws.String("default-igw"),
+			Attachments:       []*ec2.InternetGatewayAttachment{{VpcId: aws.String("default-vpc")}},
+		},
+		{
+			InternetGatewayId: aws.String("custom-igw"),
+			Attachments:       []*ec2.InternetGatewayAttachment{{VpcId: aws.String("custom-vpc")}},
+		},
+	}
+	res, err := resource.DeletableResources(resource.InternetGateway, rawResources)
+	require.NoError(t, err)
+
+	// when
+	err = a.MarkDefaults(resource.InternetGateway, res, rawResources)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	require.True(t, res[0].Default)
+	require.False(t, res[1].Default)
+}
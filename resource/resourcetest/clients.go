@@ -0,0 +1,1788 @@
+package resourcetest
+
+import (
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/apigateway/apigatewayiface"
+	"github.com/aws/aws-sdk-go/service/athena"
+	"github.com/aws/aws-sdk-go/service/athena/athenaiface"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/cloudfront/cloudfrontiface"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"github.com/aws/aws-sdk-go/service/cloudtrail/cloudtrailiface"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+	"github.com/aws/aws-sdk-go/service/cloudwatchevents"
+	"github.com/aws/aws-sdk-go/service/cloudwatchevents/cloudwatcheventsiface"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"github.com/aws/aws-sdk-go/service/cognitoidentity"
+	"github.com/aws/aws-sdk-go/service/cognitoidentity/cognitoidentityiface"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider/cognitoidentityprovideriface"
+	"github.com/aws/aws-sdk-go/service/configservice"
+	"github.com/aws/aws-sdk-go/service/configservice/configserviceiface"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
+	"github.com/aws/aws-sdk-go/service/efs"
+	"github.com/aws/aws-sdk-go/service/efs/efsiface"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk/elasticbeanstalkiface"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/firehose/firehoseiface"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/glue/glueiface"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kinesis/kinesisiface"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+	"github.com/aws/aws-sdk-go/service/redshift"
+	"github.com/aws/aws-sdk-go/service/redshift/redshiftiface"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi/resourcegroupstaggingapiiface"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/ses"
+	"github.com/aws/aws-sdk-go/service/ses/sesiface"
+	"github.com/aws/aws-sdk-go/service/sfn"
+	"github.com/aws/aws-sdk-go/service/sfn/sfniface"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/aws/aws-sdk-go/service/support"
+	"github.com/aws/aws-sdk-go/service/support/supportiface"
+)
+
+// APIGateway is a fake of the APIGateway API.
+type APIGateway struct {
+	apigatewayiface.APIGatewayAPI
+	b *Backend
+}
+
+// GetRestApis returns the fixture of the operation.
+func (c *APIGateway) GetRestApis(input *apigateway.GetRestApisInput) (*apigateway.GetRestApisOutput, error) {
+	output := &apigateway.GetRestApisOutput{}
+	return output, c.b.output(apigateway.ServiceName, "GetRestApis", output)
+}
+
+// GetRestApisPages calls fn with the fixture of the operation as the only page.
+func (c *APIGateway) GetRestApisPages(input *apigateway.GetRestApisInput, fn func(*apigateway.GetRestApisOutput, bool) bool) error {
+	output, err := c.GetRestApis(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// Athena is a fake of the Athena API.
+type Athena struct {
+	athenaiface.AthenaAPI
+	b *Backend
+}
+
+// BatchGetNamedQuery returns the fixture of the operation.
+func (c *Athena) BatchGetNamedQuery(input *athena.BatchGetNamedQueryInput) (*athena.BatchGetNamedQueryOutput, error) {
+	output := &athena.BatchGetNamedQueryOutput{}
+	return output, c.b.output(athena.ServiceName, "BatchGetNamedQuery", output)
+}
+
+// ListNamedQueries returns the fixture of the operation.
+func (c *Athena) ListNamedQueries(input *athena.ListNamedQueriesInput) (*athena.ListNamedQueriesOutput, error) {
+	output := &athena.ListNamedQueriesOutput{}
+	return output, c.b.output(athena.ServiceName, "ListNamedQueries", output)
+}
+
+// ListNamedQueriesPages calls fn with the fixture of the operation as the only page.
+func (c *Athena) ListNamedQueriesPages(input *athena.ListNamedQueriesInput, fn func(*athena.ListNamedQueriesOutput, bool) bool) error {
+	output, err := c.ListNamedQueries(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// AutoScaling is a fake of the AutoScaling API.
+type AutoScaling struct {
+	autoscalingiface.AutoScalingAPI
+	b *Backend
+}
+
+// DeleteTags returns the fixture of the operation.
+func (c *AutoScaling) DeleteTags(input *autoscaling.DeleteTagsInput) (*autoscaling.DeleteTagsOutput, error) {
+	output := &autoscaling.DeleteTagsOutput{}
+	return output, c.b.output(autoscaling.ServiceName, "DeleteTags", output)
+}
+
+// DescribeAutoScalingGroups returns the fixture of the operation.
+func (c *AutoScaling) DescribeAutoScalingGroups(input *autoscaling.DescribeAutoScalingGroupsInput) (*autoscaling.DescribeAutoScalingGroupsOutput, error) {
+	output := &autoscaling.DescribeAutoScalingGroupsOutput{}
+	return output, c.b.output(autoscaling.ServiceName, "DescribeAutoScalingGroups", output)
+}
+
+// DescribeAutoScalingGroupsPages calls fn with the fixture of the operation as the only page.
+func (c *AutoScaling) DescribeAutoScalingGroupsPages(input *autoscaling.DescribeAutoScalingGroupsInput, fn func(*autoscaling.DescribeAutoScalingGroupsOutput, bool) bool) error {
+	output, err := c.DescribeAutoScalingGroups(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// DescribeLaunchConfigurations returns the fixture of the operation.
+func (c *AutoScaling) DescribeLaunchConfigurations(input *autoscaling.DescribeLaunchConfigurationsInput) (*autoscaling.DescribeLaunchConfigurationsOutput, error) {
+	output := &autoscaling.DescribeLaunchConfigurationsOutput{}
+	return output, c.b.output(autoscaling.ServiceName, "DescribeLaunchConfigurations", output)
+}
+
+// DescribeLaunchConfigurationsPages calls fn with the fixture of the operation as the only page.
+func (c *AutoScaling) DescribeLaunchConfigurationsPages(input *autoscaling.DescribeLaunchConfigurationsInput, fn func(*autoscaling.DescribeLaunchConfigurationsOutput, bool) bool) error {
+	output, err := c.DescribeLaunchConfigurations(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// CloudFormation is a fake of the CloudFormation API.
+type CloudFormation struct {
+	cloudformationiface.CloudFormationAPI
+	b *Backend
+}
+
+// DescribeStackEvents returns the fixture of the operation.
+func (c *CloudFormation) DescribeStackEvents(input *cloudformation.DescribeStackEventsInput) (*cloudformation.DescribeStackEventsOutput, error) {
+	output := &cloudformation.DescribeStackEventsOutput{}
+	return output, c.b.output(cloudformation.ServiceName, "DescribeStackEvents", output)
+}
+
+// DescribeStackEventsPages calls fn with the fixture of the operation as the only page.
+func (c *CloudFormation) DescribeStackEventsPages(input *cloudformation.DescribeStackEventsInput, fn func(*cloudformation.DescribeStackEventsOutput, bool) bool) error {
+	output, err := c.DescribeStackEvents(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// DescribeStacks returns the fixture of the operation.
+func (c *CloudFormation) DescribeStacks(input *cloudformation.DescribeStacksInput) (*cloudformation.DescribeStacksOutput, error) {
+	output := &cloudformation.DescribeStacksOutput{}
+	return output, c.b.output(cloudformation.ServiceName, "DescribeStacks", output)
+}
+
+// DescribeStacksPages calls fn with the fixture of the operation as the only page.
+func (c *CloudFormation) DescribeStacksPages(input *cloudformation.DescribeStacksInput, fn func(*cloudformation.DescribeStacksOutput, bool) bool) error {
+	output, err := c.DescribeStacks(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// CloudFront is a fake of the CloudFront API.
+type CloudFront struct {
+	cloudfrontiface.CloudFrontAPI
+	b *Backend
+}
+
+// ListDistributions returns the fixture of the operation.
+func (c *CloudFront) ListDistributions(input *cloudfront.ListDistributionsInput) (*cloudfront.ListDistributionsOutput, error) {
+	output := &cloudfront.ListDistributionsOutput{}
+	return output, c.b.output(cloudfront.ServiceName, "ListDistributions", output)
+}
+
+// ListDistributionsPages calls fn with the fixture of the operation as the only page.
+func (c *CloudFront) ListDistributionsPages(input *cloudfront.ListDistributionsInput, fn func(*cloudfront.ListDistributionsOutput, bool) bool) error {
+	output, err := c.ListDistributions(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListTagsForResource returns the fixture of the operation.
+func (c *CloudFront) ListTagsForResource(input *cloudfront.ListTagsForResourceInput) (*cloudfront.ListTagsForResourceOutput, error) {
+	output := &cloudfront.ListTagsForResourceOutput{}
+	return output, c.b.output(cloudfront.ServiceName, "ListTagsForResource", output)
+}
+
+// CloudTrail is a fake of the CloudTrail API.
+type CloudTrail struct {
+	cloudtrailiface.CloudTrailAPI
+	b *Backend
+}
+
+// DescribeTrails returns the fixture of the operation.
+func (c *CloudTrail) DescribeTrails(input *cloudtrail.DescribeTrailsInput) (*cloudtrail.DescribeTrailsOutput, error) {
+	output := &cloudtrail.DescribeTrailsOutput{}
+	return output, c.b.output(cloudtrail.ServiceName, "DescribeTrails", output)
+}
+
+// LookupEvents returns the fixture of the operation.
+func (c *CloudTrail) LookupEvents(input *cloudtrail.LookupEventsInput) (*cloudtrail.LookupEventsOutput, error) {
+	output := &cloudtrail.LookupEventsOutput{}
+	return output, c.b.output(cloudtrail.ServiceName, "LookupEvents", output)
+}
+
+// CloudWatch is a fake of the CloudWatch API.
+type CloudWatch struct {
+	cloudwatchiface.CloudWatchAPI
+	b *Backend
+}
+
+// DeleteAlarms returns the fixture of the operation.
+func (c *CloudWatch) DeleteAlarms(input *cloudwatch.DeleteAlarmsInput) (*cloudwatch.DeleteAlarmsOutput, error) {
+	output := &cloudwatch.DeleteAlarmsOutput{}
+	return output, c.b.output(cloudwatch.ServiceName, "DeleteAlarms", output)
+}
+
+// DeleteDashboards returns the fixture of the operation.
+func (c *CloudWatch) DeleteDashboards(input *cloudwatch.DeleteDashboardsInput) (*cloudwatch.DeleteDashboardsOutput, error) {
+	output := &cloudwatch.DeleteDashboardsOutput{}
+	return output, c.b.output(cloudwatch.ServiceName, "DeleteDashboards", output)
+}
+
+// DescribeAlarms returns the fixture of the operation.
+func (c *CloudWatch) DescribeAlarms(input *cloudwatch.DescribeAlarmsInput) (*cloudwatch.DescribeAlarmsOutput, error) {
+	output := &cloudwatch.DescribeAlarmsOutput{}
+	return output, c.b.output(cloudwatch.ServiceName, "DescribeAlarms", output)
+}
+
+// DescribeAlarmsPages calls fn with the fixture of the operation as the only page.
+func (c *CloudWatch) DescribeAlarmsPages(input *cloudwatch.DescribeAlarmsInput, fn func(*cloudwatch.DescribeAlarmsOutput, bool) bool) error {
+	output, err := c.DescribeAlarms(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// GetMetricData returns the fixture of the operation.
+func (c *CloudWatch) GetMetricData(input *cloudwatch.GetMetricDataInput) (*cloudwatch.GetMetricDataOutput, error) {
+	output := &cloudwatch.GetMetricDataOutput{}
+	return output, c.b.output(cloudwatch.ServiceName, "GetMetricData", output)
+}
+
+// GetMetricDataPages calls fn with the fixture of the operation as the only page.
+func (c *CloudWatch) GetMetricDataPages(input *cloudwatch.GetMetricDataInput, fn func(*cloudwatch.GetMetricDataOutput, bool) bool) error {
+	output, err := c.GetMetricData(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListDashboards returns the fixture of the operation.
+func (c *CloudWatch) ListDashboards(input *cloudwatch.ListDashboardsInput) (*cloudwatch.ListDashboardsOutput, error) {
+	output := &cloudwatch.ListDashboardsOutput{}
+	return output, c.b.output(cloudwatch.ServiceName, "ListDashboards", output)
+}
+
+// ListMetrics returns the fixture of the operation.
+func (c *CloudWatch) ListMetrics(input *cloudwatch.ListMetricsInput) (*cloudwatch.ListMetricsOutput, error) {
+	output := &cloudwatch.ListMetricsOutput{}
+	return output, c.b.output(cloudwatch.ServiceName, "ListMetrics", output)
+}
+
+// ListMetricsPages calls fn with the fixture of the operation as the only page.
+func (c *CloudWatch) ListMetricsPages(input *cloudwatch.ListMetricsInput, fn func(*cloudwatch.ListMetricsOutput, bool) bool) error {
+	output, err := c.ListMetrics(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// CloudWatchEvents is a fake of the CloudWatchEvents API.
+type CloudWatchEvents struct {
+	cloudwatcheventsiface.CloudWatchEventsAPI
+	b *Backend
+}
+
+// ListRules returns the fixture of the operation.
+func (c *CloudWatchEvents) ListRules(input *cloudwatchevents.ListRulesInput) (*cloudwatchevents.ListRulesOutput, error) {
+	output := &cloudwatchevents.ListRulesOutput{}
+	return output, c.b.output(cloudwatchevents.ServiceName, "ListRules", output)
+}
+
+// CloudWatchLogs is a fake of the CloudWatchLogs API.
+type CloudWatchLogs struct {
+	cloudwatchlogsiface.CloudWatchLogsAPI
+	b *Backend
+}
+
+// DescribeLogGroups returns the fixture of the operation.
+func (c *CloudWatchLogs) DescribeLogGroups(input *cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+	output := &cloudwatchlogs.DescribeLogGroupsOutput{}
+	return output, c.b.output(cloudwatchlogs.ServiceName, "DescribeLogGroups", output)
+}
+
+// DescribeLogGroupsPages calls fn with the fixture of the operation as the only page.
+func (c *CloudWatchLogs) DescribeLogGroupsPages(input *cloudwatchlogs.DescribeLogGroupsInput, fn func(*cloudwatchlogs.DescribeLogGroupsOutput, bool) bool) error {
+	output, err := c.DescribeLogGroups(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// DescribeLogStreams returns the fixture of the operation.
+func (c *CloudWatchLogs) DescribeLogStreams(input *cloudwatchlogs.DescribeLogStreamsInput) (*cloudwatchlogs.DescribeLogStreamsOutput, error) {
+	output := &cloudwatchlogs.DescribeLogStreamsOutput{}
+	return output, c.b.output(cloudwatchlogs.ServiceName, "DescribeLogStreams", output)
+}
+
+// DescribeLogStreamsPages calls fn with the fixture of the operation as the only page.
+func (c *CloudWatchLogs) DescribeLogStreamsPages(input *cloudwatchlogs.DescribeLogStreamsInput, fn func(*cloudwatchlogs.DescribeLogStreamsOutput, bool) bool) error {
+	output, err := c.DescribeLogStreams(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListTagsLogGroup returns the fixture of the operation.
+func (c *CloudWatchLogs) ListTagsLogGroup(input *cloudwatchlogs.ListTagsLogGroupInput) (*cloudwatchlogs.ListTagsLogGroupOutput, error) {
+	output := &cloudwatchlogs.ListTagsLogGroupOutput{}
+	return output, c.b.output(cloudwatchlogs.ServiceName, "ListTagsLogGroup", output)
+}
+
+// UntagLogGroup returns the fixture of the operation.
+func (c *CloudWatchLogs) UntagLogGroup(input *cloudwatchlogs.UntagLogGroupInput) (*cloudwatchlogs.UntagLogGroupOutput, error) {
+	output := &cloudwatchlogs.UntagLogGroupOutput{}
+	return output, c.b.output(cloudwatchlogs.ServiceName, "UntagLogGroup", output)
+}
+
+// CognitoIdentity is a fake of the CognitoIdentity API.
+type CognitoIdentity struct {
+	cognitoidentityiface.CognitoIdentityAPI
+	b *Backend
+}
+
+// ListIdentityPools returns the fixture of the operation.
+func (c *CognitoIdentity) ListIdentityPools(input *cognitoidentity.ListIdentityPoolsInput) (*cognitoidentity.ListIdentityPoolsOutput, error) {
+	output := &cognitoidentity.ListIdentityPoolsOutput{}
+	return output, c.b.output(cognitoidentity.ServiceName, "ListIdentityPools", output)
+}
+
+// CognitoIdentityProvider is a fake of the CognitoIdentityProvider API.
+type CognitoIdentityProvider struct {
+	cognitoidentityprovideriface.CognitoIdentityProviderAPI
+	b *Backend
+}
+
+// DescribeUserPool returns the fixture of the operation.
+func (c *CognitoIdentityProvider) DescribeUserPool(input *cognitoidentityprovider.DescribeUserPoolInput) (*cognitoidentityprovider.DescribeUserPoolOutput, error) {
+	output := &cognitoidentityprovider.DescribeUserPoolOutput{}
+	return output, c.b.output(cognitoidentityprovider.ServiceName, "DescribeUserPool", output)
+}
+
+// ListUserPools returns the fixture of the operation.
+func (c *CognitoIdentityProvider) ListUserPools(input *cognitoidentityprovider.ListUserPoolsInput) (*cognitoidentityprovider.ListUserPoolsOutput, error) {
+	output := &cognitoidentityprovider.ListUserPoolsOutput{}
+	return output, c.b.output(cognitoidentityprovider.ServiceName, "ListUserPools", output)
+}
+
+// ConfigService is a fake of the ConfigService API.
+type ConfigService struct {
+	configserviceiface.ConfigServiceAPI
+	b *Backend
+}
+
+// DescribeConfigRules returns the fixture of the operation.
+func (c *ConfigService) DescribeConfigRules(input *configservice.DescribeConfigRulesInput) (*configservice.DescribeConfigRulesOutput, error) {
+	output := &configservice.DescribeConfigRulesOutput{}
+	return output, c.b.output(configservice.ServiceName, "DescribeConfigRules", output)
+}
+
+// DescribeConfigurationRecorderStatus returns the fixture of the operation.
+func (c *ConfigService) DescribeConfigurationRecorderStatus(input *configservice.DescribeConfigurationRecorderStatusInput) (*configservice.DescribeConfigurationRecorderStatusOutput, error) {
+	output := &configservice.DescribeConfigurationRecorderStatusOutput{}
+	return output, c.b.output(configservice.ServiceName, "DescribeConfigurationRecorderStatus", output)
+}
+
+// DescribeConfigurationRecorders returns the fixture of the operation.
+func (c *ConfigService) DescribeConfigurationRecorders(input *configservice.DescribeConfigurationRecordersInput) (*configservice.DescribeConfigurationRecordersOutput, error) {
+	output := &configservice.DescribeConfigurationRecordersOutput{}
+	return output, c.b.output(configservice.ServiceName, "DescribeConfigurationRecorders", output)
+}
+
+// DescribeDeliveryChannels returns the fixture of the operation.
+func (c *ConfigService) DescribeDeliveryChannels(input *configservice.DescribeDeliveryChannelsInput) (*configservice.DescribeDeliveryChannelsOutput, error) {
+	output := &configservice.DescribeDeliveryChannelsOutput{}
+	return output, c.b.output(configservice.ServiceName, "DescribeDeliveryChannels", output)
+}
+
+// DynamoDB is a fake of the DynamoDB API.
+type DynamoDB struct {
+	dynamodbiface.DynamoDBAPI
+	b *Backend
+}
+
+// DescribeLimits returns the fixture of the operation.
+func (c *DynamoDB) DescribeLimits(input *dynamodb.DescribeLimitsInput) (*dynamodb.DescribeLimitsOutput, error) {
+	output := &dynamodb.DescribeLimitsOutput{}
+	return output, c.b.output(dynamodb.ServiceName, "DescribeLimits", output)
+}
+
+// DescribeTable returns the fixture of the operation.
+func (c *DynamoDB) DescribeTable(input *dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error) {
+	output := &dynamodb.DescribeTableOutput{}
+	return output, c.b.output(dynamodb.ServiceName, "DescribeTable", output)
+}
+
+// ListTables returns the fixture of the operation.
+func (c *DynamoDB) ListTables(input *dynamodb.ListTablesInput) (*dynamodb.ListTablesOutput, error) {
+	output := &dynamodb.ListTablesOutput{}
+	return output, c.b.output(dynamodb.ServiceName, "ListTables", output)
+}
+
+// ListTablesPages calls fn with the fixture of the operation as the only page.
+func (c *DynamoDB) ListTablesPages(input *dynamodb.ListTablesInput, fn func(*dynamodb.ListTablesOutput, bool) bool) error {
+	output, err := c.ListTables(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListTagsOfResource returns the fixture of the operation.
+func (c *DynamoDB) ListTagsOfResource(input *dynamodb.ListTagsOfResourceInput) (*dynamodb.ListTagsOfResourceOutput, error) {
+	output := &dynamodb.ListTagsOfResourceOutput{}
+	return output, c.b.output(dynamodb.ServiceName, "ListTagsOfResource", output)
+}
+
+// UntagResource returns the fixture of the operation.
+func (c *DynamoDB) UntagResource(input *dynamodb.UntagResourceInput) (*dynamodb.UntagResourceOutput, error) {
+	output := &dynamodb.UntagResourceOutput{}
+	return output, c.b.output(dynamodb.ServiceName, "UntagResource", output)
+}
+
+// EC2 is a fake of the EC2 API.
+type EC2 struct {
+	ec2iface.EC2API
+	b *Backend
+}
+
+// DeleteTags returns the fixture of the operation.
+func (c *EC2) DeleteTags(input *ec2.DeleteTagsInput) (*ec2.DeleteTagsOutput, error) {
+	output := &ec2.DeleteTagsOutput{}
+	return output, c.b.output(ec2.ServiceName, "DeleteTags", output)
+}
+
+// DescribeAccountAttributes returns the fixture of the operation.
+func (c *EC2) DescribeAccountAttributes(input *ec2.DescribeAccountAttributesInput) (*ec2.DescribeAccountAttributesOutput, error) {
+	output := &ec2.DescribeAccountAttributesOutput{}
+	return output, c.b.output(ec2.ServiceName, "DescribeAccountAttributes", output)
+}
+
+// DescribeAddresses returns the fixture of the operation.
+func (c *EC2) DescribeAddresses(input *ec2.DescribeAddressesInput) (*ec2.DescribeAddressesOutput, error) {
+	output := &ec2.DescribeAddressesOutput{}
+	return output, c.b.output(ec2.ServiceName, "DescribeAddresses", output)
+}
+
+// DescribeImageAttribute returns the fixture of the operation.
+func (c *EC2) DescribeImageAttribute(input *ec2.DescribeImageAttributeInput) (*ec2.DescribeImageAttributeOutput, error) {
+	output := &ec2.DescribeImageAttributeOutput{}
+	return output, c.b.output(ec2.ServiceName, "DescribeImageAttribute", output)
+}
+
+// DescribeImages returns the fixture of the operation.
+func (c *EC2) DescribeImages(input *ec2.DescribeImagesInput) (*ec2.DescribeImagesOutput, error) {
+	output := &ec2.DescribeImagesOutput{}
+	return output, c.b.output(ec2.ServiceName, "DescribeImages", output)
+}
+
+// DescribeInstances returns the fixture of the operation.
+func (c *EC2) DescribeInstances(input *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+	output := &ec2.DescribeInstancesOutput{}
+	return output, c.b.output(ec2.ServiceName, "DescribeInstances", output)
+}
+
+// DescribeInstancesPages calls fn with the fixture of the operation as the only page.
+func (c *EC2) DescribeInstancesPages(input *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool) error {
+	output, err := c.DescribeInstances(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// DescribeInternetGateways returns the fixture of the operation.
+func (c *EC2) DescribeInternetGateways(input *ec2.DescribeInternetGatewaysInput) (*ec2.DescribeInternetGatewaysOutput, error) {
+	output := &ec2.DescribeInternetGatewaysOutput{}
+	return output, c.b.output(ec2.ServiceName, "DescribeInternetGateways", output)
+}
+
+// DescribeKeyPairs returns the fixture of the operation.
+func (c *EC2) DescribeKeyPairs(input *ec2.DescribeKeyPairsInput) (*ec2.DescribeKeyPairsOutput, error) {
+	output := &ec2.DescribeKeyPairsOutput{}
+	return output, c.b.output(ec2.ServiceName, "DescribeKeyPairs", output)
+}
+
+// DescribeLaunchTemplateVersions returns the fixture of the operation.
+func (c *EC2) DescribeLaunchTemplateVersions(input *ec2.DescribeLaunchTemplateVersionsInput) (*ec2.DescribeLaunchTemplateVersionsOutput, error) {
+	output := &ec2.DescribeLaunchTemplateVersionsOutput{}
+	return output, c.b.output(ec2.ServiceName, "DescribeLaunchTemplateVersions", output)
+}
+
+// DescribeLaunchTemplates returns the fixture of the operation.
+func (c *EC2) DescribeLaunchTemplates(input *ec2.DescribeLaunchTemplatesInput) (*ec2.DescribeLaunchTemplatesOutput, error) {
+	output := &ec2.DescribeLaunchTemplatesOutput{}
+	return output, c.b.output(ec2.ServiceName, "DescribeLaunchTemplates", output)
+}
+
+// DescribeNatGateways returns the fixture of the operation.
+func (c *EC2) DescribeNatGateways(input *ec2.DescribeNatGatewaysInput) (*ec2.DescribeNatGatewaysOutput, error) {
+	output := &ec2.DescribeNatGatewaysOutput{}
+	return output, c.b.output(ec2.ServiceName, "DescribeNatGateways", output)
+}
+
+// DescribeNatGatewaysPages calls fn with the fixture of the operation as the only page.
+func (c *EC2) DescribeNatGatewaysPages(input *ec2.DescribeNatGatewaysInput, fn func(*ec2.DescribeNatGatewaysOutput, bool) bool) error {
+	output, err := c.DescribeNatGateways(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// DescribeNetworkAcls returns the fixture of the operation.
+func (c *EC2) DescribeNetworkAcls(input *ec2.DescribeNetworkAclsInput) (*ec2.DescribeNetworkAclsOutput, error) {
+	output := &ec2.DescribeNetworkAclsOutput{}
+	return output, c.b.output(ec2.ServiceName, "DescribeNetworkAcls", output)
+}
+
+// DescribeNetworkInterfaces returns the fixture of the operation.
+func (c *EC2) DescribeNetworkInterfaces(input *ec2.DescribeNetworkInterfacesInput) (*ec2.DescribeNetworkInterfacesOutput, error) {
+	output := &ec2.DescribeNetworkInterfacesOutput{}
+	return output, c.b.output(ec2.ServiceName, "DescribeNetworkInterfaces", output)
+}
+
+// DescribeNetworkInterfacesPages calls fn with the fixture of the operation as the only page.
+func (c *EC2) DescribeNetworkInterfacesPages(input *ec2.DescribeNetworkInterfacesInput, fn func(*ec2.DescribeNetworkInterfacesOutput, bool) bool) error {
+	output, err := c.DescribeNetworkInterfaces(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// DescribeRegions returns the fixture of the operation.
+func (c *EC2) DescribeRegions(input *ec2.DescribeRegionsInput) (*ec2.DescribeRegionsOutput, error) {
+	output := &ec2.DescribeRegionsOutput{}
+	return output, c.b.output(ec2.ServiceName, "DescribeRegions", output)
+}
+
+// DescribeRouteTables returns the fixture of the operation.
+func (c *EC2) DescribeRouteTables(input *ec2.DescribeRouteTablesInput) (*ec2.DescribeRouteTablesOutput, error) {
+	output := &ec2.DescribeRouteTablesOutput{}
+	return output, c.b.output(ec2.ServiceName, "DescribeRouteTables", output)
+}
+
+// DescribeSecurityGroups returns the fixture of the operation.
+func (c *EC2) DescribeSecurityGroups(input *ec2.DescribeSecurityGroupsInput) (*ec2.DescribeSecurityGroupsOutput, error) {
+	output := &ec2.DescribeSecurityGroupsOutput{}
+	return output, c.b.output(ec2.ServiceName, "DescribeSecurityGroups", output)
+}
+
+// DescribeSnapshotAttribute returns the fixture of the operation.
+func (c *EC2) DescribeSnapshotAttribute(input *ec2.DescribeSnapshotAttributeInput) (*ec2.DescribeSnapshotAttributeOutput, error) {
+	output := &ec2.DescribeSnapshotAttributeOutput{}
+	return output, c.b.output(ec2.ServiceName, "DescribeSnapshotAttribute", output)
+}
+
+// DescribeSnapshots returns the fixture of the operation.
+func (c *EC2) DescribeSnapshots(input *ec2.DescribeSnapshotsInput) (*ec2.DescribeSnapshotsOutput, error) {
+	output := &ec2.DescribeSnapshotsOutput{}
+	return output, c.b.output(ec2.ServiceName, "DescribeSnapshots", output)
+}
+
+// DescribeSnapshotsPages calls fn with the fixture of the operation as the only page.
+func (c *EC2) DescribeSnapshotsPages(input *ec2.DescribeSnapshotsInput, fn func(*ec2.DescribeSnapshotsOutput, bool) bool) error {
+	output, err := c.DescribeSnapshots(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// DescribeSubnets returns the fixture of the operation.
+func (c *EC2) DescribeSubnets(input *ec2.DescribeSubnetsInput) (*ec2.DescribeSubnetsOutput, error) {
+	output := &ec2.DescribeSubnetsOutput{}
+	return output, c.b.output(ec2.ServiceName, "DescribeSubnets", output)
+}
+
+// DescribeVolumes returns the fixture of the operation.
+func (c *EC2) DescribeVolumes(input *ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error) {
+	output := &ec2.DescribeVolumesOutput{}
+	return output, c.b.output(ec2.ServiceName, "DescribeVolumes", output)
+}
+
+// DescribeVolumesPages calls fn with the fixture of the operation as the only page.
+func (c *EC2) DescribeVolumesPages(input *ec2.DescribeVolumesInput, fn func(*ec2.DescribeVolumesOutput, bool) bool) error {
+	output, err := c.DescribeVolumes(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// DescribeVpcEndpoints returns the fixture of the operation.
+func (c *EC2) DescribeVpcEndpoints(input *ec2.DescribeVpcEndpointsInput) (*ec2.DescribeVpcEndpointsOutput, error) {
+	output := &ec2.DescribeVpcEndpointsOutput{}
+	return output, c.b.output(ec2.ServiceName, "DescribeVpcEndpoints", output)
+}
+
+// DescribeVpcs returns the fixture of the operation.
+func (c *EC2) DescribeVpcs(input *ec2.DescribeVpcsInput) (*ec2.DescribeVpcsOutput, error) {
+	output := &ec2.DescribeVpcsOutput{}
+	return output, c.b.output(ec2.ServiceName, "DescribeVpcs", output)
+}
+
+// ResetImageAttribute returns the fixture of the operation.
+func (c *EC2) ResetImageAttribute(input *ec2.ResetImageAttributeInput) (*ec2.ResetImageAttributeOutput, error) {
+	output := &ec2.ResetImageAttributeOutput{}
+	return output, c.b.output(ec2.ServiceName, "ResetImageAttribute", output)
+}
+
+// ResetSnapshotAttribute returns the fixture of the operation.
+func (c *EC2) ResetSnapshotAttribute(input *ec2.ResetSnapshotAttributeInput) (*ec2.ResetSnapshotAttributeOutput, error) {
+	output := &ec2.ResetSnapshotAttributeOutput{}
+	return output, c.b.output(ec2.ServiceName, "ResetSnapshotAttribute", output)
+}
+
+// ECS is a fake of the ECS API.
+type ECS struct {
+	ecsiface.ECSAPI
+	b *Backend
+}
+
+// DescribeClusters returns the fixture of the operation.
+func (c *ECS) DescribeClusters(input *ecs.DescribeClustersInput) (*ecs.DescribeClustersOutput, error) {
+	output := &ecs.DescribeClustersOutput{}
+	return output, c.b.output(ecs.ServiceName, "DescribeClusters", output)
+}
+
+// DescribeServices returns the fixture of the operation.
+func (c *ECS) DescribeServices(input *ecs.DescribeServicesInput) (*ecs.DescribeServicesOutput, error) {
+	output := &ecs.DescribeServicesOutput{}
+	return output, c.b.output(ecs.ServiceName, "DescribeServices", output)
+}
+
+// ListClusters returns the fixture of the operation.
+func (c *ECS) ListClusters(input *ecs.ListClustersInput) (*ecs.ListClustersOutput, error) {
+	output := &ecs.ListClustersOutput{}
+	return output, c.b.output(ecs.ServiceName, "ListClusters", output)
+}
+
+// ListClustersPages calls fn with the fixture of the operation as the only page.
+func (c *ECS) ListClustersPages(input *ecs.ListClustersInput, fn func(*ecs.ListClustersOutput, bool) bool) error {
+	output, err := c.ListClusters(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListServices returns the fixture of the operation.
+func (c *ECS) ListServices(input *ecs.ListServicesInput) (*ecs.ListServicesOutput, error) {
+	output := &ecs.ListServicesOutput{}
+	return output, c.b.output(ecs.ServiceName, "ListServices", output)
+}
+
+// ListServicesPages calls fn with the fixture of the operation as the only page.
+func (c *ECS) ListServicesPages(input *ecs.ListServicesInput, fn func(*ecs.ListServicesOutput, bool) bool) error {
+	output, err := c.ListServices(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListTaskDefinitions returns the fixture of the operation.
+func (c *ECS) ListTaskDefinitions(input *ecs.ListTaskDefinitionsInput) (*ecs.ListTaskDefinitionsOutput, error) {
+	output := &ecs.ListTaskDefinitionsOutput{}
+	return output, c.b.output(ecs.ServiceName, "ListTaskDefinitions", output)
+}
+
+// ListTaskDefinitionsPages calls fn with the fixture of the operation as the only page.
+func (c *ECS) ListTaskDefinitionsPages(input *ecs.ListTaskDefinitionsInput, fn func(*ecs.ListTaskDefinitionsOutput, bool) bool) error {
+	output, err := c.ListTaskDefinitions(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// EFS is a fake of the EFS API.
+type EFS struct {
+	efsiface.EFSAPI
+	b *Backend
+}
+
+// DeleteTags returns the fixture of the operation.
+func (c *EFS) DeleteTags(input *efs.DeleteTagsInput) (*efs.DeleteTagsOutput, error) {
+	output := &efs.DeleteTagsOutput{}
+	return output, c.b.output(efs.ServiceName, "DeleteTags", output)
+}
+
+// DescribeFileSystems returns the fixture of the operation.
+func (c *EFS) DescribeFileSystems(input *efs.DescribeFileSystemsInput) (*efs.DescribeFileSystemsOutput, error) {
+	output := &efs.DescribeFileSystemsOutput{}
+	return output, c.b.output(efs.ServiceName, "DescribeFileSystems", output)
+}
+
+// DescribeMountTargets returns the fixture of the operation.
+func (c *EFS) DescribeMountTargets(input *efs.DescribeMountTargetsInput) (*efs.DescribeMountTargetsOutput, error) {
+	output := &efs.DescribeMountTargetsOutput{}
+	return output, c.b.output(efs.ServiceName, "DescribeMountTargets", output)
+}
+
+// ElasticBeanstalk is a fake of the ElasticBeanstalk API.
+type ElasticBeanstalk struct {
+	elasticbeanstalkiface.ElasticBeanstalkAPI
+	b *Backend
+}
+
+// DescribeAccountAttributes returns the fixture of the operation.
+func (c *ElasticBeanstalk) DescribeAccountAttributes(input *elasticbeanstalk.DescribeAccountAttributesInput) (*elasticbeanstalk.DescribeAccountAttributesOutput, error) {
+	output := &elasticbeanstalk.DescribeAccountAttributesOutput{}
+	return output, c.b.output(elasticbeanstalk.ServiceName, "DescribeAccountAttributes", output)
+}
+
+// DescribeApplicationVersions returns the fixture of the operation.
+func (c *ElasticBeanstalk) DescribeApplicationVersions(input *elasticbeanstalk.DescribeApplicationVersionsInput) (*elasticbeanstalk.DescribeApplicationVersionsOutput, error) {
+	output := &elasticbeanstalk.DescribeApplicationVersionsOutput{}
+	return output, c.b.output(elasticbeanstalk.ServiceName, "DescribeApplicationVersions", output)
+}
+
+// DescribeApplications returns the fixture of the operation.
+func (c *ElasticBeanstalk) DescribeApplications(input *elasticbeanstalk.DescribeApplicationsInput) (*elasticbeanstalk.DescribeApplicationsOutput, error) {
+	output := &elasticbeanstalk.DescribeApplicationsOutput{}
+	return output, c.b.output(elasticbeanstalk.ServiceName, "DescribeApplications", output)
+}
+
+// DescribeEnvironments returns the fixture of the operation.
+func (c *ElasticBeanstalk) DescribeEnvironments(input *elasticbeanstalk.DescribeEnvironmentsInput) (*elasticbeanstalk.EnvironmentDescriptionsMessage, error) {
+	output := &elasticbeanstalk.EnvironmentDescriptionsMessage{}
+	return output, c.b.output(elasticbeanstalk.ServiceName, "DescribeEnvironments", output)
+}
+
+// ListTagsForResource returns the fixture of the operation.
+func (c *ElasticBeanstalk) ListTagsForResource(input *elasticbeanstalk.ListTagsForResourceInput) (*elasticbeanstalk.ListTagsForResourceOutput, error) {
+	output := &elasticbeanstalk.ListTagsForResourceOutput{}
+	return output, c.b.output(elasticbeanstalk.ServiceName, "ListTagsForResource", output)
+}
+
+// ELB is a fake of the ELB API.
+type ELB struct {
+	elbiface.ELBAPI
+	b *Backend
+}
+
+// DescribeInstanceHealth returns the fixture of the operation.
+func (c *ELB) DescribeInstanceHealth(input *elb.DescribeInstanceHealthInput) (*elb.DescribeInstanceHealthOutput, error) {
+	output := &elb.DescribeInstanceHealthOutput{}
+	return output, c.b.output(elb.ServiceName, "DescribeInstanceHealth", output)
+}
+
+// DescribeLoadBalancers returns the fixture of the operation.
+func (c *ELB) DescribeLoadBalancers(input *elb.DescribeLoadBalancersInput) (*elb.DescribeLoadBalancersOutput, error) {
+	output := &elb.DescribeLoadBalancersOutput{}
+	return output, c.b.output(elb.ServiceName, "DescribeLoadBalancers", output)
+}
+
+// DescribeLoadBalancersPages calls fn with the fixture of the operation as the only page.
+func (c *ELB) DescribeLoadBalancersPages(input *elb.DescribeLoadBalancersInput, fn func(*elb.DescribeLoadBalancersOutput, bool) bool) error {
+	output, err := c.DescribeLoadBalancers(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// RemoveTags returns the fixture of the operation.
+func (c *ELB) RemoveTags(input *elb.RemoveTagsInput) (*elb.RemoveTagsOutput, error) {
+	output := &elb.RemoveTagsOutput{}
+	return output, c.b.output(elb.ServiceName, "RemoveTags", output)
+}
+
+// ELBV2 is a fake of the ELBV2 API.
+type ELBV2 struct {
+	elbv2iface.ELBV2API
+	b *Backend
+}
+
+// DeregisterTargets returns the fixture of the operation.
+func (c *ELBV2) DeregisterTargets(input *elbv2.DeregisterTargetsInput) (*elbv2.DeregisterTargetsOutput, error) {
+	output := &elbv2.DeregisterTargetsOutput{}
+	return output, c.b.output(elbv2.ServiceName, "DeregisterTargets", output)
+}
+
+// DescribeListeners returns the fixture of the operation.
+func (c *ELBV2) DescribeListeners(input *elbv2.DescribeListenersInput) (*elbv2.DescribeListenersOutput, error) {
+	output := &elbv2.DescribeListenersOutput{}
+	return output, c.b.output(elbv2.ServiceName, "DescribeListeners", output)
+}
+
+// DescribeListenersPages calls fn with the fixture of the operation as the only page.
+func (c *ELBV2) DescribeListenersPages(input *elbv2.DescribeListenersInput, fn func(*elbv2.DescribeListenersOutput, bool) bool) error {
+	output, err := c.DescribeListeners(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// DescribeLoadBalancers returns the fixture of the operation.
+func (c *ELBV2) DescribeLoadBalancers(input *elbv2.DescribeLoadBalancersInput) (*elbv2.DescribeLoadBalancersOutput, error) {
+	output := &elbv2.DescribeLoadBalancersOutput{}
+	return output, c.b.output(elbv2.ServiceName, "DescribeLoadBalancers", output)
+}
+
+// DescribeLoadBalancersPages calls fn with the fixture of the operation as the only page.
+func (c *ELBV2) DescribeLoadBalancersPages(input *elbv2.DescribeLoadBalancersInput, fn func(*elbv2.DescribeLoadBalancersOutput, bool) bool) error {
+	output, err := c.DescribeLoadBalancers(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// DescribeRules returns the fixture of the operation.
+func (c *ELBV2) DescribeRules(input *elbv2.DescribeRulesInput) (*elbv2.DescribeRulesOutput, error) {
+	output := &elbv2.DescribeRulesOutput{}
+	return output, c.b.output(elbv2.ServiceName, "DescribeRules", output)
+}
+
+// DescribeTags returns the fixture of the operation.
+func (c *ELBV2) DescribeTags(input *elbv2.DescribeTagsInput) (*elbv2.DescribeTagsOutput, error) {
+	output := &elbv2.DescribeTagsOutput{}
+	return output, c.b.output(elbv2.ServiceName, "DescribeTags", output)
+}
+
+// DescribeTargetGroups returns the fixture of the operation.
+func (c *ELBV2) DescribeTargetGroups(input *elbv2.DescribeTargetGroupsInput) (*elbv2.DescribeTargetGroupsOutput, error) {
+	output := &elbv2.DescribeTargetGroupsOutput{}
+	return output, c.b.output(elbv2.ServiceName, "DescribeTargetGroups", output)
+}
+
+// DescribeTargetGroupsPages calls fn with the fixture of the operation as the only page.
+func (c *ELBV2) DescribeTargetGroupsPages(input *elbv2.DescribeTargetGroupsInput, fn func(*elbv2.DescribeTargetGroupsOutput, bool) bool) error {
+	output, err := c.DescribeTargetGroups(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// DescribeTargetHealth returns the fixture of the operation.
+func (c *ELBV2) DescribeTargetHealth(input *elbv2.DescribeTargetHealthInput) (*elbv2.DescribeTargetHealthOutput, error) {
+	output := &elbv2.DescribeTargetHealthOutput{}
+	return output, c.b.output(elbv2.ServiceName, "DescribeTargetHealth", output)
+}
+
+// Firehose is a fake of the Firehose API.
+type Firehose struct {
+	firehoseiface.FirehoseAPI
+	b *Backend
+}
+
+// DescribeDeliveryStream returns the fixture of the operation.
+func (c *Firehose) DescribeDeliveryStream(input *firehose.DescribeDeliveryStreamInput) (*firehose.DescribeDeliveryStreamOutput, error) {
+	output := &firehose.DescribeDeliveryStreamOutput{}
+	return output, c.b.output(firehose.ServiceName, "DescribeDeliveryStream", output)
+}
+
+// ListDeliveryStreams returns the fixture of the operation.
+func (c *Firehose) ListDeliveryStreams(input *firehose.ListDeliveryStreamsInput) (*firehose.ListDeliveryStreamsOutput, error) {
+	output := &firehose.ListDeliveryStreamsOutput{}
+	return output, c.b.output(firehose.ServiceName, "ListDeliveryStreams", output)
+}
+
+// ListTagsForDeliveryStream returns the fixture of the operation.
+func (c *Firehose) ListTagsForDeliveryStream(input *firehose.ListTagsForDeliveryStreamInput) (*firehose.ListTagsForDeliveryStreamOutput, error) {
+	output := &firehose.ListTagsForDeliveryStreamOutput{}
+	return output, c.b.output(firehose.ServiceName, "ListTagsForDeliveryStream", output)
+}
+
+// Glue is a fake of the Glue API.
+type Glue struct {
+	glueiface.GlueAPI
+	b *Backend
+}
+
+// GetCrawlers returns the fixture of the operation.
+func (c *Glue) GetCrawlers(input *glue.GetCrawlersInput) (*glue.GetCrawlersOutput, error) {
+	output := &glue.GetCrawlersOutput{}
+	return output, c.b.output(glue.ServiceName, "GetCrawlers", output)
+}
+
+// GetCrawlersPages calls fn with the fixture of the operation as the only page.
+func (c *Glue) GetCrawlersPages(input *glue.GetCrawlersInput, fn func(*glue.GetCrawlersOutput, bool) bool) error {
+	output, err := c.GetCrawlers(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// GetDatabases returns the fixture of the operation.
+func (c *Glue) GetDatabases(input *glue.GetDatabasesInput) (*glue.GetDatabasesOutput, error) {
+	output := &glue.GetDatabasesOutput{}
+	return output, c.b.output(glue.ServiceName, "GetDatabases", output)
+}
+
+// GetDatabasesPages calls fn with the fixture of the operation as the only page.
+func (c *Glue) GetDatabasesPages(input *glue.GetDatabasesInput, fn func(*glue.GetDatabasesOutput, bool) bool) error {
+	output, err := c.GetDatabases(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// GetJobs returns the fixture of the operation.
+func (c *Glue) GetJobs(input *glue.GetJobsInput) (*glue.GetJobsOutput, error) {
+	output := &glue.GetJobsOutput{}
+	return output, c.b.output(glue.ServiceName, "GetJobs", output)
+}
+
+// GetJobsPages calls fn with the fixture of the operation as the only page.
+func (c *Glue) GetJobsPages(input *glue.GetJobsInput, fn func(*glue.GetJobsOutput, bool) bool) error {
+	output, err := c.GetJobs(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// IAM is a fake of the IAM API.
+type IAM struct {
+	iamiface.IAMAPI
+	b *Backend
+}
+
+// GenerateCredentialReport returns the fixture of the operation.
+func (c *IAM) GenerateCredentialReport(input *iam.GenerateCredentialReportInput) (*iam.GenerateCredentialReportOutput, error) {
+	output := &iam.GenerateCredentialReportOutput{}
+	return output, c.b.output(iam.ServiceName, "GenerateCredentialReport", output)
+}
+
+// GetAccessKeyLastUsed returns the fixture of the operation.
+func (c *IAM) GetAccessKeyLastUsed(input *iam.GetAccessKeyLastUsedInput) (*iam.GetAccessKeyLastUsedOutput, error) {
+	output := &iam.GetAccessKeyLastUsedOutput{}
+	return output, c.b.output(iam.ServiceName, "GetAccessKeyLastUsed", output)
+}
+
+// GetAccountAuthorizationDetails returns the fixture of the operation.
+func (c *IAM) GetAccountAuthorizationDetails(input *iam.GetAccountAuthorizationDetailsInput) (*iam.GetAccountAuthorizationDetailsOutput, error) {
+	output := &iam.GetAccountAuthorizationDetailsOutput{}
+	return output, c.b.output(iam.ServiceName, "GetAccountAuthorizationDetails", output)
+}
+
+// GetAccountAuthorizationDetailsPages calls fn with the fixture of the operation as the only page.
+func (c *IAM) GetAccountAuthorizationDetailsPages(input *iam.GetAccountAuthorizationDetailsInput, fn func(*iam.GetAccountAuthorizationDetailsOutput, bool) bool) error {
+	output, err := c.GetAccountAuthorizationDetails(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// GetAccountSummary returns the fixture of the operation.
+func (c *IAM) GetAccountSummary(input *iam.GetAccountSummaryInput) (*iam.GetAccountSummaryOutput, error) {
+	output := &iam.GetAccountSummaryOutput{}
+	return output, c.b.output(iam.ServiceName, "GetAccountSummary", output)
+}
+
+// GetCredentialReport returns the fixture of the operation.
+func (c *IAM) GetCredentialReport(input *iam.GetCredentialReportInput) (*iam.GetCredentialReportOutput, error) {
+	output := &iam.GetCredentialReportOutput{}
+	return output, c.b.output(iam.ServiceName, "GetCredentialReport", output)
+}
+
+// ListAccessKeys returns the fixture of the operation.
+func (c *IAM) ListAccessKeys(input *iam.ListAccessKeysInput) (*iam.ListAccessKeysOutput, error) {
+	output := &iam.ListAccessKeysOutput{}
+	return output, c.b.output(iam.ServiceName, "ListAccessKeys", output)
+}
+
+// ListAccessKeysPages calls fn with the fixture of the operation as the only page.
+func (c *IAM) ListAccessKeysPages(input *iam.ListAccessKeysInput, fn func(*iam.ListAccessKeysOutput, bool) bool) error {
+	output, err := c.ListAccessKeys(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListAttachedUserPolicies returns the fixture of the operation.
+func (c *IAM) ListAttachedUserPolicies(input *iam.ListAttachedUserPoliciesInput) (*iam.ListAttachedUserPoliciesOutput, error) {
+	output := &iam.ListAttachedUserPoliciesOutput{}
+	return output, c.b.output(iam.ServiceName, "ListAttachedUserPolicies", output)
+}
+
+// ListAttachedUserPoliciesPages calls fn with the fixture of the operation as the only page.
+func (c *IAM) ListAttachedUserPoliciesPages(input *iam.ListAttachedUserPoliciesInput, fn func(*iam.ListAttachedUserPoliciesOutput, bool) bool) error {
+	output, err := c.ListAttachedUserPolicies(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListEntitiesForPolicy returns the fixture of the operation.
+func (c *IAM) ListEntitiesForPolicy(input *iam.ListEntitiesForPolicyInput) (*iam.ListEntitiesForPolicyOutput, error) {
+	output := &iam.ListEntitiesForPolicyOutput{}
+	return output, c.b.output(iam.ServiceName, "ListEntitiesForPolicy", output)
+}
+
+// ListEntitiesForPolicyPages calls fn with the fixture of the operation as the only page.
+func (c *IAM) ListEntitiesForPolicyPages(input *iam.ListEntitiesForPolicyInput, fn func(*iam.ListEntitiesForPolicyOutput, bool) bool) error {
+	output, err := c.ListEntitiesForPolicy(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListGroups returns the fixture of the operation.
+func (c *IAM) ListGroups(input *iam.ListGroupsInput) (*iam.ListGroupsOutput, error) {
+	output := &iam.ListGroupsOutput{}
+	return output, c.b.output(iam.ServiceName, "ListGroups", output)
+}
+
+// ListGroupsPages calls fn with the fixture of the operation as the only page.
+func (c *IAM) ListGroupsPages(input *iam.ListGroupsInput, fn func(*iam.ListGroupsOutput, bool) bool) error {
+	output, err := c.ListGroups(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListInstanceProfiles returns the fixture of the operation.
+func (c *IAM) ListInstanceProfiles(input *iam.ListInstanceProfilesInput) (*iam.ListInstanceProfilesOutput, error) {
+	output := &iam.ListInstanceProfilesOutput{}
+	return output, c.b.output(iam.ServiceName, "ListInstanceProfiles", output)
+}
+
+// ListInstanceProfilesPages calls fn with the fixture of the operation as the only page.
+func (c *IAM) ListInstanceProfilesPages(input *iam.ListInstanceProfilesInput, fn func(*iam.ListInstanceProfilesOutput, bool) bool) error {
+	output, err := c.ListInstanceProfiles(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListPolicies returns the fixture of the operation.
+func (c *IAM) ListPolicies(input *iam.ListPoliciesInput) (*iam.ListPoliciesOutput, error) {
+	output := &iam.ListPoliciesOutput{}
+	return output, c.b.output(iam.ServiceName, "ListPolicies", output)
+}
+
+// ListPoliciesPages calls fn with the fixture of the operation as the only page.
+func (c *IAM) ListPoliciesPages(input *iam.ListPoliciesInput, fn func(*iam.ListPoliciesOutput, bool) bool) error {
+	output, err := c.ListPolicies(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListRoles returns the fixture of the operation.
+func (c *IAM) ListRoles(input *iam.ListRolesInput) (*iam.ListRolesOutput, error) {
+	output := &iam.ListRolesOutput{}
+	return output, c.b.output(iam.ServiceName, "ListRoles", output)
+}
+
+// ListRolesPages calls fn with the fixture of the operation as the only page.
+func (c *IAM) ListRolesPages(input *iam.ListRolesInput, fn func(*iam.ListRolesOutput, bool) bool) error {
+	output, err := c.ListRoles(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListUserPolicies returns the fixture of the operation.
+func (c *IAM) ListUserPolicies(input *iam.ListUserPoliciesInput) (*iam.ListUserPoliciesOutput, error) {
+	output := &iam.ListUserPoliciesOutput{}
+	return output, c.b.output(iam.ServiceName, "ListUserPolicies", output)
+}
+
+// ListUserPoliciesPages calls fn with the fixture of the operation as the only page.
+func (c *IAM) ListUserPoliciesPages(input *iam.ListUserPoliciesInput, fn func(*iam.ListUserPoliciesOutput, bool) bool) error {
+	output, err := c.ListUserPolicies(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListUsers returns the fixture of the operation.
+func (c *IAM) ListUsers(input *iam.ListUsersInput) (*iam.ListUsersOutput, error) {
+	output := &iam.ListUsersOutput{}
+	return output, c.b.output(iam.ServiceName, "ListUsers", output)
+}
+
+// ListUsersPages calls fn with the fixture of the operation as the only page.
+func (c *IAM) ListUsersPages(input *iam.ListUsersInput, fn func(*iam.ListUsersOutput, bool) bool) error {
+	output, err := c.ListUsers(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// Kinesis is a fake of the Kinesis API.
+type Kinesis struct {
+	kinesisiface.KinesisAPI
+	b *Backend
+}
+
+// DescribeLimits returns the fixture of the operation.
+func (c *Kinesis) DescribeLimits(input *kinesis.DescribeLimitsInput) (*kinesis.DescribeLimitsOutput, error) {
+	output := &kinesis.DescribeLimitsOutput{}
+	return output, c.b.output(kinesis.ServiceName, "DescribeLimits", output)
+}
+
+// DescribeStreamSummary returns the fixture of the operation.
+func (c *Kinesis) DescribeStreamSummary(input *kinesis.DescribeStreamSummaryInput) (*kinesis.DescribeStreamSummaryOutput, error) {
+	output := &kinesis.DescribeStreamSummaryOutput{}
+	return output, c.b.output(kinesis.ServiceName, "DescribeStreamSummary", output)
+}
+
+// ListStreams returns the fixture of the operation.
+func (c *Kinesis) ListStreams(input *kinesis.ListStreamsInput) (*kinesis.ListStreamsOutput, error) {
+	output := &kinesis.ListStreamsOutput{}
+	return output, c.b.output(kinesis.ServiceName, "ListStreams", output)
+}
+
+// ListStreamsPages calls fn with the fixture of the operation as the only page.
+func (c *Kinesis) ListStreamsPages(input *kinesis.ListStreamsInput, fn func(*kinesis.ListStreamsOutput, bool) bool) error {
+	output, err := c.ListStreams(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListTagsForStream returns the fixture of the operation.
+func (c *Kinesis) ListTagsForStream(input *kinesis.ListTagsForStreamInput) (*kinesis.ListTagsForStreamOutput, error) {
+	output := &kinesis.ListTagsForStreamOutput{}
+	return output, c.b.output(kinesis.ServiceName, "ListTagsForStream", output)
+}
+
+// KMS is a fake of the KMS API.
+type KMS struct {
+	kmsiface.KMSAPI
+	b *Backend
+}
+
+// DescribeKey returns the fixture of the operation.
+func (c *KMS) DescribeKey(input *kms.DescribeKeyInput) (*kms.DescribeKeyOutput, error) {
+	output := &kms.DescribeKeyOutput{}
+	return output, c.b.output(kms.ServiceName, "DescribeKey", output)
+}
+
+// ListAliases returns the fixture of the operation.
+func (c *KMS) ListAliases(input *kms.ListAliasesInput) (*kms.ListAliasesOutput, error) {
+	output := &kms.ListAliasesOutput{}
+	return output, c.b.output(kms.ServiceName, "ListAliases", output)
+}
+
+// ListAliasesPages calls fn with the fixture of the operation as the only page.
+func (c *KMS) ListAliasesPages(input *kms.ListAliasesInput, fn func(*kms.ListAliasesOutput, bool) bool) error {
+	output, err := c.ListAliases(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListKeys returns the fixture of the operation.
+func (c *KMS) ListKeys(input *kms.ListKeysInput) (*kms.ListKeysOutput, error) {
+	output := &kms.ListKeysOutput{}
+	return output, c.b.output(kms.ServiceName, "ListKeys", output)
+}
+
+// ListKeysPages calls fn with the fixture of the operation as the only page.
+func (c *KMS) ListKeysPages(input *kms.ListKeysInput, fn func(*kms.ListKeysOutput, bool) bool) error {
+	output, err := c.ListKeys(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListResourceTags returns the fixture of the operation.
+func (c *KMS) ListResourceTags(input *kms.ListResourceTagsInput) (*kms.ListResourceTagsOutput, error) {
+	output := &kms.ListResourceTagsOutput{}
+	return output, c.b.output(kms.ServiceName, "ListResourceTags", output)
+}
+
+// UntagResource returns the fixture of the operation.
+func (c *KMS) UntagResource(input *kms.UntagResourceInput) (*kms.UntagResourceOutput, error) {
+	output := &kms.UntagResourceOutput{}
+	return output, c.b.output(kms.ServiceName, "UntagResource", output)
+}
+
+// Lambda is a fake of the Lambda API.
+type Lambda struct {
+	lambdaiface.LambdaAPI
+	b *Backend
+}
+
+// GetAccountSettings returns the fixture of the operation.
+func (c *Lambda) GetAccountSettings(input *lambda.GetAccountSettingsInput) (*lambda.GetAccountSettingsOutput, error) {
+	output := &lambda.GetAccountSettingsOutput{}
+	return output, c.b.output(lambda.ServiceName, "GetAccountSettings", output)
+}
+
+// ListEventSourceMappings returns the fixture of the operation.
+func (c *Lambda) ListEventSourceMappings(input *lambda.ListEventSourceMappingsInput) (*lambda.ListEventSourceMappingsOutput, error) {
+	output := &lambda.ListEventSourceMappingsOutput{}
+	return output, c.b.output(lambda.ServiceName, "ListEventSourceMappings", output)
+}
+
+// ListEventSourceMappingsPages calls fn with the fixture of the operation as the only page.
+func (c *Lambda) ListEventSourceMappingsPages(input *lambda.ListEventSourceMappingsInput, fn func(*lambda.ListEventSourceMappingsOutput, bool) bool) error {
+	output, err := c.ListEventSourceMappings(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// Invoke returns the fixture of the operation.
+func (c *Lambda) Invoke(input *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+	output := &lambda.InvokeOutput{}
+	return output, c.b.output(lambda.ServiceName, "Invoke", output)
+}
+
+// ListFunctions returns the fixture of the operation.
+func (c *Lambda) ListFunctions(input *lambda.ListFunctionsInput) (*lambda.ListFunctionsOutput, error) {
+	output := &lambda.ListFunctionsOutput{}
+	return output, c.b.output(lambda.ServiceName, "ListFunctions", output)
+}
+
+// ListFunctionsPages calls fn with the fixture of the operation as the only page.
+func (c *Lambda) ListFunctionsPages(input *lambda.ListFunctionsInput, fn func(*lambda.ListFunctionsOutput, bool) bool) error {
+	output, err := c.ListFunctions(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// RDS is a fake of the RDS API.
+type RDS struct {
+	rdsiface.RDSAPI
+	b *Backend
+}
+
+// DescribeAccountAttributes returns the fixture of the operation.
+func (c *RDS) DescribeAccountAttributes(input *rds.DescribeAccountAttributesInput) (*rds.DescribeAccountAttributesOutput, error) {
+	output := &rds.DescribeAccountAttributesOutput{}
+	return output, c.b.output(rds.ServiceName, "DescribeAccountAttributes", output)
+}
+
+// DescribeDBClusterSnapshots returns the fixture of the operation.
+func (c *RDS) DescribeDBClusterSnapshots(input *rds.DescribeDBClusterSnapshotsInput) (*rds.DescribeDBClusterSnapshotsOutput, error) {
+	output := &rds.DescribeDBClusterSnapshotsOutput{}
+	return output, c.b.output(rds.ServiceName, "DescribeDBClusterSnapshots", output)
+}
+
+// DescribeDBClusters returns the fixture of the operation.
+func (c *RDS) DescribeDBClusters(input *rds.DescribeDBClustersInput) (*rds.DescribeDBClustersOutput, error) {
+	output := &rds.DescribeDBClustersOutput{}
+	return output, c.b.output(rds.ServiceName, "DescribeDBClusters", output)
+}
+
+// DescribeDBClustersPages calls fn with the fixture of the operation as the only page.
+func (c *RDS) DescribeDBClustersPages(input *rds.DescribeDBClustersInput, fn func(*rds.DescribeDBClustersOutput, bool) bool) error {
+	output, err := c.DescribeDBClusters(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// DescribeDBSnapshots returns the fixture of the operation.
+func (c *RDS) DescribeDBSnapshots(input *rds.DescribeDBSnapshotsInput) (*rds.DescribeDBSnapshotsOutput, error) {
+	output := &rds.DescribeDBSnapshotsOutput{}
+	return output, c.b.output(rds.ServiceName, "DescribeDBSnapshots", output)
+}
+
+// DescribeDBSnapshotsPages calls fn with the fixture of the operation as the only page.
+func (c *RDS) DescribeDBSnapshotsPages(input *rds.DescribeDBSnapshotsInput, fn func(*rds.DescribeDBSnapshotsOutput, bool) bool) error {
+	output, err := c.DescribeDBSnapshots(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListTagsForResource returns the fixture of the operation.
+func (c *RDS) ListTagsForResource(input *rds.ListTagsForResourceInput) (*rds.ListTagsForResourceOutput, error) {
+	output := &rds.ListTagsForResourceOutput{}
+	return output, c.b.output(rds.ServiceName, "ListTagsForResource", output)
+}
+
+// RemoveTagsFromResource returns the fixture of the operation.
+func (c *RDS) RemoveTagsFromResource(input *rds.RemoveTagsFromResourceInput) (*rds.RemoveTagsFromResourceOutput, error) {
+	output := &rds.RemoveTagsFromResourceOutput{}
+	return output, c.b.output(rds.ServiceName, "RemoveTagsFromResource", output)
+}
+
+// Redshift is a fake of the Redshift API.
+type Redshift struct {
+	redshiftiface.RedshiftAPI
+	b *Backend
+}
+
+// DeleteClusterSnapshot returns the fixture of the operation.
+func (c *Redshift) DeleteClusterSnapshot(input *redshift.DeleteClusterSnapshotInput) (*redshift.DeleteClusterSnapshotOutput, error) {
+	output := &redshift.DeleteClusterSnapshotOutput{}
+	return output, c.b.output(redshift.ServiceName, "DeleteClusterSnapshot", output)
+}
+
+// DescribeClusterSnapshots returns the fixture of the operation.
+func (c *Redshift) DescribeClusterSnapshots(input *redshift.DescribeClusterSnapshotsInput) (*redshift.DescribeClusterSnapshotsOutput, error) {
+	output := &redshift.DescribeClusterSnapshotsOutput{}
+	return output, c.b.output(redshift.ServiceName, "DescribeClusterSnapshots", output)
+}
+
+// DescribeClusterSnapshotsPages calls fn with the fixture of the operation as the only page.
+func (c *Redshift) DescribeClusterSnapshotsPages(input *redshift.DescribeClusterSnapshotsInput, fn func(*redshift.DescribeClusterSnapshotsOutput, bool) bool) error {
+	output, err := c.DescribeClusterSnapshots(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// DescribeClusters returns the fixture of the operation.
+func (c *Redshift) DescribeClusters(input *redshift.DescribeClustersInput) (*redshift.DescribeClustersOutput, error) {
+	output := &redshift.DescribeClustersOutput{}
+	return output, c.b.output(redshift.ServiceName, "DescribeClusters", output)
+}
+
+// DescribeClustersPages calls fn with the fixture of the operation as the only page.
+func (c *Redshift) DescribeClustersPages(input *redshift.DescribeClustersInput, fn func(*redshift.DescribeClustersOutput, bool) bool) error {
+	output, err := c.DescribeClusters(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ResourceGroupsTaggingAPI is a fake of the Resource Groups Tagging API.
+type ResourceGroupsTaggingAPI struct {
+	resourcegroupstaggingapiiface.ResourceGroupsTaggingAPIAPI
+	b *Backend
+}
+
+// GetResources returns the fixture of the operation.
+func (c *ResourceGroupsTaggingAPI) GetResources(input *resourcegroupstaggingapi.GetResourcesInput) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+	output := &resourcegroupstaggingapi.GetResourcesOutput{}
+	return output, c.b.output(resourcegroupstaggingapi.ServiceName, "GetResources", output)
+}
+
+// GetResourcesPages calls fn with the fixture of the operation as the only page.
+func (c *ResourceGroupsTaggingAPI) GetResourcesPages(input *resourcegroupstaggingapi.GetResourcesInput, fn func(*resourcegroupstaggingapi.GetResourcesOutput, bool) bool) error {
+	output, err := c.GetResources(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// Route53 is a fake of the Route53 API.
+type Route53 struct {
+	route53iface.Route53API
+	b *Backend
+}
+
+// ChangeTagsForResource returns the fixture of the operation.
+func (c *Route53) ChangeTagsForResource(input *route53.ChangeTagsForResourceInput) (*route53.ChangeTagsForResourceOutput, error) {
+	output := &route53.ChangeTagsForResourceOutput{}
+	return output, c.b.output(route53.ServiceName, "ChangeTagsForResource", output)
+}
+
+// GetHostedZoneCount returns the fixture of the operation.
+func (c *Route53) GetHostedZoneCount(input *route53.GetHostedZoneCountInput) (*route53.GetHostedZoneCountOutput, error) {
+	output := &route53.GetHostedZoneCountOutput{}
+	return output, c.b.output(route53.ServiceName, "GetHostedZoneCount", output)
+}
+
+// ListHostedZones returns the fixture of the operation.
+func (c *Route53) ListHostedZones(input *route53.ListHostedZonesInput) (*route53.ListHostedZonesOutput, error) {
+	output := &route53.ListHostedZonesOutput{}
+	return output, c.b.output(route53.ServiceName, "ListHostedZones", output)
+}
+
+// ListHostedZonesPages calls fn with the fixture of the operation as the only page.
+func (c *Route53) ListHostedZonesPages(input *route53.ListHostedZonesInput, fn func(*route53.ListHostedZonesOutput, bool) bool) error {
+	output, err := c.ListHostedZones(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListTagsForResources returns the fixture of the operation.
+func (c *Route53) ListTagsForResources(input *route53.ListTagsForResourcesInput) (*route53.ListTagsForResourcesOutput, error) {
+	output := &route53.ListTagsForResourcesOutput{}
+	return output, c.b.output(route53.ServiceName, "ListTagsForResources", output)
+}
+
+// S3 is a fake of the S3 API.
+type S3 struct {
+	s3iface.S3API
+	b *Backend
+}
+
+// DeleteBucketTagging returns the fixture of the operation.
+func (c *S3) DeleteBucketTagging(input *s3.DeleteBucketTaggingInput) (*s3.DeleteBucketTaggingOutput, error) {
+	output := &s3.DeleteBucketTaggingOutput{}
+	return output, c.b.output(s3.ServiceName, "DeleteBucketTagging", output)
+}
+
+// DeleteObjects returns the fixture of the operation.
+func (c *S3) DeleteObjects(input *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+	output := &s3.DeleteObjectsOutput{}
+	return output, c.b.output(s3.ServiceName, "DeleteObjects", output)
+}
+
+// GetBucketLocation returns the fixture of the operation.
+func (c *S3) GetBucketLocation(input *s3.GetBucketLocationInput) (*s3.GetBucketLocationOutput, error) {
+	output := &s3.GetBucketLocationOutput{}
+	return output, c.b.output(s3.ServiceName, "GetBucketLocation", output)
+}
+
+// GetBucketTagging returns the fixture of the operation.
+func (c *S3) GetBucketTagging(input *s3.GetBucketTaggingInput) (*s3.GetBucketTaggingOutput, error) {
+	output := &s3.GetBucketTaggingOutput{}
+	return output, c.b.output(s3.ServiceName, "GetBucketTagging", output)
+}
+
+// ListBuckets returns the fixture of the operation.
+func (c *S3) ListBuckets(input *s3.ListBucketsInput) (*s3.ListBucketsOutput, error) {
+	output := &s3.ListBucketsOutput{}
+	return output, c.b.output(s3.ServiceName, "ListBuckets", output)
+}
+
+// ListObjectVersions returns the fixture of the operation.
+func (c *S3) ListObjectVersions(input *s3.ListObjectVersionsInput) (*s3.ListObjectVersionsOutput, error) {
+	output := &s3.ListObjectVersionsOutput{}
+	return output, c.b.output(s3.ServiceName, "ListObjectVersions", output)
+}
+
+// ListObjectVersionsPages calls fn with the fixture of the operation as the only page.
+func (c *S3) ListObjectVersionsPages(input *s3.ListObjectVersionsInput, fn func(*s3.ListObjectVersionsOutput, bool) bool) error {
+	output, err := c.ListObjectVersions(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// PutBucketTagging returns the fixture of the operation.
+func (c *S3) PutBucketTagging(input *s3.PutBucketTaggingInput) (*s3.PutBucketTaggingOutput, error) {
+	output := &s3.PutBucketTaggingOutput{}
+	return output, c.b.output(s3.ServiceName, "PutBucketTagging", output)
+}
+
+// SES is a fake of the SES API.
+type SES struct {
+	sesiface.SESAPI
+	b *Backend
+}
+
+// GetSendQuota returns the fixture of the operation.
+func (c *SES) GetSendQuota(input *ses.GetSendQuotaInput) (*ses.GetSendQuotaOutput, error) {
+	output := &ses.GetSendQuotaOutput{}
+	return output, c.b.output(ses.ServiceName, "GetSendQuota", output)
+}
+
+// ListConfigurationSets returns the fixture of the operation.
+func (c *SES) ListConfigurationSets(input *ses.ListConfigurationSetsInput) (*ses.ListConfigurationSetsOutput, error) {
+	output := &ses.ListConfigurationSetsOutput{}
+	return output, c.b.output(ses.ServiceName, "ListConfigurationSets", output)
+}
+
+// ListIdentities returns the fixture of the operation.
+func (c *SES) ListIdentities(input *ses.ListIdentitiesInput) (*ses.ListIdentitiesOutput, error) {
+	output := &ses.ListIdentitiesOutput{}
+	return output, c.b.output(ses.ServiceName, "ListIdentities", output)
+}
+
+// ListIdentitiesPages calls fn with the fixture of the operation as the only page.
+func (c *SES) ListIdentitiesPages(input *ses.ListIdentitiesInput, fn func(*ses.ListIdentitiesOutput, bool) bool) error {
+	output, err := c.ListIdentities(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListReceiptRuleSets returns the fixture of the operation.
+func (c *SES) ListReceiptRuleSets(input *ses.ListReceiptRuleSetsInput) (*ses.ListReceiptRuleSetsOutput, error) {
+	output := &ses.ListReceiptRuleSetsOutput{}
+	return output, c.b.output(ses.ServiceName, "ListReceiptRuleSets", output)
+}
+
+// SFN is a fake of the SFN API.
+type SFN struct {
+	sfniface.SFNAPI
+	b *Backend
+}
+
+// DescribeStateMachine returns the fixture of the operation.
+func (c *SFN) DescribeStateMachine(input *sfn.DescribeStateMachineInput) (*sfn.DescribeStateMachineOutput, error) {
+	output := &sfn.DescribeStateMachineOutput{}
+	return output, c.b.output(sfn.ServiceName, "DescribeStateMachine", output)
+}
+
+// ListStateMachines returns the fixture of the operation.
+func (c *SFN) ListStateMachines(input *sfn.ListStateMachinesInput) (*sfn.ListStateMachinesOutput, error) {
+	output := &sfn.ListStateMachinesOutput{}
+	return output, c.b.output(sfn.ServiceName, "ListStateMachines", output)
+}
+
+// ListStateMachinesPages calls fn with the fixture of the operation as the only page.
+func (c *SFN) ListStateMachinesPages(input *sfn.ListStateMachinesInput, fn func(*sfn.ListStateMachinesOutput, bool) bool) error {
+	output, err := c.ListStateMachines(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// SNS is a fake of the SNS API.
+type SNS struct {
+	snsiface.SNSAPI
+	b *Backend
+}
+
+// ListSubscriptions returns the fixture of the operation.
+func (c *SNS) ListSubscriptions(input *sns.ListSubscriptionsInput) (*sns.ListSubscriptionsOutput, error) {
+	output := &sns.ListSubscriptionsOutput{}
+	return output, c.b.output(sns.ServiceName, "ListSubscriptions", output)
+}
+
+// ListSubscriptionsPages calls fn with the fixture of the operation as the only page.
+func (c *SNS) ListSubscriptionsPages(input *sns.ListSubscriptionsInput, fn func(*sns.ListSubscriptionsOutput, bool) bool) error {
+	output, err := c.ListSubscriptions(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListSubscriptionsByTopic returns the fixture of the operation.
+func (c *SNS) ListSubscriptionsByTopic(input *sns.ListSubscriptionsByTopicInput) (*sns.ListSubscriptionsByTopicOutput, error) {
+	output := &sns.ListSubscriptionsByTopicOutput{}
+	return output, c.b.output(sns.ServiceName, "ListSubscriptionsByTopic", output)
+}
+
+// ListSubscriptionsByTopicPages calls fn with the fixture of the operation as the only page.
+func (c *SNS) ListSubscriptionsByTopicPages(input *sns.ListSubscriptionsByTopicInput, fn func(*sns.ListSubscriptionsByTopicOutput, bool) bool) error {
+	output, err := c.ListSubscriptionsByTopic(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListTopics returns the fixture of the operation.
+func (c *SNS) ListTopics(input *sns.ListTopicsInput) (*sns.ListTopicsOutput, error) {
+	output := &sns.ListTopicsOutput{}
+	return output, c.b.output(sns.ServiceName, "ListTopics", output)
+}
+
+// ListTopicsPages calls fn with the fixture of the operation as the only page.
+func (c *SNS) ListTopicsPages(input *sns.ListTopicsInput, fn func(*sns.ListTopicsOutput, bool) bool) error {
+	output, err := c.ListTopics(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// SQS is a fake of the SQS API.
+type SQS struct {
+	sqsiface.SQSAPI
+	b *Backend
+}
+
+// ListQueueTags returns the fixture of the operation.
+func (c *SQS) ListQueueTags(input *sqs.ListQueueTagsInput) (*sqs.ListQueueTagsOutput, error) {
+	output := &sqs.ListQueueTagsOutput{}
+	return output, c.b.output(sqs.ServiceName, "ListQueueTags", output)
+}
+
+// ListQueues returns the fixture of the operation.
+func (c *SQS) ListQueues(input *sqs.ListQueuesInput) (*sqs.ListQueuesOutput, error) {
+	output := &sqs.ListQueuesOutput{}
+	return output, c.b.output(sqs.ServiceName, "ListQueues", output)
+}
+
+// SSM is a fake of the SSM API.
+type SSM struct {
+	ssmiface.SSMAPI
+	b *Backend
+}
+
+// DescribeParameters returns the fixture of the operation.
+func (c *SSM) DescribeParameters(input *ssm.DescribeParametersInput) (*ssm.DescribeParametersOutput, error) {
+	output := &ssm.DescribeParametersOutput{}
+	return output, c.b.output(ssm.ServiceName, "DescribeParameters", output)
+}
+
+// DescribeParametersPages calls fn with the fixture of the operation as the only page.
+func (c *SSM) DescribeParametersPages(input *ssm.DescribeParametersInput, fn func(*ssm.DescribeParametersOutput, bool) bool) error {
+	output, err := c.DescribeParameters(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// GetParametersByPath returns the fixture of the operation.
+func (c *SSM) GetParametersByPath(input *ssm.GetParametersByPathInput) (*ssm.GetParametersByPathOutput, error) {
+	output := &ssm.GetParametersByPathOutput{}
+	return output, c.b.output(ssm.ServiceName, "GetParametersByPath", output)
+}
+
+// GetParametersByPathPages calls fn with the fixture of the operation as the only page.
+func (c *SSM) GetParametersByPathPages(input *ssm.GetParametersByPathInput, fn func(*ssm.GetParametersByPathOutput, bool) bool) error {
+	output, err := c.GetParametersByPath(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+// ListTagsForResource returns the fixture of the operation.
+func (c *SSM) ListTagsForResource(input *ssm.ListTagsForResourceInput) (*ssm.ListTagsForResourceOutput, error) {
+	output := &ssm.ListTagsForResourceOutput{}
+	return output, c.b.output(ssm.ServiceName, "ListTagsForResource", output)
+}
+
+// STS is a fake of the STS API.
+type STS struct {
+	stsiface.STSAPI
+	b *Backend
+}
+
+// GetCallerIdentity returns the fixture of the operation.
+func (c *STS) GetCallerIdentity(input *sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error) {
+	output := &sts.GetCallerIdentityOutput{}
+	return output, c.b.output(sts.ServiceName, "GetCallerIdentity", output)
+}
+
+// Support is a fake of the Support API.
+type Support struct {
+	supportiface.SupportAPI
+	b *Backend
+}
+
+// DescribeTrustedAdvisorCheckResult returns the fixture of the operation.
+func (c *Support) DescribeTrustedAdvisorCheckResult(input *support.DescribeTrustedAdvisorCheckResultInput) (*support.DescribeTrustedAdvisorCheckResultOutput, error) {
+	output := &support.DescribeTrustedAdvisorCheckResultOutput{}
+	return output, c.b.output(support.ServiceName, "DescribeTrustedAdvisorCheckResult", output)
+}
+
+// DescribeTrustedAdvisorChecks returns the fixture of the operation.
+func (c *Support) DescribeTrustedAdvisorChecks(input *support.DescribeTrustedAdvisorChecksInput) (*support.DescribeTrustedAdvisorChecksOutput, error) {
+	output := &support.DescribeTrustedAdvisorChecksOutput{}
+	return output, c.b.output(support.ServiceName, "DescribeTrustedAdvisorChecks", output)
+}
@@ -0,0 +1,157 @@
+// Package resourcetest provides an in-memory fake of the AWS API clients that AWSweeper uses,
+// so that listing, filtering and selecting resources can be tested without an AWS account.
+//
+// The fake is seeded with fixtures that contain the outputs of the API operations per service, e.g.
+//
+//	ec2:
+//	  DescribeInstances:
+//	    Reservations:
+//	      - Instances:
+//	          - InstanceId: i-1234
+//	            Tags:
+//	              - Key: foo
+//	                Value: bar
+//
+// Services are named as in the AWS SDK (e.g. "email" for SES, see the ServiceName constants of its packages)
+// and field names are the ones of the output structs. Outputs don't depend on the input
+// of an operation (i.e. filters and pagination are ignored). Operations without a fixture return an empty output.
+// Calling an operation that is not faked panics.
+package resourcetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+)
+
+// Fixtures are the outputs of API operations per name of service (e.g. "ec2")
+// and name of operation (e.g. "DescribeInstances").
+type Fixtures map[string]map[string]interface{}
+
+// LoadFixtures reads fixtures from a yaml file.
+func LoadFixtures(filename string) (Fixtures, error) {
+	data, err := afero.ReadFile(resource.AppFs, filename)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read fixtures: %s", filename)
+	}
+
+	fixtures := Fixtures{}
+	if err := yaml.Unmarshal(data, &fixtures); err != nil {
+		return nil, errors.Wrapf(err, "cannot unmarshal fixtures: %s", filename)
+	}
+	return fixtures, nil
+}
+
+// Backend serves the fixtures to the fake clients and records the operations they are called with.
+type Backend struct {
+	fixtures Fixtures
+
+	mu    sync.Mutex
+	calls []string
+}
+
+// NewBackend creates a backend seeded with the given fixtures.
+func NewBackend(fixtures Fixtures) *Backend {
+	return &Backend{fixtures: fixtures}
+}
+
+// NewAWS creates AWS API clients that are faked by a backend seeded with the given fixtures.
+func NewAWS(fixtures Fixtures) (*resource.AWS, *Backend) {
+	b := NewBackend(fixtures)
+
+	return &resource.AWS{
+		APIGatewayAPI:               &APIGateway{b: b},
+		AthenaAPI:                   &Athena{b: b},
+		AutoScalingAPI:              &AutoScaling{b: b},
+		CloudFormationAPI:           &CloudFormation{b: b},
+		CloudFrontAPI:               &CloudFront{b: b},
+		CloudTrailAPI:               &CloudTrail{b: b},
+		CloudWatchAPI:               &CloudWatch{b: b},
+		CloudWatchEventsAPI:         &CloudWatchEvents{b: b},
+		CloudWatchLogsAPI:           &CloudWatchLogs{b: b},
+		CognitoIdentityAPI:          &CognitoIdentity{b: b},
+		CognitoIdentityProviderAPI:  &CognitoIdentityProvider{b: b},
+		ConfigServiceAPI:            &ConfigService{b: b},
+		DynamoDBAPI:                 &DynamoDB{b: b},
+		EC2API:                      &EC2{b: b},
+		ECSAPI:                      &ECS{b: b},
+		EFSAPI:                      &EFS{b: b},
+		ElasticBeanstalkAPI:         &ElasticBeanstalk{b: b},
+		ELBAPI:                      &ELB{b: b},
+		ELBV2API:                    &ELBV2{b: b},
+		FirehoseAPI:                 &Firehose{b: b},
+		GlueAPI:                     &Glue{b: b},
+		IAMAPI:                      &IAM{b: b},
+		KinesisAPI:                  &Kinesis{b: b},
+		KMSAPI:                      &KMS{b: b},
+		LambdaAPI:                   &Lambda{b: b},
+		RDSAPI:                      &RDS{b: b},
+		RedshiftAPI:                 &Redshift{b: b},
+		ResourceGroupsTaggingAPIAPI: &ResourceGroupsTaggingAPI{b: b},
+		Route53API:                  &Route53{b: b},
+		S3API:                       &S3{b: b},
+		SESAPI:                      &SES{b: b},
+		SFNAPI:                      &SFN{b: b},
+		SNSAPI:                      &SNS{b: b},
+		SQSAPI:                      &SQS{b: b},
+		SSMAPI:                      &SSM{b: b},
+		STSAPI:                      &STS{b: b},
+		SupportAPI:                  &Support{b: b},
+	}, b
+}
+
+// Calls returns the called operations in the form <service>:<operation> (e.g. "ec2:DescribeInstances").
+func (b *Backend) Calls() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]string{}, b.calls...)
+}
+
+// output records a call of an operation and fills its output with the fixture (if any).
+func (b *Backend) output(service, operation string, output interface{}) error {
+	b.mu.Lock()
+	b.calls = append(b.calls, service+":"+operation)
+	b.mu.Unlock()
+
+	fixture, found := b.fixtures[service][operation]
+	if !found {
+		return nil
+	}
+
+	// the output structs of the AWS SDK have no yaml tags, but
+	// encoding/json matches their field names (case-insensitively)
+	data, err := json.Marshal(jsonCompatible(fixture))
+	if err != nil {
+		return errors.Wrapf(err, "invalid fixture of %s:%s", service, operation)
+	}
+	if err := json.Unmarshal(data, output); err != nil {
+		return errors.Wrapf(err, "invalid fixture of %s:%s", service, operation)
+	}
+	return nil
+}
+
+// jsonCompatible converts the maps that yaml unmarshals into maps with string keys.
+func jsonCompatible(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for key, value := range v {
+			m[fmt.Sprint(key)] = jsonCompatible(value)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, value := range v {
+			l[i] = jsonCompatible(value)
+		}
+		return l
+	default:
+		return v
+	}
+}
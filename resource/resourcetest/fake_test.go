@@ -0,0 +1,71 @@
+package resourcetest_test
+
+import (
+	"testing"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/resourcetest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAWS(t *testing.T) {
+	// given
+	resource.AppFs = afero.NewMemMapFs()
+	afero.WriteFile(resource.AppFs, "fixtures.yml", []byte(`
+ec2:
+  DescribeInstances:
+    Reservations:
+      - Instances:
+          - InstanceId: i-foo
+            LaunchTime: 2018-10-28T12:28:39Z
+            Tags:
+              - Key: foo
+                Value: bar
+          - InstanceId: i-bar
+iam:
+  ListUsers:
+    Users:
+      - UserName: foo
+`), 0644)
+
+	fixtures, err := resourcetest.LoadFixtures("fixtures.yml")
+	require.NoError(t, err)
+
+	// when
+	a, backend := resourcetest.NewAWS(fixtures)
+	rawInstances, err := a.RawResources(resource.Instance)
+	require.NoError(t, err)
+	instances, err := resource.DeletableResources(resource.Instance, rawInstances)
+	require.NoError(t, err)
+
+	rawVpcs, err := a.RawResources(resource.Vpc)
+	require.NoError(t, err)
+	vpcs, err := resource.DeletableResources(resource.Vpc, rawVpcs)
+	require.NoError(t, err)
+
+	// then
+	require.Len(t, instances, 2)
+	assert.Equal(t, "i-foo", instances[0].ID)
+	assert.Equal(t, map[string]string{"foo": "bar"}, instances[0].Tags)
+	require.NotNil(t, instances[0].Created)
+	assert.Equal(t, 2018, instances[0].Created.Year())
+	assert.Equal(t, "i-bar", instances[1].ID)
+
+	assert.Empty(t, vpcs)
+	assert.Equal(t, []string{"ec2:DescribeInstances", "ec2:DescribeVpcs"}, backend.Calls())
+}
+
+func TestNewAWS_InvalidFixture(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"ec2": {"DescribeVpcs": map[interface{}]interface{}{"Vpcs": "not a list"}},
+	})
+
+	// when
+	_, err := a.RawResources(resource.Vpc)
+
+	// then
+	assert.Error(t, err)
+}
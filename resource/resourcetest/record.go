@@ -0,0 +1,166 @@
+package resourcetest
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+)
+
+// sanitizedAccountID replaces the account IDs in recorded outputs.
+const sanitizedAccountID = "123456789012"
+
+var accountIDPattern = regexp.MustCompile(`\b\d{12}\b`)
+
+// paginationFields are removed from recorded outputs, as the pages of an operation are merged into a single output.
+var paginationFields = []string{"IsTruncated", "Marker", "NextMarker", "NextToken"}
+
+// Recorder records the outputs of the read-only API operations (Describe*, Get*, List*)
+// called by AWS clients as fixtures (a.k.a. cassette), so that a run can be replayed offline with NewAWS.
+//
+// The pages of an operation as well as the outputs of calls with different inputs are merged
+// (i.e. lists are concatenated). Account IDs are replaced by a dummy ID.
+type Recorder struct {
+	mu       sync.Mutex
+	fixtures Fixtures
+}
+
+// NewRecorder creates a recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{fixtures: Fixtures{}}
+}
+
+// Attach records the API operations of the clients created from the given session (or copies of it).
+func (r *Recorder) Attach(sess *session.Session) {
+	sess.Handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "awsweeper.Recorder",
+		Fn:   r.record,
+	})
+}
+
+func (r *Recorder) record(req *request.Request) {
+	if req.Error != nil || req.Data == nil || !readOnly(req.Operation.Name) {
+		return
+	}
+
+	output, err := sanitized(req.Data)
+	if err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	service := req.ClientInfo.ServiceName
+	if r.fixtures[service] == nil {
+		r.fixtures[service] = map[string]interface{}{}
+	}
+	r.fixtures[service][req.Operation.Name] = merge(r.fixtures[service][req.Operation.Name], output)
+}
+
+// Fixtures returns the recorded outputs.
+func (r *Recorder) Fixtures() Fixtures {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fixtures := Fixtures{}
+	for service, operations := range r.fixtures {
+		fixtures[service] = map[string]interface{}{}
+		for operation, output := range operations {
+			fixtures[service][operation] = output
+		}
+	}
+	return fixtures
+}
+
+// Save writes the recorded outputs to a yaml file, which can be loaded with LoadFixtures.
+func (r *Recorder) Save(filename string) error {
+	data, err := yaml.Marshal(r.Fixtures())
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal recorded fixtures")
+	}
+	if err := afero.WriteFile(resource.AppFs, filename, data, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write recorded fixtures: %s", filename)
+	}
+	return nil
+}
+
+func readOnly(operation string) bool {
+	for _, prefix := range []string{"Describe", "Get", "List"} {
+		if strings.HasPrefix(operation, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitized converts an output struct of the AWS SDK into its generic (map) representation
+// without empty fields, pagination fields and account IDs.
+func sanitized(output interface{}) (interface{}, error) {
+	data, err := json.Marshal(output)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic map[string]interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	for _, field := range paginationFields {
+		delete(generic, field)
+	}
+	return sanitizedValue(generic), nil
+}
+
+func sanitizedValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := map[string]interface{}{}
+		for key, value := range v {
+			if value != nil {
+				m[key] = sanitizedValue(value)
+			}
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, value := range v {
+			l[i] = sanitizedValue(value)
+		}
+		return l
+	case string:
+		return accountIDPattern.ReplaceAllString(v, sanitizedAccountID)
+	default:
+		return v
+	}
+}
+
+// merge merges the recorded output of an operation with the output of another call of the operation.
+func merge(recorded, output interface{}) interface{} {
+	switch o := output.(type) {
+	case map[string]interface{}:
+		r, ok := recorded.(map[string]interface{})
+		if !ok {
+			return o
+		}
+		for key, value := range o {
+			r[key] = merge(r[key], value)
+		}
+		return r
+	case []interface{}:
+		r, ok := recorded.([]interface{})
+		if !ok {
+			return o
+		}
+		return append(r, o...)
+	default:
+		return output
+	}
+}
@@ -0,0 +1,112 @@
+package resourcetest_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/resourcetest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newOfflineSession creates a session, which requests are answered with the given outputs (or empty ones)
+// instead of sending them.
+func newOfflineSession(outputs map[string]interface{}) *session.Session {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	}))
+	sess.Handlers.Send.Clear()
+	sess.Handlers.Send.PushBack(func(r *request.Request) {
+		r.Handlers.UnmarshalMeta.Clear()
+		r.Handlers.Unmarshal.Clear()
+		r.Handlers.ValidateResponse.Clear()
+
+		output, found := outputs[r.Operation.Name]
+		if !found {
+			return
+		}
+		reflect.ValueOf(r.Data).Elem().Set(reflect.ValueOf(output).Elem())
+	})
+	return sess
+}
+
+func TestRecorder(t *testing.T) {
+	// given
+	sess := newOfflineSession(map[string]interface{}{
+		"DescribeVpcs": &ec2.DescribeVpcsOutput{
+			Vpcs: []*ec2.Vpc{{VpcId: aws.String("vpc-1"), CidrBlock: aws.String("10.0.0.0/16")}},
+		},
+		"ListRoles": &iam.ListRolesOutput{
+			Roles:       []*iam.Role{{RoleName: aws.String("foo"), Arn: aws.String("arn:aws:iam::210987654321:role/foo")}},
+			IsTruncated: aws.Bool(true),
+			Marker:      aws.String("next"),
+		},
+	})
+	recorder := resourcetest.NewRecorder()
+	recorder.Attach(sess)
+
+	// when
+	a := resource.NewAWS(sess)
+	_, err := a.RawResources(resource.Vpc)
+	require.NoError(t, err)
+	_, err = a.ListRoles(&iam.ListRolesInput{})
+	require.NoError(t, err)
+	_, err = a.ListRoles(&iam.ListRolesInput{Marker: aws.String("next")})
+	require.NoError(t, err)
+	_, err = a.DeleteVpc(&ec2.DeleteVpcInput{VpcId: aws.String("vpc-1")})
+	require.NoError(t, err)
+
+	// then
+	fixtures := recorder.Fixtures()
+	assert.Equal(t, map[string]interface{}{
+		"Vpcs": []interface{}{
+			map[string]interface{}{"VpcId": "vpc-1", "CidrBlock": "10.0.0.0/16"},
+		},
+	}, fixtures["ec2"]["DescribeVpcs"])
+	assert.Equal(t, map[string]interface{}{
+		"Roles": []interface{}{
+			map[string]interface{}{"RoleName": "foo", "Arn": "arn:aws:iam::123456789012:role/foo"},
+			map[string]interface{}{"RoleName": "foo", "Arn": "arn:aws:iam::123456789012:role/foo"},
+		},
+	}, fixtures["iam"]["ListRoles"])
+	assert.Len(t, fixtures, 2)
+}
+
+func TestRecorder_Replay(t *testing.T) {
+	// given
+	sess := newOfflineSession(map[string]interface{}{
+		"DescribeVpcs": &ec2.DescribeVpcsOutput{
+			Vpcs: []*ec2.Vpc{{VpcId: aws.String("vpc-1"), Tags: []*ec2.Tag{{Key: aws.String("foo"), Value: aws.String("bar")}}}},
+		},
+	})
+	recorder := resourcetest.NewRecorder()
+	recorder.Attach(sess)
+	_, err := resource.NewAWS(sess).RawResources(resource.Vpc)
+	require.NoError(t, err)
+
+	resource.AppFs = afero.NewMemMapFs()
+	require.NoError(t, recorder.Save("cassette.yml"))
+
+	// when
+	fixtures, err := resourcetest.LoadFixtures("cassette.yml")
+	require.NoError(t, err)
+	a, _ := resourcetest.NewAWS(fixtures)
+	raw, err := a.RawResources(resource.Vpc)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.Vpc, raw)
+	require.NoError(t, err)
+
+	// then
+	require.Len(t, res, 1)
+	assert.Equal(t, "vpc-1", res[0].ID)
+	assert.Equal(t, map[string]string{"foo": "bar"}, res[0].Tags)
+}
@@ -0,0 +1,80 @@
+package resource
+
+import (
+	"fmt"
+	"sort"
+)
+
+// revisioner is implemented by models of resources that are revisions of a family
+// (e.g., the revisions of an ECS task definition or the versions of an Elastic Beanstalk application).
+type revisioner interface {
+	// revisionOf returns the family of the revision and a number that orders the revisions of
+	// the family (the higher, the later).
+	revisionOf() (string, int64)
+}
+
+// revisionTypes are the resource types which resources can be retained per family via keep_latest.
+var revisionTypes = map[TerraformResourceType]bool{
+	BeanstalkAppVersion: true,
+	EcsTaskDefinition:   true,
+}
+
+// validateKeepLatest checks whether resources of a type can be retained per family.
+func validateKeepLatest(resType TerraformResourceType, keepLatest *int) error {
+	if keepLatest == nil {
+		return nil
+	}
+	if !revisionTypes[resType] {
+		return fmt.Errorf("keep_latest is not supported for resource type: %s", resType)
+	}
+	if *keepLatest < 0 {
+		return fmt.Errorf("keep_latest must not be negative for resource type: %s", resType)
+	}
+	return nil
+}
+
+// usesKeepLatest checks whether the filter retains the latest revisions of the given type.
+func (f Filter) usesKeepLatest(resType TerraformResourceType) bool {
+	for _, rtf := range f.Cfg[resType] {
+		if rtf.KeepLatest != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// matchKeepLatest checks whether a resource is not among the latest revisions of its family
+// that are to be kept, if required by the filter.
+func (rtf ResourceTypeFilter) matchKeepLatest(r *Resource) bool {
+	if rtf.KeepLatest == nil {
+		return true
+	}
+	return r.newerRevisions >= *rtf.KeepLatest
+}
+
+// rankRevisions counts for each of the given resources how many later revisions of its family there are,
+// if the filter needs to know.
+func (f Filter) rankRevisions(resType TerraformResourceType, res Resources) {
+	if !f.usesKeepLatest(resType) {
+		return
+	}
+
+	families := map[string]Resources{}
+	for _, r := range res {
+		if m, ok := r.Model.(revisioner); ok {
+			family, _ := m.revisionOf()
+			families[family] = append(families[family], r)
+		}
+	}
+
+	for _, revisions := range families {
+		sort.SliceStable(revisions, func(i, j int) bool {
+			_, ri := revisions[i].Model.(revisioner).revisionOf()
+			_, rj := revisions[j].Model.(revisioner).revisionOf()
+			return ri > rj
+		})
+		for i, r := range revisions {
+			r.newerRevisions = i
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/resourcetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Apply_KeepLatest(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"ecs": {
+			"ListTaskDefinitions": map[string]interface{}{
+				"TaskDefinitionArns": []interface{}{
+					"arn:aws:ecs:us-east-1:123456789012:task-definition/web:9",
+					"arn:aws:ecs:us-east-1:123456789012:task-definition/web:10",
+					"arn:aws:ecs:us-east-1:123456789012:task-definition/web:8",
+					"arn:aws:ecs:us-east-1:123456789012:task-definition/worker:1",
+				},
+			},
+		},
+	})
+	raw, err := a.RawResources(resource.EcsTaskDefinition)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.EcsTaskDefinition, raw)
+	require.NoError(t, err)
+
+	keepLatest := 2
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.EcsTaskDefinition: {{KeepLatest: &keepLatest}},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	result := f.Apply(resource.EcsTaskDefinition, res, raw, a)
+
+	// then
+	require.Len(t, result[0], 1)
+	assert.Equal(t, "arn:aws:ecs:us-east-1:123456789012:task-definition/web:8", result[0][0].ID)
+	m := result[0][0].Model.(resource.EcsTaskDefinitionModel)
+	assert.Equal(t, "web", m.Family)
+	assert.Equal(t, int64(8), m.Revision)
+	assert.Equal(t, map[string]string{"arn": result[0][0].ID}, resource.StateAttributes(result[0][0]))
+}
+
+func TestFilter_Apply_KeepLatestPerApplication(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"elasticbeanstalk": {
+			"DescribeApplicationVersions": map[string]interface{}{
+				"ApplicationVersions": []interface{}{
+					map[string]interface{}{"ApplicationName": "shop", "VersionLabel": "v1", "DateCreated": "2018-10-01T00:00:00Z"},
+					map[string]interface{}{"ApplicationName": "shop", "VersionLabel": "v3", "DateCreated": "2018-10-03T00:00:00Z"},
+					map[string]interface{}{"ApplicationName": "shop", "VersionLabel": "v2", "DateCreated": "2018-10-02T00:00:00Z"},
+					map[string]interface{}{"ApplicationName": "blog", "VersionLabel": "v1", "DateCreated": "2018-09-01T00:00:00Z"},
+				},
+			},
+		},
+	})
+	raw, err := a.RawResources(resource.BeanstalkAppVersion)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.BeanstalkAppVersion, raw)
+	require.NoError(t, err)
+
+	keepLatest := 1
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.BeanstalkAppVersion: {{KeepLatest: &keepLatest}},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	result := f.Apply(resource.BeanstalkAppVersion, res, raw, a)
+
+	// then
+	var selected []string
+	for _, r := range result[0] {
+		m := r.Model.(resource.BeanstalkAppVersionModel)
+		selected = append(selected, m.Application+"/"+r.ID)
+	}
+	assert.ElementsMatch(t, []string{"shop/v1", "shop/v2"}, selected)
+}
+
+func TestYamlFilter_Validate_KeepLatestNotSupported(t *testing.T) {
+	// given
+	keepLatest := 3
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {{KeepLatest: &keepLatest}},
+		},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.EqualError(t, err, "keep_latest is not supported for resource type: aws_instance")
+}
@@ -0,0 +1,108 @@
+package resource
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sample selects a random subset of resources, either a percentage or a fixed number of them.
+type Sample struct {
+	Percent float64
+	Count   int
+}
+
+// ParseSample parses a sample given either as percentage (e.g., "10%") or number of resources (e.g., "5").
+func ParseSample(s string) (*Sample, error) {
+	if strings.HasSuffix(s, "%") {
+		p, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		if err != nil || p <= 0 || p > 100 {
+			return nil, fmt.Errorf("invalid sample percentage: %s", s)
+		}
+		return &Sample{Percent: p}, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("invalid sample size: %s", s)
+	}
+	return &Sample{Count: n}, nil
+}
+
+// size returns the number of resources to select out of the given total. A percentage is rounded up,
+// so that at least one resource is selected.
+func (s Sample) size(total int) int {
+	n := s.Count
+	if s.Percent > 0 {
+		n = int(math.Ceil(float64(total) * s.Percent / 100))
+	}
+
+	if n > total {
+		return total
+	}
+	return n
+}
+
+// Apply returns a random subset of the given resources. All resources are returned for a nil sample.
+func (s *Sample) Apply(res Resources) Resources {
+	if s == nil {
+		return res
+	}
+
+	// a source per call, as regions are swept in parallel
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	result := make(Resources, 0, s.size(len(res)))
+	for _, i := range rnd.Perm(len(res))[:s.size(len(res))] {
+		result = append(result, res[i])
+	}
+	return result
+}
+
+// ApplyGroups samples the groups of resources a filter selects (in deletion order) as a whole: only the resources
+// selected by the filter themselves are sampled, and the resources deleted along with them (e.g., the services of ECS
+// clusters) are kept if all the resources they are deleted along with are part of the sample.
+// All resources are returned for a nil sample.
+func (s *Sample) ApplyGroups(groups []Resources) []Resources {
+	if s == nil {
+		return groups
+	}
+
+	var selected Resources
+	for _, res := range groups {
+		for _, r := range res {
+			if len(r.parents) == 0 {
+				selected = append(selected, r)
+			}
+		}
+	}
+	sampled := map[*Resource]bool{}
+	for _, r := range s.Apply(selected) {
+		sampled[r] = true
+	}
+
+	result := make([]Resources, 0, len(groups))
+	for _, res := range groups {
+		kept := Resources{}
+		for _, r := range res {
+			if sampled[r] || (len(r.parents) > 0 && allSampled(r.parents, sampled)) {
+				kept = append(kept, r)
+			}
+		}
+		result = append(result, kept)
+	}
+	return result
+}
+
+// allSampled checks whether all the given resources are part of a sample.
+func allSampled(res Resources, sampled map[*Resource]bool) bool {
+	for _, r := range res {
+		if !sampled[r] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,166 @@
+package resource_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSampleResources(n int) resource.Resources {
+	var res resource.Resources
+	for i := 0; i < n; i++ {
+		res = append(res, &resource.Resource{Type: resource.Instance, ID: fmt.Sprintf("i-%d", i)})
+	}
+	return res
+}
+
+func TestParseSample(t *testing.T) {
+	s, err := resource.ParseSample("10%")
+	require.NoError(t, err)
+	assert.Equal(t, &resource.Sample{Percent: 10}, s)
+
+	s, err = resource.ParseSample("5")
+	require.NoError(t, err)
+	assert.Equal(t, &resource.Sample{Count: 5}, s)
+}
+
+func TestParseSample_Invalid(t *testing.T) {
+	for _, s := range []string{"", "foo", "0", "-1", "0%", "101%", "x%"} {
+		_, err := resource.ParseSample(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestSample_Apply_Percent(t *testing.T) {
+	// given
+	res := testSampleResources(25)
+	s := &resource.Sample{Percent: 10}
+
+	// when
+	result := s.Apply(res)
+
+	// then
+	require.Len(t, result, 3)
+	ids := map[string]bool{}
+	for _, r := range result {
+		assert.Contains(t, res, r)
+		ids[r.ID] = true
+	}
+	assert.Len(t, ids, 3)
+}
+
+func TestSample_Apply_Count(t *testing.T) {
+	assert.Len(t, (&resource.Sample{Count: 2}).Apply(testSampleResources(5)), 2)
+	assert.Len(t, (&resource.Sample{Count: 10}).Apply(testSampleResources(5)), 5)
+	assert.Empty(t, (&resource.Sample{Count: 2}).Apply(resource.Resources{}))
+}
+
+func TestSample_Apply_Nil(t *testing.T) {
+	var s *resource.Sample
+	res := testSampleResources(5)
+
+	assert.Equal(t, res, s.Apply(res))
+}
+
+func TestSample_ApplyGroups_Dependents(t *testing.T) {
+	// given
+	var clusters []*rds.DBCluster
+	for i := 0; i < 10; i++ {
+		id := fmt.Sprintf("cluster-%d", i)
+		clusters = append(clusters, &rds.DBCluster{
+			DBClusterIdentifier: aws.String(id),
+			DBClusterMembers: []*rds.DBClusterMember{
+				{DBInstanceIdentifier: aws.String(id + "-1")},
+				{DBInstanceIdentifier: aws.String(id + "-2")},
+			},
+		})
+	}
+	res, err := resource.DeletableResources(resource.RdsCluster, clusters)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.RdsCluster: {},
+		},
+	}
+	groups := f.Apply(resource.RdsCluster, res, clusters, nil)
+
+	// when
+	result := (&resource.Sample{Count: 3}).ApplyGroups(groups)
+
+	// then
+	require.Len(t, result, 2)
+	require.Len(t, result[1], 3)
+	require.Len(t, result[0], 6)
+
+	sampled := map[string]bool{}
+	for _, r := range result[1] {
+		sampled[r.ID] = true
+	}
+	for _, r := range result[0] {
+		assert.True(t, sampled[r.ID[:strings.LastIndex(r.ID, "-")]], r.ID)
+	}
+}
+
+func TestSample_ApplyGroups_SeveralParents(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockKMSAPI(mockCtrl)
+	mockObj.EXPECT().DescribeKey(gomock.Any()).Return(
+		&kms.DescribeKeyOutput{
+			KeyMetadata: &kms.KeyMetadata{
+				KeyId:      aws.String("key"),
+				KeyManager: aws.String(kms.KeyManagerTypeCustomer),
+				KeyState:   aws.String(kms.KeyStateEnabled),
+			},
+		}, nil)
+
+	aliases := []*kms.AliasListEntry{
+		{AliasName: aws.String("alias/foo"), TargetKeyId: aws.String("key")},
+		{AliasName: aws.String("alias/bar"), TargetKeyId: aws.String("key")},
+	}
+	res, err := resource.DeletableResources(resource.KmsAlias, aliases)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.KmsAlias: {{Cascade: true}},
+		},
+	}
+	require.NoError(t, f.Validate())
+	groups := f.Apply(resource.KmsAlias, res, aliases, &resource.AWS{KMSAPI: mockObj})
+	require.Len(t, groups[1], 1)
+
+	// when
+	result := (&resource.Sample{Count: 1}).ApplyGroups(groups)
+
+	// then
+	require.Len(t, result, 2)
+	assert.Len(t, result[0], 1)
+	assert.Empty(t, result[1], "the key is still referenced by the alias that is not sampled")
+
+	// when
+	result = (&resource.Sample{Count: 2}).ApplyGroups(groups)
+
+	// then
+	assert.Len(t, result[0], 2)
+	assert.Len(t, result[1], 1)
+}
+
+func TestSample_ApplyGroups_Nil(t *testing.T) {
+	var s *resource.Sample
+	groups := []resource.Resources{testSampleResources(2), testSampleResources(3)}
+
+	assert.Equal(t, groups, s.ApplyGroups(groups))
+}
@@ -1,28 +1,95 @@
 package resource
 
 import (
-	"log"
-	"strings"
-
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/efs"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/s3"
 )
 
 // here is where the filtering of resources happens, i.e.
 // the filter entry in the config for a certain resource type
 // is applied to all resources of that type.
 func (f Filter) Apply(resType TerraformResourceType, res Resources, raw interface{}, aws *AWS) []Resources {
+	if err := aws.MarkDefaults(resType, res, raw); err != nil {
+		Fatal(err)
+	}
+	if err := f.fetchMetrics(resType, res, aws); err != nil {
+		Fatal(err)
+	}
+	if err := f.fetchUsage(resType, res, raw, aws); err != nil {
+		Fatal(err)
+	}
+	if err := f.fetchLastUsed(resType, res, aws); err != nil {
+		Fatal(err)
+	}
+	if err := f.fetchAttributes(resType, res, aws); err != nil {
+		Fatal(err)
+	}
+	f.rankRevisions(resType, res)
+
+	if aws != nil {
+		for _, r := range res {
+			r.listedIn = aws.Region
+		}
+	}
+	if err := f.buildArns(resType, res, aws); err != nil {
+		Fatal(err)
+	}
+
+	result := f.applyTypeFilter(resType, res, raw, aws)
+
+	// resources selected by entries with dry_run are the ones that are not selected without these entries
+	if f.hasDryRunEntries(resType) {
+		f.skipDryRunEntries = true
+
+		deletable := map[string]bool{}
+		for _, rs := range f.applyTypeFilter(resType, res, raw, aws) {
+			for _, r := range rs {
+				deletable[string(r.Type)+"/"+r.ID] = true
+			}
+		}
+		for _, rs := range result {
+			for _, r := range rs {
+				r.DryRun = !deletable[string(r.Type)+"/"+r.ID]
+			}
+		}
+	}
+	return result
+}
+
+// applyTypeFilter selects the resources of a type, together with the resources
+// that have to be deleted before them (e.g., the services of ECS clusters).
+func (f Filter) applyTypeFilter(resType TerraformResourceType, res Resources, raw interface{}, aws *AWS) []Resources {
 	switch resType {
+	case BeanstalkApplication:
+		return f.beanstalkApplicationFilter(res, raw, aws)
+	case EcsCluster:
+		return f.ecsClusterFilter(res, raw, aws)
 	case EfsFileSystem:
 		return f.efsFileSystemFilter(res, raw, aws)
 	case IamUser:
 		return f.iamUserFilter(res, raw, aws)
 	case IamPolicy:
 		return f.iamPolicyFilter(res, raw, aws)
+	case KmsAlias:
+		return f.kmsAliasesFilter(res, raw, aws)
 	case KmsKey:
 		return f.kmsKeysFilter(res, raw, aws)
+	case LambdaFunction:
+		return f.lambdaFunctionFilter(res, raw, aws)
+	case Lb:
+		return f.lbFilter(res, raw, aws)
+	case RdsCluster:
+		return f.rdsClusterFilter(res, raw, aws)
+	case RedshiftCluster:
+		return f.redshiftClusterFilter(res, raw, aws)
+	case S3Bucket:
+		return f.s3BucketFilter(res, raw, aws)
+	case SnsTopic:
+		return f.snsTopicFilter(res, raw, aws)
 	default:
 		return f.defaultFilter(res, raw, aws)
 	}
@@ -42,6 +109,29 @@ func (f Filter) defaultFilter(res Resources, raw interface{}, c *AWS) []Resource
 	return []Resources{result}
 }
 
+// An Elastic Beanstalk application can't be deleted while it has running environments, which are terminated
+// (and waited for until they are terminated) before the application is deleted.
+func (f Filter) beanstalkApplicationFilter(res Resources, raw interface{}, c *AWS) []Resources {
+	result := Resources{}
+	resultEnvironments := Resources{}
+
+	for _, r := range res {
+		if f.matches(r) {
+			environments, err := c.beanstalkEnvironmentsOf(aws.String(r.ID))
+			if err != nil {
+				Fatal(err)
+			}
+			environmentResources, err := DeletableResources(BeanstalkEnvironment, environments)
+			if err != nil {
+				Fatal(err)
+			}
+			resultEnvironments = append(resultEnvironments, dependentsOf(r, environmentResources)...)
+			result = append(result, r)
+		}
+	}
+	return []Resources{resultEnvironments, result}
+}
+
 func (f Filter) efsFileSystemFilter(res Resources, raw interface{}, c *AWS) []Resources {
 	result := Resources{}
 	resultMt := Resources{}
@@ -53,10 +143,11 @@ func (f Filter) efsFileSystemFilter(res Resources, raw interface{}, c *AWS) []Re
 			})
 
 			if err == nil {
-				for _, r := range res.MountTargets {
+				for _, mt := range res.MountTargets {
 					resultMt = append(resultMt, &Resource{
-						Type: "aws_efs_mount_target",
-						ID:   *r.MountTargetId,
+						Type:    "aws_efs_mount_target",
+						ID:      *mt.MountTargetId,
+						parents: Resources{r},
 					})
 				}
 			}
@@ -66,6 +157,119 @@ func (f Filter) efsFileSystemFilter(res Resources, raw interface{}, c *AWS) []Re
 	return []Resources{resultMt, result}
 }
 
+// ecsClusterFilter selects clusters together with their services, which are deleted first
+// (clusters can't be deleted as long as they have active services).
+func (f Filter) ecsClusterFilter(res Resources, raw interface{}, c *AWS) []Resources {
+	result := Resources{}
+	resultServices := Resources{}
+
+	for _, r := range res {
+		if f.matches(r) {
+			services, err := c.ecsClusterServices(aws.String(r.ID))
+			if err != nil {
+				Fatal(err)
+			}
+			serviceResources, err := DeletableResources(EcsService, services)
+			if err != nil {
+				Fatal(err)
+			}
+			resultServices = append(resultServices, dependentsOf(r, serviceResources)...)
+			result = append(result, r)
+		}
+	}
+	return []Resources{resultServices, result}
+}
+
+// snsTopicFilter selects topics together with their subscriptions, which are deleted first.
+func (f Filter) snsTopicFilter(res Resources, raw interface{}, c *AWS) []Resources {
+	result := Resources{}
+	resultSubscriptions := Resources{}
+
+	for _, r := range res {
+		if f.matches(r) {
+			subscriptions, err := c.snsTopicSubscriptions(aws.String(r.ID))
+			if err != nil {
+				Fatal(err)
+			}
+			subscriptionResources, err := DeletableResources(SnsTopicSubscription, subscriptions)
+			if err != nil {
+				Fatal(err)
+			}
+			resultSubscriptions = append(resultSubscriptions, dependentsOf(r, subscriptionResources)...)
+			result = append(result, r)
+		}
+	}
+	return []Resources{resultSubscriptions, result}
+}
+
+// lbFilter selects load balancers (ELBv2) together with their listeners, which are deleted first.
+// With cascade, the rules of the listeners are deleted before the listeners, and the target groups
+// that no other load balancer forwards to are deleted after the load balancers.
+func (f Filter) lbFilter(res Resources, raw interface{}, c *AWS) []Resources {
+	result := Resources{}
+	resultListeners := Resources{}
+	resultRules := Resources{}
+	cascaded := Resources{}
+
+	for _, r := range res {
+		if rtf, found := f.matchingEntry(r); found && rtf.action() == ActionDelete {
+			listeners, err := c.lbListenersOf(aws.String(r.ID))
+			if err != nil {
+				Fatal(err)
+			}
+			listenerResources, err := DeletableResources(LbListener, listeners)
+			if err != nil {
+				Fatal(err)
+			}
+			if rtf.Cascade {
+				rules, err := lbListenerRulesOf(listeners, c)
+				if err != nil {
+					Fatal(err)
+				}
+				resultRules = append(resultRules, dependentsOf(r, rules)...)
+				cascaded = append(cascaded, r)
+			}
+			resultListeners = append(resultListeners, dependentsOf(r, listenerResources)...)
+			result = append(result, r)
+		}
+	}
+
+	resultTargetGroups, err := orphanedLbTargetGroups(cascaded, c)
+	if err != nil {
+		Fatal(err)
+	}
+	return []Resources{resultRules, resultListeners, result, resultTargetGroups}
+}
+
+// The permissions of Lambda functions are part of the functions, but their event source mappings
+// are not and have to be deleted before them.
+func (f Filter) lambdaFunctionFilter(res Resources, raw interface{}, c *AWS) []Resources {
+	result := Resources{}
+	resultMappings := Resources{}
+
+	for _, r := range res {
+		if f.matches(r) {
+			err := c.ListEventSourceMappingsPages(&lambda.ListEventSourceMappingsInput{
+				FunctionName: aws.String(r.ID),
+			}, func(page *lambda.ListEventSourceMappingsOutput, lastPage bool) bool {
+				for _, m := range page.EventSourceMappings {
+					resultMappings = append(resultMappings, &Resource{
+						Type:    "aws_lambda_event_source_mapping",
+						ID:      *m.UUID,
+						parents: Resources{r},
+					})
+				}
+				return true
+			})
+			if err != nil {
+				Fatal(err)
+			}
+			result = append(result, r)
+		}
+	}
+	return []Resources{resultMappings, result}
+}
+
 func (f Filter) iamUserFilter(res Resources, raw interface{}, c *AWS) []Resources {
 	result := Resources{}
 	resultAttPol := Resources{}
@@ -80,8 +284,9 @@ func (f Filter) iamUserFilter(res Resources, raw interface{}, c *AWS) []Resource
 			if err == nil {
 				for _, up := range ups.PolicyNames {
 					resultUserPol = append(resultUserPol, &Resource{
-						Type: "aws_iam_user_policy",
-						ID:   r.ID + ":" + *up,
+						Type:    "aws_iam_user_policy",
+						ID:      r.ID + ":" + *up,
+						parents: Resources{r},
 					})
 				}
 			}
@@ -92,14 +297,17 @@ func (f Filter) iamUserFilter(res Resources, raw interface{}, c *AWS) []Resource
 			})
 			if err == nil {
 				for _, upol := range upols.AttachedPolicies {
-					resultAttPol = append(resultAttPol, &Resource{
-						Type: "aws_iam_user_policy_attachment",
-						ID:   *upol.PolicyArn,
-						Attrs: map[string]string{
-							"user":       r.ID,
-							"policy_arn": *upol.PolicyArn,
-						},
-					})
+					att := &Resource{
+						Type:    IamUserPolicyAttachment,
+						ID:      r.ID + "/" + *upol.PolicyArn,
+						parents: Resources{r},
+					}
+					att.Model = IamUserPolicyAttachmentModel{
+						BaseModel: BaseModel{r: att},
+						User:      r.ID,
+						PolicyArn: *upol.PolicyArn,
+					}
+					resultAttPol = append(resultAttPol, att)
 				}
 			}
 
@@ -119,7 +327,7 @@ func (f Filter) iamPolicyFilter(res Resources, raw interface{}, c *AWS) []Resour
 				PolicyArn: &r.ID,
 			})
 			if err != nil {
-				log.Fatal(err)
+				Fatal(err)
 			}
 
 			roles := []string{}
@@ -136,17 +344,20 @@ func (f Filter) iamPolicyFilter(res Resources, raw interface{}, c *AWS) []Resour
 				roles = append(roles, *r.RoleName)
 			}
 
-			resultAtt = append(resultAtt, &Resource{
-				Type: "aws_iam_policy_attachment",
-				ID:   "none",
-				Attrs: map[string]string{
-					"policy_arn": r.ID,
-					"name":       *raw.([]*iam.Policy)[i].PolicyName,
-					"users":      strings.Join(users, "."),
-					"roles":      strings.Join(roles, "."),
-					"groups":     strings.Join(groups, "."),
-				},
-			})
+			att := &Resource{
+				Type:    "aws_iam_policy_attachment",
+				ID:      "none",
+				parents: Resources{r},
+			}
+			att.Model = IamPolicyAttachmentModel{
+				BaseModel: BaseModel{r: att},
+				PolicyArn: r.ID,
+				Name:      *raw.([]*iam.Policy)[i].PolicyName,
+				Users:     users,
+				Roles:     roles,
+				Groups:    groups,
+			}
+			resultAtt = append(resultAtt, att)
 			result = append(result, r)
 		}
 	}
@@ -155,25 +366,106 @@ func (f Filter) iamPolicyFilter(res Resources, raw interface{}, c *AWS) []Resour
 	return []Resources{resultAtt, result}
 }
 
-func (f Filter) kmsKeysFilter(res Resources, raw interface{}, c *AWS) []Resources {
+// dependentsOf marks the given resources as deleted along with a selected resource and returns them.
+func dependentsOf(parent *Resource, res Resources) Resources {
+	for _, r := range res {
+		r.parents = append(r.parents, parent)
+	}
+	return res
+}
+
+// S3 buckets are listed globally, but must be deleted via the endpoint of the region they are located in.
+func (f Filter) s3BucketFilter(res Resources, raw interface{}, c *AWS) []Resources {
 	result := Resources{}
 
 	for _, r := range res {
 		if f.matches(r) {
-			req, res := c.DescribeKeyRequest(&kms.DescribeKeyInput{
-				KeyId: aws.String(r.ID),
+			loc, err := c.GetBucketLocation(&s3.GetBucketLocationInput{
+				Bucket: aws.String(r.ID),
 			})
-			err := req.Send()
 			if err == nil {
-				if *res.KeyMetadata.KeyState != "PendingDeletion" {
-					result = append(result, &Resource{
-						Type: "aws_kms_key",
-						ID:   r.ID,
+				r.Region = s3.NormalizeBucketLocation(aws.StringValue(loc.LocationConstraint))
+			}
+			result = append(result, r)
+		}
+	}
+	return []Resources{result}
+}
+
+func (f Filter) kmsAliasesFilter(res Resources, raw interface{}, c *AWS) []Resources {
+	result := Resources{}
+	cascaded := Resources{}
+
+	for _, r := range res {
+		if rtf, found := f.matchingEntry(r); found && rtf.action() == ActionDelete {
+			result = append(result, r)
+			if rtf.Cascade {
+				cascaded = append(cascaded, r)
+			}
+		}
+	}
+	// keys are scheduled for deletion after their aliases are deleted
+	return []Resources{result, unreferencedKmsKeys(cascaded, raw, c)}
+}
+
+func (f Filter) kmsKeysFilter(res Resources, raw interface{}, c *AWS) []Resources {
+	result := Resources{}
+	resultAliases := Resources{}
+
+	for _, r := range res {
+		// keys managed by AWS can't be deleted and keys pending deletion are deleted already
+		if m, ok := r.Model.(KmsKeyModel); ok &&
+			(m.KeyManager == kms.KeyManagerTypeAws || m.KeyState == kms.KeyStatePendingDeletion) {
+			continue
+		}
+		if rtf, found := f.matchingEntry(r); found && rtf.action() == ActionDelete {
+			result = append(result, r)
+			if rtf.Cascade {
+				resultAliases = append(resultAliases, dependentsOf(r, kmsAliasesOf(r))...)
+			}
+		}
+	}
+	// otherwise, associated aliases will also be deleted after waiting period (between 7 to 30 days)
+	return []Resources{resultAliases, result}
+}
+
+// rdsClusterFilter selects clusters together with their member instances, which are deleted first
+// (clusters can't be deleted as long as they have instances).
+func (f Filter) rdsClusterFilter(res Resources, raw interface{}, c *AWS) []Resources {
+	result := Resources{}
+	resultInstances := Resources{}
+
+	for _, r := range res {
+		if f.matches(r) {
+			if m, ok := r.Model.(RdsClusterModel); ok {
+				for _, id := range m.members {
+					resultInstances = append(resultInstances, &Resource{
+						Type:    RdsClusterInstance,
+						ID:      id,
+						parents: Resources{r},
 					})
 				}
 			}
+			result = append(result, r)
+		}
+	}
+	return []Resources{resultInstances, result}
+}
+
+// Redshift clusters are deleted with a final snapshot, unless they are selected by a filter entry
+// with skip_final_snapshot.
+func (f Filter) redshiftClusterFilter(res Resources, raw interface{}, c *AWS) []Resources {
+	result := Resources{}
+
+	for _, r := range res {
+		if f.matches(r) {
+			rtf, _ := f.matchingEntry(r)
+			if m, ok := r.Model.(RedshiftClusterModel); ok && rtf.SkipFinalSnapshot {
+				m.finalSnapshotID = ""
+				r.Model = m
+			}
+			result = append(result, r)
 		}
 	}
-	// associated aliases will also be deleted after waiting period (between 7 to 30 days)
 	return []Resources{result}
 }
@@ -5,6 +5,12 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/cloudetc/awsweeper/resource/resourcetest"
+	"github.com/golang/mock/gomock"
 
 	"github.com/stretchr/testify/require"
 
@@ -389,3 +395,729 @@ func TestYamlFilter_Apply_MultipleFiltersPerResourceType(t *testing.T) {
 	assert.Equal(t, "select-this", result[0][0].ID)
 	assert.Equal(t, "select-this-too", result[0][1].ID)
 }
+
+func TestYamlFilter_Apply_ProtectDefaults(t *testing.T) {
+	//given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Vpc: {},
+		},
+		ProtectDefaults: true,
+	}
+	res := []*resource.Resource{
+		{
+			Type:    resource.Vpc,
+			ID:      "do-not-select-this",
+			Default: true,
+		},
+		{
+			Type: resource.Vpc,
+			ID:   "select-this",
+		},
+	}
+
+	// when
+	result := f.Apply(resource.Vpc, res, nil, nil)
+
+	// then
+	require.Len(t, result[0], 1)
+	assert.Equal(t, "select-this", result[0][0].ID)
+}
+
+func TestYamlFilter_Apply_FilterUntagged(t *testing.T) {
+	//given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {
+				{
+					Untagged: true,
+				},
+			},
+		},
+	}
+	res := []*resource.Resource{
+		{
+			Type: resource.Instance,
+			ID:   "select-this",
+			Tags: map[string]string{},
+		},
+		{
+			Type: resource.Instance,
+			ID:   "do-not-select-this",
+			Tags: map[string]string{"foo": "bar"},
+		},
+		{
+			Type: resource.Instance,
+			ID:   "do-not-select-this-either",
+		},
+	}
+
+	// when
+	result := f.Apply(resource.Instance, res, testInstance, nil)
+
+	// then
+	require.Len(t, result[0], 1)
+	assert.Equal(t, "select-this", result[0][0].ID)
+}
+
+func TestYamlFilter_Taggings(t *testing.T) {
+	//given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {
+				{
+					ID:        aws.String("^tag"),
+					Action:    resource.ActionApplyTags,
+					ApplyTags: map[string]string{"owner": "foo"},
+				},
+				{
+					ID: aws.String("^delete"),
+				},
+			},
+		},
+	}
+	res := []*resource.Resource{
+		{
+			Type: resource.Instance,
+			ID:   "tag-this",
+		},
+		{
+			Type: resource.Instance,
+			ID:   "delete-this",
+		},
+	}
+
+	// when
+	taggings := f.Taggings(res)
+	result := f.Apply(resource.Instance, res, testInstance, nil)
+
+	// then
+	require.Len(t, taggings, 1)
+	assert.Equal(t, "tag-this", taggings[0].Resource.ID)
+	assert.Equal(t, map[string]string{"owner": "foo"}, taggings[0].Tags)
+	require.Len(t, result[0], 1)
+	assert.Equal(t, "delete-this", result[0][0].ID)
+}
+
+func TestYamlFilter_Apply_S3BucketRegion(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	//given
+	mockObj := mocks.NewMockS3API(mockCtrl)
+	awsMock := &resource.AWS{
+		S3API: mockObj,
+	}
+
+	mockObj.EXPECT().GetBucketLocation(&s3.GetBucketLocationInput{
+		Bucket: aws.String("select-this"),
+	}).Return(&s3.GetBucketLocationOutput{
+		LocationConstraint: aws.String("EU"),
+	}, nil)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.S3Bucket: {
+				{
+					ID: aws.String("^select"),
+				},
+			},
+		},
+	}
+	res := []*resource.Resource{
+		{
+			Type: resource.S3Bucket,
+			ID:   "select-this",
+		},
+		{
+			Type: resource.S3Bucket,
+			ID:   "do-not-select-this",
+		},
+	}
+
+	// when
+	result := f.Apply(resource.S3Bucket, res, nil, awsMock)
+
+	// then
+	require.Len(t, result[0], 1)
+	assert.Equal(t, "select-this", result[0][0].ID)
+	assert.Equal(t, "eu-west-1", result[0][0].Region)
+}
+
+func TestYamlFilter_Apply_LambdaEventSourceMappings(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockLambdaAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		LambdaAPI: mockObj,
+	}
+
+	mockObj.EXPECT().ListEventSourceMappingsPages(&lambda.ListEventSourceMappingsInput{
+		FunctionName: aws.String("select-this"),
+	}, gomock.Any()).DoAndReturn(
+		func(input *lambda.ListEventSourceMappingsInput, fn func(*lambda.ListEventSourceMappingsOutput, bool) bool) error {
+			fn(&lambda.ListEventSourceMappingsOutput{
+				EventSourceMappings: []*lambda.EventSourceMappingConfiguration{
+					{UUID: aws.String("a1b2c3")},
+				},
+			}, true)
+			return nil
+		})
+
+	functions := []*lambda.FunctionConfiguration{
+		{FunctionName: aws.String("select-this"), Runtime: aws.String("go1.x")},
+		{FunctionName: aws.String("do-not-select-this"), Runtime: aws.String("go1.x")},
+	}
+	res, err := resource.DeletableResources(resource.LambdaFunction, functions)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.LambdaFunction: {
+				{
+					ID: aws.String("^select"),
+				},
+			},
+		},
+	}
+
+	// when
+	result := f.Apply(resource.LambdaFunction, res, functions, awsMock)
+
+	// then
+	require.Len(t, result, 2)
+	require.Len(t, result[0], 1)
+	assert.Equal(t, "a1b2c3", result[0][0].ID)
+	assert.Equal(t, resource.TerraformResourceType("aws_lambda_event_source_mapping"), result[0][0].Type)
+	require.Len(t, result[1], 1)
+	assert.Equal(t, "select-this", result[1][0].ID)
+	assert.Equal(t, map[string]string{"function_name": "select-this"}, resource.StateAttributes(result[1][0]))
+}
+
+func TestYamlFilter_Apply_KmsKeyAttributes(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockKMSAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		KMSAPI: mockObj,
+	}
+
+	mockObj.EXPECT().ListKeysPages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(input *kms.ListKeysInput, fn func(*kms.ListKeysOutput, bool) bool) error {
+			fn(&kms.ListKeysOutput{
+				Keys: []*kms.KeyListEntry{
+					{KeyId: aws.String("select-this")},
+					{KeyId: aws.String("disabled")},
+					{KeyId: aws.String("aws-managed")},
+				},
+			}, true)
+			return nil
+		})
+	mockObj.EXPECT().ListAliasesPages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(input *kms.ListAliasesInput, fn func(*kms.ListAliasesOutput, bool) bool) error {
+			fn(&kms.ListAliasesOutput{
+				Aliases: []*kms.AliasListEntry{
+					{AliasName: aws.String("alias/app-foo"), TargetKeyId: aws.String("select-this")},
+					{AliasName: aws.String("alias/app-bar"), TargetKeyId: aws.String("disabled")},
+					{AliasName: aws.String("alias/aws/s3"), TargetKeyId: aws.String("aws-managed")},
+				},
+			}, true)
+			return nil
+		})
+	mockObj.EXPECT().DescribeKey(gomock.Any()).DoAndReturn(
+		func(input *kms.DescribeKeyInput) (*kms.DescribeKeyOutput, error) {
+			metadata := &kms.KeyMetadata{
+				KeyId:        input.KeyId,
+				CreationDate: aws.Time(time.Now()),
+				KeyManager:   aws.String(kms.KeyManagerTypeCustomer),
+				KeyState:     aws.String(kms.KeyStateEnabled),
+				KeyUsage:     aws.String(kms.KeyUsageTypeEncryptDecrypt),
+			}
+			switch *input.KeyId {
+			case "disabled":
+				metadata.KeyState = aws.String(kms.KeyStateDisabled)
+			case "aws-managed":
+				metadata.KeyManager = aws.String(kms.KeyManagerTypeAws)
+			}
+			return &kms.DescribeKeyOutput{KeyMetadata: metadata}, nil
+		}).Times(3)
+
+	raw, err := awsMock.RawResources(resource.KmsKey)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.KmsKey, raw)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.KmsKey: {
+				{
+					Attributes: map[string]string{"aliases": "^alias/(app|aws)", "key_state": "Enabled"},
+					Cascade:    true,
+				},
+			},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	result := f.Apply(resource.KmsKey, res, raw, awsMock)
+
+	// then
+	require.Len(t, result[1], 1)
+	assert.Equal(t, "select-this", result[1][0].ID)
+	m := result[1][0].Model.(resource.KmsKeyModel)
+	assert.Equal(t, []string{"alias/app-foo"}, m.Aliases)
+	require.Len(t, result[0], 1)
+	assert.Equal(t, resource.KmsAlias, result[0][0].Type)
+	assert.Equal(t, "alias/app-foo", result[0][0].ID)
+}
+
+func TestYamlFilter_Apply_RdsClusterMembers(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"rds": {
+			"DescribeDBClusters": map[string]interface{}{
+				"DBClusters": []interface{}{
+					map[string]interface{}{
+						"DBClusterIdentifier": "select-this",
+						"DBClusterArn":        "arn:aws:rds:us-east-1:123456789012:cluster:select-this",
+						"ClusterCreateTime":   "2018-10-01T00:00:00Z",
+						"Engine":              "aurora-postgresql",
+						"DBClusterMembers": []interface{}{
+							map[string]interface{}{"DBInstanceIdentifier": "select-this-1"},
+							map[string]interface{}{"DBInstanceIdentifier": "select-this-2"},
+						},
+					},
+				},
+			},
+			"ListTagsForResource": map[string]interface{}{
+				"TagList": []interface{}{
+					map[string]interface{}{"Key": "env", "Value": "dev"},
+				},
+			},
+		},
+	})
+	raw, err := a.RawResources(resource.RdsCluster)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.RdsCluster, raw)
+	require.NoError(t, err)
+	require.NoError(t, a.FetchTags(res))
+
+	before := time.Date(2018, 11, 1, 0, 0, 0, 0, time.UTC)
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.RdsCluster: {
+				{
+					Tags:    map[string]string{"env": "dev"},
+					Created: &resource.Created{Before: &before},
+				},
+			},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	result := f.Apply(resource.RdsCluster, res, raw, a)
+
+	// then
+	require.Len(t, result, 2)
+	require.Len(t, result[0], 2)
+	assert.Equal(t, resource.RdsClusterInstance, result[0][0].Type)
+	assert.Equal(t, "select-this-1", result[0][0].ID)
+	require.Len(t, result[1], 1)
+	assert.Equal(t, "select-this", result[1][0].ID)
+	assert.Equal(t, int64(2), result[1][0].Model.(resource.RdsClusterModel).MemberCount)
+	assert.Equal(t, map[string]string{"skip_final_snapshot": "true"}, resource.StateAttributes(result[1][0]))
+}
+
+func TestYamlFilter_Apply_RedshiftClusterFinalSnapshot(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"redshift": {
+			"DescribeClusters": map[string]interface{}{
+				"Clusters": []interface{}{
+					map[string]interface{}{"ClusterIdentifier": "analytics"},
+					map[string]interface{}{"ClusterIdentifier": "ci-1"},
+				},
+			},
+		},
+	})
+	raw, err := a.RawResources(resource.RedshiftCluster)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.RedshiftCluster, raw)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.RedshiftCluster: {
+				{ID: aws.String("^ci-"), SkipFinalSnapshot: true},
+				{ID: aws.String("^analytics$")},
+			},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	result := f.Apply(resource.RedshiftCluster, res, raw, a)
+
+	// then
+	require.Len(t, result, 1)
+	require.Len(t, result[0], 2)
+
+	assert.Equal(t, "analytics", result[0][0].ID)
+	attrs := resource.StateAttributes(result[0][0])
+	assert.Equal(t, "false", attrs["skip_final_snapshot"])
+	assert.Regexp(t, "^analytics-final-[0-9]{14}$", attrs["final_snapshot_identifier"])
+
+	assert.Equal(t, "ci-1", result[0][1].ID)
+	assert.Equal(t, map[string]string{"skip_final_snapshot": "true"}, resource.StateAttributes(result[0][1]))
+}
+
+func TestYamlFilter_Apply_EcsClusterServices(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"ecs": {
+			"ListClusters": map[string]interface{}{
+				"ClusterArns": []interface{}{
+					"arn:aws:ecs:us-east-1:123456789012:cluster/select-this",
+					"arn:aws:ecs:us-east-1:123456789012:cluster/deleted",
+				},
+			},
+			"DescribeClusters": map[string]interface{}{
+				"Clusters": []interface{}{
+					map[string]interface{}{
+						"ClusterArn":          "arn:aws:ecs:us-east-1:123456789012:cluster/select-this",
+						"ClusterName":         "select-this",
+						"Status":              "ACTIVE",
+						"ActiveServicesCount": 1,
+					},
+					map[string]interface{}{
+						"ClusterArn":  "arn:aws:ecs:us-east-1:123456789012:cluster/deleted",
+						"ClusterName": "deleted",
+						"Status":      "INACTIVE",
+					},
+				},
+			},
+			"ListServices": map[string]interface{}{
+				"ServiceArns": []interface{}{
+					"arn:aws:ecs:us-east-1:123456789012:service/web",
+					"arn:aws:ecs:us-east-1:123456789012:service/old",
+				},
+			},
+			"DescribeServices": map[string]interface{}{
+				"Services": []interface{}{
+					map[string]interface{}{
+						"ServiceArn":   "arn:aws:ecs:us-east-1:123456789012:service/web",
+						"ClusterArn":   "arn:aws:ecs:us-east-1:123456789012:cluster/select-this",
+						"Status":       "ACTIVE",
+						"LaunchType":   "FARGATE",
+						"DesiredCount": 2,
+						"CreatedAt":    "2018-10-01T00:00:00Z",
+					},
+					map[string]interface{}{
+						"ServiceArn": "arn:aws:ecs:us-east-1:123456789012:service/old",
+						"ClusterArn": "arn:aws:ecs:us-east-1:123456789012:cluster/select-this",
+						"Status":     "DRAINING",
+					},
+				},
+			},
+		},
+	})
+	raw, err := a.RawResources(resource.EcsCluster)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.EcsCluster, raw)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.EcsCluster: {{ID: aws.String("/select-this$")}},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	result := f.Apply(resource.EcsCluster, res, raw, a)
+
+	// then
+	require.Len(t, result, 2)
+	require.Len(t, result[0], 1)
+	assert.Equal(t, resource.EcsService, result[0][0].Type)
+	assert.Equal(t, "arn:aws:ecs:us-east-1:123456789012:service/web", result[0][0].ID)
+	m := result[0][0].Model.(resource.EcsServiceModel)
+	assert.Equal(t, "select-this", m.Cluster)
+	assert.Equal(t, int64(2), m.DesiredCount)
+	assert.Equal(t, map[string]string{"cluster": "arn:aws:ecs:us-east-1:123456789012:cluster/select-this"},
+		resource.StateAttributes(result[0][0]))
+	require.Len(t, result[1], 1)
+	assert.Equal(t, "arn:aws:ecs:us-east-1:123456789012:cluster/select-this", result[1][0].ID)
+	assert.Equal(t, int64(1), result[1][0].Model.(resource.EcsClusterModel).ServiceCount)
+	assert.Equal(t, map[string]string{"name": "select-this"}, resource.StateAttributes(result[1][0]))
+}
+
+func TestYamlFilter_Apply_SnsTopicSubscriptions(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"sns": {
+			"ListTopics": map[string]interface{}{
+				"Topics": []interface{}{
+					map[string]interface{}{"TopicArn": "arn:aws:sns:us-east-1:123456789012:select-this"},
+					map[string]interface{}{"TopicArn": "arn:aws:sns:us-east-1:123456789012:keep"},
+				},
+			},
+			"ListSubscriptionsByTopic": map[string]interface{}{
+				"Subscriptions": []interface{}{
+					map[string]interface{}{
+						"SubscriptionArn": "arn:aws:sns:us-east-1:123456789012:select-this:5f3d",
+						"TopicArn":        "arn:aws:sns:us-east-1:123456789012:select-this",
+						"Protocol":        "sqs",
+						"Endpoint":        "arn:aws:sqs:us-east-1:123456789012:events",
+					},
+					map[string]interface{}{
+						"SubscriptionArn": "PendingConfirmation",
+						"TopicArn":        "arn:aws:sns:us-east-1:123456789012:select-this",
+						"Protocol":        "email",
+					},
+				},
+			},
+		},
+	})
+	raw, err := a.RawResources(resource.SnsTopic)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.SnsTopic, raw)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.SnsTopic: {{ID: aws.String(":select-this$")}},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	result := f.Apply(resource.SnsTopic, res, raw, a)
+
+	// then
+	require.Len(t, result, 2)
+	require.Len(t, result[0], 1)
+	assert.Equal(t, resource.SnsTopicSubscription, result[0][0].Type)
+	assert.Equal(t, "arn:aws:sns:us-east-1:123456789012:select-this:5f3d", result[0][0].ID)
+	m := result[0][0].Model.(resource.SnsSubscriptionModel)
+	assert.Equal(t, "select-this", m.Topic)
+	assert.Equal(t, "sqs", m.Protocol)
+	require.Len(t, result[1], 1)
+	assert.Equal(t, "arn:aws:sns:us-east-1:123456789012:select-this", result[1][0].ID)
+}
+
+func TestYamlFilter_Apply_LbListeners(t *testing.T) {
+	// given
+	lbArn := "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/ci/50dc6c495c0c9188"
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"elasticloadbalancing": {
+			"DescribeLoadBalancers": map[string]interface{}{
+				"LoadBalancers": []interface{}{
+					map[string]interface{}{"LoadBalancerArn": lbArn, "LoadBalancerName": "ci"},
+				},
+			},
+			"DescribeTags": map[string]interface{}{
+				"TagDescriptions": []interface{}{
+					map[string]interface{}{
+						"ResourceArn": lbArn,
+						"Tags":        []interface{}{map[string]interface{}{"Key": "Environment", "Value": "ci"}},
+					},
+				},
+			},
+			"DescribeListeners": map[string]interface{}{
+				"Listeners": []interface{}{
+					map[string]interface{}{
+						"ListenerArn":     "arn:aws:elasticloadbalancing:us-east-1:123456789012:listener/app/ci/50dc6c495c0c9188/f2f7dc8efc522ab2",
+						"LoadBalancerArn": lbArn,
+					},
+				},
+			},
+		},
+	})
+	raw, err := a.RawResources(resource.Lb)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.Lb, raw)
+	require.NoError(t, err)
+	require.NoError(t, a.FetchTags(res))
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Lb: {{Tags: map[string]string{"Environment": "^ci$"}}},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	result := f.Apply(resource.Lb, res, raw, a)
+
+	// then
+	require.Len(t, result, 4)
+	assert.Empty(t, result[0])
+	require.Len(t, result[1], 1)
+	assert.Equal(t, resource.LbListener, result[1][0].Type)
+	assert.Equal(t, "arn:aws:elasticloadbalancing:us-east-1:123456789012:listener/app/ci/50dc6c495c0c9188/f2f7dc8efc522ab2",
+		result[1][0].ID)
+	require.Len(t, result[2], 1)
+	assert.Equal(t, lbArn, result[2][0].ID)
+	assert.Empty(t, result[3])
+}
+
+func TestYamlFilter_Apply_LbCascade(t *testing.T) {
+	// given
+	lbArn := "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/ci/50dc6c495c0c9188"
+	otherLbArn := "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/prod/6d0ecf831eec9f09"
+	a, b := resourcetest.NewAWS(resourcetest.Fixtures{
+		"elasticloadbalancing": {
+			"DescribeLoadBalancers": map[string]interface{}{
+				"LoadBalancers": []interface{}{
+					map[string]interface{}{"LoadBalancerArn": lbArn, "LoadBalancerName": "ci"},
+				},
+			},
+			"DescribeListeners": map[string]interface{}{
+				"Listeners": []interface{}{
+					map[string]interface{}{"ListenerArn": "listener", "LoadBalancerArn": lbArn},
+				},
+			},
+			"DescribeRules": map[string]interface{}{
+				"Rules": []interface{}{
+					map[string]interface{}{"RuleArn": "default-rule", "IsDefault": true},
+					map[string]interface{}{"RuleArn": "rule", "IsDefault": false},
+				},
+			},
+			"DescribeTargetGroups": map[string]interface{}{
+				"TargetGroups": []interface{}{
+					map[string]interface{}{"TargetGroupArn": "orphaned", "LoadBalancerArns": []interface{}{lbArn}},
+					map[string]interface{}{"TargetGroupArn": "shared", "LoadBalancerArns": []interface{}{lbArn, otherLbArn}},
+					map[string]interface{}{"TargetGroupArn": "unused"},
+				},
+			},
+		},
+	})
+	raw, err := a.RawResources(resource.Lb)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.Lb, raw)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Lb: {{ID: aws.String("app/ci/"), Cascade: true}},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	result := f.Apply(resource.Lb, res, raw, a)
+
+	// then
+	require.Len(t, result, 4)
+	require.Len(t, result[0], 1)
+	assert.Equal(t, resource.TerraformResourceType("aws_lb_listener_rule"), result[0][0].Type)
+	assert.Equal(t, "rule", result[0][0].ID)
+	require.Len(t, result[1], 1)
+	assert.Equal(t, "listener", result[1][0].ID)
+	require.Len(t, result[2], 1)
+	assert.Equal(t, lbArn, result[2][0].ID)
+	require.Len(t, result[3], 1)
+	assert.Equal(t, resource.LbTargetGroup, result[3][0].Type)
+	assert.Equal(t, "orphaned", result[3][0].ID)
+	assert.Contains(t, b.Calls(), "elasticloadbalancing:DescribeRules")
+}
+
+func TestYamlFilter_Apply_SnsTopicDryRun(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"sns": {
+			"ListTopics": map[string]interface{}{
+				"Topics": []interface{}{
+					map[string]interface{}{"TopicArn": "arn:aws:sns:us-east-1:123456789012:trial"},
+				},
+			},
+			"ListSubscriptionsByTopic": map[string]interface{}{
+				"Subscriptions": []interface{}{
+					map[string]interface{}{
+						"SubscriptionArn": "arn:aws:sns:us-east-1:123456789012:trial:5f3d",
+						"TopicArn":        "arn:aws:sns:us-east-1:123456789012:trial",
+					},
+				},
+			},
+		},
+	})
+	raw, err := a.RawResources(resource.SnsTopic)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.SnsTopic, raw)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.SnsTopic: {{ID: aws.String(":trial$"), DryRun: true}},
+		},
+	}
+
+	// when
+	result := f.Apply(resource.SnsTopic, res, raw, a)
+
+	// then the subscriptions deleted together with the topic are only reported as well
+	require.Len(t, result, 2)
+	require.Len(t, result[0], 1)
+	assert.True(t, result[0][0].DryRun)
+	require.Len(t, result[1], 1)
+	assert.True(t, result[1][0].DryRun)
+}
+
+func TestYamlFilter_Apply_BeanstalkEnvironments(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"elasticbeanstalk": {
+			"DescribeApplications": map[string]interface{}{
+				"Applications": []interface{}{
+					map[string]interface{}{"ApplicationName": "ci"},
+				},
+			},
+			"DescribeEnvironments": map[string]interface{}{
+				"Environments": []interface{}{
+					map[string]interface{}{
+						"ApplicationName": "ci",
+						"EnvironmentId":   "e-2abc3def4g",
+						"EnvironmentName": "ci-web",
+						"Status":          "Ready",
+					},
+					map[string]interface{}{
+						"ApplicationName": "ci",
+						"EnvironmentId":   "e-5hij6klm7n",
+						"EnvironmentName": "ci-worker",
+						"Status":          "Terminated",
+					},
+				},
+			},
+		},
+	})
+	raw, err := a.RawResources(resource.BeanstalkApplication)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.BeanstalkApplication, raw)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.BeanstalkApplication: {{}},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	result := f.Apply(resource.BeanstalkApplication, res, raw, a)
+
+	// then
+	require.Len(t, result, 2)
+	require.Len(t, result[0], 1)
+	assert.Equal(t, resource.BeanstalkEnvironment, result[0][0].Type)
+	assert.Equal(t, "e-2abc3def4g", result[0][0].ID)
+	assert.Equal(t, "ci", result[0][0].Model.(resource.BeanstalkEnvironmentModel).Application)
+	require.Len(t, result[1], 1)
+	assert.Equal(t, "ci", result[1][0].ID)
+}
@@ -0,0 +1,89 @@
+package resource
+
+import (
+	"fmt"
+)
+
+// Severity classifies resource types by the blast radius of deleting their resources by mistake.
+type Severity string
+
+const (
+	// SeverityLow means that deleting resources by mistake loses little (e.g., snapshots, logs).
+	SeverityLow Severity = "low"
+	// SeverityMedium means that deleting resources by mistake breaks a workload, which can be recreated.
+	SeverityMedium Severity = "medium"
+	// SeverityHigh means that deleting resources by mistake can break access, encryption or name resolution
+	// of whole accounts (e.g., IAM roles, KMS keys, hosted zones) or lose data that can't be recreated.
+	SeverityHigh Severity = "high"
+)
+
+// severities are the severities of the resource types that deviate from medium.
+var severities = map[TerraformResourceType]Severity{
+	AthenaNamedQuery:        SeverityLow,
+	CloudwatchDashboard:     SeverityLow,
+	CloudwatchLogGroup:      SeverityLow,
+	CloudwatchLogStream:     SeverityLow,
+	CloudwatchMetricAlarm:   SeverityLow,
+	DbClusterSnapshot:       SeverityLow,
+	DbSnapshot:              SeverityLow,
+	DynamodbTable:           SeverityHigh,
+	EbsSnapshot:             SeverityLow,
+	IamAccessKey:            SeverityHigh,
+	IamGroup:                SeverityHigh,
+	IamInstanceProfile:      SeverityHigh,
+	IamPolicy:               SeverityHigh,
+	IamRole:                 SeverityHigh,
+	IamRolePolicyAttachment: SeverityHigh,
+	IamUser:                 SeverityHigh,
+	IamUserGroupMembership:  SeverityHigh,
+	IamUserPolicyAttachment: SeverityHigh,
+	KmsAlias:                SeverityHigh,
+	KmsKey:                  SeverityHigh,
+	RdsCluster:              SeverityHigh,
+	RedshiftSnapshot:        SeverityLow,
+	Route53Zone:             SeverityHigh,
+	S3Bucket:                SeverityHigh,
+}
+
+// DefaultSeverity returns the severity of a resource type, unless overridden by the config.
+func DefaultSeverity(resType TerraformResourceType) Severity {
+	if s, found := severities[resType]; found {
+		return s
+	}
+	return SeverityMedium
+}
+
+// Severity returns the severity of a resource type, as overridden by the config or classified by default.
+func (f Filter) Severity(resType TerraformResourceType) Severity {
+	if s, found := f.Severities[resType]; found {
+		return s
+	}
+	return DefaultSeverity(resType)
+}
+
+// HighSeverityTypes returns the resource types of the filter whose severity is high.
+func (f Filter) HighSeverityTypes() []TerraformResourceType {
+	var result []TerraformResourceType
+	for _, resType := range f.Types() {
+		if f.Severity(resType) == SeverityHigh {
+			result = append(result, resType)
+		}
+	}
+	return result
+}
+
+// validateSeverities checks whether the severities of the config are given for supported types
+// and are low, medium or high.
+func (f Filter) validateSeverities() error {
+	for resType, s := range f.Severities {
+		if !SupportedResourceType(resType) {
+			return fmt.Errorf("unsupported resource type in severities: %s", resType)
+		}
+		switch s {
+		case SeverityLow, SeverityMedium, SeverityHigh:
+		default:
+			return fmt.Errorf("invalid severity of resource type %s: %s (must be low, medium or high)", resType, s)
+		}
+	}
+	return nil
+}
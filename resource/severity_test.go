@@ -0,0 +1,54 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Severity(t *testing.T) {
+	// given
+	resource.AppFs = afero.NewMemMapFs()
+	afero.WriteFile(resource.AppFs, "config.yml", []byte(`
+severities:
+  aws_s3_bucket: low
+  aws_instance: high
+aws_s3_bucket:
+aws_instance:
+aws_iam_role:
+aws_ebs_snapshot:
+aws_key_pair:
+`), 0644)
+
+	// when
+	f := resource.NewFilter("config.yml")
+
+	// then
+	require.NoError(t, f.Validate())
+	assert.Equal(t, resource.SeverityLow, f.Severity(resource.S3Bucket))
+	assert.Equal(t, resource.SeverityLow, f.Severity(resource.EbsSnapshot))
+	assert.Equal(t, resource.SeverityMedium, f.Severity(resource.KeyPair))
+	assert.Equal(t, resource.SeverityHigh, f.Severity(resource.Instance))
+	assert.Equal(t, []resource.TerraformResourceType{resource.IamRole, resource.Instance}, f.HighSeverityTypes())
+}
+
+func TestDefaultSeverity(t *testing.T) {
+	assert.Equal(t, resource.SeverityHigh, resource.DefaultSeverity(resource.IamRole))
+	assert.Equal(t, resource.SeverityHigh, resource.DefaultSeverity(resource.KmsKey))
+	assert.Equal(t, resource.SeverityHigh, resource.DefaultSeverity(resource.Route53Zone))
+	assert.Equal(t, resource.SeverityLow, resource.DefaultSeverity(resource.CloudwatchLogGroup))
+	assert.Equal(t, resource.SeverityMedium, resource.DefaultSeverity(resource.Instance))
+}
+
+func TestFilter_Validate_InvalidSeverities(t *testing.T) {
+	for _, severities := range []map[resource.TerraformResourceType]resource.Severity{
+		{resource.Instance: "critical"},
+		{"aws_foo": resource.SeverityLow},
+	} {
+		f := &resource.Filter{Cfg: resource.Config{}, Severities: severities}
+		assert.Error(t, f.Validate())
+	}
+}
@@ -0,0 +1,100 @@
+package resource
+
+import (
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+)
+
+// PublicAccess is reported instead of an account ID if a resource is shared with everyone.
+const PublicAccess = "all"
+
+// sharingActions are required to look up and remove the permissions of other accounts
+// on the resources of the types that can be shared.
+var sharingActions = map[TerraformResourceType]Permissions{
+	Ami: {
+		List:   []string{"ec2:DescribeImageAttribute"},
+		Delete: []string{"ec2:ResetImageAttribute"},
+	},
+	EbsSnapshot: {
+		List:   []string{"ec2:DescribeSnapshotAttribute"},
+		Delete: []string{"ec2:ResetSnapshotAttribute"},
+	},
+}
+
+// SupportsSharing checks whether resources of a type can be shared with other accounts.
+func SupportsSharing(resType TerraformResourceType) bool {
+	_, found := sharingActions[resType]
+	return found
+}
+
+// SharedWith returns the (sorted) IDs of the other accounts that are allowed to use a resource
+// (e.g., to launch instances from an AMI), or PublicAccess if anyone is.
+func (a *AWS) SharedWith(r *Resource) ([]string, error) {
+	var accounts []string
+
+	switch r.Type {
+	case Ami:
+		output, err := a.DescribeImageAttribute(&ec2.DescribeImageAttributeInput{
+			ImageId:   aws.String(r.ID),
+			Attribute: aws.String(ec2.ImageAttributeNameLaunchPermission),
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to describe launch permissions of AMI %s", r.ID)
+		}
+		for _, p := range output.LaunchPermissions {
+			accounts = append(accounts, permittedAccount(p.UserId, p.Group))
+		}
+	case EbsSnapshot:
+		output, err := a.DescribeSnapshotAttribute(&ec2.DescribeSnapshotAttributeInput{
+			SnapshotId: aws.String(r.ID),
+			Attribute:  aws.String(ec2.SnapshotAttributeNameCreateVolumePermission),
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to describe create volume permissions of snapshot %s", r.ID)
+		}
+		for _, p := range output.CreateVolumePermissions {
+			accounts = append(accounts, permittedAccount(p.UserId, p.Group))
+		}
+	default:
+		return nil, errors.Errorf("sharing is not supported for resource type: %s", r.Type)
+	}
+
+	sort.Strings(accounts)
+	return accounts, nil
+}
+
+// permittedAccount returns the account ID of a permission, or PublicAccess if the permission is granted to everyone.
+func permittedAccount(userID, group *string) string {
+	if aws.StringValue(group) == ec2.PermissionGroupAll {
+		return PublicAccess
+	}
+	return aws.StringValue(userID)
+}
+
+// Unshare removes the permissions of all other accounts on a resource, so that only the owner can use it.
+func (a *AWS) Unshare(r *Resource) error {
+	switch r.Type {
+	case Ami:
+		_, err := a.ResetImageAttribute(&ec2.ResetImageAttributeInput{
+			ImageId:   aws.String(r.ID),
+			Attribute: aws.String(ec2.ResetImageAttributeNameLaunchPermission),
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to remove launch permissions of AMI %s", r.ID)
+		}
+		return nil
+	case EbsSnapshot:
+		_, err := a.ResetSnapshotAttribute(&ec2.ResetSnapshotAttributeInput{
+			SnapshotId: aws.String(r.ID),
+			Attribute:  aws.String(ec2.SnapshotAttributeNameCreateVolumePermission),
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to remove create volume permissions of snapshot %s", r.ID)
+		}
+		return nil
+	}
+	return errors.Errorf("sharing is not supported for resource type: %s", r.Type)
+}
@@ -0,0 +1,86 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/resourcetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWS_SharedWith_Snapshot(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"ec2": {
+			"DescribeSnapshotAttribute": map[string]interface{}{
+				"CreateVolumePermissions": []interface{}{
+					map[string]interface{}{"UserId": "210987654321"},
+					map[string]interface{}{"Group": "all"},
+					map[string]interface{}{"UserId": "123456789012"},
+				},
+			},
+		},
+	})
+
+	// when
+	accounts, err := a.SharedWith(&resource.Resource{Type: resource.EbsSnapshot, ID: "snap-1"})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"123456789012", "210987654321", resource.PublicAccess}, accounts)
+}
+
+func TestAWS_SharedWith_Ami(t *testing.T) {
+	// given
+	a, b := resourcetest.NewAWS(resourcetest.Fixtures{
+		"ec2": {
+			"DescribeImageAttribute": map[string]interface{}{
+				"LaunchPermissions": []interface{}{
+					map[string]interface{}{"UserId": "210987654321"},
+				},
+			},
+		},
+	})
+	r := &resource.Resource{Type: resource.Ami, ID: "ami-1"}
+
+	// when
+	accounts, err := a.SharedWith(r)
+	require.NoError(t, err)
+	err = a.Unshare(r)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"210987654321"}, accounts)
+	assert.Equal(t, []string{"ec2:DescribeImageAttribute", "ec2:ResetImageAttribute"}, b.Calls())
+}
+
+func TestAWS_SharedWith_UnsupportedType(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{})
+
+	// when
+	_, err := a.SharedWith(&resource.Resource{Type: resource.Vpc, ID: "vpc-1"})
+
+	// then
+	assert.EqualError(t, err, "sharing is not supported for resource type: aws_vpc")
+}
+
+func TestFilter_Actions_RemoveSharing(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.EbsSnapshot: {},
+		},
+		RemoveSharing: true,
+	}
+
+	// when
+	listActions := f.Actions(true)
+	actions := f.Actions(false)
+
+	// then
+	assert.Contains(t, listActions, "ec2:DescribeSnapshotAttribute")
+	assert.NotContains(t, listActions, "ec2:ResetSnapshotAttribute")
+	assert.Contains(t, actions, "ec2:ResetSnapshotAttribute")
+}
@@ -0,0 +1,103 @@
+package resource
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+)
+
+const (
+	// FinalSnapshotVolumeTag is the tag key of final snapshots with the ID of the deleted volume.
+	FinalSnapshotVolumeTag = "awsweeper:volume-id"
+	// FinalSnapshotInstanceTag is the tag key of final snapshots with the ID of the instance the volume was attached to.
+	FinalSnapshotInstanceTag = "awsweeper:instance-id"
+	// FinalSnapshotDeviceTag is the tag key of final snapshots with the device name the volume was attached as.
+	FinalSnapshotDeviceTag = "awsweeper:device"
+	// FinalSnapshotDeletedTag is the tag key of final snapshots with the time the volume was deleted (RFC 3339).
+	FinalSnapshotDeletedTag = "awsweeper:deleted-at"
+)
+
+// finalSnapshotActions are required to snapshot volumes before deleting them.
+var finalSnapshotActions = []string{"ec2:CreateSnapshot", "ec2:CreateTags"}
+
+// validateFinalSnapshot checks whether final snapshots can be created of the resources of a type.
+func validateFinalSnapshot(resType TerraformResourceType, rtf ResourceTypeFilter) error {
+	if !rtf.FinalSnapshot {
+		return nil
+	}
+	if resType != EbsVolume {
+		return fmt.Errorf("final_snapshot is not supported for resource type: %s", resType)
+	}
+	if rtf.action() != ActionDelete {
+		return fmt.Errorf("final_snapshot requires action %s for resource type: %s", ActionDelete, resType)
+	}
+	return nil
+}
+
+// validateSkipFinalSnapshot checks whether the resources of a type are snapshotted when they are deleted,
+// so that the final snapshot can be skipped.
+func validateSkipFinalSnapshot(resType TerraformResourceType, rtf ResourceTypeFilter) error {
+	if rtf.SkipFinalSnapshot && resType != RedshiftCluster {
+		return fmt.Errorf("skip_final_snapshot is not supported for resource type: %s", resType)
+	}
+	return nil
+}
+
+// finalClusterSnapshotID returns the identifier of the final snapshot taken when a Redshift cluster is deleted.
+func finalClusterSnapshotID(clusterID string, deletedAt time.Time) string {
+	return fmt.Sprintf("%s-final-%s", clusterID, deletedAt.UTC().Format("20060102150405"))
+}
+
+// FinalSnapshots returns the resources that match a filter entry with final_snapshot,
+// i.e. the ones to snapshot before they are deleted.
+func (f Filter) FinalSnapshots(res Resources) Resources {
+	var result Resources
+
+	for _, r := range res {
+		if rtf, found := f.matchingEntry(r); found && rtf.FinalSnapshot {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// CreateFinalSnapshot snapshots a volume that is about to be deleted and returns the ID of the snapshot. The snapshot
+// is tagged with the ID of the volume, the instance and device it was attached to, and the time of deletion, so that
+// it can be traced back and expired by a later sweep (see FinalSnapshotFilter).
+func (a *AWS) CreateFinalSnapshot(r *Resource, deletedAt time.Time) (string, error) {
+	m, _ := r.Model.(VolumeModel)
+
+	tags := []*ec2.Tag{
+		{Key: aws.String(FinalSnapshotVolumeTag), Value: aws.String(r.ID)},
+		{Key: aws.String(FinalSnapshotDeletedTag), Value: aws.String(deletedAt.UTC().Format(time.RFC3339))},
+	}
+	if m.InstanceID != "" {
+		tags = append(tags,
+			&ec2.Tag{Key: aws.String(FinalSnapshotInstanceTag), Value: aws.String(m.InstanceID)},
+			&ec2.Tag{Key: aws.String(FinalSnapshotDeviceTag), Value: aws.String(m.Device)})
+	}
+
+	output, err := a.CreateSnapshot(&ec2.CreateSnapshotInput{
+		VolumeId:    aws.String(r.ID),
+		Description: aws.String("Final snapshot of " + r.ID + " created by AWSweeper"),
+		TagSpecifications: []*ec2.TagSpecification{
+			{ResourceType: aws.String(ec2.ResourceTypeSnapshot), Tags: tags},
+		},
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create final snapshot of volume %s", r.ID)
+	}
+	return aws.StringValue(output.SnapshotId), nil
+}
+
+// FinalSnapshotFilter returns a filter entry for EBS snapshots that selects the final snapshots
+// of deleted volumes, once they are older than the given age.
+func FinalSnapshotFilter(olderThan time.Duration) ResourceTypeFilter {
+	return ResourceTypeFilter{
+		Tags:    map[string]string{FinalSnapshotVolumeTag: "."},
+		Created: &Created{Before: aws.Time(time.Now().Add(-olderThan))},
+	}
+}
@@ -0,0 +1,136 @@
+package resource_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWS_CreateFinalSnapshot(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockEC2API(mockCtrl)
+	awsMock := &resource.AWS{
+		EC2API: mockObj,
+	}
+
+	r := &resource.Resource{
+		Type:  resource.EbsVolume,
+		ID:    "vol-1",
+		Model: resource.VolumeModel{InstanceID: "i-1", Device: "/dev/sdf"},
+	}
+
+	var tags map[string]string
+	mockObj.EXPECT().CreateSnapshot(gomock.Any()).DoAndReturn(func(input *ec2.CreateSnapshotInput) (*ec2.Snapshot, error) {
+		assert.Equal(t, "vol-1", *input.VolumeId)
+		tags = map[string]string{}
+		for _, t := range input.TagSpecifications[0].Tags {
+			tags[*t.Key] = *t.Value
+		}
+		return &ec2.Snapshot{SnapshotId: aws.String("snap-1")}, nil
+	})
+
+	// when
+	id, err := awsMock.CreateFinalSnapshot(r, time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "snap-1", id)
+	assert.Equal(t, map[string]string{
+		resource.FinalSnapshotVolumeTag:   "vol-1",
+		resource.FinalSnapshotInstanceTag: "i-1",
+		resource.FinalSnapshotDeviceTag:   "/dev/sdf",
+		resource.FinalSnapshotDeletedTag:  "2018-10-01T12:00:00Z",
+	}, tags)
+}
+
+func TestFilter_FinalSnapshots(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.EbsVolume: {
+				{ID: aws.String("^vol-keep"), FinalSnapshot: true},
+				{ID: aws.String("^vol-")},
+			},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	res := resource.Resources{
+		{Type: resource.EbsVolume, ID: "vol-keep1"},
+		{Type: resource.EbsVolume, ID: "vol-tmp1"},
+	}
+
+	// when
+	snapshots := f.FinalSnapshots(res)
+
+	// then
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, "vol-keep1", snapshots[0].ID)
+}
+
+func TestYamlFilter_Validate_FinalSnapshotUnsupportedType(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {{FinalSnapshot: true}},
+		},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.EqualError(t, err, "final_snapshot is not supported for resource type: aws_instance")
+}
+
+func TestFinalSnapshotFilter(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.EbsSnapshot: {resource.FinalSnapshotFilter(30 * 24 * time.Hour)},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	old := time.Now().Add(-31 * 24 * time.Hour)
+	recent := time.Now().Add(-24 * time.Hour)
+	res := resource.Resources{
+		{Type: resource.EbsSnapshot, ID: "snap-old", Created: &old,
+			Tags: map[string]string{resource.FinalSnapshotVolumeTag: "vol-1"}},
+		{Type: resource.EbsSnapshot, ID: "snap-recent", Created: &recent,
+			Tags: map[string]string{resource.FinalSnapshotVolumeTag: "vol-2"}},
+		{Type: resource.EbsSnapshot, ID: "snap-other", Created: &old, Tags: map[string]string{}},
+	}
+
+	// when
+	result := f.Apply(resource.EbsSnapshot, res, nil, nil)
+
+	// then
+	require.Len(t, result[0], 1)
+	assert.Equal(t, "snap-old", result[0][0].ID)
+}
+
+func TestYamlFilter_Validate_SkipFinalSnapshotUnsupportedType(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.RdsCluster: {{SkipFinalSnapshot: true}},
+		},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.EqualError(t, err, "skip_final_snapshot is not supported for resource type: aws_rds_cluster")
+}
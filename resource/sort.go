@@ -0,0 +1,118 @@
+package resource
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortKey is an attribute the resources in the output are sorted by.
+type SortKey string
+
+const (
+	// SortByType sorts resources by their Terraform type.
+	SortByType SortKey = "type"
+	// SortByRegion sorts resources by the region they are deleted in.
+	SortByRegion SortKey = "region"
+	// SortByCreated sorts resources by their creation time, the oldest first.
+	SortByCreated SortKey = "created"
+	// SortByID sorts resources by their ID.
+	SortByID SortKey = "id"
+)
+
+// SortOrder are the keys the resources in the output are sorted by, the most significant one first.
+// A nil sort order is the default one.
+type SortOrder []SortKey
+
+// DefaultSortOrder sorts resources by type, then region, then creation time.
+var DefaultSortOrder = SortOrder{SortByType, SortByRegion, SortByCreated}
+
+// ParseSortOrder parses a sort order given as comma-separated keys (e.g., "region,type,created").
+func ParseSortOrder(s string) (SortOrder, error) {
+	var o SortOrder
+	seen := map[SortKey]bool{}
+
+	for _, k := range strings.Split(s, ",") {
+		key := SortKey(strings.TrimSpace(k))
+		switch key {
+		case SortByType, SortByRegion, SortByCreated, SortByID:
+		default:
+			return nil, fmt.Errorf("invalid sort key: %s (must be one of type, region, created or id)", k)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate sort key: %s", key)
+		}
+		seen[key] = true
+		o = append(o, key)
+	}
+	return o, nil
+}
+
+// keys returns the keys of the sort order (the ones of the default sort order, if nil).
+func (o SortOrder) keys() SortOrder {
+	if len(o) == 0 {
+		return DefaultSortOrder
+	}
+	return o
+}
+
+// TypesFirst checks whether the output is grouped by resource type before region, which is the case
+// unless the region is sorted by before the type.
+func (o SortOrder) TypesFirst() bool {
+	for _, k := range o.keys() {
+		switch k {
+		case SortByType:
+			return true
+		case SortByRegion:
+			return false
+		}
+	}
+	return true
+}
+
+// Less checks whether resource a (listed in region regA) is sorted before resource b (listed in region regB).
+// Resources without creation time are sorted after the ones with one, and resources that are equal by all keys
+// are sorted by ID, so that the order is the same across runs.
+func (o SortOrder) Less(a *Resource, regA string, b *Resource, regB string) bool {
+	if a.Region != "" {
+		regA = a.Region
+	}
+	if b.Region != "" {
+		regB = b.Region
+	}
+
+	for _, k := range o.keys() {
+		switch k {
+		case SortByType:
+			if a.Type != b.Type {
+				return a.Type < b.Type
+			}
+		case SortByRegion:
+			if regA != regB {
+				return regA < regB
+			}
+		case SortByCreated:
+			if a.Created == nil || b.Created == nil {
+				if a.Created != b.Created {
+					return b.Created == nil
+				}
+				continue
+			}
+			if !a.Created.Equal(*b.Created) {
+				return a.Created.Before(*b.Created)
+			}
+		case SortByID:
+			if a.ID != b.ID {
+				return a.ID < b.ID
+			}
+		}
+	}
+	return a.ID < b.ID
+}
+
+// Sort sorts the resources listed in a region.
+func (o SortOrder) Sort(res Resources, region string) {
+	sort.SliceStable(res, func(i, j int) bool {
+		return o.Less(res[i], region, res[j], region)
+	})
+}
@@ -0,0 +1,66 @@
+package resource_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSortOrder(t *testing.T) {
+	o, err := resource.ParseSortOrder("region, type,created")
+	require.NoError(t, err)
+	assert.Equal(t, resource.SortOrder{resource.SortByRegion, resource.SortByType, resource.SortByCreated}, o)
+	assert.False(t, o.TypesFirst())
+
+	assert.True(t, resource.SortOrder(nil).TypesFirst())
+	assert.True(t, resource.SortOrder{resource.SortByCreated}.TypesFirst())
+}
+
+func TestParseSortOrder_Invalid(t *testing.T) {
+	for _, s := range []string{"", "name", "type,type", "type,,region"} {
+		_, err := resource.ParseSortOrder(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestSortOrder_Sort(t *testing.T) {
+	// given
+	older := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	res := resource.Resources{
+		{Type: resource.S3Bucket, ID: "no-creation-time"},
+		{Type: resource.S3Bucket, ID: "newer", Created: &newer},
+		{Type: resource.S3Bucket, ID: "other-region", Created: &older, Region: "us-west-2"},
+		{Type: resource.S3Bucket, ID: "b-older", Created: &older},
+		{Type: resource.S3Bucket, ID: "a-older", Created: &older},
+	}
+
+	// when
+	resource.SortOrder(nil).Sort(res, "eu-west-1")
+
+	// then
+	var ids []string
+	for _, r := range res {
+		ids = append(ids, r.ID)
+	}
+	assert.Equal(t, []string{"a-older", "b-older", "newer", "no-creation-time", "other-region"}, ids)
+}
+
+func TestSortOrder_Sort_ByID(t *testing.T) {
+	// given
+	older := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	res := resource.Resources{
+		{Type: resource.Instance, ID: "i-2", Created: &older},
+		{Type: resource.Instance, ID: "i-1"},
+	}
+
+	// when
+	resource.SortOrder{resource.SortByID}.Sort(res, "eu-west-1")
+
+	// then
+	assert.Equal(t, "i-1", res[0].ID)
+	assert.Equal(t, "i-2", res[1].ID)
+}
@@ -0,0 +1,250 @@
+package resource
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+)
+
+// Spec defines a resource type that is listed and deleted via plain API operations (without the Terraform
+// provider), so that simple resource types can be added via a spec file instead of code.
+type Spec struct {
+	// Type is the name of the resource type as used in the config
+	Type TerraformResourceType
+	// Service is the name of the AWS API (e.g., ec2 or sqs), which must be one AWSweeper has a client for
+	Service string
+	// ListOperation is the name of the operation listing all resources (e.g., DescribeVpcs)
+	ListOperation string `yaml:"list_operation"`
+	// OutputPath is the field of the output of the list operation that contains the resources (e.g., Vpcs)
+	OutputPath string `yaml:"output_path"`
+	// IDField is the field of a listed resource that contains its ID (empty if resources are listed as plain strings)
+	IDField string `yaml:"id_field,omitempty"`
+	// TagPath is the field of a listed resource that contains its tags as a list of keys and values (default: Tags)
+	TagPath string `yaml:"tag_path,omitempty"`
+	// DeleteOperation is the name of the operation deleting a resource by its ID (e.g., DeleteVpc)
+	DeleteOperation string `yaml:"delete_operation"`
+	// DeleteInputField is the field of the input of the delete operation the ID is set to (default: IDField)
+	DeleteInputField string `yaml:"delete_input_field,omitempty"`
+	// Global is true if resources don't belong to a particular region
+	Global bool `yaml:",omitempty"`
+}
+
+var (
+	// specs are the resource types loaded from spec files
+	specs = map[TerraformResourceType]Spec{}
+
+	// serviceActionPrefixes are the prefixes of IAM actions of services that differ from the name of the API
+	serviceActionPrefixes = map[string]string{
+		"cloudwatchevents":         "events",
+		"configservice":            "config",
+		"elb":                      "elasticloadbalancing",
+		"resourcegroupstaggingapi": "tag",
+	}
+)
+
+// LoadSpecs reads the resource types defined in a spec file (YAML or JSON) and makes them supported.
+func LoadSpecs(filename string) error {
+	data, err := afero.ReadFile(AppFs, filename)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read spec file: %s", filename)
+	}
+
+	var ss []Spec
+	if err := yaml.UnmarshalStrict(data, &ss); err != nil {
+		return errors.Wrapf(err, "cannot unmarshal spec file: %s", filename)
+	}
+
+	for _, s := range ss {
+		if err := registerSpec(s); err != nil {
+			return errors.Wrapf(err, "invalid spec in %s", filename)
+		}
+	}
+	return nil
+}
+
+// registerSpec checks that the operations and fields of a spec exist and adds the resource type to the supported ones.
+func registerSpec(s Spec) error {
+	if s.Type == "" {
+		return errors.New("spec without type")
+	}
+	if _, found := deleteIDs[s.Type]; found {
+		if _, isSpec := specs[s.Type]; !isSpec {
+			return errors.Errorf("resource type is already supported: %s", s.Type)
+		}
+	}
+
+	client, found := serviceClientType(s.Service)
+	if !found {
+		return errors.Errorf("unknown service of resource type %s: %s", s.Type, s.Service)
+	}
+
+	list, found := client.MethodByName(s.ListOperation)
+	if !found {
+		return errors.Errorf("unknown list operation of resource type %s: %s", s.Type, s.ListOperation)
+	}
+	output, found := list.Type.Out(0).Elem().FieldByName(s.OutputPath)
+	if !found || output.Type.Kind() != reflect.Slice {
+		return errors.Errorf("output of %s has no list %s", s.ListOperation, s.OutputPath)
+	}
+
+	var raw interface{} = ""
+	elem := output.Type.Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() == reflect.Struct {
+		if _, found := elem.FieldByName(s.IDField); !found {
+			return errors.Errorf("resources of type %s have no ID field %s", s.Type, s.IDField)
+		}
+		raw = reflect.New(elem).Elem().Interface()
+	} else if elem.Kind() != reflect.String || s.IDField != "" {
+		return errors.Errorf("resources of type %s are listed as %s, which has no ID field %s", s.Type, elem, s.IDField)
+	}
+
+	del, found := client.MethodByName(s.DeleteOperation)
+	if !found {
+		return errors.Errorf("unknown delete operation of resource type %s: %s", s.Type, s.DeleteOperation)
+	}
+	if _, found := del.Type.In(0).Elem().FieldByName(s.deleteInputField()); !found {
+		return errors.Errorf("input of %s has no field %s", s.DeleteOperation, s.deleteInputField())
+	}
+
+	specs[s.Type] = s
+	deleteIDs[s.Type] = s.IDField
+	rawResourceTypes[s.Type] = raw
+	globalTypes[s.Type] = s.Global
+	batchDeleters[s.Type] = batchDeleter{
+		size: 1,
+		delete: func(a *AWS, ids []string) error {
+			return a.specDelete(s, ids[0])
+		},
+	}
+	permissions[s.Type] = Permissions{
+		List:   []string{s.action(s.ListOperation)},
+		Delete: []string{s.action(s.DeleteOperation)},
+	}
+	return nil
+}
+
+func (s Spec) deleteInputField() string {
+	if s.DeleteInputField == "" {
+		return s.IDField
+	}
+	return s.DeleteInputField
+}
+
+// action returns the IAM action needed to call an operation of the API of the spec.
+func (s Spec) action(operation string) string {
+	prefix, found := serviceActionPrefixes[s.Service]
+	if !found {
+		prefix = s.Service
+	}
+	return fmt.Sprintf("%s:%s", prefix, operation)
+}
+
+// serviceClientType returns the type of the client the AWS struct has for an API (e.g., ec2iface.EC2API for ec2).
+func serviceClientType(service string) (reflect.Type, bool) {
+	t := reflect.TypeOf(AWS{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if strings.ToLower(strings.TrimSuffix(f.Name, "API")) == service {
+			return f.Type, true
+		}
+	}
+	return nil, false
+}
+
+// serviceClient returns the client the AWS struct has for an API.
+func (a *AWS) serviceClient(service string) (reflect.Value, error) {
+	v := reflect.ValueOf(a).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if strings.ToLower(strings.TrimSuffix(v.Type().Field(i).Name, "API")) == service {
+			if v.Field(i).IsNil() {
+				return reflect.Value{}, errors.Errorf("no client for service: %s", service)
+			}
+			return v.Field(i), nil
+		}
+	}
+	return reflect.Value{}, errors.Errorf("unknown service: %s", service)
+}
+
+// call calls an operation of an API with the given input and returns its output.
+func call(client reflect.Value, operation string, input reflect.Value) (reflect.Value, error) {
+	out := client.MethodByName(operation).Call([]reflect.Value{input})
+	if err, _ := out[1].Interface().(error); err != nil {
+		return reflect.Value{}, err
+	}
+	return out[0], nil
+}
+
+// specResources lists the resources of a type defined by a spec. Further pages are requested
+// as long as the output contains a NextToken or NextMarker.
+func (a *AWS) specResources(s Spec) (interface{}, error) {
+	client, err := a.serviceClient(s.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	method := client.MethodByName(s.ListOperation)
+	input := reflect.New(method.Type().In(0).Elem())
+
+	var result reflect.Value
+	for {
+		output, err := call(client, s.ListOperation, input)
+		if err != nil {
+			return nil, err
+		}
+
+		page := output.Elem().FieldByName(s.OutputPath)
+		if !result.IsValid() {
+			result = reflect.MakeSlice(page.Type(), 0, page.Len())
+		}
+		result = reflect.AppendSlice(result, page)
+
+		if !nextPage(input.Elem(), output.Elem()) {
+			return result.Interface(), nil
+		}
+	}
+}
+
+// nextPage sets the pagination token of the output of a list operation on its input,
+// and returns false if there are no more pages.
+func nextPage(input, output reflect.Value) bool {
+	for out, in := range map[string]string{"NextToken": "NextToken", "NextMarker": "Marker"} {
+		token := output.FieldByName(out)
+		field := input.FieldByName(in)
+		if !token.IsValid() || !field.IsValid() || token.IsNil() || aws.StringValue(token.Interface().(*string)) == "" {
+			continue
+		}
+		field.Set(token)
+		return true
+	}
+	return false
+}
+
+// specDelete deletes a resource of a type defined by a spec.
+func (a *AWS) specDelete(s Spec, id string) error {
+	client, err := a.serviceClient(s.Service)
+	if err != nil {
+		return err
+	}
+
+	input := reflect.New(client.MethodByName(s.DeleteOperation).Type().In(0).Elem())
+	input.Elem().FieldByName(s.deleteInputField()).Set(reflect.ValueOf(aws.String(id)))
+
+	_, err = call(client, s.DeleteOperation, input)
+	return err
+}
+
+// tagFieldNamesOf returns the names of the fields that can contain the tags of resources of a type.
+func tagFieldNamesOf(resType TerraformResourceType) []string {
+	if s, found := specs[resType]; found && s.TagPath != "" {
+		return []string{s.TagPath}
+	}
+	return tagFieldNames
+}
@@ -0,0 +1,128 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSpecs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	resource.AppFs = afero.NewMemMapFs()
+	afero.WriteFile(resource.AppFs, "specs.yml", []byte(`
+- type: custom_nat_gateway
+  service: ec2
+  list_operation: DescribeNatGateways
+  output_path: NatGateways
+  id_field: NatGatewayId
+  delete_operation: DeleteNatGateway
+`), 0644)
+
+	mockObj := mocks.NewMockEC2API(mockCtrl)
+	awsMock := &resource.AWS{
+		EC2API: mockObj,
+	}
+
+	mockObj.EXPECT().DescribeNatGateways(&ec2.DescribeNatGatewaysInput{}).Return(
+		&ec2.DescribeNatGatewaysOutput{
+			NatGateways: []*ec2.NatGateway{
+				{
+					NatGatewayId: aws.String("nat-0123"),
+					Tags:         []*ec2.Tag{{Key: aws.String("foo"), Value: aws.String("bar")}},
+				},
+			},
+			NextToken: aws.String("next"),
+		}, nil)
+	mockObj.EXPECT().DescribeNatGateways(&ec2.DescribeNatGatewaysInput{
+		NextToken: aws.String("next"),
+	}).Return(
+		&ec2.DescribeNatGatewaysOutput{
+			NatGateways: []*ec2.NatGateway{
+				{NatGatewayId: aws.String("nat-4567")},
+			},
+		}, nil)
+	mockObj.EXPECT().DeleteNatGateway(&ec2.DeleteNatGatewayInput{
+		NatGatewayId: aws.String("nat-0123"),
+	}).Return(&ec2.DeleteNatGatewayOutput{}, nil)
+
+	resType := resource.TerraformResourceType("custom_nat_gateway")
+
+	// when
+	err := resource.LoadSpecs("specs.yml")
+
+	// then
+	require.NoError(t, err)
+	assert.True(t, resource.SupportedResourceType(resType))
+	assert.True(t, resource.SupportsBatchDelete(resType))
+	assert.Equal(t, resource.Capabilities{Tags: true, Created: true}, resource.TypeCapabilities(resType))
+
+	raw, err := awsMock.RawResources(resType)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resType, raw)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.Equal(t, "nat-0123", res[0].ID)
+	assert.Equal(t, map[string]string{"foo": "bar"}, res[0].Tags)
+
+	assert.Empty(t, awsMock.BatchDelete(res[:1]))
+}
+
+func TestLoadSpecs_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown service": `
+- type: aws_thing
+  service: unknown
+  list_operation: ListThings
+  output_path: Things
+  id_field: Id
+  delete_operation: DeleteThing
+`,
+		"unknown list operation": `
+- type: aws_thing
+  service: ec2
+  list_operation: DescribeThings
+  output_path: Things
+  id_field: Id
+  delete_operation: DeleteThing
+`,
+		"unknown ID field": `
+- type: aws_thing
+  service: ec2
+  list_operation: DescribeVpcPeeringConnections
+  output_path: VpcPeeringConnections
+  id_field: Id
+  delete_operation: DeleteVpcPeeringConnection
+`,
+		"already supported": `
+- type: aws_vpc
+  service: ec2
+  list_operation: DescribeVpcs
+  output_path: Vpcs
+  id_field: VpcId
+  delete_operation: DeleteVpc
+`,
+	}
+
+	for name, spec := range tests {
+		// given
+		resource.AppFs = afero.NewMemMapFs()
+		afero.WriteFile(resource.AppFs, "specs.yml", []byte(spec), 0644)
+
+		// when
+		err := resource.LoadSpecs("specs.yml")
+
+		// then
+		assert.Error(t, err, name)
+		assert.False(t, resource.SupportedResourceType("aws_thing"), name)
+	}
+}
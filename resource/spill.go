@@ -0,0 +1,135 @@
+package resource
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// SpillChunkSize is the number of spilled resources that are filtered and deleted at once.
+const SpillChunkSize = 1000
+
+// Spill is a temporary file the listed resources of a type are written to, so that the resources of big accounts
+// (e.g., millions of snapshots) can be filtered and deleted in chunks instead of keeping all of them in memory.
+type Spill struct {
+	file afero.File
+	// elemType is the type of the listed resources (e.g., *ec2.Snapshot)
+	elemType reflect.Type
+	n        int
+}
+
+// Spillable returns whether the resources of a type are listed page by page into a spill file (see
+// SpillRawResources), as there can be a huge number of them. The resources of other types are listed at once
+// and kept in memory while sweeping.
+func Spillable(resType TerraformResourceType) bool {
+	switch resType {
+	case CloudwatchLogStream, DbSnapshot, EbsSnapshot:
+		return true
+	}
+	return false
+}
+
+// rawResourcePages lists the resources of a spillable type like RawResourcesWithTags, but passes them
+// a page at a time to fn.
+func (a *AWS) rawResourcePages(resType TerraformResourceType, tags map[string][]string,
+	fn func(page interface{}) error) error {
+	switch resType {
+	case CloudwatchLogStream:
+		return a.cloudwatchLogStreamPages(fn)
+	case DbSnapshot:
+		return a.dbSnapshotPages(fn)
+	case EbsSnapshot:
+		var filters []*ec2.Filter
+		if len(tags) > 0 {
+			filters = ec2TagFilters(tags)
+		}
+		return a.ebsSnapshotPages(filters, fn)
+	}
+	return errors.Errorf("resource type is not spillable: %s", resType)
+}
+
+// SpillRawResources lists the resources of a spillable type (like RawResourcesWithTags) into a temporary file
+// in dir. The file is removed when the spill is closed.
+func (a *AWS) SpillRawResources(dir string, resType TerraformResourceType, tags map[string][]string) (*Spill, error) {
+	f, err := afero.TempFile(AppFs, dir, "awsweeper-"+string(resType)+"-")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create spill file")
+	}
+	s := &Spill{file: f}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	err = a.rawResourcePages(resType, tags, func(page interface{}) error {
+		v := reflect.ValueOf(page)
+		s.elemType = v.Type().Elem()
+		for i := 0; i < v.Len(); i++ {
+			if err := enc.Encode(v.Index(i).Interface()); err != nil {
+				return errors.Wrapf(err, "failed to spill %s resources to %s", resType, f.Name())
+			}
+			s.n++
+		}
+		return nil
+	})
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Len returns the number of spilled resources.
+func (s *Spill) Len() int {
+	return s.n
+}
+
+// Chunks calls fn with the spilled resources in chunks of at most size resources (in the order they have been
+// listed). Each chunk is a slice of the same type as the ones returned by RawResources.
+func (s *Spill) Chunks(size int, fn func(raw interface{}) error) error {
+	if s.n == 0 {
+		return nil
+	}
+
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return errors.Wrapf(err, "failed to read spill file %s", s.file.Name())
+	}
+	dec := json.NewDecoder(bufio.NewReader(s.file))
+
+	chunk := reflect.MakeSlice(reflect.SliceOf(s.elemType), 0, size)
+	for i := 0; i < s.n; i++ {
+		elem := reflect.New(s.elemType)
+		if err := dec.Decode(elem.Interface()); err != nil {
+			return errors.Wrapf(err, "failed to read spill file %s", s.file.Name())
+		}
+		chunk = reflect.Append(chunk, elem.Elem())
+
+		if chunk.Len() == size {
+			if err := fn(chunk.Interface()); err != nil {
+				return err
+			}
+			chunk = reflect.MakeSlice(reflect.SliceOf(s.elemType), 0, size)
+		}
+	}
+
+	if chunk.Len() > 0 {
+		return fn(chunk.Interface())
+	}
+	return nil
+}
+
+// Close removes the spill file.
+func (s *Spill) Close() error {
+	name := s.file.Name()
+	s.file.Close()
+	if err := AppFs.Remove(name); err != nil {
+		return errors.Wrapf(err, "failed to remove spill file %s", name)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/resourcetest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWS_SpillRawResources(t *testing.T) {
+	// given
+	resource.AppFs = afero.NewMemMapFs()
+	require.NoError(t, resource.AppFs.MkdirAll("/spill", 0755))
+
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"sts": {
+			"GetCallerIdentity": map[string]interface{}{"Account": "123456789012"},
+		},
+		"ec2": {
+			"DescribeSnapshots": map[string]interface{}{
+				"Snapshots": []interface{}{
+					map[string]interface{}{"SnapshotId": "snap-1", "VolumeSize": 8, "StartTime": "2018-10-01T00:00:00Z"},
+					map[string]interface{}{"SnapshotId": "snap-2", "VolumeSize": 8},
+					map[string]interface{}{
+						"SnapshotId": "snap-3",
+						"Tags": []interface{}{
+							map[string]interface{}{"Key": "Name", "Value": "ci-3"},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	// when
+	spill, err := a.SpillRawResources("/spill", resource.EbsSnapshot, nil)
+	require.NoError(t, err)
+
+	var chunks []resource.Resources
+	err = spill.Chunks(2, func(raw interface{}) error {
+		assert.IsType(t, []*ec2.Snapshot{}, raw)
+		res, err := resource.DeletableResources(resource.EbsSnapshot, raw)
+		require.NoError(t, err)
+		chunks = append(chunks, res)
+		return nil
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 3, spill.Len())
+	require.Len(t, chunks, 2)
+	require.Len(t, chunks[0], 2)
+	assert.Equal(t, "snap-1", chunks[0][0].ID)
+	assert.NotNil(t, chunks[0][0].Created)
+	assert.Equal(t, int64(8), chunks[0][0].Model.(resource.SnapshotModel).SizeGB)
+	require.Len(t, chunks[1], 1)
+	assert.Equal(t, map[string]string{"Name": "ci-3"}, chunks[1][0].Tags)
+
+	files, err := afero.ReadDir(resource.AppFs, "/spill")
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	require.NoError(t, spill.Close())
+	files, err = afero.ReadDir(resource.AppFs, "/spill")
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestSpillable(t *testing.T) {
+	assert.True(t, resource.Spillable(resource.EbsSnapshot))
+	assert.True(t, resource.Spillable(resource.CloudwatchLogStream))
+	assert.False(t, resource.Spillable(resource.Instance))
+}
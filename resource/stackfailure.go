@@ -0,0 +1,125 @@
+package resource
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/pkg/errors"
+)
+
+// stackResourceTypes map the CloudFormation types of stack resources to supported resource types,
+// whose IDs are the physical IDs of the stack resources.
+var stackResourceTypes = map[string]TerraformResourceType{
+	"AWS::AutoScaling::AutoScalingGroup":        AutoscalingGroup,
+	"AWS::AutoScaling::LaunchConfiguration":     LaunchConfiguration,
+	"AWS::DynamoDB::Table":                      DynamodbTable,
+	"AWS::EC2::Instance":                        Instance,
+	"AWS::EC2::InternetGateway":                 InternetGateway,
+	"AWS::EC2::NatGateway":                      NatGateway,
+	"AWS::EC2::NetworkAcl":                      NetworkAcl,
+	"AWS::EC2::NetworkInterface":                NetworkInterface,
+	"AWS::EC2::RouteTable":                      RouteTable,
+	"AWS::EC2::SecurityGroup":                   SecurityGroup,
+	"AWS::EC2::Subnet":                          Subnet,
+	"AWS::EC2::Volume":                          EbsVolume,
+	"AWS::EC2::VPC":                             Vpc,
+	"AWS::EC2::VPCEndpoint":                     VpcEndpoint,
+	"AWS::ECS::Cluster":                         EcsCluster,
+	"AWS::EFS::FileSystem":                      EfsFileSystem,
+	"AWS::ElasticLoadBalancing::LoadBalancer":   Elb,
+	"AWS::ElasticLoadBalancingV2::LoadBalancer": Lb,
+	"AWS::ElasticLoadBalancingV2::TargetGroup":  LbTargetGroup,
+	"AWS::IAM::InstanceProfile":                 IamInstanceProfile,
+	"AWS::IAM::ManagedPolicy":                   IamPolicy,
+	"AWS::IAM::Role":                            IamRole,
+	"AWS::IAM::User":                            IamUser,
+	"AWS::KMS::Key":                             KmsKey,
+	"AWS::Lambda::Function":                     LambdaFunction,
+	"AWS::Logs::LogGroup":                       CloudwatchLogGroup,
+	"AWS::Route53::HostedZone":                  Route53Zone,
+	"AWS::S3::Bucket":                           S3Bucket,
+	"AWS::SNS::Topic":                           SnsTopic,
+	"AWS::SQS::Queue":                           SqsQueue,
+}
+
+// stackType is the CloudFormation type of stacks, as reported in the events of their own status.
+const stackType = "AWS::CloudFormation::Stack"
+
+// StackFailure is a resource of a stack that failed to be deleted, so that the stack ended up in DELETE_FAILED.
+type StackFailure struct {
+	LogicalID  string
+	PhysicalID string
+	// Type is the CloudFormation type of the resource (e.g., AWS::EC2::SecurityGroup)
+	Type   string
+	Reason string
+	// Resource is the resource to delete directly (nil, if its type isn't supported)
+	Resource *Resource
+}
+
+// StackFailures returns the resources that failed to be deleted during the latest attempt to delete a stack,
+// according to the events of the stack.
+func (a *AWS) StackFailures(stackID string) ([]StackFailure, error) {
+	var failures []StackFailure
+
+	// events are returned newest first, so the ones of the latest deletion come before its start
+	err := a.DescribeStackEventsPages(&cloudformation.DescribeStackEventsInput{
+		StackName: aws.String(stackID),
+	}, func(page *cloudformation.DescribeStackEventsOutput, lastPage bool) bool {
+		for _, e := range page.StackEvents {
+			status := aws.StringValue(e.ResourceStatus)
+			if aws.StringValue(e.ResourceType) == stackType {
+				if status == cloudformation.ResourceStatusDeleteInProgress {
+					return false
+				}
+				continue
+			}
+			if status != cloudformation.ResourceStatusDeleteFailed {
+				continue
+			}
+
+			f := StackFailure{
+				LogicalID:  aws.StringValue(e.LogicalResourceId),
+				PhysicalID: aws.StringValue(e.PhysicalResourceId),
+				Type:       aws.StringValue(e.ResourceType),
+				Reason:     aws.StringValue(e.ResourceStatusReason),
+			}
+			if resType, found := stackResourceTypes[f.Type]; found && f.PhysicalID != "" {
+				f.Resource = &Resource{Type: resType, ID: f.PhysicalID}
+			}
+			failures = append(failures, f)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe events of stack %s", stackID)
+	}
+	return failures, nil
+}
+
+// BlockingResources returns the (supported) resources of a stack that failed to be deleted, which are to be deleted
+// directly before the deletion of the stack is retried. This is only the case if the stack has been selected by a
+// filter entry with delete_blocking_resources. Blocking resources that are allowlisted are never deleted.
+func (f Filter) BlockingResources(stack *Resource, failures []StackFailure) Resources {
+	rtf, found := f.matchingEntry(stack)
+	if !found || !rtf.DeleteBlockingResources {
+		return nil
+	}
+
+	var result Resources
+	for _, failure := range failures {
+		if failure.Resource != nil && !f.allowlisted(failure.Resource) {
+			result = append(result, failure.Resource)
+		}
+	}
+	return result
+}
+
+// validateDeleteBlockingResources checks whether resources blocking the deletion can be deleted
+// for the resources of a type.
+func validateDeleteBlockingResources(resType TerraformResourceType, rtf ResourceTypeFilter) error {
+	if rtf.DeleteBlockingResources && resType != CloudformationStack {
+		return fmt.Errorf("delete_blocking_resources is not supported for resource type: %s", resType)
+	}
+	return nil
+}
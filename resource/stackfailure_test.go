@@ -0,0 +1,110 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/resourcetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testStackID = "arn:aws:cloudformation:us-east-1:123456789012:stack/ci/5f3d1e50-c8a0-11e8-a8d5-f2801f1b9fd1"
+
+func testStackEvents() resourcetest.Fixtures {
+	return resourcetest.Fixtures{
+		"cloudformation": {
+			"DescribeStackEvents": map[string]interface{}{
+				"StackEvents": []interface{}{
+					map[string]interface{}{"ResourceType": "AWS::CloudFormation::Stack", "LogicalResourceId": "ci",
+						"ResourceStatus": "DELETE_FAILED"},
+					map[string]interface{}{"ResourceType": "AWS::EC2::SecurityGroup", "LogicalResourceId": "WebSG",
+						"PhysicalResourceId": "sg-1", "ResourceStatus": "DELETE_FAILED",
+						"ResourceStatusReason": "resource sg-1 has a dependent object"},
+					map[string]interface{}{"ResourceType": "Custom::Cleanup", "LogicalResourceId": "Cleanup",
+						"PhysicalResourceId": "cleanup-1", "ResourceStatus": "DELETE_FAILED"},
+					map[string]interface{}{"ResourceType": "AWS::SQS::Queue", "LogicalResourceId": "Queue",
+						"PhysicalResourceId": "https://sqs.us-east-1.amazonaws.com/123456789012/ci", "ResourceStatus": "DELETE_COMPLETE"},
+					map[string]interface{}{"ResourceType": "AWS::CloudFormation::Stack", "LogicalResourceId": "ci",
+						"ResourceStatus": "DELETE_IN_PROGRESS"},
+					// events of a previous deletion
+					map[string]interface{}{"ResourceType": "AWS::EC2::Subnet", "LogicalResourceId": "Subnet",
+						"PhysicalResourceId": "subnet-1", "ResourceStatus": "DELETE_FAILED"},
+				},
+			},
+		},
+	}
+}
+
+func TestAWS_StackFailures(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(testStackEvents())
+
+	// when
+	failures, err := a.StackFailures(testStackID)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, failures, 2)
+	assert.Equal(t, "WebSG", failures[0].LogicalID)
+	assert.Equal(t, "resource sg-1 has a dependent object", failures[0].Reason)
+	assert.Equal(t, &resource.Resource{Type: resource.SecurityGroup, ID: "sg-1"}, failures[0].Resource)
+	// resources of unsupported types are only reported
+	assert.Equal(t, "Custom::Cleanup", failures[1].Type)
+	assert.Nil(t, failures[1].Resource)
+}
+
+func TestFilter_BlockingResources(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(testStackEvents())
+	failures, err := a.StackFailures(testStackID)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.CloudformationStack: {{DeleteBlockingResources: true}},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	blocking := f.BlockingResources(&resource.Resource{Type: resource.CloudformationStack, ID: testStackID}, failures)
+
+	// then
+	require.Len(t, blocking, 1)
+	assert.Equal(t, "sg-1", blocking[0].ID)
+}
+
+func TestFilter_BlockingResources_NotConfigured(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.CloudformationStack: {},
+		},
+	}
+	failures := []resource.StackFailure{
+		{Type: "AWS::EC2::SecurityGroup", PhysicalID: "sg-1",
+			Resource: &resource.Resource{Type: resource.SecurityGroup, ID: "sg-1"}},
+	}
+
+	// when
+	blocking := f.BlockingResources(&resource.Resource{Type: resource.CloudformationStack, ID: testStackID}, failures)
+
+	// then
+	assert.Empty(t, blocking)
+}
+
+func TestYamlFilter_Validate_DeleteBlockingResourcesUnsupportedType(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Vpc: {{DeleteBlockingResources: true}},
+		},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.EqualError(t, err, "delete_blocking_resources is not supported for resource type: aws_vpc")
+}
@@ -1,31 +1,88 @@
 package resource
 
 import (
-	"log"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/apigateway/apigatewayiface"
+	"github.com/aws/aws-sdk-go/service/athena"
+	"github.com/aws/aws-sdk-go/service/athena/athenaiface"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/cloudfront/cloudfrontiface"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"github.com/aws/aws-sdk-go/service/cloudtrail/cloudtrailiface"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+	"github.com/aws/aws-sdk-go/service/cloudwatchevents"
+	"github.com/aws/aws-sdk-go/service/cloudwatchevents/cloudwatcheventsiface"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"github.com/aws/aws-sdk-go/service/cognitoidentity"
+	"github.com/aws/aws-sdk-go/service/cognitoidentity/cognitoidentityiface"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider/cognitoidentityprovideriface"
+	"github.com/aws/aws-sdk-go/service/configservice"
+	"github.com/aws/aws-sdk-go/service/configservice/configserviceiface"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
 	"github.com/aws/aws-sdk-go/service/efs"
 	"github.com/aws/aws-sdk-go/service/efs/efsiface"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk/elasticbeanstalkiface"
 	"github.com/aws/aws-sdk-go/service/elb"
 	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/firehose/firehoseiface"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/glue/glueiface"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kinesis/kinesisiface"
 	"github.com/aws/aws-sdk-go/service/kms"
 	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+	"github.com/aws/aws-sdk-go/service/redshift"
+	"github.com/aws/aws-sdk-go/service/redshift/redshiftiface"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi/resourcegroupstaggingapiiface"
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/aws/aws-sdk-go/service/route53/route53iface"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/ses"
+	"github.com/aws/aws-sdk-go/service/ses/sesiface"
+	"github.com/aws/aws-sdk-go/service/sfn"
+	"github.com/aws/aws-sdk-go/service/sfn/sfniface"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/aws/aws-sdk-go/service/support"
+	"github.com/aws/aws-sdk-go/service/support/supportiface"
+	"github.com/cloudetc/awsweeper/internal/arn"
 	"github.com/go-errors/errors"
 )
 
@@ -33,68 +90,196 @@ import (
 type TerraformResourceType string
 
 const (
-	Ami                 TerraformResourceType = "aws_ami"
-	AutoscalingGroup    TerraformResourceType = "aws_autoscaling_group"
-	CloudformationStack TerraformResourceType = "aws_cloudformation_stack"
-	EbsSnapshot         TerraformResourceType = "aws_ebs_snapshot"
-	EbsVolume           TerraformResourceType = "aws_ebs_volume"
-	EfsFileSystem       TerraformResourceType = "aws_efs_file_system"
-	Eip                 TerraformResourceType = "aws_eip"
-	Elb                 TerraformResourceType = "aws_elb"
-	IamGroup            TerraformResourceType = "aws_iam_group"
-	IamInstanceProfile  TerraformResourceType = "aws_iam_instance_profile"
-	IamPolicy           TerraformResourceType = "aws_iam_policy"
-	IamRole             TerraformResourceType = "aws_iam_role"
-	IamUser             TerraformResourceType = "aws_iam_user"
-	Instance            TerraformResourceType = "aws_instance"
-	InternetGateway     TerraformResourceType = "aws_internet_gateway"
-	KeyPair             TerraformResourceType = "aws_key_pair"
-	KmsAlias            TerraformResourceType = "aws_kms_alias"
-	KmsKey              TerraformResourceType = "aws_kms_key"
-	LaunchConfiguration TerraformResourceType = "aws_launch_configuration"
-	NatGateway          TerraformResourceType = "aws_nat_gateway"
-	NetworkAcl          TerraformResourceType = "aws_network_acl"
-	NetworkInterface    TerraformResourceType = "aws_network_interface"
-	Route53Zone         TerraformResourceType = "aws_route53_zone"
-	RouteTable          TerraformResourceType = "aws_route_table"
-	S3Bucket            TerraformResourceType = "aws_s3_bucket"
-	SecurityGroup       TerraformResourceType = "aws_security_group"
-	Subnet              TerraformResourceType = "aws_subnet"
-	Vpc                 TerraformResourceType = "aws_vpc"
-	VpcEndpoint         TerraformResourceType = "aws_vpc_endpoint"
+	Ami                     TerraformResourceType = "aws_ami"
+	ApiGatewayRestApi       TerraformResourceType = "aws_api_gateway_rest_api"
+	AthenaNamedQuery        TerraformResourceType = "aws_athena_named_query"
+	AutoscalingGroup        TerraformResourceType = "aws_autoscaling_group"
+	CloudformationStack     TerraformResourceType = "aws_cloudformation_stack"
+	CloudfrontDistribution  TerraformResourceType = "aws_cloudfront_distribution"
+	Cloudtrail              TerraformResourceType = "aws_cloudtrail"
+	CloudwatchDashboard     TerraformResourceType = "aws_cloudwatch_dashboard"
+	CloudwatchLogGroup      TerraformResourceType = "aws_cloudwatch_log_group"
+	CloudwatchLogStream     TerraformResourceType = "aws_cloudwatch_log_stream"
+	CloudwatchMetricAlarm   TerraformResourceType = "aws_cloudwatch_metric_alarm"
+	CognitoIdentityPool     TerraformResourceType = "aws_cognito_identity_pool"
+	CognitoUserPool         TerraformResourceType = "aws_cognito_user_pool"
+	ConfigConfigRule        TerraformResourceType = "aws_config_config_rule"
+	ConfigRecorder          TerraformResourceType = "aws_config_configuration_recorder"
+	ConfigDelivery          TerraformResourceType = "aws_config_delivery_channel"
+	DbClusterSnapshot       TerraformResourceType = "aws_db_cluster_snapshot"
+	DbSnapshot              TerraformResourceType = "aws_db_snapshot"
+	DynamodbTable           TerraformResourceType = "aws_dynamodb_table"
+	EbsSnapshot             TerraformResourceType = "aws_ebs_snapshot"
+	EbsVolume               TerraformResourceType = "aws_ebs_volume"
+	EcsCluster              TerraformResourceType = "aws_ecs_cluster"
+	EcsService              TerraformResourceType = "aws_ecs_service"
+	EcsTaskDefinition       TerraformResourceType = "aws_ecs_task_definition"
+	EfsFileSystem           TerraformResourceType = "aws_efs_file_system"
+	Eip                     TerraformResourceType = "aws_eip"
+	BeanstalkApplication    TerraformResourceType = "aws_elastic_beanstalk_application"
+	BeanstalkAppVersion     TerraformResourceType = "aws_elastic_beanstalk_application_version"
+	BeanstalkEnvironment    TerraformResourceType = "aws_elastic_beanstalk_environment"
+	Elb                     TerraformResourceType = "aws_elb"
+	GlueCatalogDatabase     TerraformResourceType = "aws_glue_catalog_database"
+	GlueCrawler             TerraformResourceType = "aws_glue_crawler"
+	GlueJob                 TerraformResourceType = "aws_glue_job"
+	IamAccessKey            TerraformResourceType = "aws_iam_access_key"
+	IamGroup                TerraformResourceType = "aws_iam_group"
+	IamInstanceProfile      TerraformResourceType = "aws_iam_instance_profile"
+	IamPolicy               TerraformResourceType = "aws_iam_policy"
+	IamRole                 TerraformResourceType = "aws_iam_role"
+	IamRolePolicyAttachment TerraformResourceType = "aws_iam_role_policy_attachment"
+	IamUser                 TerraformResourceType = "aws_iam_user"
+	IamUserGroupMembership  TerraformResourceType = "aws_iam_user_group_membership"
+	IamUserPolicyAttachment TerraformResourceType = "aws_iam_user_policy_attachment"
+	Instance                TerraformResourceType = "aws_instance"
+	InternetGateway         TerraformResourceType = "aws_internet_gateway"
+	KeyPair                 TerraformResourceType = "aws_key_pair"
+	FirehoseDeliveryStream  TerraformResourceType = "aws_kinesis_firehose_delivery_stream"
+	KinesisStream           TerraformResourceType = "aws_kinesis_stream"
+	KmsAlias                TerraformResourceType = "aws_kms_alias"
+	KmsKey                  TerraformResourceType = "aws_kms_key"
+	LambdaFunction          TerraformResourceType = "aws_lambda_function"
+	LaunchConfiguration     TerraformResourceType = "aws_launch_configuration"
+	Lb                      TerraformResourceType = "aws_lb"
+	LbListener              TerraformResourceType = "aws_lb_listener"
+	LbTargetGroup           TerraformResourceType = "aws_lb_target_group"
+	NatGateway              TerraformResourceType = "aws_nat_gateway"
+	NetworkAcl              TerraformResourceType = "aws_network_acl"
+	NetworkInterface        TerraformResourceType = "aws_network_interface"
+	RdsCluster              TerraformResourceType = "aws_rds_cluster"
+	RdsClusterInstance      TerraformResourceType = "aws_rds_cluster_instance"
+	RedshiftCluster         TerraformResourceType = "aws_redshift_cluster"
+	RedshiftSnapshot        TerraformResourceType = "aws_redshift_snapshot"
+	Route53Zone             TerraformResourceType = "aws_route53_zone"
+	RouteTable              TerraformResourceType = "aws_route_table"
+	S3Bucket                TerraformResourceType = "aws_s3_bucket"
+	SecurityGroup           TerraformResourceType = "aws_security_group"
+	SesConfigurationSet     TerraformResourceType = "aws_ses_configuration_set"
+	SesDomainIdentity       TerraformResourceType = "aws_ses_domain_identity"
+	SesEmailIdentity        TerraformResourceType = "aws_ses_email_identity"
+	SesReceiptRuleSet       TerraformResourceType = "aws_ses_receipt_rule_set"
+	SfnStateMachine         TerraformResourceType = "aws_sfn_state_machine"
+	SnsTopic                TerraformResourceType = "aws_sns_topic"
+	SnsTopicSubscription    TerraformResourceType = "aws_sns_topic_subscription"
+	SqsQueue                TerraformResourceType = "aws_sqs_queue"
+	SsmParameter            TerraformResourceType = "aws_ssm_parameter"
+	Subnet                  TerraformResourceType = "aws_subnet"
+	Vpc                     TerraformResourceType = "aws_vpc"
+	VpcEndpoint             TerraformResourceType = "aws_vpc_endpoint"
 )
 
 var (
 	deleteIDs = map[TerraformResourceType]string{
-		Ami:                 "ImageId",
-		AutoscalingGroup:    "AutoScalingGroupName",
-		CloudformationStack: "StackId",
-		EbsSnapshot:         "SnapshotId",
-		EbsVolume:           "VolumeId",
-		EfsFileSystem:       "FileSystemId",
-		Eip:                 "AllocationId",
-		Elb:                 "LoadBalancerName",
-		IamGroup:            "GroupName",
-		IamInstanceProfile:  "InstanceProfileName",
-		IamPolicy:           "Arn",
-		IamRole:             "RoleName",
-		IamUser:             "UserName",
-		Instance:            "InstanceId",
-		InternetGateway:     "InternetGatewayId",
-		KeyPair:             "KeyName",
-		KmsAlias:            "AliasName",
-		KmsKey:              "KeyId",
-		LaunchConfiguration: "LaunchConfigurationName",
-		NatGateway:          "NatGatewayId",
-		NetworkAcl:          "NetworkAclId",
-		NetworkInterface:    "NetworkInterfaceId",
-		Route53Zone:         "Id",
-		RouteTable:          "RouteTableId",
-		S3Bucket:            "Name",
-		SecurityGroup:       "GroupId",
-		Subnet:              "SubnetId",
-		Vpc:                 "VpcId",
-		VpcEndpoint:         "VpcEndpointId",
+		Ami:                     "ImageId",
+		ApiGatewayRestApi:       "Id",
+		AthenaNamedQuery:        "NamedQueryId",
+		AutoscalingGroup:        "AutoScalingGroupName",
+		CloudformationStack:     "StackId",
+		CloudfrontDistribution:  "Id",
+		Cloudtrail:              "Name",
+		CloudwatchDashboard:     "DashboardName",
+		CloudwatchLogGroup:      "LogGroupName",
+		CloudwatchLogStream:     "LogStreamName",
+		CloudwatchMetricAlarm:   "AlarmName",
+		CognitoIdentityPool:     "IdentityPoolId",
+		CognitoUserPool:         "Id",
+		ConfigConfigRule:        "ConfigRuleName",
+		ConfigRecorder:          "Name",
+		ConfigDelivery:          "Name",
+		DbClusterSnapshot:       "DBClusterSnapshotIdentifier",
+		DbSnapshot:              "DBSnapshotIdentifier",
+		DynamodbTable:           "TableName",
+		EbsSnapshot:             "SnapshotId",
+		EbsVolume:               "VolumeId",
+		EcsCluster:              "ClusterArn",
+		EcsService:              "ServiceArn",
+		EcsTaskDefinition:       "TaskDefinitionArn",
+		EfsFileSystem:           "FileSystemId",
+		Eip:                     "AllocationId",
+		BeanstalkApplication:    "ApplicationName",
+		BeanstalkAppVersion:     "VersionLabel",
+		BeanstalkEnvironment:    "EnvironmentId",
+		Elb:                     "LoadBalancerName",
+		GlueCatalogDatabase:     "ID",
+		GlueCrawler:             "Name",
+		GlueJob:                 "Name",
+		IamAccessKey:            "AccessKeyId",
+		IamGroup:                "GroupName",
+		IamInstanceProfile:      "InstanceProfileName",
+		IamPolicy:               "Arn",
+		IamRole:                 "RoleName",
+		IamRolePolicyAttachment: "ID",
+		IamUser:                 "UserName",
+		IamUserGroupMembership:  "ID",
+		IamUserPolicyAttachment: "ID",
+		Instance:                "InstanceId",
+		InternetGateway:         "InternetGatewayId",
+		KeyPair:                 "KeyName",
+		FirehoseDeliveryStream:  "DeliveryStreamARN",
+		KinesisStream:           "StreamARN",
+		KmsAlias:                "AliasName",
+		KmsKey:                  "KeyId",
+		LambdaFunction:          "FunctionName",
+		LaunchConfiguration:     "LaunchConfigurationName",
+		Lb:                      "LoadBalancerArn",
+		LbListener:              "ListenerArn",
+		LbTargetGroup:           "TargetGroupArn",
+		NatGateway:              "NatGatewayId",
+		NetworkAcl:              "NetworkAclId",
+		NetworkInterface:        "NetworkInterfaceId",
+		RdsCluster:              "DBClusterIdentifier",
+		RedshiftCluster:         "ClusterIdentifier",
+		RedshiftSnapshot:        "SnapshotIdentifier",
+		Route53Zone:             "Id",
+		RouteTable:              "RouteTableId",
+		S3Bucket:                "Name",
+		SecurityGroup:           "GroupId",
+		SesConfigurationSet:     "Name",
+		// SES identities are listed as plain strings, which are used as ID directly
+		SesDomainIdentity:    "",
+		SesEmailIdentity:     "",
+		SesReceiptRuleSet:    "Name",
+		SfnStateMachine:      "StateMachineArn",
+		SnsTopic:             "TopicArn",
+		SnsTopicSubscription: "SubscriptionArn",
+		// SQS queues are listed by their URL, which is the ID Terraform uses
+		SqsQueue:     "",
+		SsmParameter: "Name",
+		Subnet:       "SubnetId",
+		Vpc:          "VpcId",
+		VpcEndpoint:  "VpcEndpointId",
+	}
+
+	// deleteTypes maps resource types that are not (yet) known to the Terraform AWS provider
+	// to a type whose delete routine can be used instead.
+	deleteTypes = map[TerraformResourceType]TerraformResourceType{
+		SesEmailIdentity: SesDomainIdentity,
+	}
+
+	// globalTypes are resource types which resources don't belong to a particular region,
+	// i.e. they are listed the same way in every region.
+	globalTypes = map[TerraformResourceType]bool{
+		// dashboards are listed in every region
+		CloudwatchDashboard:     true,
+		CloudfrontDistribution:  true,
+		IamAccessKey:            true,
+		IamGroup:                true,
+		IamInstanceProfile:      true,
+		IamPolicy:               true,
+		IamRole:                 true,
+		IamRolePolicyAttachment: true,
+		IamUser:                 true,
+		IamUserGroupMembership:  true,
+		IamUserPolicyAttachment: true,
+		Route53Zone:             true,
+		S3Bucket:                true,
+	}
+
+	// deletedLast are resource types which resources can't be deleted while resources of other types
+	// still use them (e.g., target groups that are forwarded to by load balancers), so that they are swept last.
+	deletedLast = map[TerraformResourceType]bool{
+		LbTargetGroup: true,
 	}
 
 	tagFieldNames = []string{
@@ -105,7 +290,22 @@ var (
 	creationTimeFieldNames = []string{
 		"LaunchTime",
 		"CreatedTime",
+		"CreatedDate",
 		"CreationDate",
+		"CreationDateTime",
+		"DateCreated",
+		"CreatedTimestamp",
+		"CreatedAt",
+		"CreationTime",
+		"ClusterCreateTime",
+		"SnapshotCreateTime",
+		"CreationTimestamp",
+		"CreateDate",
+		"CreateTime",
+		"StartTime",
+		"StreamCreationTimestamp",
+		"CreateTimestamp",
+		"CreatedOn",
 	}
 )
 
@@ -115,6 +315,42 @@ func SupportedResourceType(resType TerraformResourceType) bool {
 	return found
 }
 
+// IsGlobal checks whether resources of a type don't belong to a particular region.
+func IsGlobal(resType TerraformResourceType) bool {
+	return globalTypes[resType]
+}
+
+// RegionalTypes returns the given resource types without the global ones.
+func RegionalTypes(resTypes []TerraformResourceType) []TerraformResourceType {
+	var result []TerraformResourceType
+	for _, resType := range resTypes {
+		if !IsGlobal(resType) {
+			result = append(result, resType)
+		}
+	}
+	return result
+}
+
+// DeleteType returns the Terraform resource type whose delete routine is used to delete resources of the given type.
+func DeleteType(resType TerraformResourceType) TerraformResourceType {
+	if deleteType, found := deleteTypes[resType]; found {
+		return deleteType
+	}
+	return resType
+}
+
+// SupportedResourceTypes returns all supported resource types in alphabetical order.
+func SupportedResourceTypes() []TerraformResourceType {
+	resTypes := make([]TerraformResourceType, 0, len(deleteIDs))
+
+	for k := range deleteIDs {
+		resTypes = append(resTypes, k)
+	}
+	sort.Slice(resTypes, func(i, j int) bool { return resTypes[i] < resTypes[j] })
+
+	return resTypes
+}
+
 func getDeleteID(resType TerraformResourceType) (string, error) {
 	deleteID, found := deleteIDs[resType]
 	if !found {
@@ -126,30 +362,92 @@ func getDeleteID(resType TerraformResourceType) (string, error) {
 // AWS wraps the AWS API
 type AWS struct {
 	ec2iface.EC2API
+	apigatewayiface.APIGatewayAPI
+	athenaiface.AthenaAPI
 	autoscalingiface.AutoScalingAPI
 	elbiface.ELBAPI
+	elbv2iface.ELBV2API
 	route53iface.Route53API
 	cloudformationiface.CloudFormationAPI
+	cloudfrontiface.CloudFrontAPI
+	cloudtrailiface.CloudTrailAPI
+	cloudwatchiface.CloudWatchAPI
+	cloudwatcheventsiface.CloudWatchEventsAPI
+	cloudwatchlogsiface.CloudWatchLogsAPI
+	cognitoidentityiface.CognitoIdentityAPI
+	cognitoidentityprovideriface.CognitoIdentityProviderAPI
+	configserviceiface.ConfigServiceAPI
+	dynamodbiface.DynamoDBAPI
+	ecsiface.ECSAPI
 	efsiface.EFSAPI
+	elasticbeanstalkiface.ElasticBeanstalkAPI
+	firehoseiface.FirehoseAPI
+	glueiface.GlueAPI
 	iamiface.IAMAPI
+	kinesisiface.KinesisAPI
 	kmsiface.KMSAPI
+	lambdaiface.LambdaAPI
+	rdsiface.RDSAPI
+	redshiftiface.RedshiftAPI
+	resourcegroupstaggingapiiface.ResourceGroupsTaggingAPIAPI
 	s3iface.S3API
+	sesiface.SESAPI
+	sfniface.SFNAPI
+	snsiface.SNSAPI
+	sqsiface.SQSAPI
+	ssmiface.SSMAPI
 	stsiface.STSAPI
+	supportiface.SupportAPI
+
+	// Region is the name of the region the clients are configured for
+	Region string
+	// Identities caches the identity of the credentials, so that it is only looked up once
+	// (and can be shared between the clients of all regions). It isn't cached if nil.
+	Identities *IdentityCache
 }
 
 // NewAWS creates an AWS instance
 func NewAWS(s *session.Session) *AWS {
 	return &AWS{
-		AutoScalingAPI:    autoscaling.New(s),
-		CloudFormationAPI: cloudformation.New(s),
-		EC2API:            ec2.New(s),
-		EFSAPI:            efs.New(s),
-		ELBAPI:            elb.New(s),
-		IAMAPI:            iam.New(s),
-		KMSAPI:            kms.New(s),
-		Route53API:        route53.New(s),
-		S3API:             s3.New(s),
-		STSAPI:            sts.New(s),
+		APIGatewayAPI:               apigateway.New(s),
+		AthenaAPI:                   athena.New(s),
+		AutoScalingAPI:              autoscaling.New(s),
+		CloudFormationAPI:           cloudformation.New(s),
+		CloudFrontAPI:               cloudfront.New(s),
+		CloudTrailAPI:               cloudtrail.New(s),
+		CloudWatchAPI:               cloudwatch.New(s),
+		CloudWatchEventsAPI:         cloudwatchevents.New(s),
+		CloudWatchLogsAPI:           cloudwatchlogs.New(s),
+		CognitoIdentityAPI:          cognitoidentity.New(s),
+		CognitoIdentityProviderAPI:  cognitoidentityprovider.New(s),
+		ConfigServiceAPI:            configservice.New(s),
+		DynamoDBAPI:                 dynamodb.New(s),
+		EC2API:                      ec2.New(s),
+		ECSAPI:                      ecs.New(s),
+		EFSAPI:                      efs.New(s),
+		ElasticBeanstalkAPI:         elasticbeanstalk.New(s),
+		ELBAPI:                      elb.New(s),
+		ELBV2API:                    elbv2.New(s),
+		FirehoseAPI:                 firehose.New(s),
+		GlueAPI:                     glue.New(s),
+		IAMAPI:                      iam.New(s),
+		KinesisAPI:                  kinesis.New(s),
+		KMSAPI:                      kms.New(s),
+		LambdaAPI:                   lambda.New(s),
+		RDSAPI:                      rds.New(s),
+		RedshiftAPI:                 redshift.New(s),
+		ResourceGroupsTaggingAPIAPI: resourcegroupstaggingapi.New(s),
+		Route53API:                  route53.New(s),
+		S3API:                       s3.New(s),
+		SESAPI:                      ses.New(s),
+		SFNAPI:                      sfn.New(s),
+		SNSAPI:                      sns.New(s),
+		SQSAPI:                      sqs.New(s),
+		SSMAPI:                      ssm.New(s),
+		STSAPI:                      sts.New(s),
+		SupportAPI:                  support.New(s, aws.NewConfig().WithRegion(advisorRegion)),
+		Region:                      aws.StringValue(s.Config.Region),
+		Identities:                  &IdentityCache{},
 	}
 }
 
@@ -162,7 +460,78 @@ type Resource struct {
 	ID      string
 	Tags    map[string]string
 	Created *time.Time
-	Attrs   map[string]string
+	// Model is the typed representation of the resource with the fields specific to its type
+	// (nil for resources that are deleted along with the ones listed, e.g., EFS mount targets).
+	Model Model
+	// Default is true for resources that exist in every account by default or are managed by AWS.
+	Default bool
+	// Region is the region the resource has to be deleted in, if it can differ from
+	// the region it has been listed in (e.g., S3 buckets are listed globally).
+	Region string
+	// DryRun is true for resources selected by a filter entry with dry_run (or deleted together with such
+	// resources), which are only reported instead of deleted.
+	DryRun bool
+
+	// metrics are the aggregated values of the CloudWatch metrics the filter selects resources by
+	metrics map[string]float64
+	// unused is true if no other resource uses the resource (only known if the filter selects resources by usage)
+	unused bool
+	// lastUsed is the time the resource has been used the last time (nil if never used or unknown)
+	lastUsed *time.Time
+	// listedIn is the region the resource has been listed in (empty if unknown)
+	listedIn string
+	// arn is the ARN of the resource, if its ID isn't an ARN (empty if unknown)
+	arn string
+	// tagsFailed is true if looking up the tags of the resource has failed, so that they are unknown
+	tagsFailed bool
+	// newerRevisions is the number of later revisions of the family of the resource
+	// (only known if the filter keeps the latest revisions)
+	newerRevisions int
+	// parents are the selected resources the resource is deleted along with (e.g., the cluster of an ECS service),
+	// none for resources selected by the filter themselves
+	parents Resources
+}
+
+// RawResourcesWithTags lists the resources of a type like RawResources, but the resources of EC2 types are
+// restricted via API-side filters to the ones with a tag (given by its key) matching any of the given wildcard
+// patterns (e.g., ci-*), which reduces the data transferred in big accounts.
+func (a *AWS) RawResourcesWithTags(resType TerraformResourceType, tags map[string][]string) (interface{}, error) {
+	if len(tags) == 0 {
+		return a.RawResources(resType)
+	}
+	filters := ec2TagFilters(tags)
+
+	switch resType {
+	case Ami:
+		return a.amis(filters...)
+	case EbsSnapshot:
+		return a.ebsSnapshots(filters...)
+	case EbsVolume:
+		return a.ebsVolumes(filters...)
+	case Eip:
+		return a.eips(filters...)
+	case Instance:
+		return a.instances(filters...)
+	case InternetGateway:
+		return a.internetGateways(filters...)
+	case NatGateway:
+		return a.natGateways(filters...)
+	case NetworkAcl:
+		return a.networkAcls(filters...)
+	case NetworkInterface:
+		return a.networkInterfaces(filters...)
+	case RouteTable:
+		return a.routeTables(filters...)
+	case SecurityGroup:
+		return a.SecurityGroup(filters...)
+	case Subnet:
+		return a.subnets(filters...)
+	case Vpc:
+		return a.vpcs(filters...)
+	case VpcEndpoint:
+		return a.vpcEndpoints(filters...)
+	}
+	return a.RawResources(resType)
 }
 
 // RawResources lists all resources of a particular type
@@ -170,20 +539,72 @@ func (a *AWS) RawResources(resType TerraformResourceType) (interface{}, error) {
 	switch resType {
 	case Ami:
 		return a.amis()
+	case AthenaNamedQuery:
+		return a.athenaNamedQueries()
+	case ApiGatewayRestApi:
+		return a.apiGatewayRestApis()
 	case AutoscalingGroup:
 		return a.autoscalingGroups()
 	case CloudformationStack:
 		return a.cloudformationStacks()
+	case CloudfrontDistribution:
+		return a.cloudfrontDistributions()
+	case Cloudtrail:
+		return a.cloudtrails()
+	case CloudwatchDashboard:
+		return a.cloudwatchDashboards()
+	case CloudwatchLogGroup:
+		return a.cloudwatchLogGroups()
+	case CloudwatchLogStream:
+		return a.cloudwatchLogStreams()
+	case CloudwatchMetricAlarm:
+		return a.cloudwatchMetricAlarms()
+	case CognitoIdentityPool:
+		return a.cognitoIdentityPools()
+	case CognitoUserPool:
+		return a.cognitoUserPools()
+	case ConfigConfigRule:
+		return a.configRules()
+	case ConfigRecorder:
+		return a.configRecorders()
+	case ConfigDelivery:
+		return a.configDeliveryChannels()
+	case DbClusterSnapshot:
+		return a.dbClusterSnapshots()
+	case DbSnapshot:
+		return a.dbSnapshots()
+	case DynamodbTable:
+		return a.dynamodbTables()
 	case EbsSnapshot:
 		return a.ebsSnapshots()
 	case EbsVolume:
 		return a.ebsVolumes()
+	case EcsCluster:
+		return a.ecsClusters()
+	case EcsService:
+		return a.ecsServices()
+	case EcsTaskDefinition:
+		return a.ecsTaskDefinitions()
 	case EfsFileSystem:
 		return a.efsFileSystems()
 	case Eip:
 		return a.eips()
+	case BeanstalkApplication:
+		return a.beanstalkApplications()
+	case BeanstalkAppVersion:
+		return a.beanstalkAppVersions()
+	case BeanstalkEnvironment:
+		return a.beanstalkEnvironments()
 	case Elb:
 		return a.elbs()
+	case GlueCatalogDatabase:
+		return a.glueCatalogDatabases()
+	case GlueCrawler:
+		return a.glueCrawlers()
+	case GlueJob:
+		return a.glueJobs()
+	case IamAccessKey:
+		return a.iamAccessKeys()
 	case IamGroup:
 		return a.iamGroups()
 	case IamInstanceProfile:
@@ -192,26 +613,50 @@ func (a *AWS) RawResources(resType TerraformResourceType) (interface{}, error) {
 		return a.iamPolicies()
 	case IamRole:
 		return a.iamRoles()
+	case IamRolePolicyAttachment:
+		return a.iamRolePolicyAttachments()
 	case IamUser:
 		return a.iamUsers()
+	case IamUserGroupMembership:
+		return a.iamUserGroupMemberships()
+	case IamUserPolicyAttachment:
+		return a.iamUserPolicyAttachments()
 	case Instance:
 		return a.instances()
 	case InternetGateway:
 		return a.internetGateways()
 	case KeyPair:
 		return a.keyPairs()
+	case FirehoseDeliveryStream:
+		return a.firehoseDeliveryStreams()
+	case KinesisStream:
+		return a.kinesisStreams()
 	case KmsAlias:
 		return a.KmsAliases()
 	case KmsKey:
 		return a.KmsKeys()
+	case LambdaFunction:
+		return a.lambdaFunctions()
 	case LaunchConfiguration:
 		return a.launchConfigurations()
+	case Lb:
+		return a.lbs()
+	case LbListener:
+		return a.lbListeners()
+	case LbTargetGroup:
+		return a.lbTargetGroups()
 	case NatGateway:
 		return a.natGateways()
 	case NetworkAcl:
 		return a.networkAcls()
 	case NetworkInterface:
 		return a.networkInterfaces()
+	case RdsCluster:
+		return a.rdsClusters()
+	case RedshiftCluster:
+		return a.redshiftClusters()
+	case RedshiftSnapshot:
+		return a.redshiftSnapshots()
 	case Route53Zone:
 		return a.route53Zones()
 	case RouteTable:
@@ -220,6 +665,24 @@ func (a *AWS) RawResources(resType TerraformResourceType) (interface{}, error) {
 		return a.s3Buckets()
 	case SecurityGroup:
 		return a.SecurityGroup()
+	case SesConfigurationSet:
+		return a.sesConfigurationSets()
+	case SesDomainIdentity:
+		return a.sesIdentities("Domain")
+	case SesEmailIdentity:
+		return a.sesIdentities("EmailAddress")
+	case SesReceiptRuleSet:
+		return a.sesReceiptRuleSets()
+	case SfnStateMachine:
+		return a.sfnStateMachines()
+	case SnsTopic:
+		return a.snsTopics()
+	case SnsTopicSubscription:
+		return a.snsSubscriptions()
+	case SqsQueue:
+		return a.sqsQueues()
+	case SsmParameter:
+		return a.ssmParameters()
 	case Subnet:
 		return a.subnets()
 	case Vpc:
@@ -227,13 +690,16 @@ func (a *AWS) RawResources(resType TerraformResourceType) (interface{}, error) {
 	case VpcEndpoint:
 		return a.vpcEndpoints()
 	default:
+		if s, found := specs[resType]; found {
+			return a.specResources(s)
+		}
 		return nil, errors.Errorf("unknown or unsupported resource type: %s", resType)
 	}
 }
 
-func (a *AWS) instances() (interface{}, error) {
+func (a *AWS) instances(filters ...*ec2.Filter) (interface{}, error) {
 	output, err := a.DescribeInstances(&ec2.DescribeInstancesInput{
-		Filters: []*ec2.Filter{
+		Filters: append([]*ec2.Filter{
 			{
 				Name: aws.String("instance-state-name"),
 				Values: []*string{
@@ -241,7 +707,7 @@ func (a *AWS) instances() (interface{}, error) {
 					aws.String("stopping"), aws.String("stopped"),
 				},
 			},
-		},
+		}, filters...),
 	})
 
 	if err != nil {
@@ -274,25 +740,55 @@ func (a *AWS) elbs() (interface{}, error) {
 	return output.LoadBalancerDescriptions, nil
 }
 
-func (a *AWS) vpcEndpoints() (interface{}, error) {
-	output, err := a.DescribeVpcEndpoints(&ec2.DescribeVpcEndpointsInput{})
+// elbHealthyInstanceCounts sets the number of registered instances that are in service on the models of ELBs.
+func (a *AWS) elbHealthyInstanceCounts(res Resources) error {
+	for _, r := range res {
+		m, ok := r.Model.(ElbModel)
+		if !ok {
+			continue
+		}
+
+		var output *elb.DescribeInstanceHealthOutput
+		err := retryThrottled(func() error {
+			var err error
+			output, err = a.DescribeInstanceHealth(&elb.DescribeInstanceHealthInput{
+				LoadBalancerName: aws.String(r.ID),
+			})
+			return err
+		})
+		if err != nil {
+			return errors.Errorf("failed to get health of instances of load balancer %s: %s", r.ID, err)
+		}
+
+		m.HealthyInstanceCount = 0
+		for _, s := range output.InstanceStates {
+			if aws.StringValue(s.State) == "InService" {
+				m.HealthyInstanceCount++
+			}
+		}
+		r.Model = m
+	}
+	return nil
+}
+
+func (a *AWS) vpcEndpoints(filters ...*ec2.Filter) (interface{}, error) {
+	output, err := a.DescribeVpcEndpoints(&ec2.DescribeVpcEndpointsInput{Filters: filters})
 	if err != nil {
 		return nil, err
 	}
 	return output.VpcEndpoints, nil
 }
 
-// TODO support findTags
-func (a *AWS) natGateways() (interface{}, error) {
+func (a *AWS) natGateways(filters ...*ec2.Filter) (interface{}, error) {
 	output, err := a.DescribeNatGateways(&ec2.DescribeNatGatewaysInput{
-		Filter: []*ec2.Filter{
+		Filter: append([]*ec2.Filter{
 			{
 				Name: aws.String("state"),
 				Values: []*string{
 					aws.String("available"),
 				},
 			},
-		},
+		}, filters...),
 	})
 
 	if err != nil {
@@ -309,6 +805,87 @@ func (a *AWS) cloudformationStacks() (interface{}, error) {
 	return output.Stacks, nil
 }
 
+func (a *AWS) cloudfrontDistributions() (interface{}, error) {
+	var distributions []*cloudfront.DistributionSummary
+	err := a.ListDistributionsPages(&cloudfront.ListDistributionsInput{}, func(page *cloudfront.ListDistributionsOutput, lastPage bool) bool {
+		distributions = append(distributions, page.DistributionList.Items...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return distributions, nil
+}
+
+func (a *AWS) cloudtrails() (interface{}, error) {
+	output, err := a.DescribeTrails(&cloudtrail.DescribeTrailsInput{
+		// trails of other regions are listed as shadow trails, but can only be deleted in their home region
+		IncludeShadowTrails: aws.Bool(false),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.TrailList, nil
+}
+
+func (a *AWS) cognitoIdentityPools() (interface{}, error) {
+	var pools []*cognitoidentity.IdentityPoolShortDescription
+	input := &cognitoidentity.ListIdentityPoolsInput{MaxResults: aws.Int64(60)}
+	for {
+		output, err := a.ListIdentityPools(input)
+		if err != nil {
+			return nil, err
+		}
+		pools = append(pools, output.IdentityPools...)
+		if aws.StringValue(output.NextToken) == "" {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+	return pools, nil
+}
+
+func (a *AWS) cognitoUserPools() (interface{}, error) {
+	var pools []*cognitoidentityprovider.UserPoolDescriptionType
+	input := &cognitoidentityprovider.ListUserPoolsInput{MaxResults: aws.Int64(60)}
+	for {
+		output, err := a.ListUserPools(input)
+		if err != nil {
+			return nil, err
+		}
+		pools = append(pools, output.UserPools...)
+		if aws.StringValue(output.NextToken) == "" {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+	return pools, nil
+}
+
+func (a *AWS) configRules() (interface{}, error) {
+	output, err := a.DescribeConfigRules(&configservice.DescribeConfigRulesInput{})
+	if err != nil {
+		return nil, err
+	}
+	return output.ConfigRules, nil
+}
+
+func (a *AWS) configRecorders() (interface{}, error) {
+	output, err := a.DescribeConfigurationRecorders(&configservice.DescribeConfigurationRecordersInput{})
+	if err != nil {
+		return nil, err
+	}
+	return output.ConfigurationRecorders, nil
+}
+
+func (a *AWS) configDeliveryChannels() (interface{}, error) {
+	output, err := a.DescribeDeliveryChannels(&configservice.DescribeDeliveryChannelsInput{})
+	if err != nil {
+		return nil, err
+	}
+	return output.DeliveryChannels, nil
+}
+
 func (a *AWS) route53Zones() (interface{}, error) {
 	output, err := a.ListHostedZones(&route53.ListHostedZonesInput{})
 	if err != nil {
@@ -317,6 +894,286 @@ func (a *AWS) route53Zones() (interface{}, error) {
 	return output.HostedZones, nil
 }
 
+func (a *AWS) apiGatewayRestApis() (interface{}, error) {
+	var apis []*apigateway.RestApi
+	err := a.GetRestApisPages(&apigateway.GetRestApisInput{},
+		func(page *apigateway.GetRestApisOutput, lastPage bool) bool {
+			apis = append(apis, page.Items...)
+			return true
+		})
+	return apis, err
+}
+
+// athenaNamedQueries gets all named queries in batches, as named queries are only listed by ID.
+func (a *AWS) athenaNamedQueries() (interface{}, error) {
+	var ids []*string
+	err := a.ListNamedQueriesPages(&athena.ListNamedQueriesInput{},
+		func(page *athena.ListNamedQueriesOutput, lastPage bool) bool {
+			ids = append(ids, page.NamedQueryIds...)
+			return true
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	var queries []*athena.NamedQuery
+	// the API gets at most 50 named queries at once
+	for start := 0; start < len(ids); start += 50 {
+		end := start + 50
+		if end > len(ids) {
+			end = len(ids)
+		}
+		output, err := a.BatchGetNamedQuery(&athena.BatchGetNamedQueryInput{NamedQueryIds: ids[start:end]})
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, output.NamedQueries...)
+	}
+	return queries, nil
+}
+
+func (a *AWS) cloudwatchDashboards() (interface{}, error) {
+	var dashboards []*cloudwatch.DashboardEntry
+	input := &cloudwatch.ListDashboardsInput{}
+	for {
+		output, err := a.ListDashboards(input)
+		if err != nil {
+			return nil, err
+		}
+		dashboards = append(dashboards, output.DashboardEntries...)
+
+		if output.NextToken == nil {
+			return dashboards, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+func (a *AWS) cloudwatchMetricAlarms() (interface{}, error) {
+	var alarms []*cloudwatch.MetricAlarm
+	err := a.DescribeAlarmsPages(&cloudwatch.DescribeAlarmsInput{},
+		func(page *cloudwatch.DescribeAlarmsOutput, lastPage bool) bool {
+			alarms = append(alarms, page.MetricAlarms...)
+			return true
+		})
+	return alarms, err
+}
+
+func (a *AWS) cloudwatchLogGroups() (interface{}, error) {
+	var groups []*cloudwatchlogs.LogGroup
+	err := a.DescribeLogGroupsPages(&cloudwatchlogs.DescribeLogGroupsInput{},
+		func(page *cloudwatchlogs.DescribeLogGroupsOutput, lastPage bool) bool {
+			groups = append(groups, page.LogGroups...)
+			return true
+		})
+	return groups, err
+}
+
+// logStream is a stream of a log group, which the AWS API lists per group.
+type logStream struct {
+	LogStreamName      *string
+	LogGroupName       *string
+	CreationTime       *int64
+	LastEventTimestamp *int64
+	StoredBytes        *int64
+}
+
+// cloudwatchLogStreams lists the streams of all log groups.
+func (a *AWS) cloudwatchLogStreams() (interface{}, error) {
+	var streams []*logStream
+	err := a.cloudwatchLogStreamPages(func(page interface{}) error {
+		streams = append(streams, page.([]*logStream)...)
+		return nil
+	})
+	return streams, err
+}
+
+// cloudwatchLogStreamPages lists the streams of all log groups, passing the streams of one group at a time to fn.
+func (a *AWS) cloudwatchLogStreamPages(fn func(page interface{}) error) error {
+	groups, err := a.cloudwatchLogGroups()
+	if err != nil {
+		return err
+	}
+
+	for _, g := range groups.([]*cloudwatchlogs.LogGroup) {
+		var groupStreams []*logStream
+		// the API allows only a few requests per second
+		err := retryThrottled(func() error {
+			groupStreams = nil
+			return a.DescribeLogStreamsPages(&cloudwatchlogs.DescribeLogStreamsInput{
+				LogGroupName: g.LogGroupName,
+			}, func(page *cloudwatchlogs.DescribeLogStreamsOutput, lastPage bool) bool {
+				for _, s := range page.LogStreams {
+					groupStreams = append(groupStreams, &logStream{
+						LogStreamName:      s.LogStreamName,
+						LogGroupName:       g.LogGroupName,
+						CreationTime:       s.CreationTime,
+						LastEventTimestamp: s.LastEventTimestamp,
+						StoredBytes:        s.StoredBytes,
+					})
+				}
+				return true
+			})
+		})
+		if err != nil {
+			return err
+		}
+		if err := fn(groupStreams); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *AWS) lambdaFunctions() (interface{}, error) {
+	var functions []*lambda.FunctionConfiguration
+	err := a.ListFunctionsPages(&lambda.ListFunctionsInput{},
+		func(page *lambda.ListFunctionsOutput, lastPage bool) bool {
+			functions = append(functions, page.Functions...)
+			return true
+		})
+	return functions, err
+}
+
+func (a *AWS) rdsClusters() (interface{}, error) {
+	var clusters []*rds.DBCluster
+	err := a.DescribeDBClustersPages(&rds.DescribeDBClustersInput{},
+		func(page *rds.DescribeDBClustersOutput, lastPage bool) bool {
+			clusters = append(clusters, page.DBClusters...)
+			return true
+		})
+	return clusters, err
+}
+
+func (a *AWS) redshiftClusters() (interface{}, error) {
+	var clusters []*redshift.Cluster
+	err := a.RedshiftAPI.DescribeClustersPages(&redshift.DescribeClustersInput{},
+		func(page *redshift.DescribeClustersOutput, lastPage bool) bool {
+			clusters = append(clusters, page.Clusters...)
+			return true
+		})
+	return clusters, err
+}
+
+// redshiftSnapshots lists the manual snapshots of Redshift clusters owned by the account (automated snapshots are
+// deleted along with their cluster or when their retention period ends).
+func (a *AWS) redshiftSnapshots() (interface{}, error) {
+	accountID, err := a.AccountID()
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []*redshift.Snapshot
+	err = a.DescribeClusterSnapshotsPages(&redshift.DescribeClusterSnapshotsInput{
+		OwnerAccount: aws.String(accountID),
+		SnapshotType: aws.String("manual"),
+	}, func(page *redshift.DescribeClusterSnapshotsOutput, lastPage bool) bool {
+		snapshots = append(snapshots, page.Snapshots...)
+		return true
+	})
+	return snapshots, err
+}
+
+// dbSnapshots lists the manual snapshots of DB instances owned by the account (automated snapshots are
+// deleted along with their instance or when their retention period ends).
+func (a *AWS) dbSnapshots() (interface{}, error) {
+	var snapshots []*rds.DBSnapshot
+	err := a.dbSnapshotPages(func(page interface{}) error {
+		snapshots = append(snapshots, page.([]*rds.DBSnapshot)...)
+		return nil
+	})
+	return snapshots, err
+}
+
+// dbSnapshotPages lists the manual snapshots of DB instances owned by the account, passing a page of them
+// at a time to fn.
+func (a *AWS) dbSnapshotPages(fn func(page interface{}) error) error {
+	// RDS can't filter snapshots by owner, but their ARNs contain the owning account
+	accountID, err := a.AccountID()
+	if err != nil {
+		return err
+	}
+
+	var fnErr error
+	err = a.DescribeDBSnapshotsPages(&rds.DescribeDBSnapshotsInput{
+		SnapshotType: aws.String("manual"),
+	}, func(page *rds.DescribeDBSnapshotsOutput, lastPage bool) bool {
+		var owned []*rds.DBSnapshot
+		for _, s := range page.DBSnapshots {
+			if arn.AccountID(aws.StringValue(s.DBSnapshotArn)) == accountID {
+				owned = append(owned, s)
+			}
+		}
+		fnErr = fn(owned)
+		return fnErr == nil
+	})
+	if err != nil {
+		return err
+	}
+	return fnErr
+}
+
+// dbClusterSnapshots lists the manual snapshots of DB clusters owned by the account.
+func (a *AWS) dbClusterSnapshots() (interface{}, error) {
+	var snapshots []*rds.DBClusterSnapshot
+	input := &rds.DescribeDBClusterSnapshotsInput{
+		SnapshotType: aws.String("manual"),
+	}
+	for {
+		output, err := a.DescribeDBClusterSnapshots(input)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, output.DBClusterSnapshots...)
+		if output.Marker == nil {
+			break
+		}
+		input.Marker = output.Marker
+	}
+
+	accountID, err := a.AccountID()
+	if err != nil {
+		return nil, err
+	}
+	var owned []*rds.DBClusterSnapshot
+	for _, s := range snapshots {
+		if arn.AccountID(aws.StringValue(s.DBClusterSnapshotArn)) == accountID {
+			owned = append(owned, s)
+		}
+	}
+	return owned, nil
+}
+
+// dynamodbTables describes all tables, as they are only listed by name.
+func (a *AWS) dynamodbTables() (interface{}, error) {
+	var names []*string
+	err := a.ListTablesPages(&dynamodb.ListTablesInput{}, func(page *dynamodb.ListTablesOutput, lastPage bool) bool {
+		names = append(names, page.TableNames...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []*dynamodb.TableDescription
+	for _, name := range names {
+		var output *dynamodb.DescribeTableOutput
+		err := retryThrottled(func() error {
+			var err error
+			output, err = a.DescribeTable(&dynamodb.DescribeTableInput{TableName: name})
+			return err
+		})
+		if IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, output.Table)
+	}
+	return tables, nil
+}
+
 func (a *AWS) efsFileSystems() (interface{}, error) {
 	output, err := a.DescribeFileSystems(&efs.DescribeFileSystemsInput{})
 	if err != nil {
@@ -325,68 +1182,256 @@ func (a *AWS) efsFileSystems() (interface{}, error) {
 	return output.FileSystems, nil
 }
 
+// ecsClusters describes all active clusters, as they are only listed by ARN.
+func (a *AWS) ecsClusters() (interface{}, error) {
+	var arns []*string
+	err := a.ListClustersPages(&ecs.ListClustersInput{}, func(page *ecs.ListClustersOutput, lastPage bool) bool {
+		arns = append(arns, page.ClusterArns...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var clusters []*ecs.Cluster
+	// at most 100 clusters can be described at once
+	for i := 0; i < len(arns); i += 100 {
+		end := i + 100
+		if end > len(arns) {
+			end = len(arns)
+		}
+		output, err := a.ECSAPI.DescribeClusters(&ecs.DescribeClustersInput{Clusters: arns[i:end]})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range output.Clusters {
+			// deleted clusters stay visible as INACTIVE for a while
+			if aws.StringValue(c.Status) == "ACTIVE" {
+				clusters = append(clusters, c)
+			}
+		}
+	}
+	return clusters, nil
+}
+
+// ecsServices lists the active services of all clusters.
+func (a *AWS) ecsServices() (interface{}, error) {
+	var clusterArns []*string
+	err := a.ListClustersPages(&ecs.ListClustersInput{}, func(page *ecs.ListClustersOutput, lastPage bool) bool {
+		clusterArns = append(clusterArns, page.ClusterArns...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var services []*ecs.Service
+	for _, clusterArn := range clusterArns {
+		clusterServices, err := a.ecsClusterServices(clusterArn)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, clusterServices...)
+	}
+	return services, nil
+}
+
+// ecsClusterServices describes the active services of a cluster, as they are only listed by ARN.
+func (a *AWS) ecsClusterServices(clusterArn *string) ([]*ecs.Service, error) {
+	var arns []*string
+	err := a.ListServicesPages(&ecs.ListServicesInput{
+		Cluster: clusterArn,
+	}, func(page *ecs.ListServicesOutput, lastPage bool) bool {
+		arns = append(arns, page.ServiceArns...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var services []*ecs.Service
+	// at most 10 services can be described at once
+	for i := 0; i < len(arns); i += 10 {
+		end := i + 10
+		if end > len(arns) {
+			end = len(arns)
+		}
+		output, err := a.ECSAPI.DescribeServices(&ecs.DescribeServicesInput{
+			Cluster:  clusterArn,
+			Services: arns[i:end],
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range output.Services {
+			// services being deleted are DRAINING, deleted ones INACTIVE
+			if aws.StringValue(s.Status) == "ACTIVE" {
+				services = append(services, s)
+			}
+		}
+	}
+	return services, nil
+}
+
+// ecsTaskDefinitionRevision is a revision of an ECS task definition, which the AWS API lists by its ARN only
+// (arn:aws:ecs:<region>:<account>:task-definition/<family>:<revision>).
+type ecsTaskDefinitionRevision struct {
+	TaskDefinitionArn *string
+	Family            *string
+	Revision          *int64
+}
+
+func newEcsTaskDefinitionRevision(taskDefinitionArn *string) *ecsTaskDefinitionRevision {
+	r := &ecsTaskDefinitionRevision{TaskDefinitionArn: taskDefinitionArn}
+
+	parsed, err := arn.Parse(aws.StringValue(taskDefinitionArn))
+	if err != nil {
+		return r
+	}
+	name := strings.TrimPrefix(parsed.Resource, "task-definition/")
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		if revision, err := strconv.ParseInt(name[i+1:], 10, 64); err == nil {
+			r.Family = aws.String(name[:i])
+			r.Revision = aws.Int64(revision)
+		}
+	}
+	return r
+}
+
+// ecsTaskDefinitions lists the active revisions of all task definitions
+// (inactive revisions cannot be deleted, AWS removes them eventually).
+func (a *AWS) ecsTaskDefinitions() (interface{}, error) {
+	var revisions []*ecsTaskDefinitionRevision
+	err := a.ListTaskDefinitionsPages(&ecs.ListTaskDefinitionsInput{
+		Status: aws.String(ecs.TaskDefinitionStatusActive),
+	}, func(page *ecs.ListTaskDefinitionsOutput, lastPage bool) bool {
+		for _, arn := range page.TaskDefinitionArns {
+			revisions = append(revisions, newEcsTaskDefinitionRevision(arn))
+		}
+		return true
+	})
+	return revisions, err
+}
+
+func (a *AWS) beanstalkApplications() (interface{}, error) {
+	output, err := a.DescribeApplications(&elasticbeanstalk.DescribeApplicationsInput{})
+	if err != nil {
+		return nil, err
+	}
+	return output.Applications, nil
+}
+
+func (a *AWS) beanstalkEnvironments() (interface{}, error) {
+	return a.beanstalkEnvironmentsOf(nil)
+}
+
+// beanstalkEnvironmentsOf describes the environments of an Elastic Beanstalk application (of all applications, if nil).
+// Terminated environments are still described for about an hour, but can't be deleted anymore.
+func (a *AWS) beanstalkEnvironmentsOf(appName *string) ([]*elasticbeanstalk.EnvironmentDescription, error) {
+	var environments []*elasticbeanstalk.EnvironmentDescription
+
+	input := &elasticbeanstalk.DescribeEnvironmentsInput{
+		ApplicationName: appName,
+		IncludeDeleted:  aws.Bool(false),
+	}
+	for {
+		output, err := a.DescribeEnvironments(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range output.Environments {
+			if aws.StringValue(e.Status) != elasticbeanstalk.EnvironmentStatusTerminated {
+				environments = append(environments, e)
+			}
+		}
+
+		if output.NextToken == nil {
+			return environments, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// beanstalkAppVersions lists the versions of all Elastic Beanstalk applications.
+func (a *AWS) beanstalkAppVersions() (interface{}, error) {
+	var versions []*elasticbeanstalk.ApplicationVersionDescription
+
+	input := &elasticbeanstalk.DescribeApplicationVersionsInput{}
+	for {
+		output, err := a.DescribeApplicationVersions(input)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, output.ApplicationVersions...)
+
+		if output.NextToken == nil {
+			return versions, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
 // Elastic network interface (ENI) resource
 // sort by owner of the network interface?
-// support findTags
 // attached to subnet
-func (a *AWS) networkInterfaces() (interface{}, error) {
-	output, err := a.DescribeNetworkInterfaces(&ec2.DescribeNetworkInterfacesInput{})
+func (a *AWS) networkInterfaces(filters ...*ec2.Filter) (interface{}, error) {
+	output, err := a.DescribeNetworkInterfaces(&ec2.DescribeNetworkInterfacesInput{Filters: filters})
 	if err != nil {
 		return nil, err
 	}
 	return output.NetworkInterfaces, nil
 }
 
-func (a *AWS) eips() (interface{}, error) {
-	output, err := a.DescribeAddresses(&ec2.DescribeAddressesInput{})
+func (a *AWS) eips(filters ...*ec2.Filter) (interface{}, error) {
+	output, err := a.DescribeAddresses(&ec2.DescribeAddressesInput{Filters: filters})
 	if err != nil {
 		return nil, err
 	}
 	return output.Addresses, nil
 }
 
-func (a *AWS) internetGateways() (interface{}, error) {
-	output, err := a.DescribeInternetGateways(&ec2.DescribeInternetGatewaysInput{})
+func (a *AWS) internetGateways(filters ...*ec2.Filter) (interface{}, error) {
+	output, err := a.DescribeInternetGateways(&ec2.DescribeInternetGatewaysInput{Filters: filters})
 	if err != nil {
 		return nil, err
 	}
 	return output.InternetGateways, nil
 }
 
-func (a *AWS) subnets() (interface{}, error) {
-	output, err := a.DescribeSubnets(&ec2.DescribeSubnetsInput{})
+func (a *AWS) subnets(filters ...*ec2.Filter) (interface{}, error) {
+	output, err := a.DescribeSubnets(&ec2.DescribeSubnetsInput{Filters: filters})
 	if err != nil {
 		return nil, err
 	}
 	return output.Subnets, nil
 }
 
-func (a *AWS) routeTables() (interface{}, error) {
-	output, err := a.DescribeRouteTables(&ec2.DescribeRouteTablesInput{})
+func (a *AWS) routeTables(filters ...*ec2.Filter) (interface{}, error) {
+	output, err := a.DescribeRouteTables(&ec2.DescribeRouteTablesInput{Filters: filters})
 	if err != nil {
 		return nil, err
 	}
 	return output.RouteTables, nil
 }
 
-func (a *AWS) SecurityGroup() (interface{}, error) {
-	output, err := a.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{})
+func (a *AWS) SecurityGroup(filters ...*ec2.Filter) (interface{}, error) {
+	output, err := a.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{Filters: filters})
 	if err != nil {
 		return nil, err
 	}
 	return output.SecurityGroups, nil
 }
 
-func (a *AWS) networkAcls() (interface{}, error) {
-	output, err := a.DescribeNetworkAcls(&ec2.DescribeNetworkAclsInput{})
+func (a *AWS) networkAcls(filters ...*ec2.Filter) (interface{}, error) {
+	output, err := a.DescribeNetworkAcls(&ec2.DescribeNetworkAclsInput{Filters: filters})
 	if err != nil {
 		return nil, err
 	}
 	return output.NetworkAcls, nil
 }
 
-func (a *AWS) vpcs() (interface{}, error) {
-	output, err := a.DescribeVpcs(&ec2.DescribeVpcsInput{})
+func (a *AWS) vpcs(filters ...*ec2.Filter) (interface{}, error) {
+	output, err := a.DescribeVpcs(&ec2.DescribeVpcsInput{Filters: filters})
 	if err != nil {
 		return nil, err
 	}
@@ -402,7 +1447,7 @@ func (a *AWS) iamPolicies() (interface{}, error) {
 }
 
 func (a *AWS) iamGroups() (interface{}, error) {
-	output, err := a.ListGroups(&iam.ListGroupsInput{})
+	output, err := a.IAMAPI.ListGroups(&iam.ListGroupsInput{})
 	if err != nil {
 		return nil, err
 	}
@@ -410,13 +1455,114 @@ func (a *AWS) iamGroups() (interface{}, error) {
 }
 
 func (a *AWS) iamUsers() (interface{}, error) {
-	output, err := a.ListUsers(&iam.ListUsersInput{})
+	output, err := a.IAMAPI.ListUsers(&iam.ListUsersInput{})
 	if err != nil {
 		return nil, err
 	}
 	return output.Users, nil
 }
 
+// iamRelation is a relation between IAM principals and groups or policies (e.g., a policy attached to a role),
+// which the AWS API lists as part of the principals, but Terraform manages as a resource of its own.
+type iamRelation struct {
+	// ID is <principal>/<group or policy ARN> (the format Terraform imports relations by)
+	ID        *string
+	Principal *string
+	Target    *string
+}
+
+func newIamRelation(principal, target *string) *iamRelation {
+	return &iamRelation{
+		ID:        aws.String(aws.StringValue(principal) + "/" + aws.StringValue(target)),
+		Principal: principal,
+		Target:    target,
+	}
+}
+
+// iamAuthorizationDetails lists the details (including groups and attached policies) of all IAM users or roles.
+func (a *AWS) iamAuthorizationDetails(entityType string) (*iam.GetAccountAuthorizationDetailsOutput, error) {
+	details := &iam.GetAccountAuthorizationDetailsOutput{}
+	err := a.GetAccountAuthorizationDetailsPages(&iam.GetAccountAuthorizationDetailsInput{
+		Filter: aws.StringSlice([]string{entityType}),
+	}, func(page *iam.GetAccountAuthorizationDetailsOutput, lastPage bool) bool {
+		details.UserDetailList = append(details.UserDetailList, page.UserDetailList...)
+		details.RoleDetailList = append(details.RoleDetailList, page.RoleDetailList...)
+		return true
+	})
+	return details, err
+}
+
+// iamUserGroupMemberships lists the memberships of users in groups, one per user and group.
+func (a *AWS) iamUserGroupMemberships() (interface{}, error) {
+	details, err := a.iamAuthorizationDetails(iam.EntityTypeUser)
+	if err != nil {
+		return nil, err
+	}
+
+	var relations []*iamRelation
+	for _, u := range details.UserDetailList {
+		for _, g := range u.GroupList {
+			relations = append(relations, newIamRelation(u.UserName, g))
+		}
+	}
+	return relations, nil
+}
+
+func (a *AWS) iamUserPolicyAttachments() (interface{}, error) {
+	details, err := a.iamAuthorizationDetails(iam.EntityTypeUser)
+	if err != nil {
+		return nil, err
+	}
+
+	var relations []*iamRelation
+	for _, u := range details.UserDetailList {
+		for _, p := range u.AttachedManagedPolicies {
+			relations = append(relations, newIamRelation(u.UserName, p.PolicyArn))
+		}
+	}
+	return relations, nil
+}
+
+func (a *AWS) iamRolePolicyAttachments() (interface{}, error) {
+	details, err := a.iamAuthorizationDetails(iam.EntityTypeRole)
+	if err != nil {
+		return nil, err
+	}
+
+	var relations []*iamRelation
+	for _, r := range details.RoleDetailList {
+		for _, p := range r.AttachedManagedPolicies {
+			relations = append(relations, newIamRelation(r.RoleName, p.PolicyArn))
+		}
+	}
+	return relations, nil
+}
+
+func (a *AWS) iamAccessKeys() (interface{}, error) {
+	var users []*iam.User
+	err := a.ListUsersPages(&iam.ListUsersInput{}, func(page *iam.ListUsersOutput, lastPage bool) bool {
+		users = append(users, page.Users...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*iam.AccessKeyMetadata
+	for _, u := range users {
+		err := a.ListAccessKeysPages(&iam.ListAccessKeysInput{
+			UserName: u.UserName,
+		}, func(page *iam.ListAccessKeysOutput, lastPage bool) bool {
+			keys = append(keys, page.AccessKeyMetadata...)
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
 func (a *AWS) iamRoles() (interface{}, error) {
 	output, err := a.ListRoles(&iam.ListRolesInput{})
 	if err != nil {
@@ -433,20 +1579,182 @@ func (a *AWS) iamInstanceProfiles() (interface{}, error) {
 	return output.InstanceProfiles, nil
 }
 
+// firehoseDeliveryStreams describes all delivery streams that aren't being deleted, as they are only listed by name.
+func (a *AWS) firehoseDeliveryStreams() (interface{}, error) {
+	var names []*string
+	input := &firehose.ListDeliveryStreamsInput{}
+	for {
+		output, err := a.ListDeliveryStreams(input)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, output.DeliveryStreamNames...)
+		if !aws.BoolValue(output.HasMoreDeliveryStreams) || len(output.DeliveryStreamNames) == 0 {
+			break
+		}
+		input.ExclusiveStartDeliveryStreamName = output.DeliveryStreamNames[len(output.DeliveryStreamNames)-1]
+	}
+
+	var streams []*firehose.DeliveryStreamDescription
+	for _, name := range names {
+		output, err := a.DescribeDeliveryStream(&firehose.DescribeDeliveryStreamInput{DeliveryStreamName: name})
+		if err != nil {
+			if IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		s := output.DeliveryStreamDescription
+		if aws.StringValue(s.DeliveryStreamStatus) != firehose.DeliveryStreamStatusDeleting {
+			streams = append(streams, s)
+		}
+	}
+	return streams, nil
+}
+
+// glueDatabase is a database of the Glue data catalog of the account.
+type glueDatabase struct {
+	// ID is <catalog ID>:<name> (the format Terraform imports databases by)
+	ID          *string
+	Name        *string
+	LocationUri *string
+	CreateTime  *time.Time
+}
+
+// glueCatalogDatabases lists the databases of the data catalog of the account (the catalog ID is the account ID).
+func (a *AWS) glueCatalogDatabases() (interface{}, error) {
+	accountID, err := a.AccountID()
+	if err != nil {
+		return nil, err
+	}
+
+	var databases []*glueDatabase
+	err = a.GetDatabasesPages(&glue.GetDatabasesInput{}, func(page *glue.GetDatabasesOutput, lastPage bool) bool {
+		for _, d := range page.DatabaseList {
+			databases = append(databases, &glueDatabase{
+				ID:          aws.String(accountID + ":" + aws.StringValue(d.Name)),
+				Name:        d.Name,
+				LocationUri: d.LocationUri,
+				CreateTime:  d.CreateTime,
+			})
+		}
+		return true
+	})
+	return databases, err
+}
+
+func (a *AWS) glueCrawlers() (interface{}, error) {
+	var crawlers []*glue.Crawler
+	err := a.GetCrawlersPages(&glue.GetCrawlersInput{}, func(page *glue.GetCrawlersOutput, lastPage bool) bool {
+		crawlers = append(crawlers, page.Crawlers...)
+		return true
+	})
+	return crawlers, err
+}
+
+func (a *AWS) glueJobs() (interface{}, error) {
+	var jobs []*glue.Job
+	err := a.GetJobsPages(&glue.GetJobsInput{}, func(page *glue.GetJobsOutput, lastPage bool) bool {
+		jobs = append(jobs, page.Jobs...)
+		return true
+	})
+	return jobs, err
+}
+
+// kinesisStreams describes all streams that aren't being deleted, as streams are only listed by name.
+func (a *AWS) kinesisStreams() (interface{}, error) {
+	var names []*string
+	err := a.ListStreamsPages(&kinesis.ListStreamsInput{}, func(page *kinesis.ListStreamsOutput, lastPage bool) bool {
+		names = append(names, page.StreamNames...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var streams []*kinesis.StreamDescriptionSummary
+	for _, name := range names {
+		output, err := a.DescribeStreamSummary(&kinesis.DescribeStreamSummaryInput{StreamName: name})
+		if err != nil {
+			if IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if aws.StringValue(output.StreamDescriptionSummary.StreamStatus) != kinesis.StreamStatusDeleting {
+			streams = append(streams, output.StreamDescriptionSummary)
+		}
+	}
+	return streams, nil
+}
+
 func (a *AWS) KmsAliases() (interface{}, error) {
-	output, err := a.ListAliases(&kms.ListAliasesInput{})
+	output, err := a.KMSAPI.ListAliases(&kms.ListAliasesInput{})
 	if err != nil {
 		return nil, err
 	}
 	return output.Aliases, nil
 }
 
+// kmsKey is the metadata of a KMS key together with the names of its aliases.
+type kmsKey struct {
+	KeyId        *string
+	Arn          *string
+	CreationDate *time.Time
+	Description  *string
+	KeyManager   *string
+	KeyState     *string
+	KeyUsage     *string
+	Aliases      []*string
+}
+
+// KmsKeys lists all keys with their metadata and aliases, as keys are only listed by ID.
 func (a *AWS) KmsKeys() (interface{}, error) {
-	output, err := a.ListKeys(&kms.ListKeysInput{})
+	var entries []*kms.KeyListEntry
+	err := a.ListKeysPages(&kms.ListKeysInput{}, func(page *kms.ListKeysOutput, lastPage bool) bool {
+		entries = append(entries, page.Keys...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := map[string][]*string{}
+	err = a.KMSAPI.ListAliasesPages(&kms.ListAliasesInput{}, func(page *kms.ListAliasesOutput, lastPage bool) bool {
+		for _, alias := range page.Aliases {
+			aliases[aws.StringValue(alias.TargetKeyId)] = append(aliases[aws.StringValue(alias.TargetKeyId)], alias.AliasName)
+		}
+		return true
+	})
 	if err != nil {
 		return nil, err
 	}
-	return output.Keys, nil
+
+	var keys []*kmsKey
+	for _, e := range entries {
+		var output *kms.DescribeKeyOutput
+		err := retryThrottled(func() error {
+			var err error
+			output, err = a.DescribeKey(&kms.DescribeKeyInput{KeyId: e.KeyId})
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		m := output.KeyMetadata
+		keys = append(keys, &kmsKey{
+			KeyId:        m.KeyId,
+			Arn:          m.Arn,
+			CreationDate: m.CreationDate,
+			Description:  m.Description,
+			KeyManager:   m.KeyManager,
+			KeyState:     m.KeyState,
+			KeyUsage:     m.KeyUsage,
+			Aliases:      aliases[aws.StringValue(m.KeyId)],
+		})
+	}
+	return keys, nil
 }
 
 func (a *AWS) s3Buckets() (interface{}, error) {
@@ -457,42 +1765,172 @@ func (a *AWS) s3Buckets() (interface{}, error) {
 	return output.Buckets, nil
 }
 
-func (a *AWS) ebsSnapshots() (interface{}, error) {
-	output, err := a.DescribeSnapshots(&ec2.DescribeSnapshotsInput{
-		Filters: []*ec2.Filter{
-			{
-				Name: aws.String("owner-id"),
-				Values: []*string{
-					a.callerIdentity(),
-				},
-			},
-		},
+func (a *AWS) sesIdentities(identityType string) (interface{}, error) {
+	output, err := a.SESAPI.ListIdentities(&ses.ListIdentitiesInput{
+		IdentityType: aws.String(identityType),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.Identities, nil
+}
+
+func (a *AWS) sfnStateMachines() (interface{}, error) {
+	var stateMachines []*sfn.StateMachineListItem
+	err := a.ListStateMachinesPages(&sfn.ListStateMachinesInput{}, func(page *sfn.ListStateMachinesOutput, lastPage bool) bool {
+		stateMachines = append(stateMachines, page.StateMachines...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stateMachines, nil
+}
+
+func (a *AWS) snsTopics() (interface{}, error) {
+	var topics []*sns.Topic
+	err := a.ListTopicsPages(&sns.ListTopicsInput{}, func(page *sns.ListTopicsOutput, lastPage bool) bool {
+		topics = append(topics, page.Topics...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return topics, nil
+}
+
+// snsSubscriptions lists the subscriptions of all topics.
+func (a *AWS) snsSubscriptions() (interface{}, error) {
+	var subscriptions []*sns.Subscription
+	err := a.ListSubscriptionsPages(&sns.ListSubscriptionsInput{}, func(page *sns.ListSubscriptionsOutput, lastPage bool) bool {
+		subscriptions = append(subscriptions, confirmedSubscriptions(page.Subscriptions)...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// snsTopicSubscriptions lists the subscriptions of a topic.
+func (a *AWS) snsTopicSubscriptions(topicArn *string) ([]*sns.Subscription, error) {
+	var subscriptions []*sns.Subscription
+	err := a.ListSubscriptionsByTopicPages(&sns.ListSubscriptionsByTopicInput{
+		TopicArn: topicArn,
+	}, func(page *sns.ListSubscriptionsByTopicOutput, lastPage bool) bool {
+		subscriptions = append(subscriptions, confirmedSubscriptions(page.Subscriptions)...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// confirmedSubscriptions skips subscriptions pending confirmation, which have no ARN yet
+// and can't be deleted (they expire after three days or together with their topic).
+func confirmedSubscriptions(subscriptions []*sns.Subscription) []*sns.Subscription {
+	var result []*sns.Subscription
+	for _, s := range subscriptions {
+		if aws.StringValue(s.SubscriptionArn) != "PendingConfirmation" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// sqsQueues lists the URLs of all queues (SQS returns at most 1000 queues).
+func (a *AWS) sqsQueues() (interface{}, error) {
+	output, err := a.ListQueues(&sqs.ListQueuesInput{})
+	if err != nil {
+		return nil, err
+	}
+	return output.QueueUrls, nil
+}
+
+func (a *AWS) ssmParameters() (interface{}, error) {
+	var parameters []*ssm.ParameterMetadata
+	err := a.DescribeParametersPages(&ssm.DescribeParametersInput{}, func(page *ssm.DescribeParametersOutput, lastPage bool) bool {
+		parameters = append(parameters, page.Parameters...)
+		return true
 	})
+	if err != nil {
+		return nil, err
+	}
+	return parameters, nil
+}
 
+func (a *AWS) sesConfigurationSets() (interface{}, error) {
+	output, err := a.ListConfigurationSets(&ses.ListConfigurationSetsInput{})
 	if err != nil {
 		return nil, err
 	}
-	return output.Snapshots, nil
+	return output.ConfigurationSets, nil
 }
 
-func (a *AWS) ebsVolumes() (interface{}, error) {
-	output, err := a.DescribeVolumes(&ec2.DescribeVolumesInput{})
+func (a *AWS) sesReceiptRuleSets() (interface{}, error) {
+	output, err := a.ListReceiptRuleSets(&ses.ListReceiptRuleSetsInput{})
+	if err != nil {
+		return nil, err
+	}
+	return output.RuleSets, nil
+}
+
+func (a *AWS) ebsSnapshots(filters ...*ec2.Filter) (interface{}, error) {
+	var snapshots []*ec2.Snapshot
+	err := a.ebsSnapshotPages(filters, func(page interface{}) error {
+		snapshots = append(snapshots, page.([]*ec2.Snapshot)...)
+		return nil
+	})
+	return snapshots, err
+}
+
+// ebsSnapshotPages lists the snapshots owned by the account, passing a page of them at a time to fn.
+func (a *AWS) ebsSnapshotPages(filters []*ec2.Filter, fn func(page interface{}) error) error {
+	accountID, err := a.AccountID()
+	if err != nil {
+		return err
+	}
+
+	var fnErr error
+	err = a.DescribeSnapshotsPages(&ec2.DescribeSnapshotsInput{
+		Filters: append([]*ec2.Filter{
+			{
+				Name:   aws.String("owner-id"),
+				Values: []*string{aws.String(accountID)},
+			},
+		}, filters...),
+	}, func(page *ec2.DescribeSnapshotsOutput, lastPage bool) bool {
+		fnErr = fn(page.Snapshots)
+		return fnErr == nil
+	})
+	if err != nil {
+		return err
+	}
+	return fnErr
+}
+
+func (a *AWS) ebsVolumes(filters ...*ec2.Filter) (interface{}, error) {
+	output, err := a.DescribeVolumes(&ec2.DescribeVolumesInput{Filters: filters})
 	if err != nil {
 		return nil, err
 	}
 	return output.Volumes, nil
 }
 
-func (a *AWS) amis() (interface{}, error) {
+func (a *AWS) amis(filters ...*ec2.Filter) (interface{}, error) {
+	accountID, err := a.AccountID()
+	if err != nil {
+		return nil, err
+	}
+
 	output, err := a.DescribeImages(&ec2.DescribeImagesInput{
-		Filters: []*ec2.Filter{
+		Filters: append([]*ec2.Filter{
 			{
-				Name: aws.String("owner-id"),
-				Values: []*string{
-					a.callerIdentity(),
-				},
+				Name:   aws.String("owner-id"),
+				Values: []*string{aws.String(accountID)},
 			},
-		},
+		}, filters...),
 	})
 
 	if err != nil {
@@ -517,11 +1955,54 @@ func (a *AWS) launchConfigurations() (interface{}, error) {
 	return output.LaunchConfigurations, nil
 }
 
-// callerIdentity returns the account ID of the AWS account for the currently used credentials
-func (a *AWS) callerIdentity() *string {
-	res, err := a.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+func (a *AWS) lbs() (interface{}, error) {
+	var lbs []*elbv2.LoadBalancer
+	err := a.ELBV2API.DescribeLoadBalancersPages(&elbv2.DescribeLoadBalancersInput{},
+		func(page *elbv2.DescribeLoadBalancersOutput, lastPage bool) bool {
+			lbs = append(lbs, page.LoadBalancers...)
+			return true
+		})
+	return lbs, err
+}
+
+func (a *AWS) lbListeners() (interface{}, error) {
+	lbs, err := a.lbs()
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+
+	var listeners []*elbv2.Listener
+	for _, lb := range lbs.([]*elbv2.LoadBalancer) {
+		lbListeners, err := a.lbListenersOf(lb.LoadBalancerArn)
+		if err != nil {
+			return nil, err
+		}
+		listeners = append(listeners, lbListeners...)
+	}
+	return listeners, nil
+}
+
+// lbListenersOf describes the listeners of a load balancer, as listeners can't be described across load balancers.
+func (a *AWS) lbListenersOf(lbArn *string) ([]*elbv2.Listener, error) {
+	var listeners []*elbv2.Listener
+	err := a.DescribeListenersPages(&elbv2.DescribeListenersInput{
+		LoadBalancerArn: lbArn,
+	}, func(page *elbv2.DescribeListenersOutput, lastPage bool) bool {
+		listeners = append(listeners, page.Listeners...)
+		return true
+	})
+	if err != nil {
+		return nil, err
 	}
-	return res.Account
+	return listeners, nil
+}
+
+func (a *AWS) lbTargetGroups() (interface{}, error) {
+	var groups []*elbv2.TargetGroup
+	err := a.DescribeTargetGroupsPages(&elbv2.DescribeTargetGroupsInput{},
+		func(page *elbv2.DescribeTargetGroupsOutput, lastPage bool) bool {
+			groups = append(groups, page.TargetGroups...)
+			return true
+		})
+	return groups, err
 }
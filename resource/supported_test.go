@@ -9,6 +9,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/cloudetc/awsweeper/resource"
 	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/cloudetc/awsweeper/resource/resourcetest"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -77,6 +78,180 @@ func TestAWS_Resources_Amis(t *testing.T) {
 	assert.Equal(t, *res[0].ImageId, testAmiName)
 }
 
+func TestAWS_Resources_DbSnapshotsOfAccount(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"sts": {
+			"GetCallerIdentity": map[string]interface{}{"Account": "123456789012"},
+		},
+		"rds": {
+			"DescribeDBSnapshots": map[string]interface{}{
+				"DBSnapshots": []interface{}{
+					map[string]interface{}{
+						"DBSnapshotIdentifier": "own",
+						"DBSnapshotArn":        "arn:aws:rds:us-east-1:123456789012:snapshot:own",
+						"AllocatedStorage":     20,
+						"SnapshotCreateTime":   "2018-10-01T00:00:00Z",
+					},
+					map[string]interface{}{
+						"DBSnapshotIdentifier": "arn:aws:rds:us-east-1:210987654321:snapshot:shared",
+						"DBSnapshotArn":        "arn:aws:rds:us-east-1:210987654321:snapshot:shared",
+					},
+				},
+			},
+		},
+	})
+
+	// when
+	raw, err := a.RawResources(resource.DbSnapshot)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.DbSnapshot, raw)
+	require.NoError(t, err)
+
+	// then
+	require.Len(t, res, 1)
+	assert.Equal(t, "own", res[0].ID)
+	assert.NotNil(t, res[0].Created)
+	assert.Equal(t, int64(20), res[0].Model.(resource.DbSnapshotModel).SizeGb)
+}
+
+func TestAWS_Resources_ApiGatewayRestApis(t *testing.T) {
+	// given
+	a, b := resourcetest.NewAWS(resourcetest.Fixtures{
+		"apigateway": {
+			"GetRestApis": map[string]interface{}{
+				"Items": []interface{}{
+					map[string]interface{}{"Id": "a1b2c3", "Name": "ci-api", "CreatedDate": "2018-10-01T00:00:00Z"},
+				},
+			},
+		},
+	})
+
+	// when
+	raw, err := a.RawResources(resource.ApiGatewayRestApi)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.ApiGatewayRestApi, raw)
+	require.NoError(t, err)
+
+	// then
+	require.Len(t, res, 1)
+	assert.Equal(t, "a1b2c3", res[0].ID)
+	assert.NotNil(t, res[0].Created)
+	assert.Equal(t, []string{"apigateway:GetRestApis"}, b.Calls())
+}
+
+func TestAWS_Resources_GlueCatalogDatabases(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"sts": {
+			"GetCallerIdentity": map[string]interface{}{"Account": "123456789012"},
+		},
+		"glue": {
+			"GetDatabases": map[string]interface{}{
+				"DatabaseList": []interface{}{
+					map[string]interface{}{"Name": "experiments", "CreateTime": "2018-10-01T00:00:00Z"},
+				},
+			},
+		},
+	})
+
+	// when
+	raw, err := a.RawResources(resource.GlueCatalogDatabase)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.GlueCatalogDatabase, raw)
+	require.NoError(t, err)
+
+	// then
+	require.Len(t, res, 1)
+	assert.Equal(t, "123456789012:experiments", res[0].ID)
+	assert.NotNil(t, res[0].Created)
+}
+
+func TestAWS_Resources_GlueJobs(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"glue": {
+			"GetJobs": map[string]interface{}{
+				"Jobs": []interface{}{
+					map[string]interface{}{
+						"Name":      "etl-experiment",
+						"Role":      "arn:aws:iam::123456789012:role/glue",
+						"Command":   map[string]interface{}{"Name": "glueetl"},
+						"CreatedOn": "2018-10-01T00:00:00Z",
+					},
+				},
+			},
+		},
+	})
+
+	// when
+	raw, err := a.RawResources(resource.GlueJob)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.GlueJob, raw)
+	require.NoError(t, err)
+
+	// then
+	require.Len(t, res, 1)
+	assert.Equal(t, "etl-experiment", res[0].ID)
+	assert.NotNil(t, res[0].Created)
+	assert.Equal(t, "glueetl", res[0].Model.(resource.GlueJobModel).Command)
+}
+
+func TestAWS_Resources_AthenaNamedQueries(t *testing.T) {
+	// given
+	a, b := resourcetest.NewAWS(resourcetest.Fixtures{
+		"athena": {
+			"ListNamedQueries": map[string]interface{}{
+				"NamedQueryIds": []interface{}{"q-1"},
+			},
+			"BatchGetNamedQuery": map[string]interface{}{
+				"NamedQueries": []interface{}{
+					map[string]interface{}{"NamedQueryId": "q-1", "Name": "top-users", "Database": "sandbox"},
+				},
+			},
+		},
+	})
+
+	// when
+	raw, err := a.RawResources(resource.AthenaNamedQuery)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.AthenaNamedQuery, raw)
+	require.NoError(t, err)
+
+	// then
+	require.Len(t, res, 1)
+	assert.Equal(t, "q-1", res[0].ID)
+	assert.Equal(t, "sandbox", res[0].Model.(resource.AthenaNamedQueryModel).Database)
+	assert.Equal(t, []string{"athena:ListNamedQueries", "athena:BatchGetNamedQuery"}, b.Calls())
+}
+
+func TestAWS_Resources_SfnStateMachines(t *testing.T) {
+	// given
+	arn := "arn:aws:states:us-east-1:123456789012:stateMachine:etl"
+	a, b := resourcetest.NewAWS(resourcetest.Fixtures{
+		"states": {
+			"ListStateMachines": map[string]interface{}{
+				"StateMachines": []interface{}{
+					map[string]interface{}{"StateMachineArn": arn, "Name": "etl", "CreationDate": "2018-10-01T00:00:00Z"},
+				},
+			},
+		},
+	})
+
+	// when
+	raw, err := a.RawResources(resource.SfnStateMachine)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.SfnStateMachine, raw)
+	require.NoError(t, err)
+
+	// then
+	require.Len(t, res, 1)
+	assert.Equal(t, arn, res[0].ID)
+	require.NotNil(t, res[0].Created)
+	assert.Equal(t, 2018, res[0].Created.Year())
+	assert.Equal(t, []string{"states:ListStateMachines"}, b.Calls())
+}
+
 func TestAWS_Resources_AutoScalingGroups(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -166,3 +341,51 @@ func convertTags(tags map[string]string) []*autoscaling.TagDescription {
 	}
 	return tagDescriptions
 }
+
+func TestRegionalTypes(t *testing.T) {
+	// when
+	resTypes := resource.RegionalTypes([]resource.TerraformResourceType{
+		resource.IamRole, resource.Instance, resource.S3Bucket, resource.Vpc,
+	})
+
+	// then
+	assert.Equal(t, []resource.TerraformResourceType{resource.Instance, resource.Vpc}, resTypes)
+}
+
+func TestTypeCapabilities(t *testing.T) {
+	assert.Equal(t, resource.Capabilities{Tags: true, Created: true}, resource.TypeCapabilities(resource.Instance))
+	assert.Equal(t, resource.Capabilities{Tags: true, Created: true}, resource.TypeCapabilities(resource.Elb))
+	assert.Equal(t, resource.Capabilities{Tags: false, Created: true}, resource.TypeCapabilities(resource.IamRole))
+	assert.Equal(t, resource.Capabilities{Tags: false, Created: false}, resource.TypeCapabilities(resource.KeyPair))
+	assert.Equal(t, resource.Capabilities{Tags: false, Created: false}, resource.TypeCapabilities(resource.SesEmailIdentity))
+}
+
+func TestTypeCapabilities_AllSupportedTypes(t *testing.T) {
+	for _, resType := range resource.SupportedResourceTypes() {
+		// every supported type needs its raw resource type registered
+		caps := resource.TypeCapabilities(resType)
+		if caps.Tags || caps.Created {
+			continue
+		}
+		assert.Contains(t, []resource.TerraformResourceType{
+			resource.AthenaNamedQuery,
+			resource.CloudwatchDashboard,
+			resource.CognitoIdentityPool,
+			resource.ConfigConfigRule,
+			resource.ConfigRecorder,
+			resource.ConfigDelivery,
+			resource.EcsCluster,
+			resource.EcsTaskDefinition,
+			resource.IamRolePolicyAttachment,
+			resource.IamUserGroupMembership,
+			resource.IamUserPolicyAttachment,
+			resource.KeyPair,
+			resource.KmsAlias,
+			resource.LbListener,
+			resource.SesConfigurationSet,
+			resource.SesDomainIdentity,
+			resource.SesEmailIdentity,
+			resource.SnsTopicSubscription,
+		}, resType)
+	}
+}
@@ -0,0 +1,142 @@
+package resource
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+)
+
+// CreatorPlaceholder can be used as tag value in apply_tags
+// to write the name of the principal that created a resource.
+const CreatorPlaceholder = "{{creator}}"
+
+// ec2TagBatchSize is the maximum number of resources tagged with a single request to the EC2 API.
+const ec2TagBatchSize = 100
+
+// ec2Types are the resource types that are tagged by their ID via the EC2 API.
+var ec2Types = map[TerraformResourceType]bool{
+	Ami:              true,
+	EbsSnapshot:      true,
+	EbsVolume:        true,
+	Eip:              true,
+	Instance:         true,
+	InternetGateway:  true,
+	NatGateway:       true,
+	NetworkAcl:       true,
+	NetworkInterface: true,
+	RouteTable:       true,
+	SecurityGroup:    true,
+	Subnet:           true,
+	Vpc:              true,
+	VpcEndpoint:      true,
+}
+
+// ResolveTags replaces placeholders in the values of the given tags by values derived for the resource.
+func (a *AWS) ResolveTags(r *Resource, tags map[string]string) (map[string]string, error) {
+	result := map[string]string{}
+
+	for k, v := range tags {
+		if strings.Contains(v, CreatorPlaceholder) {
+			creator, err := a.creator(r)
+			if err != nil {
+				return nil, err
+			}
+			v = strings.Replace(v, CreatorPlaceholder, creator, -1)
+		}
+		result[k] = v
+	}
+	return result, nil
+}
+
+// Tag writes the given tags onto resources (of the same type).
+// Where the API allows it, multiple resources are tagged with a single request.
+func (a *AWS) Tag(res Resources, tags map[string]string) error {
+	if len(res) == 0 {
+		return nil
+	}
+
+	resType := res[0].Type
+
+	switch {
+	case ec2Types[resType]:
+		var ec2Tags []*ec2.Tag
+		for k, v := range tags {
+			ec2Tags = append(ec2Tags, &ec2.Tag{Key: aws.String(k), Value: aws.String(v)})
+		}
+
+		var ids []string
+		for _, r := range res {
+			ids = append(ids, r.ID)
+		}
+
+		for _, chunk := range chunks(ids, ec2TagBatchSize) {
+			err := retryThrottled(func() error {
+				_, err := a.EC2API.CreateTags(&ec2.CreateTagsInput{
+					Resources: aws.StringSlice(chunk),
+					Tags:      ec2Tags,
+				})
+				return err
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case resType == AutoscalingGroup:
+		for _, r := range res {
+			var asgTags []*autoscaling.Tag
+			for k, v := range tags {
+				asgTags = append(asgTags, &autoscaling.Tag{
+					Key:               aws.String(k),
+					Value:             aws.String(v),
+					ResourceId:        aws.String(r.ID),
+					ResourceType:      aws.String("auto-scaling-group"),
+					PropagateAtLaunch: aws.Bool(false),
+				})
+			}
+
+			err := retryThrottled(func() error {
+				_, err := a.CreateOrUpdateTags(&autoscaling.CreateOrUpdateTagsInput{
+					Tags: asgTags,
+				})
+				return err
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return errors.Errorf("applying tags is not supported for resource type: %s", resType)
+	}
+}
+
+// creator looks up the name of the principal that created a resource in the CloudTrail event history,
+// which covers the last 90 days.
+func (a *AWS) creator(r *Resource) (string, error) {
+	output, err := a.LookupEvents(&cloudtrail.LookupEventsInput{
+		LookupAttributes: []*cloudtrail.LookupAttribute{
+			{
+				AttributeKey:   aws.String(cloudtrail.LookupAttributeKeyResourceName),
+				AttributeValue: aws.String(r.ID),
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// events are sorted by time (newest first)
+	for i := len(output.Events) - 1; i >= 0; i-- {
+		e := output.Events[i]
+		name := aws.StringValue(e.EventName)
+		if strings.HasPrefix(name, "Create") || strings.HasPrefix(name, "Run") || strings.HasPrefix(name, "Allocate") {
+			return aws.StringValue(e.Username), nil
+		}
+	}
+	return "", errors.Errorf("no creation event found in CloudTrail for resource: %s", r.ID)
+}
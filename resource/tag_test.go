@@ -0,0 +1,73 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWS_Tag_Instance(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockEC2API(mockCtrl)
+	awsMock := &resource.AWS{
+		EC2API: mockObj,
+	}
+
+	mockObj.EXPECT().CreateTags(&ec2.CreateTagsInput{
+		Resources: []*string{aws.String(testInstanceID)},
+		Tags: []*ec2.Tag{
+			{Key: aws.String("owner"), Value: aws.String("foo")},
+		},
+	}).Return(&ec2.CreateTagsOutput{}, nil)
+
+	// when
+	err := awsMock.Tag(resource.Resources{
+		{
+			Type: resource.Instance,
+			ID:   testInstanceID,
+		},
+	}, map[string]string{"owner": "foo"})
+
+	// then
+	require.NoError(t, err)
+}
+
+func TestAWS_Tag_UnsupportedType(t *testing.T) {
+	// given
+	awsMock := &resource.AWS{}
+
+	// when
+	err := awsMock.Tag(resource.Resources{
+		{
+			Type: resource.IamRole,
+			ID:   "foo",
+		},
+	}, map[string]string{"owner": "foo"})
+
+	// then
+	assert.EqualError(t, err, "applying tags is not supported for resource type: aws_iam_role")
+}
+
+func TestAWS_ResolveTags_NoPlaceholder(t *testing.T) {
+	// given
+	awsMock := &resource.AWS{}
+
+	// when
+	tags, err := awsMock.ResolveTags(&resource.Resource{
+		Type: resource.Instance,
+		ID:   testInstanceID,
+	}, map[string]string{"CostCenter": "unknown"})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"CostCenter": "unknown"}, tags)
+}
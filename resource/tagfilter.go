@@ -0,0 +1,90 @@
+package resource
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// literalTagPattern matches tag regexes that are literals, optionally anchored at the start and/or end
+// (e.g., ^ci-), which can be translated into wildcard patterns of EC2 filters.
+var literalTagPattern = regexp.MustCompile(`^(\^?)([\w\-:/= @]+)(\$?)$`)
+
+// APITagFilters returns the wildcard patterns per tag key that restrict listing the resources of an EC2 type
+// on the API side to the ones the filter could select (nil if all resources have to be listed). This is only
+// possible if every entry of the type requires the same tag key with a literal regex (e.g., ^ci-) and no
+// resources are selected regardless of the entries (e.g., by their age).
+func (f Filter) APITagFilters(resType TerraformResourceType) map[string][]string {
+	if !ec2Types[resType] || f.MaxResourceAge > 0 || f.Inventory != nil {
+		return nil
+	}
+
+	entries := f.Cfg[resType]
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var keys []string
+	for key := range entries[0].Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var patterns []string
+		for _, rtf := range entries {
+			pattern, ok := wildcardPattern(rtf.Tags, key)
+			if !ok {
+				patterns = nil
+				break
+			}
+			patterns = append(patterns, pattern)
+		}
+		if len(patterns) > 0 {
+			return map[string][]string{key: patterns}
+		}
+	}
+	return nil
+}
+
+// wildcardPattern translates the literal regex of a tag key of a filter entry into a wildcard pattern.
+func wildcardPattern(tags map[string]string, key string) (string, bool) {
+	regex, found := tags[key]
+	if !found {
+		return "", false
+	}
+
+	m := literalTagPattern.FindStringSubmatch(regex)
+	if m == nil {
+		return "", false
+	}
+
+	pattern := m[2]
+	if m[1] == "" {
+		pattern = "*" + pattern
+	}
+	if m[3] == "" {
+		pattern = pattern + "*"
+	}
+	return pattern, true
+}
+
+// ec2TagFilters creates the EC2 filters for tags matching any of the given wildcard patterns per key.
+func ec2TagFilters(tags map[string][]string) []*ec2.Filter {
+	var keys []string
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var filters []*ec2.Filter
+	for _, key := range keys {
+		filters = append(filters, &ec2.Filter{
+			Name:   aws.String("tag:" + key),
+			Values: aws.StringSlice(tags[key]),
+		})
+	}
+	return filters
+}
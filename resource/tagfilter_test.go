@@ -0,0 +1,105 @@
+package resource_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_APITagFilters(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   resource.Filter
+		expected map[string][]string
+	}{
+		{
+			name: "literal regexes",
+			filter: resource.Filter{Cfg: resource.Config{resource.EbsVolume: {
+				{Tags: map[string]string{"Name": "^ci-", "Owner": "bob"}},
+				{Tags: map[string]string{"Name": "-tmp$"}},
+				{Tags: map[string]string{"Name": "^build$"}},
+				{Tags: map[string]string{"Name": "test"}},
+			}}},
+			expected: map[string][]string{"Name": {"ci-*", "*-tmp", "build", "*test*"}},
+		},
+		{
+			name: "first common key",
+			filter: resource.Filter{Cfg: resource.Config{resource.EbsVolume: {
+				{Tags: map[string]string{"Name": "^ci-", "Owner": "^bob$"}},
+				{Tags: map[string]string{"Owner": "^alice$"}},
+			}}},
+			expected: map[string][]string{"Owner": {"bob", "alice"}},
+		},
+		{
+			name: "regex",
+			filter: resource.Filter{Cfg: resource.Config{resource.EbsVolume: {
+				{Tags: map[string]string{"Name": "^ci-[0-9]+$"}},
+			}}},
+		},
+		{
+			name: "entry without tags",
+			filter: resource.Filter{Cfg: resource.Config{resource.EbsVolume: {
+				{Tags: map[string]string{"Name": "^ci-"}},
+				{ID: aws.String("^vol-")},
+			}}},
+		},
+		{
+			name:   "all resources",
+			filter: resource.Filter{Cfg: resource.Config{resource.EbsVolume: {}}},
+		},
+		{
+			name: "max resource age",
+			filter: resource.Filter{
+				Cfg:            resource.Config{resource.EbsVolume: {{Tags: map[string]string{"Name": "^ci-"}}}},
+				MaxResourceAge: 24 * time.Hour,
+			},
+		},
+		{
+			name: "no EC2 type",
+			filter: resource.Filter{Cfg: resource.Config{resource.IamRole: {
+				{Tags: map[string]string{"Name": "^ci-"}},
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for resType := range tt.filter.Cfg {
+				assert.Equal(t, tt.expected, tt.filter.APITagFilters(resType))
+			}
+		})
+	}
+}
+
+func TestAWS_RawResourcesWithTags(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockEC2API(mockCtrl)
+	awsMock := &resource.AWS{
+		EC2API: mockObj,
+	}
+
+	mockObj.EXPECT().DescribeVolumes(&ec2.DescribeVolumesInput{
+		Filters: []*ec2.Filter{
+			{Name: aws.String("tag:Name"), Values: aws.StringSlice([]string{"ci-*", "*-tmp"})},
+		},
+	}).Return(&ec2.DescribeVolumesOutput{
+		Volumes: []*ec2.Volume{{VolumeId: aws.String("vol-1")}},
+	}, nil)
+
+	// when
+	raw, err := awsMock.RawResourcesWithTags(resource.EbsVolume, map[string][]string{"Name": {"ci-*", "*-tmp"}})
+
+	// then
+	require.NoError(t, err)
+	assert.Len(t, raw, 1)
+}
@@ -0,0 +1,494 @@
+package resource
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/efs"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/cloudetc/awsweeper/internal/arn"
+)
+
+// tagFetchers fetch the tags of resources of types whose list API doesn't return tags.
+// Tags of resources of all other types (without native tags) are looked up via the Resource Groups Tagging API.
+var tagFetchers = map[TerraformResourceType]func(a *AWS, res Resources) error{
+	CloudfrontDistribution: (*AWS).cloudfrontTags,
+	CloudwatchLogGroup:     (*AWS).logGroupTags,
+	CognitoUserPool:        (*AWS).cognitoUserPoolTags,
+	DbClusterSnapshot:      (*AWS).rdsTags,
+	DbSnapshot:             (*AWS).rdsTags,
+	DynamodbTable:          (*AWS).dynamodbTags,
+	BeanstalkEnvironment:   (*AWS).beanstalkEnvironmentTags,
+	EfsFileSystem:          (*AWS).efsTags,
+	Elb:                    (*AWS).elbTags,
+	FirehoseDeliveryStream: (*AWS).firehoseTags,
+	KinesisStream:          (*AWS).kinesisTags,
+	KmsKey:                 (*AWS).kmsTags,
+	Lb:                     (*AWS).lbTags,
+	LbTargetGroup:          (*AWS).lbTags,
+	RdsCluster:             (*AWS).rdsTags,
+	Route53Zone:            (*AWS).route53Tags,
+	S3Bucket:               (*AWS).s3Tags,
+	SqsQueue:               (*AWS).sqsTags,
+	SsmParameter:           (*AWS).ssmTags,
+}
+
+// FetchTags sets the tags of resources (of the same type) for which the list API hasn't returned any tags.
+// Resources which tags can't be determined keep having no tags (nil). If the lookup fails, the resources
+// without tags are treated as leased (see Leased), as they might have a lease tag.
+func (a *AWS) FetchTags(res Resources) error {
+	err := a.fetchTags(res)
+	if err != nil {
+		for _, r := range res {
+			if r.Tags == nil {
+				r.tagsFailed = true
+			}
+		}
+	}
+	return err
+}
+
+func (a *AWS) fetchTags(res Resources) error {
+	var missing Resources
+	for _, r := range res {
+		if r.Tags == nil {
+			missing = append(missing, r)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if fetch, found := tagFetchers[missing[0].Type]; found {
+		return fetch(a, missing)
+	}
+	if filter, found := taggingAPITypes[missing[0].Type]; found {
+		return a.taggingAPITags(missing, filter)
+	}
+	return nil
+}
+
+func (a *AWS) logGroupTags(res Resources) error {
+	for _, r := range res {
+		output, err := a.ListTagsLogGroup(&cloudwatchlogs.ListTagsLogGroupInput{
+			LogGroupName: aws.String(r.ID),
+		})
+		if err != nil {
+			return err
+		}
+		r.Tags = aws.StringValueMap(output.Tags)
+	}
+	return nil
+}
+
+func (a *AWS) dynamodbTags(res Resources) error {
+	for _, r := range res {
+		m, ok := r.Model.(DynamodbTableModel)
+		if !ok {
+			continue
+		}
+
+		tags := map[string]string{}
+		input := &dynamodb.ListTagsOfResourceInput{ResourceArn: aws.String(m.arn)}
+		for {
+			output, err := a.ListTagsOfResource(input)
+			if err != nil {
+				return err
+			}
+			for _, t := range output.Tags {
+				tags[*t.Key] = aws.StringValue(t.Value)
+			}
+			if output.NextToken == nil {
+				break
+			}
+			input.NextToken = output.NextToken
+		}
+		r.Tags = tags
+	}
+	return nil
+}
+
+func (a *AWS) rdsTags(res Resources) error {
+	for _, r := range res {
+		// tags of RDS resources are looked up by ARN
+		arn, ok := rdsArn(r.Model)
+		if !ok {
+			continue
+		}
+
+		output, err := a.RDSAPI.ListTagsForResource(&rds.ListTagsForResourceInput{
+			ResourceName: aws.String(arn),
+		})
+		if err != nil {
+			return err
+		}
+
+		r.Tags = map[string]string{}
+		for _, t := range output.TagList {
+			r.Tags[*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+	return nil
+}
+
+// rdsArn returns the ARN of an RDS resource, which identifies it for the tagging APIs of RDS.
+func rdsArn(m Model) (string, bool) {
+	switch m := m.(type) {
+	case RdsClusterModel:
+		return m.arn, true
+	case DbSnapshotModel:
+		return m.arn, true
+	case DbClusterSnapshotModel:
+		return m.arn, true
+	default:
+		return "", false
+	}
+}
+
+// beanstalkEnvironmentTags looks up the tags of environments by ARN, as the IDs of environments
+// aren't part of their ARNs.
+func (a *AWS) beanstalkEnvironmentTags(res Resources) error {
+	for _, r := range res {
+		m, ok := r.Model.(BeanstalkEnvironmentModel)
+		if !ok {
+			continue
+		}
+
+		output, err := a.ElasticBeanstalkAPI.ListTagsForResource(&elasticbeanstalk.ListTagsForResourceInput{
+			ResourceArn: aws.String(m.arn),
+		})
+		if err != nil {
+			return err
+		}
+
+		r.Tags = map[string]string{}
+		for _, t := range output.ResourceTags {
+			r.Tags[*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+	return nil
+}
+
+func (a *AWS) efsTags(res Resources) error {
+	for _, r := range res {
+		output, err := a.EFSAPI.DescribeTags(&efs.DescribeTagsInput{
+			FileSystemId: aws.String(r.ID),
+		})
+		if err != nil {
+			return err
+		}
+
+		r.Tags = map[string]string{}
+		for _, t := range output.Tags {
+			r.Tags[*t.Key] = *t.Value
+		}
+	}
+	return nil
+}
+
+func (a *AWS) elbTags(res Resources) error {
+	byName := map[string]*Resource{}
+	var names []string
+	for _, r := range res {
+		byName[r.ID] = r
+		names = append(names, r.ID)
+	}
+
+	// tags of at most 20 load balancers can be described at once
+	for _, chunk := range chunks(names, 20) {
+		output, err := a.ELBAPI.DescribeTags(&elb.DescribeTagsInput{
+			LoadBalancerNames: aws.StringSlice(chunk),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, td := range output.TagDescriptions {
+			r, found := byName[aws.StringValue(td.LoadBalancerName)]
+			if !found {
+				continue
+			}
+			r.Tags = map[string]string{}
+			for _, t := range td.Tags {
+				r.Tags[*t.Key] = aws.StringValue(t.Value)
+			}
+		}
+	}
+	return nil
+}
+
+// lbTags fetches the tags of load balancers or target groups (ELBv2) by their ARNs.
+func (a *AWS) lbTags(res Resources) error {
+	byArn := map[string]*Resource{}
+	var arns []string
+	for _, r := range res {
+		byArn[r.ID] = r
+		arns = append(arns, r.ID)
+	}
+
+	// tags of at most 20 resources can be described at once
+	for _, chunk := range chunks(arns, 20) {
+		output, err := a.ELBV2API.DescribeTags(&elbv2.DescribeTagsInput{
+			ResourceArns: aws.StringSlice(chunk),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, td := range output.TagDescriptions {
+			r, found := byArn[aws.StringValue(td.ResourceArn)]
+			if !found {
+				continue
+			}
+			r.Tags = map[string]string{}
+			for _, t := range td.Tags {
+				r.Tags[*t.Key] = aws.StringValue(t.Value)
+			}
+		}
+	}
+	return nil
+}
+
+func (a *AWS) firehoseTags(res Resources) error {
+	for _, r := range res {
+		m, ok := r.Model.(FirehoseDeliveryStreamModel)
+		if !ok {
+			continue
+		}
+
+		r.Tags = map[string]string{}
+		input := &firehose.ListTagsForDeliveryStreamInput{DeliveryStreamName: aws.String(m.Name)}
+		for {
+			output, err := a.ListTagsForDeliveryStream(input)
+			if err != nil {
+				return err
+			}
+			for _, t := range output.Tags {
+				r.Tags[*t.Key] = aws.StringValue(t.Value)
+			}
+			if !aws.BoolValue(output.HasMoreTags) || len(output.Tags) == 0 {
+				break
+			}
+			input.ExclusiveStartTagKey = output.Tags[len(output.Tags)-1].Key
+		}
+	}
+	return nil
+}
+
+func (a *AWS) kinesisTags(res Resources) error {
+	for _, r := range res {
+		m, ok := r.Model.(KinesisStreamModel)
+		if !ok {
+			continue
+		}
+
+		r.Tags = map[string]string{}
+		input := &kinesis.ListTagsForStreamInput{StreamName: aws.String(m.Name)}
+		for {
+			output, err := a.ListTagsForStream(input)
+			if err != nil {
+				return err
+			}
+			for _, t := range output.Tags {
+				r.Tags[*t.Key] = aws.StringValue(t.Value)
+			}
+			if !aws.BoolValue(output.HasMoreTags) || len(output.Tags) == 0 {
+				break
+			}
+			input.ExclusiveStartTagKey = output.Tags[len(output.Tags)-1].Key
+		}
+	}
+	return nil
+}
+
+func (a *AWS) kmsTags(res Resources) error {
+	for _, r := range res {
+		output, err := a.ListResourceTags(&kms.ListResourceTagsInput{
+			KeyId: aws.String(r.ID),
+		})
+		if err != nil {
+			return err
+		}
+
+		r.Tags = map[string]string{}
+		for _, t := range output.Tags {
+			r.Tags[*t.TagKey] = *t.TagValue
+		}
+	}
+	return nil
+}
+
+func (a *AWS) route53Tags(res Resources) error {
+	byID := map[string]*Resource{}
+	var ids []string
+	for _, r := range res {
+		id := strings.TrimPrefix(r.ID, "/hostedzone/")
+		byID[id] = r
+		ids = append(ids, id)
+	}
+
+	// tags of at most 10 hosted zones can be listed at once
+	for _, chunk := range chunks(ids, 10) {
+		output, err := a.ListTagsForResources(&route53.ListTagsForResourcesInput{
+			ResourceIds:  aws.StringSlice(chunk),
+			ResourceType: aws.String(route53.TagResourceTypeHostedzone),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, ts := range output.ResourceTagSets {
+			r, found := byID[aws.StringValue(ts.ResourceId)]
+			if !found {
+				continue
+			}
+			r.Tags = map[string]string{}
+			for _, t := range ts.Tags {
+				r.Tags[*t.Key] = aws.StringValue(t.Value)
+			}
+		}
+	}
+	return nil
+}
+
+func (a *AWS) s3Tags(res Resources) error {
+	for _, r := range res {
+		output, err := a.GetBucketTagging(&s3.GetBucketTaggingInput{
+			Bucket: aws.String(r.ID),
+		})
+		if err != nil {
+			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NoSuchTagSet" {
+				r.Tags = map[string]string{}
+				continue
+			}
+			return err
+		}
+
+		r.Tags = map[string]string{}
+		for _, t := range output.TagSet {
+			r.Tags[*t.Key] = *t.Value
+		}
+	}
+	return nil
+}
+
+// taggingAPITags looks up the tags of resources via the Resource Groups Tagging API, which lists the ARNs
+// of the tagged resources of a type (given by the filter of the tagging API) in a region. A resource is identified
+// by its ARN or, if unknown, by its ID being the last part of an ARN (after the last '/' or ':') of its type.
+func (a *AWS) taggingAPITags(res Resources, resourceTypeFilter string) error {
+	tagsByARN := map[string]map[string]string{}
+	tagsByID := map[string]map[string]string{}
+
+	err := a.ResourceGroupsTaggingAPIAPI.GetResourcesPages(&resourcegroupstaggingapi.GetResourcesInput{
+		ResourceTypeFilters: []*string{aws.String(resourceTypeFilter)},
+	}, func(page *resourcegroupstaggingapi.GetResourcesOutput, lastPage bool) bool {
+		for _, m := range page.ResourceTagMappingList {
+			tags := map[string]string{}
+			for _, t := range m.Tags {
+				tags[*t.Key] = aws.StringValue(t.Value)
+			}
+
+			resourceARN := aws.StringValue(m.ResourceARN)
+			tagsByARN[resourceARN] = tags
+			tagsByID[arn.ResourceID(resourceARN)] = tags
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range res {
+		if resourceARN := r.fullARN(); resourceARN != "" {
+			if tags, found := tagsByARN[resourceARN]; found {
+				r.Tags = tags
+			}
+			continue
+		}
+		if tags, found := tagsByID[r.ID]; found {
+			r.Tags = tags
+		}
+	}
+	return nil
+}
+
+func (a *AWS) sqsTags(res Resources) error {
+	for _, r := range res {
+		output, err := a.ListQueueTags(&sqs.ListQueueTagsInput{
+			QueueUrl: aws.String(r.ID),
+		})
+		if err != nil {
+			return err
+		}
+		r.Tags = aws.StringValueMap(output.Tags)
+	}
+	return nil
+}
+
+func (a *AWS) ssmTags(res Resources) error {
+	for _, r := range res {
+		output, err := a.SSMAPI.ListTagsForResource(&ssm.ListTagsForResourceInput{
+			ResourceType: aws.String(ssm.ResourceTypeForTaggingParameter),
+			ResourceId:   aws.String(r.ID),
+		})
+		if err != nil {
+			return err
+		}
+
+		r.Tags = map[string]string{}
+		for _, t := range output.TagList {
+			r.Tags[*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+	return nil
+}
+
+func (a *AWS) cloudfrontTags(res Resources) error {
+	for _, r := range res {
+		output, err := a.CloudFrontAPI.ListTagsForResource(&cloudfront.ListTagsForResourceInput{
+			Resource: aws.String(r.arn),
+		})
+		if err != nil {
+			return err
+		}
+
+		r.Tags = map[string]string{}
+		if output.Tags == nil {
+			continue
+		}
+		for _, t := range output.Tags.Items {
+			r.Tags[*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+	return nil
+}
+
+func (a *AWS) cognitoUserPoolTags(res Resources) error {
+	for _, r := range res {
+		output, err := a.DescribeUserPool(&cognitoidentityprovider.DescribeUserPoolInput{
+			UserPoolId: aws.String(r.ID),
+		})
+		if err != nil {
+			return err
+		}
+		r.Tags = aws.StringValueMap(output.UserPool.UserPoolTags)
+	}
+	return nil
+}
@@ -0,0 +1,426 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/cloudetc/awsweeper/resource/resourcetest"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWS_FetchTags_TaggingAPI(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockResourceGroupsTaggingAPIAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		ResourceGroupsTaggingAPIAPI: mockObj,
+	}
+
+	mockObj.EXPECT().GetResourcesPages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(input *resourcegroupstaggingapi.GetResourcesInput,
+			fn func(*resourcegroupstaggingapi.GetResourcesOutput, bool) bool) error {
+			// only resources of the type are listed, so that a queue named tagged doesn't match
+			assert.Equal(t, []string{"cloudtrail:trail"}, aws.StringValueSlice(input.ResourceTypeFilters))
+			fn(&resourcegroupstaggingapi.GetResourcesOutput{
+				ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
+					{
+						ResourceARN: aws.String("arn:aws:cloudtrail:us-west-2:123456789012:trail/tagged"),
+						Tags: []*resourcegroupstaggingapi.Tag{
+							{Key: aws.String("foo"), Value: aws.String("bar")},
+						},
+					},
+				},
+			}, true)
+			return nil
+		})
+
+	res := resource.Resources{
+		{
+			Type: resource.Cloudtrail,
+			ID:   "tagged",
+		},
+		{
+			Type: resource.Cloudtrail,
+			ID:   "unknown",
+		},
+		{
+			Type: resource.Cloudtrail,
+			ID:   "native",
+			Tags: map[string]string{"bar": "baz"},
+		},
+	}
+
+	// when
+	err := awsMock.FetchTags(res)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"foo": "bar"}, res[0].Tags)
+	assert.Nil(t, res[1].Tags)
+	assert.Equal(t, map[string]string{"bar": "baz"}, res[2].Tags)
+}
+
+func TestAWS_FetchTags_TaggingAPI_MatchesARN(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockResourceGroupsTaggingAPIAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		ResourceGroupsTaggingAPIAPI: mockObj,
+	}
+
+	mockObj.EXPECT().GetResourcesPages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(input *resourcegroupstaggingapi.GetResourcesInput,
+			fn func(*resourcegroupstaggingapi.GetResourcesOutput, bool) bool) error {
+			fn(&resourcegroupstaggingapi.GetResourcesOutput{
+				ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
+					{
+						ResourceARN: aws.String("arn:aws:sns:us-west-2:123456789012:other:foo"),
+						Tags: []*resourcegroupstaggingapi.Tag{
+							{Key: aws.String("foo"), Value: aws.String("bar")},
+						},
+					},
+				},
+			}, true)
+			return nil
+		})
+
+	res := resource.Resources{
+		{Type: resource.SnsTopic, ID: "arn:aws:sns:us-west-2:123456789012:foo"},
+	}
+
+	// when
+	err := awsMock.FetchTags(res)
+
+	// then
+	require.NoError(t, err)
+	assert.Nil(t, res[0].Tags)
+}
+
+func TestAWS_FetchTags_NoTags(t *testing.T) {
+	// given
+	awsMock := &resource.AWS{}
+	res := resource.Resources{{Type: resource.ConfigConfigRule, ID: "rule"}}
+
+	// when
+	err := awsMock.FetchTags(res)
+
+	// then
+	require.NoError(t, err)
+	assert.Nil(t, res[0].Tags)
+}
+
+func TestFilter_UsesTags(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {
+				{
+					Tags: map[string]string{"foo": "bar"},
+				},
+			},
+			resource.Vpc: {
+				{
+					ID: aws.String("^foo"),
+				},
+			},
+		},
+	}
+
+	// then
+	assert.True(t, f.UsesTags(resource.Instance))
+	assert.False(t, f.UsesTags(resource.Vpc))
+}
+
+func TestAWS_FetchTags_DynamodbTable(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockDynamoDBAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		DynamoDBAPI: mockObj,
+	}
+
+	mockObj.EXPECT().ListTablesPages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(input *dynamodb.ListTablesInput, fn func(*dynamodb.ListTablesOutput, bool) bool) error {
+			fn(&dynamodb.ListTablesOutput{TableNames: aws.StringSlice([]string{"orders"})}, true)
+			return nil
+		})
+	mockObj.EXPECT().DescribeTable(&dynamodb.DescribeTableInput{
+		TableName: aws.String("orders"),
+	}).Return(&dynamodb.DescribeTableOutput{
+		Table: &dynamodb.TableDescription{
+			TableName: aws.String("orders"),
+			TableArn:  aws.String("arn:aws:dynamodb:us-east-1:123456789012:table/orders"),
+			ItemCount: aws.Int64(42),
+		},
+	}, nil)
+	mockObj.EXPECT().ListTagsOfResource(&dynamodb.ListTagsOfResourceInput{
+		ResourceArn: aws.String("arn:aws:dynamodb:us-east-1:123456789012:table/orders"),
+	}).Return(&dynamodb.ListTagsOfResourceOutput{
+		Tags: []*dynamodb.Tag{{Key: aws.String("foo"), Value: aws.String("bar")}},
+	}, nil)
+
+	raw, err := awsMock.RawResources(resource.DynamodbTable)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.DynamodbTable, raw)
+	require.NoError(t, err)
+
+	// when
+	err = awsMock.FetchTags(res)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, "orders", res[0].ID)
+	assert.Equal(t, map[string]string{"foo": "bar"}, res[0].Tags)
+	assert.Equal(t, int64(42), res[0].Model.(resource.DynamodbTableModel).ItemCount)
+}
+
+func TestAWS_FetchTags_SqsQueues(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"sqs": {
+			"ListQueues": map[string]interface{}{
+				"QueueUrls": []interface{}{
+					"https://sqs.us-east-1.amazonaws.com/123456789012/ci-1234",
+				},
+			},
+			"ListQueueTags": map[string]interface{}{
+				"Tags": map[string]interface{}{"pipeline": "build"},
+			},
+		},
+	})
+	raw, err := a.RawResources(resource.SqsQueue)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.SqsQueue, raw)
+	require.NoError(t, err)
+
+	// when
+	err = a.FetchTags(res)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, "https://sqs.us-east-1.amazonaws.com/123456789012/ci-1234", res[0].ID)
+	assert.Equal(t, map[string]string{"pipeline": "build"}, res[0].Tags)
+	assert.Equal(t, resource.Capabilities{Tags: true, Created: false}, resource.TypeCapabilities(resource.SqsQueue))
+}
+
+func TestAWS_FetchTags_KinesisStreams(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"kinesis": {
+			"ListStreams": map[string]interface{}{
+				"StreamNames":    []interface{}{"ci-events"},
+				"HasMoreStreams": false,
+			},
+			"DescribeStreamSummary": map[string]interface{}{
+				"StreamDescriptionSummary": map[string]interface{}{
+					"StreamARN":               "arn:aws:kinesis:us-east-1:123456789012:stream/ci-events",
+					"StreamName":              "ci-events",
+					"StreamStatus":            "ACTIVE",
+					"OpenShardCount":          2,
+					"RetentionPeriodHours":    24,
+					"StreamCreationTimestamp": "2018-10-01T00:00:00Z",
+				},
+			},
+			"ListTagsForStream": map[string]interface{}{
+				"Tags": []interface{}{
+					map[string]interface{}{"Key": "pipeline", "Value": "build"},
+				},
+				"HasMoreTags": false,
+			},
+		},
+	})
+	raw, err := a.RawResources(resource.KinesisStream)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.KinesisStream, raw)
+	require.NoError(t, err)
+
+	// when
+	err = a.FetchTags(res)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, "arn:aws:kinesis:us-east-1:123456789012:stream/ci-events", res[0].ID)
+	assert.Equal(t, map[string]string{"pipeline": "build"}, res[0].Tags)
+	assert.Equal(t, map[string]string{"name": "ci-events"}, resource.StateAttributes(res[0]))
+	assert.Equal(t, int64(2), res[0].Model.(resource.KinesisStreamModel).OpenShardCount)
+	assert.Equal(t, resource.Capabilities{Tags: true, Created: true}, resource.TypeCapabilities(resource.KinesisStream))
+}
+
+func TestAWS_FetchTags_FirehoseDeliveryStreams(t *testing.T) {
+	// given
+	a, _ := resourcetest.NewAWS(resourcetest.Fixtures{
+		"firehose": {
+			"ListDeliveryStreams": map[string]interface{}{
+				"DeliveryStreamNames":    []interface{}{"ci-logs"},
+				"HasMoreDeliveryStreams": false,
+			},
+			"DescribeDeliveryStream": map[string]interface{}{
+				"DeliveryStreamDescription": map[string]interface{}{
+					"DeliveryStreamARN":    "arn:aws:firehose:us-east-1:123456789012:deliverystream/ci-logs",
+					"DeliveryStreamName":   "ci-logs",
+					"DeliveryStreamStatus": "ACTIVE",
+					"DeliveryStreamType":   "DirectPut",
+					"CreateTimestamp":      "2018-10-01T00:00:00Z",
+					"Destinations": []interface{}{
+						map[string]interface{}{
+							"DestinationId": "destinationId-000000000001",
+							"ExtendedS3DestinationDescription": map[string]interface{}{
+								"BucketARN": "arn:aws:s3:::ci-logs-1234",
+							},
+						},
+					},
+				},
+			},
+			"ListTagsForDeliveryStream": map[string]interface{}{
+				"Tags": []interface{}{
+					map[string]interface{}{"Key": "pipeline", "Value": "build"},
+				},
+				"HasMoreTags": false,
+			},
+		},
+	})
+	raw, err := a.RawResources(resource.FirehoseDeliveryStream)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.FirehoseDeliveryStream, raw)
+	require.NoError(t, err)
+
+	// when
+	err = a.FetchTags(res)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, "arn:aws:firehose:us-east-1:123456789012:deliverystream/ci-logs", res[0].ID)
+	assert.Equal(t, map[string]string{"pipeline": "build"}, res[0].Tags)
+	assert.Equal(t, map[string]string{"name": "ci-logs"}, resource.StateAttributes(res[0]))
+	assert.Equal(t, "ci-logs-1234", res[0].Model.(resource.FirehoseDeliveryStreamModel).S3Bucket)
+	assert.Equal(t, resource.Capabilities{Tags: true, Created: true},
+		resource.TypeCapabilities(resource.FirehoseDeliveryStream))
+}
+
+func TestAWS_FetchTags_SsmParameters(t *testing.T) {
+	// given
+	a, b := resourcetest.NewAWS(resourcetest.Fixtures{
+		"ssm": {
+			"DescribeParameters": map[string]interface{}{
+				"Parameters": []interface{}{
+					map[string]interface{}{"Name": "/ci/1234/db-password", "Type": "SecureString", "Version": 3,
+						"LastModifiedDate": "2018-10-01T00:00:00Z"},
+				},
+			},
+			"ListTagsForResource": map[string]interface{}{
+				"TagList": []interface{}{
+					map[string]interface{}{"Key": "pipeline", "Value": "build"},
+				},
+			},
+		},
+	})
+	raw, err := a.RawResources(resource.SsmParameter)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.SsmParameter, raw)
+	require.NoError(t, err)
+
+	// when
+	err = a.FetchTags(res)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, "/ci/1234/db-password", res[0].ID)
+	assert.Equal(t, map[string]string{"pipeline": "build"}, res[0].Tags)
+	assert.Equal(t, "SecureString", res[0].Model.(resource.SsmParameterModel).Type)
+	assert.Equal(t, []string{"ssm:DescribeParameters", "ssm:ListTagsForResource"}, b.Calls())
+	assert.Equal(t, resource.Capabilities{Tags: true}, resource.TypeCapabilities(resource.SsmParameter))
+}
+
+func TestAWS_FetchTags_CloudfrontDistributions(t *testing.T) {
+	// given
+	a, b := resourcetest.NewAWS(resourcetest.Fixtures{
+		"cloudfront": {
+			"ListDistributions": map[string]interface{}{
+				"DistributionList": map[string]interface{}{
+					"Items": []interface{}{
+						map[string]interface{}{"Id": "E2QWRUHEXAMPLE",
+							"ARN":        "arn:aws:cloudfront::123456789012:distribution/E2QWRUHEXAMPLE",
+							"DomainName": "d111111abcdef8.cloudfront.net", "Enabled": false, "Status": "Deployed",
+							"Aliases": map[string]interface{}{"Items": []interface{}{"static.example.com"}}},
+					},
+				},
+			},
+			"ListTagsForResource": map[string]interface{}{
+				"Tags": map[string]interface{}{
+					"Items": []interface{}{
+						map[string]interface{}{"Key": "Environment", "Value": "preview"},
+					},
+				},
+			},
+		},
+	})
+	raw, err := a.RawResources(resource.CloudfrontDistribution)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.CloudfrontDistribution, raw)
+	require.NoError(t, err)
+
+	// when
+	err = a.FetchTags(res)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, "E2QWRUHEXAMPLE", res[0].ID)
+	assert.Equal(t, map[string]string{"Environment": "preview"}, res[0].Tags)
+	model := res[0].Model.(resource.CloudfrontDistributionModel)
+	assert.False(t, model.Enabled)
+	assert.Equal(t, []string{"static.example.com"}, model.Aliases)
+	assert.Equal(t, []string{"cloudfront:ListDistributions", "cloudfront:ListTagsForResource"}, b.Calls())
+	assert.True(t, resource.IsGlobal(resource.CloudfrontDistribution))
+}
+
+func TestAWS_FetchTags_CognitoUserPools(t *testing.T) {
+	// given
+	a, b := resourcetest.NewAWS(resourcetest.Fixtures{
+		"cognito-idp": {
+			"ListUserPools": map[string]interface{}{
+				"UserPools": []interface{}{
+					map[string]interface{}{"Id": "us-east-1_abc123", "Name": "preview-users", "Status": "Enabled"},
+				},
+			},
+			"DescribeUserPool": map[string]interface{}{
+				"UserPool": map[string]interface{}{
+					"Id":           "us-east-1_abc123",
+					"UserPoolTags": map[string]interface{}{"Environment": "preview"},
+				},
+			},
+		},
+	})
+	raw, err := a.RawResources(resource.CognitoUserPool)
+	require.NoError(t, err)
+	res, err := resource.DeletableResources(resource.CognitoUserPool, raw)
+	require.NoError(t, err)
+
+	// when
+	err = a.FetchTags(res)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, "us-east-1_abc123", res[0].ID)
+	assert.Equal(t, map[string]string{"Environment": "preview"}, res[0].Tags)
+	assert.Equal(t, "preview-users", res[0].Model.(resource.CognitoUserPoolModel).Name)
+	assert.Equal(t, []string{"cognito-idp:ListUserPools", "cognito-idp:DescribeUserPool"}, b.Calls())
+}
@@ -0,0 +1,240 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// TerraformCloudTokenEnv is the environment variable with the API token of Terraform Cloud/Enterprise.
+	TerraformCloudTokenEnv = "TFE_TOKEN"
+
+	defaultTerraformCloudAddress = "https://app.terraform.io"
+	terraformCloudMedia          = "application/vnd.api+json"
+
+	// TerraformCloudExclude protects the resources managed by matching workspaces from being deleted.
+	TerraformCloudExclude = "exclude"
+	// TerraformCloudTarget only deletes resources managed by matching workspaces.
+	TerraformCloudTarget = "target"
+)
+
+// TerraformCloudConfig configures which resources are managed by Terraform Cloud/Enterprise workspaces,
+// which are either excluded from or the target of deletion.
+type TerraformCloudConfig struct {
+	// Address is the URL of Terraform Enterprise (Terraform Cloud if not set).
+	Address string `yaml:",omitempty"`
+	// Organization is the name of the organization the workspaces belong to.
+	Organization string `yaml:",omitempty"`
+	// Workspaces is a regex the names of the workspaces must match.
+	Workspaces string `yaml:",omitempty"`
+	// Mode is either exclude (default) or target.
+	Mode string `yaml:",omitempty"`
+}
+
+func (c *TerraformCloudConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if c.Organization == "" {
+		return fmt.Errorf("terraform_cloud requires an organization")
+	}
+	if _, err := regexp.Compile(c.Workspaces); err != nil {
+		return fmt.Errorf("invalid workspaces regex of terraform_cloud: %s", err)
+	}
+	if c.Mode != "" && c.Mode != TerraformCloudExclude && c.Mode != TerraformCloudTarget {
+		return fmt.Errorf("terraform_cloud mode must be %s or %s, got: %s", TerraformCloudExclude, TerraformCloudTarget, c.Mode)
+	}
+	return nil
+}
+
+func (c *TerraformCloudConfig) address() string {
+	if c.Address == "" {
+		return defaultTerraformCloudAddress
+	}
+	return strings.TrimSuffix(c.Address, "/")
+}
+
+// ManagedResources are the resources managed by Terraform workspaces, identified by type and ID.
+type ManagedResources struct {
+	target    bool
+	resources map[string]bool
+}
+
+// ManagedResources looks up the resources in the current states of all workspaces that match the config.
+func (c *TerraformCloudConfig) ManagedResources(client *http.Client) (*ManagedResources, error) {
+	token := os.Getenv(TerraformCloudTokenEnv)
+	if token == "" {
+		return nil, errors.Errorf("terraform_cloud requires an API token in %s", TerraformCloudTokenEnv)
+	}
+	api := &terraformCloudAPI{client: client, address: c.address(), token: token}
+
+	workspaces, err := api.workspaces(c.Organization)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern := regexp.MustCompile(c.Workspaces)
+	managed := &ManagedResources{
+		target:    c.Mode == TerraformCloudTarget,
+		resources: map[string]bool{},
+	}
+	for id, name := range workspaces {
+		if !pattern.MatchString(name) {
+			continue
+		}
+
+		state, err := api.currentState(id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the state of workspace %s", name)
+		}
+		for _, key := range state {
+			managed.resources[key] = true
+		}
+	}
+	return managed, nil
+}
+
+// Seed returns the resources the filter is applied to, i.e. the ones not managed by matching workspaces
+// (or only the managed ones, in target mode). Resources are returned as is for nil.
+func (m *ManagedResources) Seed(res Resources) Resources {
+	if m == nil {
+		return res
+	}
+
+	var result Resources
+	for _, r := range res {
+		if m.resources[string(r.Type)+"/"+r.ID] == m.target {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// terraformCloudAPI is a client of the (JSON:API) API of Terraform Cloud/Enterprise.
+type terraformCloudAPI struct {
+	client  *http.Client
+	address string
+	token   string
+}
+
+func (api *terraformCloudAPI) get(url string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+api.token)
+	req.Header.Set("Content-Type", terraformCloudMedia)
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("request to %s failed: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// workspaces returns the names of the workspaces of an organization by ID.
+func (api *terraformCloudAPI) workspaces(organization string) (map[string]string, error) {
+	workspaces := map[string]string{}
+
+	for page := 1; page != 0; {
+		var output struct {
+			Data []struct {
+				ID         string `json:"id"`
+				Attributes struct {
+					Name string `json:"name"`
+				} `json:"attributes"`
+			} `json:"data"`
+			Meta struct {
+				Pagination struct {
+					NextPage int `json:"next-page"`
+				} `json:"pagination"`
+			} `json:"meta"`
+		}
+
+		url := fmt.Sprintf("%s/api/v2/organizations/%s/workspaces?page%%5Bnumber%%5D=%d", api.address, organization, page)
+		if err := api.get(url, &output); err != nil {
+			return nil, errors.Wrap(err, "failed to list Terraform workspaces")
+		}
+		for _, w := range output.Data {
+			workspaces[w.ID] = w.Attributes.Name
+		}
+		page = output.Meta.Pagination.NextPage
+	}
+	return workspaces, nil
+}
+
+// currentState returns the resources (as <type>/<id>) in the current state of a workspace.
+func (api *terraformCloudAPI) currentState(workspaceID string) ([]string, error) {
+	var version struct {
+		Data struct {
+			Attributes struct {
+				DownloadURL string `json:"hosted-state-download-url"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	url := fmt.Sprintf("%s/api/v2/workspaces/%s/current-state-version", api.address, workspaceID)
+	if err := api.get(url, &version); err != nil {
+		return nil, err
+	}
+
+	var state terraformState
+	if err := api.get(version.Data.Attributes.DownloadURL, &state); err != nil {
+		return nil, err
+	}
+	return state.resources(), nil
+}
+
+// terraformState is a Terraform state, either of version 3 (Terraform < 0.12) or version 4.
+type terraformState struct {
+	// Modules are the modules of states of version 3
+	Modules []struct {
+		Resources map[string]struct {
+			Type    string `json:"type"`
+			Primary struct {
+				ID string `json:"id"`
+			} `json:"primary"`
+		} `json:"resources"`
+	} `json:"modules"`
+	// Resources are the resources of states of version 4
+	Resources []struct {
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Instances []struct {
+			Attributes struct {
+				ID string `json:"id"`
+			} `json:"attributes"`
+		} `json:"instances"`
+	} `json:"resources"`
+}
+
+func (s terraformState) resources() []string {
+	var result []string
+
+	for _, m := range s.Modules {
+		for _, r := range m.Resources {
+			result = append(result, r.Type+"/"+r.Primary.ID)
+		}
+	}
+	for _, r := range s.Resources {
+		// data sources are not managed
+		if r.Mode == "data" {
+			continue
+		}
+		for _, i := range r.Instances {
+			result = append(result, r.Type+"/"+i.Attributes.ID)
+		}
+	}
+	return result
+}
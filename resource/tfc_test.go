@@ -0,0 +1,99 @@
+package resource_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTerraformCloud(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	mux.HandleFunc("/api/v2/organizations/acme/workspaces", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		switch r.URL.Query().Get("page[number]") {
+		case "1":
+			fmt.Fprint(w, `{"data": [{"id": "ws-1", "attributes": {"name": "prod-network"}}],
+				"meta": {"pagination": {"next-page": 2}}}`)
+		default:
+			fmt.Fprint(w, `{"data": [{"id": "ws-2", "attributes": {"name": "dev-network"}}],
+				"meta": {"pagination": {"next-page": null}}}`)
+		}
+	})
+	for _, id := range []string{"ws-1", "ws-2"} {
+		id := id
+		mux.HandleFunc("/api/v2/workspaces/"+id+"/current-state-version", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"data": {"attributes": {"hosted-state-download-url": "%s/state/%s"}}}`, server.URL, id)
+		})
+	}
+	mux.HandleFunc("/state/ws-1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"version": 4, "resources": [
+			{"mode": "managed", "type": "aws_vpc", "instances": [{"attributes": {"id": "vpc-prod"}}]},
+			{"mode": "data", "type": "aws_vpc", "instances": [{"attributes": {"id": "vpc-default"}}]}
+		]}`)
+	})
+	mux.HandleFunc("/state/ws-2", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"version": 3, "modules": [
+			{"resources": {"aws_vpc.dev": {"type": "aws_vpc", "primary": {"id": "vpc-dev"}}}}
+		]}`)
+	})
+	return server
+}
+
+func TestTerraformCloudConfig_ManagedResources(t *testing.T) {
+	// given
+	server := testTerraformCloud(t)
+	defer server.Close()
+	os.Setenv(resource.TerraformCloudTokenEnv, "secret")
+	defer os.Unsetenv(resource.TerraformCloudTokenEnv)
+
+	res := resource.Resources{
+		{Type: resource.Vpc, ID: "vpc-prod"},
+		{Type: resource.Vpc, ID: "vpc-dev"},
+		{Type: resource.Vpc, ID: "vpc-default"},
+		{Type: resource.Vpc, ID: "vpc-orphaned"},
+	}
+	ids := func(res resource.Resources) []string {
+		var result []string
+		for _, r := range res {
+			result = append(result, r.ID)
+		}
+		return result
+	}
+
+	// when
+	excluded, err := (&resource.TerraformCloudConfig{
+		Address: server.URL, Organization: "acme", Workspaces: "network$",
+	}).ManagedResources(server.Client())
+	require.NoError(t, err)
+	targeted, err := (&resource.TerraformCloudConfig{
+		Address: server.URL, Organization: "acme", Workspaces: "^prod-", Mode: resource.TerraformCloudTarget,
+	}).ManagedResources(server.Client())
+	require.NoError(t, err)
+
+	// then
+	assert.Equal(t, []string{"vpc-default", "vpc-orphaned"}, ids(excluded.Seed(res)))
+	assert.Equal(t, []string{"vpc-prod"}, ids(targeted.Seed(res)))
+	assert.Len(t, (*resource.ManagedResources)(nil).Seed(res), 4)
+}
+
+func TestTerraformCloudConfig_Validate(t *testing.T) {
+	validate := func(cfg *resource.TerraformCloudConfig) error {
+		f := &resource.Filter{Cfg: resource.Config{resource.Vpc: {}}, TerraformCloud: cfg}
+		return f.Validate()
+	}
+
+	assert.NoError(t, validate(&resource.TerraformCloudConfig{Organization: "acme", Workspaces: "^prod-"}))
+	assert.EqualError(t, validate(&resource.TerraformCloudConfig{}), "terraform_cloud requires an organization")
+	assert.EqualError(t, validate(&resource.TerraformCloudConfig{Organization: "acme", Mode: "foo"}),
+		"terraform_cloud mode must be exclude or target, got: foo")
+	assert.Error(t, validate(&resource.TerraformCloudConfig{Organization: "acme", Workspaces: "("}))
+}
@@ -0,0 +1,388 @@
+package resource
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/pkg/errors"
+)
+
+const (
+	// tracesEndpointEnv is the URL spans are exported to (OTLP over HTTP with JSON encoding).
+	tracesEndpointEnv = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	// endpointEnv is the base URL of an OTLP receiver, spans are exported to <url>/v1/traces.
+	endpointEnv = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	// headersEnv are additional headers of export requests (e.g., for authentication) as comma-separated key=value pairs.
+	headersEnv = "OTEL_EXPORTER_OTLP_HEADERS"
+	// serviceNameEnv overrides the name of the service spans are reported for.
+	serviceNameEnv = "OTEL_SERVICE_NAME"
+	// traceParentEnv is the W3C trace context of the parent span (e.g., of the CI job running AWSweeper),
+	// which makes the run part of an existing trace.
+	traceParentEnv = "TRACEPARENT"
+
+	defaultServiceName = "awsweeper"
+	// maxSpansPerExport is the maximum number of spans exported with a single request.
+	maxSpansPerExport = 1000
+
+	// OTLP span kinds and status codes
+	spanKindInternal = 1
+	spanKindClient   = 3
+	statusCodeError  = 2
+)
+
+var traceParentPattern = regexp.MustCompile(`^[0-9a-f]{2}-([0-9a-f]{32})-([0-9a-f]{16})-[0-9a-f]{2}$`)
+
+// Tracer records the spans of a run (phases, deleted resources and API calls) and exports them to an
+// OpenTelemetry collector via OTLP/HTTP. A nil tracer records nothing, so that tracing is optional for callers.
+type Tracer struct {
+	endpoint string
+	headers  map[string]string
+	service  string
+	client   *http.Client
+
+	traceID string
+	// parentID is the ID of the span the run is part of (empty if the run starts a new trace)
+	parentID string
+
+	mu    sync.Mutex
+	root  *Span
+	spans []*Span
+	calls sync.Map
+}
+
+// Span is a timed operation of a run. A nil span is valid and records nothing.
+type Span struct {
+	tracer   *Tracer
+	id       string
+	parentID string
+	name     string
+	kind     int
+	start    time.Time
+	end      time.Time
+	attrs    map[string]string
+	err      error
+}
+
+// NewTracerFromEnv creates a tracer configured by the standard OpenTelemetry environment variables
+// (nil if no OTLP endpoint is set).
+func NewTracerFromEnv() (*Tracer, error) {
+	endpoint := os.Getenv(tracesEndpointEnv)
+	if endpoint == "" {
+		if base := os.Getenv(endpointEnv); base != "" {
+			endpoint = strings.TrimSuffix(base, "/") + "/v1/traces"
+		}
+	}
+	if endpoint == "" {
+		return nil, nil
+	}
+
+	headers, err := parseHeaders(os.Getenv(headersEnv))
+	if err != nil {
+		return nil, err
+	}
+
+	t := NewTracer(endpoint)
+	t.headers = headers
+	if service := os.Getenv(serviceNameEnv); service != "" {
+		t.service = service
+	}
+
+	if traceParent := os.Getenv(traceParentEnv); traceParent != "" {
+		m := traceParentPattern.FindStringSubmatch(traceParent)
+		if m == nil {
+			return nil, errors.Errorf("invalid %s: %s", traceParentEnv, traceParent)
+		}
+		t.traceID = m[1]
+		t.parentID = m[2]
+	}
+	return t, nil
+}
+
+// parseHeaders parses headers given as comma-separated key=value pairs.
+func parseHeaders(s string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, errors.Errorf("invalid %s, expected key=value pairs: %s", headersEnv, s)
+		}
+		headers[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return headers, nil
+}
+
+// NewTracer creates a tracer that exports spans to the given URL (e.g., http://localhost:4318/v1/traces).
+func NewTracer(endpoint string) *Tracer {
+	return &Tracer{
+		endpoint: endpoint,
+		service:  defaultServiceName,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		traceID:  randomID(16),
+	}
+}
+
+// randomID returns a random ID of n bytes as hex string.
+func randomID(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%0*x", 2*n, time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// Start starts a span as child of the given span (or of the root span of the run, if nil).
+// The first span started without parent is the root span. Attributes are given as key-value pairs.
+func (t *Tracer) Start(parent *Span, name string, attrs ...string) *Span {
+	return t.start(parent, name, spanKindInternal, attrs...)
+}
+
+func (t *Tracer) start(parent *Span, name string, kind int, attrs ...string) *Span {
+	if t == nil {
+		return nil
+	}
+
+	s := &Span{
+		tracer: t,
+		id:     randomID(8),
+		name:   name,
+		kind:   kind,
+		start:  time.Now(),
+		attrs:  map[string]string{},
+	}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		s.attrs[attrs[i]] = attrs[i+1]
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch {
+	case parent != nil:
+		s.parentID = parent.id
+	case t.root != nil:
+		s.parentID = t.root.id
+	case kind == spanKindClient:
+		// API calls issued before the run has started (e.g., to look up the enabled regions)
+		s.parentID = t.parentID
+	default:
+		s.parentID = t.parentID
+		t.root = s
+	}
+	return s
+}
+
+// SetAttribute sets an attribute of the span.
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.attrs[key] = value
+}
+
+// End ends the span, which failed if err is not nil.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+
+	s.end = time.Now()
+	s.err = err
+	s.tracer.spans = append(s.tracer.spans, s)
+}
+
+// Attach records a span per API call of the clients created from the given session (or copies of it).
+// The calls are children of the root span of the run, as the clients aren't called with a context.
+func (t *Tracer) Attach(sess *session.Session) {
+	if t == nil {
+		return
+	}
+
+	sess.Handlers.Validate.PushFrontNamed(request.NamedHandler{
+		Name: "awsweeper.Tracer.Start",
+		Fn: func(req *request.Request) {
+			t.calls.Store(req, t.start(nil, req.ClientInfo.ServiceName+"."+req.Operation.Name, spanKindClient,
+				"rpc.system", "aws-api",
+				"rpc.service", req.ClientInfo.ServiceName,
+				"rpc.method", req.Operation.Name,
+				"cloud.region", aws.StringValue(req.Config.Region)))
+		},
+	})
+	sess.Handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "awsweeper.Tracer.End",
+		Fn: func(req *request.Request) {
+			v, found := t.calls.Load(req)
+			if !found {
+				return
+			}
+			t.calls.Delete(req)
+
+			s := v.(*Span)
+			s.SetAttribute("aws.request_id", req.RequestID)
+			s.SetAttribute("aws.retry_count", strconv.Itoa(req.RetryCount))
+			if req.HTTPResponse != nil {
+				s.SetAttribute("http.status_code", strconv.Itoa(req.HTTPResponse.StatusCode))
+			}
+			s.End(req.Error)
+		},
+	})
+}
+
+// otlpAttribute, otlpSpan etc. are the OTLP/JSON representation of spans
+// (see opentelemetry-proto, opentelemetry/proto/trace/v1/trace.proto).
+type otlpAttribute struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue string `json:"stringValue"`
+	} `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpScopeSpans struct {
+	Scope struct {
+		Name string `json:"name"`
+	} `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResourceSpans struct {
+	Resource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	} `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+func newOtlpAttribute(key, value string) otlpAttribute {
+	a := otlpAttribute{Key: key}
+	a.Value.StringValue = value
+	return a
+}
+
+func (t *Tracer) otlpSpan(s *Span) otlpSpan {
+	result := otlpSpan{
+		TraceID:           t.traceID,
+		SpanID:            s.id,
+		ParentSpanID:      s.parentID,
+		Name:              s.name,
+		Kind:              s.kind,
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+	}
+
+	keys := make([]string, 0, len(s.attrs))
+	for k := range s.attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		result.Attributes = append(result.Attributes, newOtlpAttribute(k, s.attrs[k]))
+	}
+
+	if s.err != nil {
+		result.Status = otlpStatus{Code: statusCodeError, Message: s.err.Error()}
+	}
+	return result
+}
+
+// Export sends the spans that have ended since the last export to the collector.
+func (t *Tracer) Export() error {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	spans := t.spans
+	t.spans = nil
+	var converted []otlpSpan
+	for _, s := range spans {
+		converted = append(converted, t.otlpSpan(s))
+	}
+	t.mu.Unlock()
+
+	for len(converted) > 0 {
+		n := maxSpansPerExport
+		if len(converted) < n {
+			n = len(converted)
+		}
+		if err := t.export(converted[:n]); err != nil {
+			return err
+		}
+		converted = converted[n:]
+	}
+	return nil
+}
+
+func (t *Tracer) export(spans []otlpSpan) error {
+	rs := otlpResourceSpans{}
+	rs.Resource.Attributes = []otlpAttribute{newOtlpAttribute("service.name", t.service)}
+	ss := otlpScopeSpans{Spans: spans}
+	ss.Scope.Name = defaultServiceName
+	rs.ScopeSpans = []otlpScopeSpans{ss}
+
+	body, err := json.Marshal(otlpTraces{ResourceSpans: []otlpResourceSpans{rs}})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to export spans")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to export spans")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("failed to export spans: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package resource_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type exportedSpan struct {
+	TraceID      string `json:"traceId"`
+	SpanID       string `json:"spanId"`
+	ParentSpanID string `json:"parentSpanId"`
+	Name         string `json:"name"`
+	Attributes   []struct {
+		Key   string `json:"key"`
+		Value struct {
+			StringValue string `json:"stringValue"`
+		} `json:"value"`
+	} `json:"attributes"`
+	Status struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"status"`
+}
+
+type exportedTraces struct {
+	ResourceSpans []struct {
+		Resource struct {
+			Attributes []struct {
+				Key   string `json:"key"`
+				Value struct {
+					StringValue string `json:"stringValue"`
+				} `json:"value"`
+			} `json:"attributes"`
+		} `json:"resource"`
+		ScopeSpans []struct {
+			Spans []exportedSpan `json:"spans"`
+		} `json:"scopeSpans"`
+	} `json:"resourceSpans"`
+}
+
+func TestTracer_Export(t *testing.T) {
+	// given
+	var traces exportedTraces
+	var auth string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		auth = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&traces))
+	}))
+	defer collector.Close()
+
+	os.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", collector.URL+"/")
+	defer os.Unsetenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	os.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "Authorization=Bearer secret")
+	defer os.Unsetenv("OTEL_EXPORTER_OTLP_HEADERS")
+	os.Setenv("OTEL_SERVICE_NAME", "sweeper")
+	defer os.Unsetenv("OTEL_SERVICE_NAME")
+	os.Setenv("TRACEPARENT", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	defer os.Unsetenv("TRACEPARENT")
+
+	tracer, err := resource.NewTracerFromEnv()
+	require.NoError(t, err)
+	require.NotNil(t, tracer)
+
+	// when
+	root := tracer.Start(nil, "sweep")
+	region := tracer.Start(nil, "region", "cloud.region", "us-west-2")
+	tracer.Start(region, "delete", "resource.id", "i-1").End(errors.New("in use"))
+	region.End(nil)
+	root.End(nil)
+
+	// then
+	require.NoError(t, tracer.Export())
+	assert.Equal(t, "Bearer secret", auth)
+
+	require.Len(t, traces.ResourceSpans, 1)
+	assert.Equal(t, "service.name", traces.ResourceSpans[0].Resource.Attributes[0].Key)
+	assert.Equal(t, "sweeper", traces.ResourceSpans[0].Resource.Attributes[0].Value.StringValue)
+
+	spans := map[string]exportedSpan{}
+	for _, s := range traces.ResourceSpans[0].ScopeSpans[0].Spans {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", s.TraceID)
+		spans[s.Name] = s
+	}
+	require.Len(t, spans, 3)
+
+	assert.Equal(t, "00f067aa0ba902b7", spans["sweep"].ParentSpanID)
+	assert.Equal(t, spans["sweep"].SpanID, spans["region"].ParentSpanID)
+	assert.Equal(t, spans["region"].SpanID, spans["delete"].ParentSpanID)
+
+	assert.Equal(t, 0, spans["region"].Status.Code)
+	assert.Equal(t, 2, spans["delete"].Status.Code)
+	assert.Equal(t, "in use", spans["delete"].Status.Message)
+	require.Len(t, spans["delete"].Attributes, 1)
+	assert.Equal(t, "resource.id", spans["delete"].Attributes[0].Key)
+	assert.Equal(t, "i-1", spans["delete"].Attributes[0].Value.StringValue)
+}
+
+func TestNewTracerFromEnv(t *testing.T) {
+	os.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	defer os.Unsetenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	os.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	defer os.Unsetenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+
+	tracer, err := resource.NewTracerFromEnv()
+	require.NoError(t, err)
+	assert.Nil(t, tracer)
+
+	// a nil tracer records nothing
+	tracer.Start(nil, "sweep").End(nil)
+	assert.NoError(t, tracer.Export())
+
+	os.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "http://localhost:4318/v1/traces")
+	defer os.Unsetenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	os.Setenv("TRACEPARENT", "invalid")
+	defer os.Unsetenv("TRACEPARENT")
+
+	_, err = resource.NewTracerFromEnv()
+	assert.EqualError(t, err, "invalid TRACEPARENT: invalid")
+}
@@ -0,0 +1,259 @@
+package resource
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/efs"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+)
+
+// removeTagsActions are required to remove tags from resources of a particular type
+// (resources of EC2 types are untagged via ec2:DeleteTags).
+var removeTagsActions = map[TerraformResourceType][]string{
+	AutoscalingGroup:   {"autoscaling:DeleteTags"},
+	CloudwatchLogGroup: {"logs:UntagLogGroup"},
+	DbClusterSnapshot:  {"rds:RemoveTagsFromResource"},
+	DbSnapshot:         {"rds:RemoveTagsFromResource"},
+	DynamodbTable:      {"dynamodb:UntagResource"},
+	EfsFileSystem:      {"elasticfilesystem:DeleteTags"},
+	Elb:                {"elasticloadbalancing:RemoveTags"},
+	KmsKey:             {"kms:UntagResource"},
+	RdsCluster:         {"rds:RemoveTagsFromResource"},
+	Route53Zone:        {"route53:ChangeTagsForResource"},
+	S3Bucket:           {"s3:PutBucketTagging", "s3:DeleteBucketTagging"},
+}
+
+// validateRemoveTags checks whether tags can be removed from the selected resources as configured.
+func validateRemoveTags(resType TerraformResourceType, rtf ResourceTypeFilter) error {
+	if _, found := removeTagsActions[resType]; !found && !ec2Types[resType] {
+		return fmt.Errorf("action %s is not supported for resource type: %s", ActionRemoveTags, resType)
+	}
+	if len(rtf.RemoveTags) == 0 {
+		return fmt.Errorf("action %s requires remove_tags for resource type: %s", ActionRemoveTags, resType)
+	}
+	for _, key := range rtf.RemoveTags {
+		if _, err := regexp.Compile(key); err != nil {
+			return fmt.Errorf("invalid tag key regex in remove_tags for resource type %s: %s", resType, key)
+		}
+	}
+	return nil
+}
+
+// Untagging is a resource together with the keys of the tags to remove from it.
+type Untagging struct {
+	Resource *Resource
+	Keys     []string
+}
+
+// Untaggings returns the resources that match a filter entry with action remove_tags and have tags to remove,
+// together with the keys of their tags matching the entry. An entry of remove_tags is a tag key or
+// a regex that has to match whole keys.
+func (f Filter) Untaggings(res Resources) []Untagging {
+	var result []Untagging
+
+	for _, r := range res {
+		rtf, found := f.matchingEntry(r)
+		if !found || rtf.action() != ActionRemoveTags {
+			continue
+		}
+
+		var keys []string
+		for key := range r.Tags {
+			for _, pattern := range rtf.RemoveTags {
+				if regexp.MustCompile("^(?:" + pattern + ")$").MatchString(key) {
+					keys = append(keys, key)
+					break
+				}
+			}
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		sort.Strings(keys)
+
+		result = append(result, Untagging{
+			Resource: r,
+			Keys:     keys,
+		})
+	}
+	return result
+}
+
+// UntagAll removes tags from resources and returns the IDs of the resources that tags couldn't be removed from
+// together with the error. Tags with the same keys are removed from up to 100 resources of EC2 types with a single request.
+func (a *AWS) UntagAll(untaggings []Untagging) map[string]error {
+	failed := map[string]error{}
+
+	var batchKeys []string
+	batches := map[string][]string{}
+	keys := map[string][]string{}
+	for _, u := range untaggings {
+		if !ec2Types[u.Resource.Type] {
+			if err := a.Untag(u.Resource, u.Keys); err != nil {
+				failed[u.Resource.ID] = err
+			}
+			continue
+		}
+
+		key := fmt.Sprint(u.Keys)
+		if _, found := batches[key]; !found {
+			batchKeys = append(batchKeys, key)
+		}
+		batches[key] = append(batches[key], u.Resource.ID)
+		keys[key] = u.Keys
+	}
+
+	for _, key := range batchKeys {
+		for _, chunk := range chunks(batches[key], ec2TagBatchSize) {
+			err := retryThrottled(func() error {
+				return a.deleteEC2Tags(chunk, keys[key])
+			})
+			if err != nil {
+				for _, id := range chunk {
+					failed[id] = errors.Wrapf(err, "failed to remove tags from %s", id)
+				}
+			}
+		}
+	}
+	return failed
+}
+
+// Untag removes the tags with the given keys from a resource via the untag API of its service.
+func (a *AWS) Untag(r *Resource, keys []string) error {
+	err := retryThrottled(func() error {
+		return a.untag(r, keys)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove tags from %s", r.ID)
+	}
+	return nil
+}
+
+func (a *AWS) untag(r *Resource, keys []string) error {
+	switch {
+	case ec2Types[r.Type]:
+		return a.deleteEC2Tags([]string{r.ID}, keys)
+	case r.Type == AutoscalingGroup:
+		var tags []*autoscaling.Tag
+		for _, k := range keys {
+			tags = append(tags, &autoscaling.Tag{
+				Key:          aws.String(k),
+				ResourceId:   aws.String(r.ID),
+				ResourceType: aws.String("auto-scaling-group"),
+			})
+		}
+		_, err := a.AutoScalingAPI.DeleteTags(&autoscaling.DeleteTagsInput{Tags: tags})
+		return err
+	case r.Type == CloudwatchLogGroup:
+		_, err := a.UntagLogGroup(&cloudwatchlogs.UntagLogGroupInput{
+			LogGroupName: aws.String(r.ID),
+			Tags:         aws.StringSlice(keys),
+		})
+		return err
+	case r.Type == DynamodbTable:
+		m, ok := r.Model.(DynamodbTableModel)
+		if !ok {
+			return errors.Errorf("ARN of table is unknown")
+		}
+		_, err := a.DynamoDBAPI.UntagResource(&dynamodb.UntagResourceInput{
+			ResourceArn: aws.String(m.arn),
+			TagKeys:     aws.StringSlice(keys),
+		})
+		return err
+	case r.Type == EfsFileSystem:
+		_, err := a.EFSAPI.DeleteTags(&efs.DeleteTagsInput{
+			FileSystemId: aws.String(r.ID),
+			TagKeys:      aws.StringSlice(keys),
+		})
+		return err
+	case r.Type == Elb:
+		var tags []*elb.TagKeyOnly
+		for _, k := range keys {
+			tags = append(tags, &elb.TagKeyOnly{Key: aws.String(k)})
+		}
+		_, err := a.ELBAPI.RemoveTags(&elb.RemoveTagsInput{
+			LoadBalancerNames: aws.StringSlice([]string{r.ID}),
+			Tags:              tags,
+		})
+		return err
+	case r.Type == KmsKey:
+		_, err := a.KMSAPI.UntagResource(&kms.UntagResourceInput{
+			KeyId:   aws.String(r.ID),
+			TagKeys: aws.StringSlice(keys),
+		})
+		return err
+	case r.Type == DbClusterSnapshot || r.Type == DbSnapshot || r.Type == RdsCluster:
+		arn, ok := rdsArn(r.Model)
+		if !ok {
+			return errors.Errorf("ARN of RDS resource is unknown")
+		}
+		_, err := a.RDSAPI.RemoveTagsFromResource(&rds.RemoveTagsFromResourceInput{
+			ResourceName: aws.String(arn),
+			TagKeys:      aws.StringSlice(keys),
+		})
+		return err
+	case r.Type == Route53Zone:
+		_, err := a.ChangeTagsForResource(&route53.ChangeTagsForResourceInput{
+			ResourceId:    aws.String(strings.TrimPrefix(r.ID, "/hostedzone/")),
+			ResourceType:  aws.String(route53.TagResourceTypeHostedzone),
+			RemoveTagKeys: aws.StringSlice(keys),
+		})
+		return err
+	case r.Type == S3Bucket:
+		return a.untagBucket(r, keys)
+	default:
+		return errors.Errorf("removing tags is not supported for resource type: %s", r.Type)
+	}
+}
+
+// deleteEC2Tags removes the tags with the given keys from resources of EC2 types with a single request.
+func (a *AWS) deleteEC2Tags(ids []string, keys []string) error {
+	var tags []*ec2.Tag
+	for _, k := range keys {
+		tags = append(tags, &ec2.Tag{Key: aws.String(k)})
+	}
+	_, err := a.EC2API.DeleteTags(&ec2.DeleteTagsInput{
+		Resources: aws.StringSlice(ids),
+		Tags:      tags,
+	})
+	return err
+}
+
+// untagBucket removes tags from a bucket by replacing its tag set with the remaining tags,
+// as S3 has no API to remove single tags.
+func (a *AWS) untagBucket(r *Resource, keys []string) error {
+	remove := map[string]bool{}
+	for _, k := range keys {
+		remove[k] = true
+	}
+
+	var tagSet []*s3.Tag
+	for k, v := range r.Tags {
+		if !remove[k] {
+			tagSet = append(tagSet, &s3.Tag{Key: aws.String(k), Value: aws.String(v)})
+		}
+	}
+
+	if len(tagSet) == 0 {
+		_, err := a.DeleteBucketTagging(&s3.DeleteBucketTaggingInput{Bucket: aws.String(r.ID)})
+		return err
+	}
+	_, err := a.PutBucketTagging(&s3.PutBucketTaggingInput{
+		Bucket:  aws.String(r.ID),
+		Tagging: &s3.Tagging{TagSet: tagSet},
+	})
+	return err
+}
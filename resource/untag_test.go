@@ -0,0 +1,179 @@
+package resource_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYamlFilter_Untaggings(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {
+				{
+					Tags:       map[string]string{"ci-run-id": ".*"},
+					Action:     resource.ActionRemoveTags,
+					RemoveTags: []string{"ci-run-id", "^tmp-.*"},
+				},
+			},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	res := resource.Resources{
+		{
+			Type: resource.Instance,
+			ID:   "select-this",
+			Tags: map[string]string{"ci-run-id": "42", "tmp-owner": "foo", "ci-run-id-old": "41", "Name": "web"},
+		},
+		{
+			Type: resource.Instance,
+			ID:   "do-not-select-this",
+			Tags: map[string]string{"Name": "db"},
+		},
+	}
+
+	// when
+	untaggings := f.Untaggings(res)
+
+	// then
+	require.Len(t, untaggings, 1)
+	assert.Equal(t, "select-this", untaggings[0].Resource.ID)
+	assert.Equal(t, []string{"ci-run-id", "tmp-owner"}, untaggings[0].Keys)
+	assert.True(t, f.UsesTags(resource.Instance))
+}
+
+func TestYamlFilter_Validate_RemoveTagsNotSupported(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.IamRole: {{Action: resource.ActionRemoveTags, RemoveTags: []string{"foo"}}},
+		},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.EqualError(t, err, "action remove_tags is not supported for resource type: aws_iam_role")
+}
+
+func TestYamlFilter_Validate_RemoveTagsWithoutKeys(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Instance: {{Action: resource.ActionRemoveTags}},
+		},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.EqualError(t, err, "action remove_tags requires remove_tags for resource type: aws_instance")
+}
+
+func TestAWS_Untag_Instance(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockEC2API(mockCtrl)
+	awsMock := &resource.AWS{
+		EC2API: mockObj,
+	}
+
+	mockObj.EXPECT().DeleteTags(&ec2.DeleteTagsInput{
+		Resources: []*string{aws.String(testInstanceID)},
+		Tags: []*ec2.Tag{
+			{Key: aws.String("ci-run-id")},
+		},
+	}).Return(&ec2.DeleteTagsOutput{}, nil)
+
+	// when
+	err := awsMock.Untag(&resource.Resource{
+		Type: resource.Instance,
+		ID:   testInstanceID,
+	}, []string{"ci-run-id"})
+
+	// then
+	require.NoError(t, err)
+}
+
+func TestAWS_UntagAll_BatchesEC2Resources(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockEC2API(mockCtrl)
+	awsMock := &resource.AWS{
+		EC2API: mockObj,
+	}
+
+	var untaggings []resource.Untagging
+	for i := 0; i < 150; i++ {
+		untaggings = append(untaggings, resource.Untagging{
+			Resource: &resource.Resource{Type: resource.Instance, ID: fmt.Sprintf("i-%d", i)},
+			Keys:     []string{"ci-run-id"},
+		})
+	}
+	untaggings = append(untaggings, resource.Untagging{
+		Resource: &resource.Resource{Type: resource.EbsVolume, ID: "vol-1"},
+		Keys:     []string{"ci-run-id", "owner"},
+	})
+
+	var batchSizes []int
+	mockObj.EXPECT().DeleteTags(gomock.Any()).DoAndReturn(func(input *ec2.DeleteTagsInput) (*ec2.DeleteTagsOutput, error) {
+		batchSizes = append(batchSizes, len(input.Resources))
+		if len(input.Tags) == 2 {
+			return nil, errors.New("UnauthorizedOperation")
+		}
+		return &ec2.DeleteTagsOutput{}, nil
+	}).Times(3)
+
+	// when
+	failed := awsMock.UntagAll(untaggings)
+
+	// then
+	assert.Equal(t, []int{100, 50, 1}, batchSizes)
+	require.Len(t, failed, 1)
+	assert.EqualError(t, failed["vol-1"], "failed to remove tags from vol-1: UnauthorizedOperation")
+}
+
+func TestAWS_Untag_S3BucketKeepsRemainingTags(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockS3API(mockCtrl)
+	awsMock := &resource.AWS{
+		S3API: mockObj,
+	}
+
+	mockObj.EXPECT().PutBucketTagging(&s3.PutBucketTaggingInput{
+		Bucket: aws.String("foo"),
+		Tagging: &s3.Tagging{TagSet: []*s3.Tag{
+			{Key: aws.String("Owner"), Value: aws.String("bar")},
+		}},
+	}).Return(&s3.PutBucketTaggingOutput{}, nil)
+
+	// when
+	err := awsMock.Untag(&resource.Resource{
+		Type: resource.S3Bucket,
+		ID:   "foo",
+		Tags: map[string]string{"Owner": "bar", "ci-run-id": "42"},
+	}, []string{"ci-run-id"})
+
+	// then
+	require.NoError(t, err)
+}
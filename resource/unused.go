@@ -0,0 +1,213 @@
+package resource
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/pkg/errors"
+)
+
+// usageAnalyses look up the IDs of the resources of a type that are in use (by other resources),
+// given the raw resources of the type as listed.
+var usageAnalyses = map[TerraformResourceType]func(a *AWS, raw interface{}) (map[string]bool, error){
+	KeyPair:       (*AWS).usedKeyPairs,
+	SecurityGroup: (*AWS).usedSecurityGroups,
+}
+
+// usageActions are the actions needed to look up which resources of a type are in use.
+var usageActions = map[TerraformResourceType][]string{
+	KeyPair: {"ec2:DescribeInstances", "autoscaling:DescribeLaunchConfigurations"},
+	SecurityGroup: {"ec2:DescribeNetworkInterfaces", "ec2:DescribeLaunchTemplates", "ec2:DescribeLaunchTemplateVersions",
+		"elasticloadbalancing:DescribeLoadBalancers", "autoscaling:DescribeLaunchConfigurations"},
+}
+
+// validateUnused checks whether resources of a type can be selected by whether they are in use.
+func validateUnused(resType TerraformResourceType, unused bool) error {
+	if _, found := usageAnalyses[resType]; unused && !found {
+		return fmt.Errorf("filtering by usage is not supported for resource type: %s", resType)
+	}
+	return nil
+}
+
+// usesUnused checks whether the filter selects resources of the given type by whether they are in use.
+func (f Filter) usesUnused(resType TerraformResourceType) bool {
+	for _, rtf := range f.Cfg[resType] {
+		if rtf.Unused {
+			return true
+		}
+	}
+	return false
+}
+
+// matchUnused checks whether a resource is not in use by any other resource, if required by the filter.
+func (rtf ResourceTypeFilter) matchUnused(r *Resource) bool {
+	if !rtf.Unused {
+		return true
+	}
+	return r.unused
+}
+
+// fetchUsage finds out which of the given resources are not in use, if the filter needs to know.
+func (f Filter) fetchUsage(resType TerraformResourceType, res Resources, raw interface{}, a *AWS) error {
+	analysis, found := usageAnalyses[resType]
+	if !found || !f.usesUnused(resType) || len(res) == 0 {
+		return nil
+	}
+
+	used, err := analysis(a, raw)
+	if err != nil {
+		return err
+	}
+	for _, r := range res {
+		r.unused = !used[r.ID]
+	}
+	return nil
+}
+
+// usedKeyPairs returns the names of the key pairs that are referenced by an instance
+// (which hasn't been terminated) or a launch configuration.
+func (a *AWS) usedKeyPairs(raw interface{}) (map[string]bool, error) {
+	used := map[string]bool{}
+
+	instances, err := a.instances()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list instances")
+	}
+	for _, i := range instances.([]*ec2.Instance) {
+		used[aws.StringValue(i.KeyName)] = true
+	}
+
+	err = a.DescribeLaunchConfigurationsPages(&autoscaling.DescribeLaunchConfigurationsInput{},
+		func(page *autoscaling.DescribeLaunchConfigurationsOutput, lastPage bool) bool {
+			for _, lc := range page.LaunchConfigurations {
+				used[aws.StringValue(lc.KeyName)] = true
+			}
+			return true
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list launch configurations")
+	}
+	return used, nil
+}
+
+// usedSecurityGroups returns the IDs of the security groups that are attached to a network interface
+// (which covers instances, RDS databases, Lambda functions in a VPC, load balancers, etc.), referenced
+// by a launch template, launch configuration, classic load balancer, or a rule of another security group.
+func (a *AWS) usedSecurityGroups(raw interface{}) (map[string]bool, error) {
+	used := map[string]bool{}
+
+	// launch configurations and templates can reference security groups (of the default VPC) by name
+	idsByName := map[string]string{}
+	groups, _ := raw.([]*ec2.SecurityGroup)
+	for _, sg := range groups {
+		idsByName[aws.StringValue(sg.GroupName)] = aws.StringValue(sg.GroupId)
+
+		for _, perm := range append(sg.IpPermissions, sg.IpPermissionsEgress...) {
+			for _, pair := range perm.UserIdGroupPairs {
+				if id := aws.StringValue(pair.GroupId); id != aws.StringValue(sg.GroupId) {
+					used[id] = true
+				}
+			}
+		}
+	}
+	use := func(idsOrNames []*string) {
+		for _, s := range aws.StringValueSlice(idsOrNames) {
+			if id, found := idsByName[s]; found {
+				s = id
+			}
+			used[s] = true
+		}
+	}
+
+	err := a.DescribeNetworkInterfacesPages(&ec2.DescribeNetworkInterfacesInput{},
+		func(page *ec2.DescribeNetworkInterfacesOutput, lastPage bool) bool {
+			for _, eni := range page.NetworkInterfaces {
+				for _, g := range eni.Groups {
+					used[aws.StringValue(g.GroupId)] = true
+				}
+			}
+			return true
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list network interfaces")
+	}
+
+	err = a.ELBAPI.DescribeLoadBalancersPages(&elb.DescribeLoadBalancersInput{},
+		func(page *elb.DescribeLoadBalancersOutput, lastPage bool) bool {
+			for _, lb := range page.LoadBalancerDescriptions {
+				use(lb.SecurityGroups)
+			}
+			return true
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list load balancers")
+	}
+
+	err = a.DescribeLaunchConfigurationsPages(&autoscaling.DescribeLaunchConfigurationsInput{},
+		func(page *autoscaling.DescribeLaunchConfigurationsOutput, lastPage bool) bool {
+			for _, lc := range page.LaunchConfigurations {
+				use(lc.SecurityGroups)
+			}
+			return true
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list launch configurations")
+	}
+
+	templates, err := a.launchTemplateIDs()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range templates {
+		input := &ec2.DescribeLaunchTemplateVersionsInput{LaunchTemplateId: aws.String(id)}
+		for {
+			output, err := a.DescribeLaunchTemplateVersions(input)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to list versions of launch template %s", id)
+			}
+
+			for _, v := range output.LaunchTemplateVersions {
+				data := v.LaunchTemplateData
+				if data == nil {
+					continue
+				}
+				use(data.SecurityGroupIds)
+				use(data.SecurityGroups)
+				for _, ni := range data.NetworkInterfaces {
+					use(ni.Groups)
+				}
+			}
+
+			if output.NextToken == nil {
+				break
+			}
+			input.NextToken = output.NextToken
+		}
+	}
+	return used, nil
+}
+
+// launchTemplateIDs returns the IDs of all launch templates.
+func (a *AWS) launchTemplateIDs() ([]string, error) {
+	var ids []string
+
+	input := &ec2.DescribeLaunchTemplatesInput{}
+	for {
+		output, err := a.DescribeLaunchTemplates(input)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list launch templates")
+		}
+
+		for _, lt := range output.LaunchTemplates {
+			ids = append(ids, aws.StringValue(lt.LaunchTemplateId))
+		}
+
+		if output.NextToken == nil {
+			return ids, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
@@ -0,0 +1,174 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Apply_UnusedSecurityGroups(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	ec2Mock := mocks.NewMockEC2API(mockCtrl)
+	elbMock := mocks.NewMockELBAPI(mockCtrl)
+	asgMock := mocks.NewMockAutoScalingAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		EC2API:         ec2Mock,
+		ELBAPI:         elbMock,
+		AutoScalingAPI: asgMock,
+	}
+
+	groups := []*ec2.SecurityGroup{
+		{GroupId: aws.String("sg-eni"), GroupName: aws.String("eni")},
+		{GroupId: aws.String("sg-elb"), GroupName: aws.String("elb")},
+		{GroupId: aws.String("sg-web"), GroupName: aws.String("web")},
+		{GroupId: aws.String("sg-lt"), GroupName: aws.String("lt")},
+		{GroupId: aws.String("sg-ref"), GroupName: aws.String("ref")},
+		{
+			GroupId:   aws.String("sg-free"),
+			GroupName: aws.String("free"),
+			IpPermissions: []*ec2.IpPermission{
+				{UserIdGroupPairs: []*ec2.UserIdGroupPair{
+					{GroupId: aws.String("sg-ref")},
+					{GroupId: aws.String("sg-free")},
+				}},
+			},
+		},
+	}
+
+	ec2Mock.EXPECT().DescribeNetworkInterfacesPages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(input *ec2.DescribeNetworkInterfacesInput, fn func(*ec2.DescribeNetworkInterfacesOutput, bool) bool) error {
+			fn(&ec2.DescribeNetworkInterfacesOutput{
+				NetworkInterfaces: []*ec2.NetworkInterface{
+					{Groups: []*ec2.GroupIdentifier{{GroupId: aws.String("sg-eni")}}},
+				},
+			}, true)
+			return nil
+		})
+	elbMock.EXPECT().DescribeLoadBalancersPages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(input *elb.DescribeLoadBalancersInput, fn func(*elb.DescribeLoadBalancersOutput, bool) bool) error {
+			fn(&elb.DescribeLoadBalancersOutput{
+				LoadBalancerDescriptions: []*elb.LoadBalancerDescription{
+					{SecurityGroups: aws.StringSlice([]string{"sg-elb"})},
+				},
+			}, true)
+			return nil
+		})
+	asgMock.EXPECT().DescribeLaunchConfigurationsPages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(input *autoscaling.DescribeLaunchConfigurationsInput,
+			fn func(*autoscaling.DescribeLaunchConfigurationsOutput, bool) bool) error {
+			fn(&autoscaling.DescribeLaunchConfigurationsOutput{
+				LaunchConfigurations: []*autoscaling.LaunchConfiguration{
+					{SecurityGroups: aws.StringSlice([]string{"web"})},
+				},
+			}, true)
+			return nil
+		})
+	ec2Mock.EXPECT().DescribeLaunchTemplates(gomock.Any()).Return(&ec2.DescribeLaunchTemplatesOutput{
+		LaunchTemplates: []*ec2.LaunchTemplate{{LaunchTemplateId: aws.String("lt-0123")}},
+	}, nil)
+	ec2Mock.EXPECT().DescribeLaunchTemplateVersions(&ec2.DescribeLaunchTemplateVersionsInput{
+		LaunchTemplateId: aws.String("lt-0123"),
+	}).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+		LaunchTemplateVersions: []*ec2.LaunchTemplateVersion{
+			{LaunchTemplateData: &ec2.ResponseLaunchTemplateData{
+				NetworkInterfaces: []*ec2.LaunchTemplateInstanceNetworkInterfaceSpecification{
+					{Groups: aws.StringSlice([]string{"sg-lt"})},
+				},
+			}},
+		},
+	}, nil)
+
+	res, err := resource.DeletableResources(resource.SecurityGroup, groups)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.SecurityGroup: {{Unused: true}},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	result := f.Apply(resource.SecurityGroup, res, groups, awsMock)
+
+	// then
+	require.Len(t, result[0], 1)
+	assert.Equal(t, "sg-free", result[0][0].ID)
+}
+
+func TestYamlFilter_Validate_UnusedNotSupported(t *testing.T) {
+	// given
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.Vpc: {{Unused: true}},
+		},
+	}
+
+	// when
+	err := f.Validate()
+
+	// then
+	assert.EqualError(t, err, "filtering by usage is not supported for resource type: aws_vpc")
+}
+
+func TestFilter_Apply_UnusedKeyPairs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	ec2Mock := mocks.NewMockEC2API(mockCtrl)
+	asgMock := mocks.NewMockAutoScalingAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		EC2API:         ec2Mock,
+		AutoScalingAPI: asgMock,
+	}
+
+	keyPairs := []*ec2.KeyPairInfo{
+		{KeyName: aws.String("instance-key"), KeyFingerprint: aws.String("1f:51:ae")},
+		{KeyName: aws.String("lc-key"), KeyFingerprint: aws.String("2a:3b:4c")},
+		{KeyName: aws.String("dead-key"), KeyFingerprint: aws.String("5d:6e:7f")},
+	}
+
+	ec2Mock.EXPECT().DescribeInstances(gomock.Any()).Return(&ec2.DescribeInstancesOutput{
+		Reservations: []*ec2.Reservation{
+			{Instances: []*ec2.Instance{{InstanceId: aws.String("i-0123"), KeyName: aws.String("instance-key")}}},
+		},
+	}, nil)
+	asgMock.EXPECT().DescribeLaunchConfigurationsPages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(input *autoscaling.DescribeLaunchConfigurationsInput,
+			fn func(*autoscaling.DescribeLaunchConfigurationsOutput, bool) bool) error {
+			fn(&autoscaling.DescribeLaunchConfigurationsOutput{
+				LaunchConfigurations: []*autoscaling.LaunchConfiguration{{KeyName: aws.String("lc-key")}},
+			}, true)
+			return nil
+		})
+
+	res, err := resource.DeletableResources(resource.KeyPair, keyPairs)
+	require.NoError(t, err)
+
+	f := &resource.Filter{
+		Cfg: resource.Config{
+			resource.KeyPair: {{Unused: true}},
+		},
+	}
+	require.NoError(t, f.Validate())
+
+	// when
+	result := f.Apply(resource.KeyPair, res, keyPairs, awsMock)
+
+	// then
+	require.Len(t, result[0], 1)
+	assert.Equal(t, "dead-key", result[0][0].ID)
+	assert.Equal(t, "5d:6e:7f", result[0][0].Model.(resource.KeyPairModel).Fingerprint)
+}
@@ -0,0 +1,61 @@
+package resource
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sfn"
+	"github.com/pkg/errors"
+)
+
+const (
+	deletionWaitAttempts = 150
+	deletionWaitInterval = 2 * time.Second
+)
+
+// deletionChecks check whether resources of types that are deleted asynchronously are gone.
+// Such resources remain in a deleting state for a while after their deletion has been requested.
+var deletionChecks = map[TerraformResourceType]func(a *AWS, r *Resource) (bool, error){
+	SfnStateMachine: (*AWS).sfnStateMachineDeleted,
+}
+
+// DeletesAsynchronously checks whether resources of a type remain in a deleting state once they have been deleted.
+func DeletesAsynchronously(resType TerraformResourceType) bool {
+	_, found := deletionChecks[resType]
+	return found
+}
+
+// WaitUntilDeleted polls until a resource that is deleted asynchronously is gone
+// (it returns right away for resources of other types).
+func (a *AWS) WaitUntilDeleted(r *Resource) error {
+	deleted, found := deletionChecks[r.Type]
+	if !found {
+		return nil
+	}
+
+	for i := 0; ; i++ {
+		done, err := deleted(a, r)
+		if err != nil {
+			return errors.Wrapf(err, "failed to check whether %s has been deleted", r.ID)
+		}
+		if done {
+			return nil
+		}
+		if i == deletionWaitAttempts-1 {
+			return errors.Errorf("%s has not been deleted in time", r.ID)
+		}
+		time.Sleep(deletionWaitInterval)
+	}
+}
+
+// sfnStateMachineDeleted checks whether a state machine is gone. State machines are in the DELETING state
+// until their running executions have stopped.
+func (a *AWS) sfnStateMachineDeleted(r *Resource) (bool, error) {
+	_, err := a.DescribeStateMachine(&sfn.DescribeStateMachineInput{
+		StateMachineArn: aws.String(r.ID),
+	})
+	if IsNotFound(err) {
+		return true, nil
+	}
+	return false, err
+}
@@ -0,0 +1,72 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/sfn"
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/cloudetc/awsweeper/resource/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testStateMachineArn = "arn:aws:states:us-east-1:123456789012:stateMachine:etl"
+
+func TestAWS_WaitUntilDeleted(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockSFNAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		SFNAPI: mockObj,
+	}
+
+	describe := mockObj.EXPECT().DescribeStateMachine(&sfn.DescribeStateMachineInput{
+		StateMachineArn: aws.String(testStateMachineArn),
+	})
+	gomock.InOrder(
+		describe.Return(&sfn.DescribeStateMachineOutput{
+			Status: aws.String(sfn.StateMachineStatusDeleting),
+		}, nil),
+		mockObj.EXPECT().DescribeStateMachine(gomock.Any()).Return(nil,
+			awserr.New(sfn.ErrCodeStateMachineDoesNotExist, "State Machine Does Not Exist", nil)),
+	)
+
+	// when
+	err := awsMock.WaitUntilDeleted(&resource.Resource{Type: resource.SfnStateMachine, ID: testStateMachineArn})
+
+	// then
+	assert.NoError(t, err)
+}
+
+func TestAWS_WaitUntilDeleted_Error(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// given
+	mockObj := mocks.NewMockSFNAPI(mockCtrl)
+	awsMock := &resource.AWS{
+		SFNAPI: mockObj,
+	}
+
+	mockObj.EXPECT().DescribeStateMachine(gomock.Any()).Return(nil,
+		awserr.New("AccessDeniedException", "not authorized", nil))
+
+	// when
+	err := awsMock.WaitUntilDeleted(&resource.Resource{Type: resource.SfnStateMachine, ID: testStateMachineArn})
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to check whether "+testStateMachineArn+" has been deleted")
+}
+
+func TestAWS_WaitUntilDeleted_SynchronousType(t *testing.T) {
+	// resources of other types are gone once they have been deleted
+	assert.False(t, resource.DeletesAsynchronously(resource.Instance))
+	assert.True(t, resource.DeletesAsynchronously(resource.SfnStateMachine))
+	assert.NoError(t, (&resource.AWS{}).WaitUntilDeleted(&resource.Resource{Type: resource.Instance, ID: "i-1234"}))
+}
@@ -0,0 +1,165 @@
+package resource
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Window is a recurring period of time in which resources are allowed to be deleted.
+type Window struct {
+	// Days are weekdays (e.g., "sat") or ranges of weekdays (e.g., "mon-fri"). Empty means every day.
+	Days []string `yaml:",omitempty"`
+	// Hours is a range of full hours (e.g., "22-06"), which ends before the second hour
+	// and can span midnight. Empty means the whole day.
+	Hours string `yaml:",omitempty"`
+	// Timezone is the name of the location the days and hours are in (default: UTC).
+	Timezone string `yaml:",omitempty"`
+}
+
+// validate checks whether the days, hours and timezone of the window can be parsed.
+func (w Window) validate() error {
+	if _, err := w.days(); err != nil {
+		return err
+	}
+	if _, _, err := w.hours(); err != nil {
+		return err
+	}
+	if _, err := w.location(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// days returns the weekdays of the window.
+func (w Window) days() (map[time.Weekday]bool, error) {
+	result := map[time.Weekday]bool{}
+
+	for _, d := range w.Days {
+		bounds := strings.Split(strings.ToLower(d), "-")
+		if len(bounds) > 2 {
+			return nil, fmt.Errorf("invalid range of days in allowed window: %s", d)
+		}
+
+		from, ok := weekdays[bounds[0]]
+		if !ok {
+			return nil, fmt.Errorf("invalid day in allowed window: %s", d)
+		}
+		to := from
+		if len(bounds) == 2 {
+			if to, ok = weekdays[bounds[1]]; !ok {
+				return nil, fmt.Errorf("invalid day in allowed window: %s", d)
+			}
+		}
+
+		for day := from; ; day = (day + 1) % 7 {
+			result[day] = true
+			if day == to {
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// hours returns the start (inclusive) and end (exclusive) hour of the window.
+func (w Window) hours() (int, int, error) {
+	if w.Hours == "" {
+		return 0, 24, nil
+	}
+
+	bounds := strings.Split(w.Hours, "-")
+	if len(bounds) != 2 {
+		return 0, 0, fmt.Errorf("invalid range of hours in allowed window: %s", w.Hours)
+	}
+
+	from, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+	if err != nil || from < 0 || from > 23 {
+		return 0, 0, fmt.Errorf("invalid range of hours in allowed window: %s", w.Hours)
+	}
+	to, err := strconv.Atoi(strings.TrimSpace(bounds[1]))
+	if err != nil || to < 0 || to > 24 {
+		return 0, 0, fmt.Errorf("invalid range of hours in allowed window: %s", w.Hours)
+	}
+	// as the end is exclusive, it is ambiguous whether such a range is empty or spans the whole day
+	if from == to {
+		return 0, 0, fmt.Errorf("empty range of hours in allowed window: %s", w.Hours)
+	}
+	return from, to, nil
+}
+
+// location returns the timezone of the window.
+func (w Window) location() (*time.Location, error) {
+	if w.Timezone == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone in allowed window: %s", w.Timezone)
+	}
+	return loc, nil
+}
+
+// contains checks whether the given point in time is within the window. For windows that span midnight,
+// the day is the one the window starts on (e.g., "fri" with "22-06" includes saturday 3 am).
+func (w Window) contains(t time.Time) bool {
+	days, err := w.days()
+	if err != nil {
+		return false
+	}
+	from, to, err := w.hours()
+	if err != nil {
+		return false
+	}
+	loc, err := w.location()
+	if err != nil {
+		return false
+	}
+
+	t = t.In(loc)
+	hour := t.Hour()
+	day := t.Weekday()
+
+	if from < to {
+		if hour < from || hour >= to {
+			return false
+		}
+	} else {
+		if hour < from && hour >= to {
+			return false
+		}
+		// the early hours belong to the window that has started the day before
+		if hour < to {
+			day = (day + 6) % 7
+		}
+	}
+
+	return len(days) == 0 || days[day]
+}
+
+// DeletionAllowed checks whether resources are allowed to be deleted at the given point in time,
+// which is the case if no windows are configured or the time is within any of them.
+func (f Filter) DeletionAllowed(t time.Time) bool {
+	if len(f.AllowedWindows) == 0 {
+		return true
+	}
+
+	for _, w := range f.AllowedWindows {
+		if w.contains(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package resource_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudetc/awsweeper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+// 2018-11-17 is a saturday
+var testSaturday = time.Date(2018, 11, 17, 0, 0, 0, 0, time.UTC)
+
+func TestFilter_DeletionAllowed_NoWindows(t *testing.T) {
+	f := &resource.Filter{}
+
+	assert.True(t, f.DeletionAllowed(testSaturday))
+}
+
+func TestFilter_DeletionAllowed_Days(t *testing.T) {
+	f := &resource.Filter{
+		AllowedWindows: []resource.Window{
+			{Days: []string{"sat", "sun"}},
+		},
+	}
+
+	assert.True(t, f.DeletionAllowed(testSaturday.Add(10*time.Hour)))
+	assert.True(t, f.DeletionAllowed(testSaturday.Add(34*time.Hour)))
+	assert.False(t, f.DeletionAllowed(testSaturday.Add(58*time.Hour)))
+}
+
+func TestFilter_DeletionAllowed_DayRange(t *testing.T) {
+	f := &resource.Filter{
+		AllowedWindows: []resource.Window{
+			{Days: []string{"fri-mon"}},
+		},
+	}
+
+	assert.True(t, f.DeletionAllowed(testSaturday.Add(-10*time.Hour)))
+	assert.True(t, f.DeletionAllowed(testSaturday.Add(58*time.Hour)))
+	assert.False(t, f.DeletionAllowed(testSaturday.Add(82*time.Hour)))
+}
+
+func TestFilter_DeletionAllowed_Hours(t *testing.T) {
+	f := &resource.Filter{
+		AllowedWindows: []resource.Window{
+			{Hours: "09-17"},
+		},
+	}
+
+	assert.True(t, f.DeletionAllowed(testSaturday.Add(9*time.Hour)))
+	assert.True(t, f.DeletionAllowed(testSaturday.Add(16*time.Hour+59*time.Minute)))
+	assert.False(t, f.DeletionAllowed(testSaturday.Add(17*time.Hour)))
+	assert.False(t, f.DeletionAllowed(testSaturday.Add(8*time.Hour)))
+}
+
+func TestFilter_DeletionAllowed_OverMidnight(t *testing.T) {
+	f := &resource.Filter{
+		AllowedWindows: []resource.Window{
+			{Days: []string{"fri"}, Hours: "22-06"},
+		},
+	}
+
+	// friday 11 pm
+	assert.True(t, f.DeletionAllowed(testSaturday.Add(-1*time.Hour)))
+	// saturday 3 am belongs to the window starting friday
+	assert.True(t, f.DeletionAllowed(testSaturday.Add(3*time.Hour)))
+	// saturday 11 pm
+	assert.False(t, f.DeletionAllowed(testSaturday.Add(23*time.Hour)))
+	// friday noon
+	assert.False(t, f.DeletionAllowed(testSaturday.Add(-12*time.Hour)))
+}
+
+func TestFilter_DeletionAllowed_Timezone(t *testing.T) {
+	f := &resource.Filter{
+		AllowedWindows: []resource.Window{
+			{Days: []string{"sat"}, Timezone: "America/New_York"},
+		},
+	}
+
+	// saturday 2 am UTC is still friday in New York
+	assert.False(t, f.DeletionAllowed(testSaturday.Add(2*time.Hour)))
+	assert.True(t, f.DeletionAllowed(testSaturday.Add(10*time.Hour)))
+}
+
+func TestYamlFilter_Validate_InvalidWindow(t *testing.T) {
+	tests := []struct {
+		window resource.Window
+		err    string
+	}{
+		{resource.Window{Days: []string{"someday"}}, "invalid day in allowed window: someday"},
+		{resource.Window{Hours: "9"}, "invalid range of hours in allowed window: 9"},
+		{resource.Window{Hours: "25-06"}, "invalid range of hours in allowed window: 25-06"},
+		{resource.Window{Hours: "10-10"}, "empty range of hours in allowed window: 10-10"},
+		{resource.Window{Timezone: "Mars/Olympus"}, "invalid timezone in allowed window: Mars/Olympus"},
+	}
+
+	for _, tc := range tests {
+		f := &resource.Filter{
+			Cfg:            resource.Config{resource.Instance: {}},
+			AllowedWindows: []resource.Window{tc.window},
+		}
+
+		assert.EqualError(t, f.Validate(), tc.err)
+	}
+}
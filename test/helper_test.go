@@ -27,7 +27,7 @@ var testAccProviders map[string]terraform.ResourceProvider
 var testAccProvider *schema.Provider
 
 var argsDryRun = []string{"cmd", "--dry-run", "config.yml"}
-var argsForceDelete = []string{"cmd", "--force", "config.yml"}
+var argsForceDelete = []string{"cmd", "--no-dry-run", "--force", "config.yml"}
 
 func initClient() *res.AWS {
 	sess := session.Must(session.NewSessionWithOptions(session.Options{
@@ -16,7 +16,6 @@ import (
 )
 
 func TestAccKmsKey_deleteByTags(t *testing.T) {
-	// TODO implement tag support
 	t.Skip("Costs money even in free tier")
 	var k1, k2 kms.KeyMetadata
 
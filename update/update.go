@@ -0,0 +1,251 @@
+// Package update replaces the running AWSweeper binary with the one of the latest release,
+// after verifying the signature of the release checksums and the checksum of the downloaded archive.
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/openpgp"
+)
+
+const (
+	// LatestReleaseURL is the API endpoint of the latest release of AWSweeper on GitHub.
+	LatestReleaseURL = "https://api.github.com/repos/cloudetc/awsweeper/releases/latest"
+
+	checksumsFile = "checksums.txt"
+	// signatureFile is the detached (binary) GPG signature of the checksums file
+	signatureFile = checksumsFile + ".sig"
+)
+
+// Release is a release of AWSweeper.
+type Release struct {
+	Version string
+	// Assets are the download URLs of the release files by name
+	Assets map[string]string
+}
+
+// Updater downloads and verifies releases of AWSweeper.
+type Updater struct {
+	// ReleaseURL is the API endpoint of the latest release
+	ReleaseURL string
+	Client     *http.Client
+	// KeyRing contains the public key releases are signed with
+	KeyRing openpgp.KeyRing
+}
+
+// NewUpdater creates an updater that verifies releases with the given (armored) public key. Without key (nil),
+// the updater can only look up the latest release, but not download it.
+func NewUpdater(armoredKey io.Reader) (*Updater, error) {
+	var keyRing openpgp.KeyRing
+	if armoredKey != nil {
+		var err error
+		if keyRing, err = openpgp.ReadArmoredKeyRing(armoredKey); err != nil {
+			return nil, errors.Wrap(err, "invalid release key")
+		}
+	}
+
+	return &Updater{
+		ReleaseURL: LatestReleaseURL,
+		Client:     &http.Client{Timeout: 5 * time.Minute},
+		KeyRing:    keyRing,
+	}, nil
+}
+
+// ArchiveName returns the name of the release archive for an operating system and architecture.
+func ArchiveName(version, goos, goarch string) string {
+	ext := "tar.gz"
+	if goos == "windows" {
+		ext = "zip"
+	}
+	return fmt.Sprintf("awsweeper_%s_%s_%s.%s", version, goos, goarch, ext)
+}
+
+// Latest returns the latest release.
+func (u *Updater) Latest() (*Release, error) {
+	data, err := u.get(u.ReleaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+		Assets  []struct {
+			Name string `json:"name"`
+			URL  string `json:"browser_download_url"`
+		} `json:"assets"`
+	}
+	if err := json.Unmarshal(data, &release); err != nil {
+		return nil, errors.Wrap(err, "cannot unmarshal latest release")
+	}
+
+	result := &Release{
+		Version: strings.TrimPrefix(release.TagName, "v"),
+		Assets:  map[string]string{},
+	}
+	for _, a := range release.Assets {
+		result.Assets[a.Name] = a.URL
+	}
+	return result, nil
+}
+
+// Binary downloads the archive of a release for an operating system and architecture and returns
+// the AWSweeper binary in it. The signature of the checksums and the checksum of the archive are verified.
+func (u *Updater) Binary(release *Release, goos, goarch string) ([]byte, error) {
+	if u.KeyRing == nil {
+		return nil, errors.New("no key to verify releases with")
+	}
+
+	checksums, err := u.asset(release, checksumsFile)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := u.asset(release, signatureFile)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := openpgp.CheckDetachedSignature(u.KeyRing, bytes.NewReader(checksums), bytes.NewReader(signature)); err != nil {
+		return nil, errors.Wrap(err, "invalid signature of release checksums")
+	}
+
+	name := ArchiveName(release.Version, goos, goarch)
+	expected, err := checksum(checksums, name)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := u.asset(release, name)
+	if err != nil {
+		return nil, err
+	}
+	actual := sha256.Sum256(archive)
+	if hex.EncodeToString(actual[:]) != expected {
+		return nil, errors.Errorf("checksum mismatch of %s", name)
+	}
+
+	if goos == "windows" {
+		return fromZip(archive, "awsweeper.exe")
+	}
+	return fromTarGz(archive, "awsweeper")
+}
+
+// Replace replaces an executable with the given binary. The executable is renamed first,
+// as running executables can't be overwritten on Windows.
+func Replace(executable string, binary []byte) error {
+	info, err := os.Stat(executable)
+	if err != nil {
+		return err
+	}
+
+	newFile := executable + ".new"
+	oldFile := executable + ".old"
+	if err := ioutil.WriteFile(newFile, binary, info.Mode()); err != nil {
+		return errors.Wrap(err, "failed to write new binary")
+	}
+
+	os.Remove(oldFile)
+	if err := os.Rename(executable, oldFile); err != nil {
+		os.Remove(newFile)
+		return errors.Wrap(err, "failed to move current binary")
+	}
+	if err := os.Rename(newFile, executable); err != nil {
+		// restore the current binary
+		os.Rename(oldFile, executable)
+		return errors.Wrap(err, "failed to move new binary")
+	}
+
+	// removing the running executable fails on Windows, it is removed by the next update instead
+	os.Remove(oldFile)
+	return nil
+}
+
+func (u *Updater) asset(release *Release, name string) ([]byte, error) {
+	url, found := release.Assets[name]
+	if !found {
+		return nil, errors.Errorf("release %s has no file %s", release.Version, name)
+	}
+	return u.get(url)
+}
+
+func (u *Updater) get(url string) ([]byte, error) {
+	resp, err := u.Client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// checksum returns the (hex-encoded) SHA-256 checksum of a file in a checksums file,
+// which has a line "<checksum>  <name>" per file.
+func checksum(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == name {
+			return fields[0], nil
+		}
+	}
+	return "", errors.Errorf("no checksum found for %s", name)
+}
+
+func fromTarGz(archive []byte, binary string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+
+	r := tar.NewReader(gz)
+	for {
+		h, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if path.Base(h.Name) == binary {
+			return ioutil.ReadAll(r)
+		}
+	}
+	return nil, errors.Errorf("archive contains no %s", binary)
+}
+
+func fromZip(archive []byte, binary string) ([]byte, error) {
+	r, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range r.File {
+		if path.Base(f.Name) != binary {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return ioutil.ReadAll(rc)
+	}
+	return nil, errors.Errorf("archive contains no %s", binary)
+}
@@ -0,0 +1,167 @@
+package update_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudetc/awsweeper/update"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/openpgp"
+)
+
+func testTarGz(t *testing.T, name string, content []byte) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	w := tar.NewWriter(gz)
+	require.NoError(t, w.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content))}))
+	_, err := w.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+// testRelease serves a release of version 1.2.3 with the given files.
+func testRelease(t *testing.T, files map[string][]byte) *httptest.Server {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	var assets []string
+	for name, content := range files {
+		content := content
+		mux.HandleFunc("/download/"+name, func(w http.ResponseWriter, r *http.Request) {
+			w.Write(content)
+		})
+		assets = append(assets, fmt.Sprintf(`{"name": %q, "browser_download_url": %q}`, name, server.URL+"/download/"+name))
+	}
+	mux.HandleFunc("/latest", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"tag_name": "v1.2.3", "assets": [%s]}`, strings.Join(assets, ","))
+	})
+	return server
+}
+
+func testUpdater(t *testing.T, signer *openpgp.Entity, binary []byte) (*update.Updater, func()) {
+	archive := testTarGz(t, "awsweeper", binary)
+	sum := sha256.Sum256(archive)
+	checksums := []byte(fmt.Sprintf("%s  awsweeper_1.2.3_linux_arm64.tar.gz\n", hex.EncodeToString(sum[:])))
+
+	var signature bytes.Buffer
+	require.NoError(t, openpgp.DetachSign(&signature, signer, bytes.NewReader(checksums), nil))
+
+	server := testRelease(t, map[string][]byte{
+		"awsweeper_1.2.3_linux_arm64.tar.gz": archive,
+		"checksums.txt":                      checksums,
+		"checksums.txt.sig":                  signature.Bytes(),
+	})
+
+	return &update.Updater{
+		ReleaseURL: server.URL + "/latest",
+		Client:     server.Client(),
+	}, server.Close
+}
+
+func TestUpdater_Binary(t *testing.T) {
+	// given
+	key, err := openpgp.NewEntity("AWSweeper", "", "release@example.com", nil)
+	require.NoError(t, err)
+	u, closeServer := testUpdater(t, key, []byte("new binary"))
+	defer closeServer()
+	u.KeyRing = openpgp.EntityList{key}
+
+	// when
+	release, err := u.Latest()
+	require.NoError(t, err)
+	binary, err := u.Binary(release, "linux", "arm64")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", release.Version)
+	assert.Equal(t, []byte("new binary"), binary)
+}
+
+func TestUpdater_Binary_InvalidSignature(t *testing.T) {
+	// given
+	key, err := openpgp.NewEntity("AWSweeper", "", "release@example.com", nil)
+	require.NoError(t, err)
+	other, err := openpgp.NewEntity("Other", "", "other@example.com", nil)
+	require.NoError(t, err)
+	u, closeServer := testUpdater(t, other, []byte("new binary"))
+	defer closeServer()
+	u.KeyRing = openpgp.EntityList{key}
+
+	// when
+	release, err := u.Latest()
+	require.NoError(t, err)
+	_, err = u.Binary(release, "linux", "arm64")
+
+	// then
+	assert.Error(t, err)
+}
+
+func TestUpdater_Binary_UnsupportedPlatform(t *testing.T) {
+	// given
+	key, err := openpgp.NewEntity("AWSweeper", "", "release@example.com", nil)
+	require.NoError(t, err)
+	u, closeServer := testUpdater(t, key, []byte("new binary"))
+	defer closeServer()
+	u.KeyRing = openpgp.EntityList{key}
+
+	// when
+	release, err := u.Latest()
+	require.NoError(t, err)
+	_, err = u.Binary(release, "windows", "amd64")
+
+	// then
+	assert.EqualError(t, err, "no checksum found for awsweeper_1.2.3_windows_amd64.zip")
+}
+
+func TestUpdater_Binary_WithoutKey(t *testing.T) {
+	// given
+	key, err := openpgp.NewEntity("AWSweeper", "", "release@example.com", nil)
+	require.NoError(t, err)
+	u, closeServer := testUpdater(t, key, []byte("new binary"))
+	defer closeServer()
+
+	// when
+	release, err := u.Latest()
+	require.NoError(t, err)
+	_, err = u.Binary(release, "linux", "arm64")
+
+	// then
+	assert.EqualError(t, err, "no key to verify releases with")
+}
+
+func TestReplace(t *testing.T) {
+	// given
+	dir, err := ioutil.TempDir("", "awsweeper")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	executable := filepath.Join(dir, "awsweeper")
+	require.NoError(t, ioutil.WriteFile(executable, []byte("old binary"), 0755))
+
+	// when
+	err = update.Replace(executable, []byte("new binary"))
+
+	// then
+	require.NoError(t, err)
+	content, err := ioutil.ReadFile(executable)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("new binary"), content)
+
+	info, err := os.Stat(executable)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode())
+}